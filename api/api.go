@@ -23,10 +23,15 @@ const (
 	InventoryPath   = "/inventory"
 	ReservationPath = "/reservation"
 	UserPath        = "/user"
+	CategoryPath    = "/category"
+	ReportPath      = "/reports"
+	CountPath       = "/count"
+	ReturnPath      = "/returns"
+	PurchasePath    = "/purchaseOrders"
 )
 
 // ConfigureRouter instantiates a go-chi router with middleware and routes for the server
-func ConfigureRouter(cfg *config.Config, invSvc InventoryService, resSvc ReservationService, userService UserService) chi.Router {
+func ConfigureRouter(cfg *config.Config, invSvc InventoryService, resSvc ReservationService, catSvc CategoryService, rptSvc ReportService, cntSvc CountService, rtnSvc ReturnService, poSvc PurchaseOrderService, userService UserService) chi.Router {
 	log.Info().Msg("configuring router...")
 	r := chi.NewRouter()
 
@@ -55,8 +60,13 @@ func ConfigureRouter(cfg *config.Config, invSvc InventoryService, resSvc Reserva
 	// r.With(Authenticate(userService)).Route("/api/v1", func(r chi.Router) {
 
 	r.Route(ApiPath, func(r chi.Router) {
-		r.Route(InventoryPath, NewInventoryApi(invSvc).ConfigureRouter)
-		r.Route(ReservationPath, NewReservationApi(resSvc).ConfigureRouter)
+		r.Route(InventoryPath+ReportPath, NewReportApi(rptSvc).ConfigureRouter)
+		r.Route(InventoryPath, NewInventoryApi(invSvc, userService).ConfigureRouter)
+		r.Route(ReservationPath, NewReservationApi(resSvc, userService).ConfigureRouter)
+		r.Route(CategoryPath, NewCategoryApi(catSvc).ConfigureRouter)
+		r.Route(CountPath, NewCountApi(cntSvc, userService).ConfigureRouter)
+		r.Route(ReturnPath, NewReturnApi(rtnSvc, userService).ConfigureRouter)
+		r.Route(PurchasePath, NewPurchaseOrderApi(poSvc, userService).ConfigureRouter)
 		r.Route(UserPath, NewUserApi(userService).ConfigureRouter)
 	})
 
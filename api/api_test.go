@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/go-chi/chi"
@@ -62,12 +63,21 @@ func TestCorsConfig(t *testing.T) {
 	}
 }
 
+var (
+	router     chi.Router
+	routerOnce sync.Once
+)
+
+// getRouter configures the router once, metrics collectors can only be registered a single time.
 func getRouter() chi.Router {
-	cfg := config.LoadDefaults()
-	invSvc, resSvc, usrSvc := getMocks()
-	return api.ConfigureRouter(cfg, invSvc, resSvc, usrSvc)
+	routerOnce.Do(func() {
+		cfg := config.LoadDefaults()
+		invSvc, resSvc, catSvc, rptSvc, cntSvc, rtnSvc, poSvc, usrSvc := getMocks()
+		router = api.ConfigureRouter(cfg, invSvc, resSvc, catSvc, rptSvc, cntSvc, rtnSvc, poSvc, usrSvc)
+	})
+	return router
 }
 
-func getMocks() (*inventory.MockInventoryService, *inventory.MockReservationService, *user.MockUserService) {
-	return inventory.NewMockInventoryService(), inventory.NewMockReservationService(), user.NewMockUserService()
+func getMocks() (*inventory.MockInventoryService, *inventory.MockReservationService, *inventory.MockCategoryService, *inventory.MockReportService, *inventory.MockCountService, *inventory.MockReturnService, *inventory.MockPurchaseOrderService, *user.MockUserService) {
+	return inventory.NewMockInventoryService(), inventory.NewMockReservationService(), inventory.NewMockCategoryService(), inventory.NewMockReportService(), inventory.NewMockCountService(), inventory.NewMockReturnService(), inventory.NewMockPurchaseOrderService(), user.NewMockUserService()
 }
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+)
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, category inventory.Category) (inventory.Category, error)
+	UpdateCategory(ctx context.Context, category inventory.Category) (inventory.Category, error)
+	DeleteCategory(ctx context.Context, ID uint64) error
+
+	GetCategory(ctx context.Context, ID uint64) (inventory.Category, error)
+	GetCategories(ctx context.Context, limit, offset int) ([]inventory.Category, error)
+	GetChildCategories(ctx context.Context, ID uint64) ([]inventory.Category, error)
+	GetCategoryInventory(ctx context.Context, ID uint64) (inventory.CategoryInventory, error)
+}
+
+type CategoryApi struct {
+	service CategoryService
+}
+
+func NewCategoryApi(service CategoryService) *CategoryApi {
+	return &CategoryApi{service: service}
+}
+
+const (
+	CtxKeyCategory CtxKey = "category"
+)
+
+func (a *CategoryApi) ConfigureRouter(r chi.Router) {
+	r.With(Paginate).Get("/", a.List)
+	r.Put("/", a.Create)
+
+	r.Route("/{ID}", func(r chi.Router) {
+		r.Use(a.CategoryCtx)
+		r.Get("/", a.Get)
+		r.Put("/", a.Update)
+		r.Delete("/", a.Delete)
+		r.Get("/children", a.GetChildren)
+		r.Get("/inventory", a.GetInventory)
+	})
+}
+
+func (a *CategoryApi) List(w http.ResponseWriter, r *http.Request) {
+	limit := r.Context().Value(CtxKeyLimit).(int)
+	offset := r.Context().Value(CtxKeyOffset).(int)
+
+	categories, err := a.service.GetCategories(r.Context(), limit, offset)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewCategoryListResponse(categories))
+}
+
+func (a *CategoryApi) Create(w http.ResponseWriter, r *http.Request) {
+	data := &CategoryRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	category, err := a.service.CreateCategory(r.Context(), data.Category)
+	if err != nil {
+		renderCategoryErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &CategoryResponse{Category: category})
+}
+
+func (a *CategoryApi) Get(w http.ResponseWriter, r *http.Request) {
+	category := r.Context().Value(CtxKeyCategory).(inventory.Category)
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &CategoryResponse{Category: category})
+}
+
+func (a *CategoryApi) Update(w http.ResponseWriter, r *http.Request) {
+	category := r.Context().Value(CtxKeyCategory).(inventory.Category)
+
+	data := &CategoryRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	data.ID = category.ID
+
+	category, err := a.service.UpdateCategory(r.Context(), data.Category)
+	if err != nil {
+		renderCategoryErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &CategoryResponse{Category: category})
+}
+
+func (a *CategoryApi) Delete(w http.ResponseWriter, r *http.Request) {
+	category := r.Context().Value(CtxKeyCategory).(inventory.Category)
+
+	if err := a.service.DeleteCategory(r.Context(), category.ID); err != nil {
+		renderCategoryErr(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *CategoryApi) GetChildren(w http.ResponseWriter, r *http.Request) {
+	category := r.Context().Value(CtxKeyCategory).(inventory.Category)
+
+	children, err := a.service.GetChildCategories(r.Context(), category.ID)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewCategoryListResponse(children))
+}
+
+func (a *CategoryApi) GetInventory(w http.ResponseWriter, r *http.Request) {
+	category := r.Context().Value(CtxKeyCategory).(inventory.Category)
+
+	ci, err := a.service.GetCategoryInventory(r.Context(), category.ID)
+	if err != nil {
+		renderCategoryErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &CategoryInventoryResponse{CategoryInventory: ci})
+}
+
+func (a *CategoryApi) CategoryCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		IDStr := chi.URLParam(r, "ID")
+		ID, err := strconv.ParseUint(IDStr, 10, 64)
+		if err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("invalid category id")))
+			return
+		}
+
+		category, err := a.service.GetCategory(r.Context(), ID)
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				Render(w, r, ErrNotFound)
+			} else {
+				log.Error().Err(err).Str("id", IDStr).Msg("error acquiring category")
+				Render(w, r, ErrInternalServer)
+			}
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), CtxKeyCategory, category)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func renderCategoryErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, inventory.ErrInvalidCategory) {
+		Render(w, r, ErrInvalidRequest(err))
+	} else if errors.Is(err, core.ErrNotFound) {
+		Render(w, r, ErrNotFound)
+	} else {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+	}
+}
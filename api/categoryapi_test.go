@@ -0,0 +1,236 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/sksmith/go-micro-example/api"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/testutil"
+)
+
+func setupCategoryTestServer() (*httptest.Server, *inventory.MockCategoryService) {
+	mockSvc := inventory.NewMockCategoryService()
+	catApi := api.NewCategoryApi(mockSvc)
+	r := chi.NewRouter()
+	catApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+
+	return ts, mockSvc
+}
+
+func TestCategoryCreate(t *testing.T) {
+	ts, mockSvc := setupCategoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		request        api.CategoryRequest
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "category is created",
+			request:        api.CategoryRequest{Category: inventory.Category{Name: "bats", ParentID: 1}},
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "name is required",
+			request:        api.CategoryRequest{Category: inventory.Category{ParentID: 1}},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "parent does not exist",
+			request:        api.CategoryRequest{Category: inventory.Category{Name: "bats", ParentID: 99}},
+			serviceErr:     inventory.ErrInvalidCategory,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "unexpected error",
+			request:        api.CategoryRequest{Category: inventory.Category{Name: "bats"}},
+			serviceErr:     errors.New("some unexpected error"),
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CreateCategoryFunc = func(ctx context.Context, category inventory.Category) (inventory.Category, error) {
+				category.ID = 7
+				return category, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL, test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+
+			if test.wantStatusCode == http.StatusCreated {
+				got := inventory.Category{}
+				testutil.Unmarshal(res, &got, t)
+
+				want := test.request.Category
+				want.ID = 7
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("category\n got=%+v\nwant=%+v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCategoryUpdate(t *testing.T) {
+	ts, mockSvc := setupCategoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		url            string
+		getErr         error
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "category is updated",
+			url:            "/3",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "category would become its own ancestor",
+			url:            "/3",
+			serviceErr:     inventory.ErrInvalidCategory,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "category not found",
+			url:            "/3",
+			getErr:         core.ErrNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid id",
+			url:            "/abc",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.GetCategoryFunc = func(ctx context.Context, ID uint64) (inventory.Category, error) {
+				return inventory.Category{ID: ID, Name: "old"}, test.getErr
+			}
+			var gotCategory inventory.Category
+			mockSvc.UpdateCategoryFunc = func(ctx context.Context, category inventory.Category) (inventory.Category, error) {
+				gotCategory = category
+				return category, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+test.url, api.CategoryRequest{Category: inventory.Category{ID: 99, Name: "new", ParentID: 1}}, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+
+			if test.wantStatusCode == http.StatusOK {
+				want := inventory.Category{ID: 3, Name: "new", ParentID: 1}
+				if !reflect.DeepEqual(gotCategory, want) {
+					t.Errorf("category\n got=%+v\nwant=%+v", gotCategory, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCategoryDelete(t *testing.T) {
+	ts, mockSvc := setupCategoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "category is deleted",
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "category has children",
+			serviceErr:     inventory.ErrInvalidCategory,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.DeleteCategoryFunc = func(ctx context.Context, ID uint64) error {
+				return test.serviceErr
+			}
+
+			res := testutil.SendRequest(http.MethodDelete, ts.URL+"/3", nil, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestCategoryGetInventory(t *testing.T) {
+	ts, mockSvc := setupCategoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		inventory      inventory.CategoryInventory
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name: "category inventory is rolled up",
+			inventory: inventory.CategoryInventory{
+				Category: inventory.Category{ID: 3, Name: "bats"},
+				Products: 4, OnHand: 12, Reserved: 3, Available: 7, Held: 2, OpenDemand: 5,
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "unexpected error",
+			serviceErr:     errors.New("some unexpected error"),
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.GetCategoryInventoryFunc = func(ctx context.Context, ID uint64) (inventory.CategoryInventory, error) {
+				return test.inventory, test.serviceErr
+			}
+
+			res, err := http.Get(ts.URL + "/3/inventory")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+
+			if test.wantStatusCode == http.StatusOK {
+				got := inventory.CategoryInventory{}
+				testutil.Unmarshal(res, &got, t)
+
+				if !reflect.DeepEqual(got, test.inventory) {
+					t.Errorf("category inventory\n got=%+v\nwant=%+v", got, test.inventory)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/sksmith/go-micro-example/core/inventory"
+)
+
+type CategoryRequest struct {
+	inventory.Category
+}
+
+func (c *CategoryRequest) Bind(_ *http.Request) error {
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type CategoryResponse struct {
+	inventory.Category
+}
+
+func (c *CategoryResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewCategoryListResponse(categories []inventory.Category) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, c := range categories {
+		list = append(list, &CategoryResponse{Category: c})
+	}
+	return list
+}
+
+type CategoryInventoryResponse struct {
+	inventory.CategoryInventory
+}
+
+func (c *CategoryInventoryResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+type AssignCategoryRequest struct {
+	CategoryID *uint64 `json:"categoryId"`
+}
+
+func (a *AssignCategoryRequest) Bind(_ *http.Request) error {
+	if a.CategoryID == nil {
+		return errors.New("categoryId is required")
+	}
+	return nil
+}
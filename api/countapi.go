@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
+)
+
+type CountService interface {
+	StartCount(ctx context.Context, skus []string) (inventory.CountSession, error)
+	RecordCount(ctx context.Context, ID uint64, counts []inventory.CountEntry) (inventory.CountSession, error)
+	ApproveCount(ctx context.Context, ID uint64, approver string) (inventory.CountSession, error)
+	CancelCount(ctx context.Context, ID uint64, user string) (inventory.CountSession, error)
+
+	GetCountSession(ctx context.Context, ID uint64) (inventory.CountSession, error)
+	GetCountSessions(ctx context.Context, state inventory.CountState, limit, offset int) ([]inventory.CountSession, error)
+}
+
+type CountApi struct {
+	service CountService
+	access  UserAccess
+}
+
+func NewCountApi(service CountService, access UserAccess) *CountApi {
+	return &CountApi{service: service, access: access}
+}
+
+const (
+	CtxKeyCountSession CtxKey = "countSession"
+)
+
+func (a *CountApi) ConfigureRouter(r chi.Router) {
+	r.With(Paginate).Get("/", a.List)
+	r.Put("/", a.Start)
+
+	r.Route("/{ID}", func(r chi.Router) {
+		r.Use(a.CountSessionCtx)
+		r.Get("/", a.Get)
+		r.Put("/counts", a.Record)
+		r.With(Authenticate(a.access), AdminOnly).Put("/approve", a.Approve)
+		r.With(Authenticate(a.access), AdminOnly).Put("/cancel", a.Cancel)
+	})
+}
+
+func (a *CountApi) List(w http.ResponseWriter, r *http.Request) {
+	limit := r.Context().Value(CtxKeyLimit).(int)
+	offset := r.Context().Value(CtxKeyOffset).(int)
+
+	var state inventory.CountState
+	if v := r.URL.Query().Get("state"); v != "" {
+		var err error
+		if state, err = inventory.ParseCountState(v); err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("invalid state")))
+			return
+		}
+	}
+
+	sessions, err := a.service.GetCountSessions(r.Context(), state, limit, offset)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewCountSessionListResponse(sessions))
+}
+
+func (a *CountApi) Start(w http.ResponseWriter, r *http.Request) {
+	data := &StartCountRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	session, err := a.service.StartCount(r.Context(), data.Skus)
+	if err != nil {
+		renderCountErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &CountSessionResponse{CountSession: session})
+}
+
+func (a *CountApi) Get(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value(CtxKeyCountSession).(inventory.CountSession)
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &CountSessionResponse{CountSession: session})
+}
+
+func (a *CountApi) Record(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value(CtxKeyCountSession).(inventory.CountSession)
+
+	data := &RecordCountRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	session, err := a.service.RecordCount(r.Context(), session.ID, data.Counts)
+	if err != nil {
+		renderCountErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &CountSessionResponse{CountSession: session})
+}
+
+func (a *CountApi) Approve(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value(CtxKeyCountSession).(inventory.CountSession)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	session, err := a.service.ApproveCount(r.Context(), session.ID, usr.Username)
+	if err != nil {
+		renderCountErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &CountSessionResponse{CountSession: session})
+}
+
+func (a *CountApi) Cancel(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value(CtxKeyCountSession).(inventory.CountSession)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	session, err := a.service.CancelCount(r.Context(), session.ID, usr.Username)
+	if err != nil {
+		renderCountErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &CountSessionResponse{CountSession: session})
+}
+
+func (a *CountApi) CountSessionCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		IDStr := chi.URLParam(r, "ID")
+		ID, err := strconv.ParseUint(IDStr, 10, 64)
+		if err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("invalid count id")))
+			return
+		}
+
+		session, err := a.service.GetCountSession(r.Context(), ID)
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				Render(w, r, ErrNotFound)
+			} else {
+				log.Error().Err(err).Str("id", IDStr).Msg("error acquiring count session")
+				Render(w, r, ErrInternalServer)
+			}
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), CtxKeyCountSession, session)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func renderCountErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, inventory.ErrInvalidCount) {
+		Render(w, r, ErrInvalidRequest(err))
+	} else if errors.Is(err, core.ErrNotFound) {
+		Render(w, r, ErrNotFound)
+	} else {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+	}
+}
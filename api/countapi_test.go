@@ -0,0 +1,295 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/sksmith/go-micro-example/api"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
+	"github.com/sksmith/go-micro-example/testutil"
+)
+
+func setupCountTestServer() (*httptest.Server, *inventory.MockCountService, *user.MockUserService) {
+	mockSvc := inventory.NewMockCountService()
+	usrSvc := user.NewMockUserService()
+	cntApi := api.NewCountApi(mockSvc, usrSvc)
+	r := chi.NewRouter()
+	cntApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+
+	return ts, mockSvc, usrSvc
+}
+
+func TestCountStart(t *testing.T) {
+	ts, mockSvc, _ := setupCountTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		request        api.StartCountRequest
+		serviceErr     error
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "count is started",
+			request:        api.StartCountRequest{Skus: []string{"sku1", "sku2"}},
+			wantStatusCode: http.StatusCreated,
+			wantCall:       1,
+		},
+		{
+			name:           "skus are required",
+			request:        api.StartCountRequest{},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "sku already being counted",
+			request:        api.StartCountRequest{Skus: []string{"sku1"}},
+			serviceErr:     inventory.ErrInvalidCount,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+		{
+			name:           "unexpected error",
+			request:        api.StartCountRequest{Skus: []string{"sku1"}},
+			serviceErr:     errors.New("some unexpected error"),
+			wantStatusCode: http.StatusInternalServerError,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.StartCountFunc = func(ctx context.Context, skus []string) (inventory.CountSession, error) {
+				lines := make([]inventory.CountLine, 0)
+				for _, sku := range skus {
+					lines = append(lines, inventory.CountLine{Sku: sku})
+				}
+				return inventory.CountSession{ID: 4, State: inventory.CountOpen, Lines: lines}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL, test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("StartCount", test.wantCall, t)
+
+			if test.wantStatusCode == http.StatusCreated {
+				got := inventory.CountSession{}
+				testutil.Unmarshal(res, &got, t)
+
+				if got.ID != 4 || len(got.Lines) != len(test.request.Skus) {
+					t.Errorf("session got=%+v", got)
+				}
+			}
+		})
+	}
+}
+
+func TestCountRecord(t *testing.T) {
+	ts, mockSvc, _ := setupCountTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		url            string
+		request        api.RecordCountRequest
+		getErr         error
+		serviceErr     error
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "counts are recorded",
+			url:            "/4/counts",
+			request:        api.RecordCountRequest{Counts: []inventory.CountEntry{{Sku: "sku1", Quantity: 8}}},
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "counts are required",
+			url:            "/4/counts",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "sku is not in the session",
+			url:            "/4/counts",
+			request:        api.RecordCountRequest{Counts: []inventory.CountEntry{{Sku: "sku9", Quantity: 8}}},
+			serviceErr:     inventory.ErrInvalidCount,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+		{
+			name:           "session not found",
+			url:            "/4/counts",
+			request:        api.RecordCountRequest{Counts: []inventory.CountEntry{{Sku: "sku1", Quantity: 8}}},
+			getErr:         core.ErrNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid id",
+			url:            "/abc/counts",
+			request:        api.RecordCountRequest{Counts: []inventory.CountEntry{{Sku: "sku1", Quantity: 8}}},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.GetCountSessionFunc = func(ctx context.Context, ID uint64) (inventory.CountSession, error) {
+				return inventory.CountSession{ID: ID, State: inventory.CountOpen}, test.getErr
+			}
+			var gotID uint64
+			var gotCounts []inventory.CountEntry
+			mockSvc.RecordCountFunc = func(ctx context.Context, ID uint64, counts []inventory.CountEntry) (inventory.CountSession, error) {
+				gotID, gotCounts = ID, counts
+				return inventory.CountSession{ID: ID, State: inventory.CountOpen}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+test.url, test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("RecordCount", test.wantCall, t)
+
+			if test.wantStatusCode == http.StatusOK {
+				if gotID != 4 {
+					t.Errorf("id got=%d want=%d", gotID, 4)
+				}
+				if !reflect.DeepEqual(gotCounts, test.request.Counts) {
+					t.Errorf("counts\n got=%+v\nwant=%+v", gotCounts, test.request.Counts)
+				}
+			}
+		})
+	}
+}
+
+func TestCountApprove(t *testing.T) {
+	ts, mockSvc, usrSvc := setupCountTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		loginUser      user.User
+		loginErr       error
+		serviceErr     error
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "admin approves the count",
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "non-admin users cannot approve",
+			loginUser:      createUser("someuser", "", false),
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "unknown users cannot approve",
+			loginErr:       core.ErrNotFound,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "count is not ready to approve",
+			loginUser:      createUser("someadmin", "", true),
+			serviceErr:     inventory.ErrInvalidCount,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+				return test.loginUser, test.loginErr
+			}
+			var gotApprover string
+			mockSvc.ApproveCountFunc = func(ctx context.Context, ID uint64, approver string) (inventory.CountSession, error) {
+				gotApprover = approver
+				return inventory.CountSession{ID: ID, State: inventory.CountApproved, ClosedBy: approver}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/4/approve", nil, t, testutil.RequestOptions{Username: "someuser", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("ApproveCount", test.wantCall, t)
+
+			if test.wantStatusCode == http.StatusOK && gotApprover != test.loginUser.Username {
+				t.Errorf("approver got=%s want=%s", gotApprover, test.loginUser.Username)
+			}
+		})
+	}
+}
+
+func TestCountList(t *testing.T) {
+	ts, mockSvc, _ := setupCountTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantState      inventory.CountState
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "all sessions",
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "sessions by state",
+			query:          "?state=Open",
+			wantState:      inventory.CountOpen,
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "invalid state",
+			query:          "?state=Bogus",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			var gotState inventory.CountState
+			mockSvc.GetCountSessionsFunc = func(ctx context.Context, state inventory.CountState, limit, offset int) ([]inventory.CountSession, error) {
+				gotState = state
+				return []inventory.CountSession{{ID: 1, State: inventory.CountOpen}}, nil
+			}
+
+			res, err := http.Get(ts.URL + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("GetCountSessions", test.wantCall, t)
+
+			if gotState != test.wantState {
+				t.Errorf("state got=%s want=%s", gotState, test.wantState)
+			}
+		})
+	}
+}
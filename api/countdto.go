@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/sksmith/go-micro-example/core/inventory"
+)
+
+type StartCountRequest struct {
+	Skus []string `json:"skus"`
+}
+
+func (s *StartCountRequest) Bind(_ *http.Request) error {
+	if len(s.Skus) == 0 {
+		return errors.New("at least one sku is required")
+	}
+	return nil
+}
+
+type RecordCountRequest struct {
+	Counts []inventory.CountEntry `json:"counts"`
+}
+
+func (c *RecordCountRequest) Bind(_ *http.Request) error {
+	if len(c.Counts) == 0 {
+		return errors.New("at least one count is required")
+	}
+	for _, count := range c.Counts {
+		if count.Sku == "" {
+			return errors.New("sku is required")
+		}
+	}
+	return nil
+}
+
+type CountSessionResponse struct {
+	inventory.CountSession
+}
+
+func (c *CountSessionResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewCountSessionListResponse(sessions []inventory.CountSession) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, s := range sessions {
+		list = append(list, &CountSessionResponse{CountSession: s})
+	}
+	return list
+}
+
+type AdjustmentResponse struct {
+	inventory.InventoryAdjustment
+}
+
+func (a *AdjustmentResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewAdjustmentListResponse(adjustments []inventory.InventoryAdjustment) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, a := range adjustments {
+		list = append(list, &AdjustmentResponse{InventoryAdjustment: a})
+	}
+	return list
+}
@@ -38,6 +38,16 @@ func ErrInvalidRequest(err error) *ErrResponse {
 	}
 }
 
+// ErrConflict is rendered when a request is valid but cannot be carried out in the resource's current state.
+func ErrConflict(err error) *ErrResponse {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusConflict,
+		StatusText:     "Conflict with current state.",
+		ErrorText:      err.Error(),
+	}
+}
+
 var ErrNotFound = &ErrResponse{
 	HTTPStatusCode: http.StatusNotFound,
 	StatusText:     "Resource not found.",
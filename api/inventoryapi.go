@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
@@ -13,15 +17,66 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/sksmith/go-micro-example/core"
 	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
 )
 
 type InventoryService interface {
 	Produce(ctx context.Context, product inventory.Product, event inventory.ProductionRequest) error
+	ProduceBatch(ctx context.Context, requests []inventory.SkuProductionRequest) ([]inventory.ProductionResult, error)
 	CreateProduct(ctx context.Context, product inventory.Product) error
+	CreateKit(ctx context.Context, product inventory.Product, components []inventory.KitComponent) error
 
 	GetProduct(ctx context.Context, sku string) (inventory.Product, error)
-	GetAllProductInventory(ctx context.Context, limit, offset int) ([]inventory.ProductInventory, error)
+	GetAllProductInventory(ctx context.Context, options inventory.GetProductInventoryOptions, limit, offset int) ([]inventory.ProductInventory, error)
 	GetProductInventory(ctx context.Context, sku string) (inventory.ProductInventory, error)
+	GetKitComponents(ctx context.Context, sku string) ([]inventory.KitComponent, error)
+
+	UpdateProductAttributes(ctx context.Context, sku string, attributes inventory.Attributes) (inventory.Product, error)
+	DefineAttribute(ctx context.Context, definition inventory.AttributeDefinition) error
+	GetAttributeDefinitions(ctx context.Context) ([]inventory.AttributeDefinition, error)
+
+	AssignProductCategory(ctx context.Context, sku string, categoryID uint64) (inventory.Product, error)
+
+	SetRequiresInspection(ctx context.Context, sku string, requiresInspection bool) (inventory.Product, error)
+	SetApprovalThreshold(ctx context.Context, sku string, threshold int64) (inventory.Product, error)
+	SetReservationSla(ctx context.Context, sku string, hours int64) (inventory.Product, error)
+	SetFreeze(ctx context.Context, sku string, freeze inventory.Freeze, frozen bool, reason, user string) (inventory.Product, error)
+	ReleaseHeld(ctx context.Context, sku string, qty int64, user string) (inventory.ProductInventory, error)
+	RejectHeld(ctx context.Context, sku string, qty int64, reference, user string) (inventory.ProductInventory, error)
+
+	PlanProduction(ctx context.Context, plan inventory.PlannedProduction) (inventory.PlannedProduction, error)
+	GetPlannedProduction(ctx context.Context, sku string) ([]inventory.PlannedProduction, error)
+	CancelPlannedProduction(ctx context.Context, sku string, ID uint64) error
+	GetAvailableToPromise(ctx context.Context, sku string) (inventory.AvailableToPromise, error)
+
+	CreateSubstitute(ctx context.Context, rule inventory.SubstituteRule) (inventory.SubstituteRule, error)
+	GetSubstitutes(ctx context.Context, sku string) ([]inventory.SubstituteRule, error)
+	DeleteSubstitute(ctx context.Context, sku string, ID uint64) error
+
+	GetProductionConsumers(ctx context.Context, requestID string) ([]inventory.Peg, error)
+
+	ImportProducts(ctx context.Context, imports []inventory.ProductImport) ([]inventory.ImportResult, error)
+
+	GetInventoryValuation(ctx context.Context, limit, offset int) (inventory.InventoryValuation, error)
+	GetValuation(ctx context.Context, sku string) (inventory.Valuation, error)
+	GetValuationHistory(ctx context.Context, sku string, limit, offset int) ([]inventory.ValuationEntry, error)
+
+	GetAdjustments(ctx context.Context, sku string, limit, offset int) ([]inventory.InventoryAdjustment, error)
+	GetInventoryTimeSeries(ctx context.Context, options inventory.ReportOptions, interval time.Duration) ([]inventory.InventoryBucket, error)
+
+	Convert(ctx context.Context, cr inventory.ConversionRequest, user string) (inventory.Conversion, error)
+	GetConversions(ctx context.Context, sku string, limit, offset int) ([]inventory.Conversion, error)
+
+	CreateCrossReference(ctx context.Context, xref inventory.CrossReference) (inventory.CrossReference, error)
+	GetCrossReferences(ctx context.Context, sku string) ([]inventory.CrossReference, error)
+	DeleteCrossReference(ctx context.Context, sku string, ID uint64) error
+	ResolveIdentifier(ctx context.Context, identifier, requester string) (inventory.CrossReference, error)
+
+	CreateStyle(ctx context.Context, style inventory.Style) (inventory.Style, error)
+	GetStyles(ctx context.Context, limit, offset int) ([]inventory.Style, error)
+	GetStyleInventory(ctx context.Context, code string) (inventory.StyleInventory, error)
+	GetAllStyleInventory(ctx context.Context, options inventory.GetProductInventoryOptions, limit, offset int) ([]inventory.StyleInventory, error)
+	AssignVariant(ctx context.Context, sku, code string, variant inventory.Variant) (inventory.Product, error)
 
 	SubscribeInventory(ch chan<- inventory.ProductInventory) (id inventory.InventorySubID)
 	UnsubscribeInventory(id inventory.InventorySubID)
@@ -29,14 +84,17 @@ type InventoryService interface {
 
 type InventoryApi struct {
 	service InventoryService
+	access  UserAccess
 }
 
-func NewInventoryApi(service InventoryService) *InventoryApi {
-	return &InventoryApi{service: service}
+func NewInventoryApi(service InventoryService, access UserAccess) *InventoryApi {
+	return &InventoryApi{service: service, access: access}
 }
 
 const (
 	CtxKeyProduct CtxKey = "product"
+
+	attributeParamPrefix = "attr."
 )
 
 func (a *InventoryApi) ConfigureRouter(r chi.Router) {
@@ -45,11 +103,48 @@ func (a *InventoryApi) ConfigureRouter(r chi.Router) {
 	r.Route("/", func(r chi.Router) {
 		r.With(Paginate).Get("/", a.List)
 		r.Put("/", a.CreateProduct)
+		r.With(Paginate).Get("/valuation", a.GetInventoryValuation)
+		r.Get("/attributes", a.GetAttributeDefinitions)
+		r.Put("/attributes", a.DefineAttribute)
+		r.Put("/productionEvents", a.CreateProductionEvents)
+		r.Post("/import", a.Import)
+		r.Get("/export", a.Export)
+		r.Get("/productionEvent/{requestId}/consumers", a.GetProductionConsumers)
+		r.Get("/lookup", a.Lookup)
+		r.With(Paginate).Get("/styles", a.GetStyles)
+		r.Put("/styles", a.CreateStyle)
+		r.Get("/styles/{code}", a.GetStyleInventory)
 
 		r.Route("/{sku}", func(r chi.Router) {
 			r.Use(a.ProductCtx)
 			r.Put("/productionEvent", a.CreateProductionEvent)
 			r.Get("/", a.GetProductInventory)
+			r.Get("/components", a.GetKitComponents)
+			r.Put("/attributes", a.UpdateProductAttributes)
+			r.Put("/category", a.AssignCategory)
+			r.Put("/variant", a.AssignVariant)
+			r.Put("/inspection", a.SetInspection)
+			r.With(Authenticate(a.access), AdminOnly).Put("/approvalThreshold", a.SetApprovalThreshold)
+			r.With(Authenticate(a.access), AdminOnly).Put("/reservationSla", a.SetReservationSla)
+			r.With(Authenticate(a.access), AdminOnly).Put("/freeze", a.SetFreeze)
+			r.With(Authenticate(a.access)).Put("/hold/release", a.ReleaseHeld)
+			r.With(Authenticate(a.access)).Put("/hold/reject", a.RejectHeld)
+			r.Get("/valuation", a.GetValuation)
+			r.Get("/atp", a.GetAvailableToPromise)
+			r.Get("/plannedProduction", a.GetPlannedProduction)
+			r.Put("/plannedProduction", a.PlanProduction)
+			r.Delete("/plannedProduction/{ID}", a.CancelPlannedProduction)
+			r.Get("/substitutes", a.GetSubstitutes)
+			r.Put("/substitutes", a.CreateSubstitute)
+			r.Delete("/substitutes/{ID}", a.DeleteSubstitute)
+			r.Get("/crossReferences", a.GetCrossReferences)
+			r.Put("/crossReferences", a.CreateCrossReference)
+			r.Delete("/crossReferences/{ID}", a.DeleteCrossReference)
+			r.With(Paginate).Get("/valuation/history", a.GetValuationHistory)
+			r.With(Paginate).Get("/adjustments", a.GetAdjustments)
+			r.Get("/timeseries", a.GetTimeSeries)
+			r.With(Paginate).Get("/conversions", a.GetConversions)
+			r.With(Authenticate(a.access)).Put("/conversions", a.Convert)
 		})
 	})
 }
@@ -100,14 +195,38 @@ func (a *InventoryApi) List(w http.ResponseWriter, r *http.Request) {
 	limit := r.Context().Value(CtxKeyLimit).(int)
 	offset := r.Context().Value(CtxKeyOffset).(int)
 
-	products, err := a.service.GetAllProductInventory(r.Context(), limit, offset)
-	if err != nil {
+	options := inventory.GetProductInventoryOptions{
+		Attributes: attributeFilter(r),
+		Style:      r.URL.Query().Get("style"),
+	}
+
+	switch groupBy := r.URL.Query().Get("groupBy"); groupBy {
+	case "":
+		products, err := a.service.GetAllProductInventory(r.Context(), options, limit, offset)
+		if err != nil {
+			renderListErr(w, r, err)
+			return
+		}
+		RenderList(w, r, NewProductListResponse(products))
+	case "style":
+		styles, err := a.service.GetAllStyleInventory(r.Context(), options, limit, offset)
+		if err != nil {
+			renderListErr(w, r, err)
+			return
+		}
+		RenderList(w, r, NewStyleInventoryListResponse(styles))
+	default:
+		Render(w, r, ErrInvalidRequest(fmt.Errorf("cannot group by %s", groupBy)))
+	}
+}
+
+func renderListErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, inventory.ErrInvalidAttribute) {
+		Render(w, r, ErrInvalidRequest(err))
+	} else {
 		log.Err(err).Send()
 		Render(w, r, ErrInternalServer)
-		return
 	}
-
-	RenderList(w, r, NewProductListResponse(products))
 }
 
 func (a *InventoryApi) CreateProduct(w http.ResponseWriter, r *http.Request) {
@@ -117,9 +236,20 @@ func (a *InventoryApi) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := a.service.CreateProduct(r.Context(), data.Product); err != nil {
-		log.Err(err).Send()
-		Render(w, r, ErrInternalServer)
+	var err error
+	if data.Type == inventory.Kit {
+		err = a.service.CreateKit(r.Context(), data.Product, data.Components)
+	} else {
+		err = a.service.CreateProduct(r.Context(), data.Product)
+	}
+	if err != nil {
+		if errors.Is(err, inventory.ErrInvalidAttribute) || errors.Is(err, inventory.ErrInvalidCategory) ||
+			errors.Is(err, inventory.ErrInvalidStyle) {
+			Render(w, r, ErrInvalidRequest(err))
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
 		return
 	}
 
@@ -127,6 +257,22 @@ func (a *InventoryApi) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	Render(w, r, NewProductResponse(inventory.ProductInventory{Product: data.Product}))
 }
 
+// attributeFilter collects attribute filters given as query parameters in the form attr.<name>=<value>.
+func attributeFilter(r *http.Request) inventory.Attributes {
+	filter := inventory.Attributes{}
+	for key, values := range r.URL.Query() {
+		if !strings.HasPrefix(key, attributeParamPrefix) || len(values) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(key, attributeParamPrefix)
+		if name == "" {
+			continue
+		}
+		filter[name] = values[0]
+	}
+	return filter
+}
+
 func (a *InventoryApi) ProductCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var product inventory.Product
@@ -164,9 +310,18 @@ func (a *InventoryApi) CreateProductionEvent(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if product.Type == inventory.Kit {
+		Render(w, r, ErrInvalidRequest(errors.New("kits cannot be produced")))
+		return
+	}
+
 	if err := a.service.Produce(r.Context(), product, *data.ProductionRequest); err != nil {
-		log.Err(err).Send()
-		Render(w, r, ErrInternalServer)
+		if errors.Is(err, inventory.ErrFrozen) {
+			Render(w, r, ErrConflict(err))
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
 		return
 	}
 
@@ -174,6 +329,25 @@ func (a *InventoryApi) CreateProductionEvent(w http.ResponseWriter, r *http.Requ
 	Render(w, r, &ProductionEventResponse{})
 }
 
+// CreateProductionEvents records a batch of production events across any number of products. Every event is reported
+// on individually, events that fail do not prevent the others from being recorded.
+func (a *InventoryApi) CreateProductionEvents(w http.ResponseWriter, r *http.Request) {
+	data := &CreateProductionEventsRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	results, err := a.service.ProduceBatch(r.Context(), data.Events)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	Render(w, r, NewProductionBatchResponse(results))
+}
+
 func (a *InventoryApi) GetProductInventory(w http.ResponseWriter, r *http.Request) {
 	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
 
@@ -193,3 +367,693 @@ func (a *InventoryApi) GetProductInventory(w http.ResponseWriter, r *http.Reques
 	render.Status(r, http.StatusOK)
 	Render(w, r, resp)
 }
+
+func (a *InventoryApi) GetKitComponents(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	if product.Type != inventory.Kit {
+		Render(w, r, ErrInvalidRequest(errors.New("product is not a kit")))
+		return
+	}
+
+	components, err := a.service.GetKitComponents(r.Context(), product.Sku)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewKitComponentListResponse(components))
+}
+
+func (a *InventoryApi) UpdateProductAttributes(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	data := &UpdateAttributesRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	product, err := a.service.UpdateProductAttributes(r.Context(), product.Sku, data.Attributes)
+	if err != nil {
+		if errors.Is(err, inventory.ErrInvalidAttribute) {
+			Render(w, r, ErrInvalidRequest(err))
+		} else if errors.Is(err, core.ErrNotFound) {
+			Render(w, r, ErrNotFound)
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &ProductDetailResponse{Product: product})
+}
+
+func (a *InventoryApi) AssignCategory(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	data := &AssignCategoryRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	product, err := a.service.AssignProductCategory(r.Context(), product.Sku, *data.CategoryID)
+	if err != nil {
+		renderCategoryErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &ProductDetailResponse{Product: product})
+}
+
+func (a *InventoryApi) DefineAttribute(w http.ResponseWriter, r *http.Request) {
+	data := &AttributeDefinitionRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := a.service.DefineAttribute(r.Context(), data.AttributeDefinition); err != nil {
+		if errors.Is(err, inventory.ErrInvalidAttribute) {
+			Render(w, r, ErrInvalidRequest(err))
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &AttributeDefinitionResponse{AttributeDefinition: data.AttributeDefinition})
+}
+
+func (a *InventoryApi) GetAttributeDefinitions(w http.ResponseWriter, r *http.Request) {
+	definitions, err := a.service.GetAttributeDefinitions(r.Context())
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewAttributeDefinitionListResponse(definitions))
+}
+
+func (a *InventoryApi) GetInventoryValuation(w http.ResponseWriter, r *http.Request) {
+	limit := r.Context().Value(CtxKeyLimit).(int)
+	offset := r.Context().Value(CtxKeyOffset).(int)
+
+	valuation, err := a.service.GetInventoryValuation(r.Context(), limit, offset)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &InventoryValuationResponse{InventoryValuation: valuation})
+}
+
+func (a *InventoryApi) GetValuation(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	valuation, err := a.service.GetValuation(r.Context(), product.Sku)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &ValuationResponse{Valuation: valuation})
+}
+
+func (a *InventoryApi) GetValuationHistory(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+	limit := r.Context().Value(CtxKeyLimit).(int)
+	offset := r.Context().Value(CtxKeyOffset).(int)
+
+	entries, err := a.service.GetValuationHistory(r.Context(), product.Sku, limit, offset)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewValuationHistoryResponse(entries))
+}
+
+func (a *InventoryApi) SetInspection(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	data := &InspectionRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	product, err := a.service.SetRequiresInspection(r.Context(), product.Sku, *data.RequiresInspection)
+	if err != nil {
+		renderHoldErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, NewProductResponse(inventory.ProductInventory{Product: product}))
+}
+
+func (a *InventoryApi) SetApprovalThreshold(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	data := &ApprovalThresholdRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	product, err := a.service.SetApprovalThreshold(r.Context(), product.Sku, *data.ApprovalThreshold)
+	if err != nil {
+		renderApprovalErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, NewProductResponse(inventory.ProductInventory{Product: product}))
+}
+
+func (a *InventoryApi) SetReservationSla(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	data := &ReservationSlaRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	product, err := a.service.SetReservationSla(r.Context(), product.Sku, *data.ReservationSlaHours)
+	if err != nil {
+		if errors.Is(err, inventory.ErrInvalidSla) {
+			Render(w, r, ErrInvalidRequest(err))
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, NewProductResponse(inventory.ProductInventory{Product: product}))
+}
+
+func (a *InventoryApi) SetFreeze(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	data := &FreezeRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	product, err := a.service.SetFreeze(r.Context(), product.Sku, data.Freeze, *data.Frozen, data.Reason, usr.Username)
+	if err != nil {
+		if errors.Is(err, inventory.ErrInvalidFreeze) {
+			Render(w, r, ErrInvalidRequest(err))
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, NewProductResponse(inventory.ProductInventory{Product: product}))
+}
+
+func (a *InventoryApi) ReleaseHeld(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	data := &HoldRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	pi, err := a.service.ReleaseHeld(r.Context(), product.Sku, data.Quantity, usr.Username)
+	if err != nil {
+		renderHoldErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, NewProductResponse(pi))
+}
+
+func (a *InventoryApi) RejectHeld(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	data := &HoldRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	pi, err := a.service.RejectHeld(r.Context(), product.Sku, data.Quantity, data.Reference, usr.Username)
+	if err != nil {
+		renderHoldErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, NewProductResponse(pi))
+}
+
+func renderHoldErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, inventory.ErrInvalidHold) {
+		Render(w, r, ErrInvalidRequest(err))
+	} else if errors.Is(err, core.ErrNotFound) {
+		Render(w, r, ErrNotFound)
+	} else {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+	}
+}
+
+const defaultTimeSeriesInterval = time.Hour
+
+// GetTimeSeries buckets the product's available inventory over the from and to query parameters by the interval
+// parameter, a duration such as 15m or 1h.
+func (a *InventoryApi) GetTimeSeries(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	options, err := reportOptions(r)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	options.Sku = product.Sku
+
+	interval := defaultTimeSeriesInterval
+	if v := r.URL.Query().Get("interval"); v != "" {
+		if interval, err = time.ParseDuration(v); err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("interval must be a duration such as 1h")))
+			return
+		}
+	}
+
+	buckets, err := a.service.GetInventoryTimeSeries(r.Context(), options, interval)
+	if err != nil {
+		renderReportErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewInventoryBucketListResponse(buckets))
+}
+
+func (a *InventoryApi) GetAdjustments(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+	limit := r.Context().Value(CtxKeyLimit).(int)
+	offset := r.Context().Value(CtxKeyOffset).(int)
+
+	adjustments, err := a.service.GetAdjustments(r.Context(), product.Sku, limit, offset)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewAdjustmentListResponse(adjustments))
+}
+
+// Convert reworks or relabels stock of the product into the target SKU given in the request.
+func (a *InventoryApi) Convert(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	data := &ConversionRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	data.SourceSku = product.Sku
+
+	conversion, err := a.service.Convert(r.Context(), *data.ConversionRequest, usr.Username)
+	if err != nil {
+		if errors.Is(err, inventory.ErrInvalidConversion) {
+			Render(w, r, ErrInvalidRequest(err))
+		} else if errors.Is(err, inventory.ErrFrozen) {
+			Render(w, r, ErrConflict(err))
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &ConversionResponse{Conversion: conversion})
+}
+
+func (a *InventoryApi) GetConversions(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+	limit := r.Context().Value(CtxKeyLimit).(int)
+	offset := r.Context().Value(CtxKeyOffset).(int)
+
+	conversions, err := a.service.GetConversions(r.Context(), product.Sku, limit, offset)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewConversionListResponse(conversions))
+}
+
+func (a *InventoryApi) GetAvailableToPromise(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	atp, err := a.service.GetAvailableToPromise(r.Context(), product.Sku)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	Render(w, r, &AvailableToPromiseResponse{AvailableToPromise: atp})
+}
+
+func (a *InventoryApi) GetPlannedProduction(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	plans, err := a.service.GetPlannedProduction(r.Context(), product.Sku)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	RenderList(w, r, NewPlannedProductionListResponse(plans))
+}
+
+func (a *InventoryApi) PlanProduction(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	data := &PlannedProductionRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	plan := inventory.PlannedProduction{Sku: product.Sku, Quantity: data.Quantity, Due: data.Due, Reference: data.Reference}
+	plan, err := a.service.PlanProduction(r.Context(), plan)
+	if err != nil {
+		if errors.Is(err, inventory.ErrInvalidPlan) {
+			Render(w, r, ErrInvalidRequest(err))
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &PlannedProductionResponse{PlannedProduction: plan})
+}
+
+func (a *InventoryApi) CancelPlannedProduction(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	ID, err := strconv.ParseUint(chi.URLParam(r, "ID"), 10, 64)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(errors.New("invalid planned production id")))
+		return
+	}
+
+	if err = a.service.CancelPlannedProduction(r.Context(), product.Sku, ID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			Render(w, r, ErrNotFound)
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *InventoryApi) GetProductionConsumers(w http.ResponseWriter, r *http.Request) {
+	requestID := chi.URLParam(r, "requestId")
+
+	pegs, err := a.service.GetProductionConsumers(r.Context(), requestID)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			Render(w, r, ErrNotFound)
+		} else {
+			log.Err(err).Str("requestId", requestID).Msg("failed to get production consumers")
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	RenderList(w, r, NewPegListResponse(pegs))
+}
+
+func (a *InventoryApi) GetSubstitutes(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	rules, err := a.service.GetSubstitutes(r.Context(), product.Sku)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	RenderList(w, r, NewSubstituteListResponse(rules))
+}
+
+func (a *InventoryApi) CreateSubstitute(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	data := &SubstituteRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	rule := inventory.SubstituteRule{
+		Sku:                product.Sku,
+		SubstituteSku:      data.SubstituteSku,
+		Quantity:           data.Quantity,
+		SubstituteQuantity: data.SubstituteQuantity,
+		TwoWay:             data.TwoWay,
+	}
+	rule, err := a.service.CreateSubstitute(r.Context(), rule)
+	if err != nil {
+		if errors.Is(err, inventory.ErrInvalidSubstitute) {
+			Render(w, r, ErrInvalidRequest(err))
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &SubstituteResponse{SubstituteRule: rule})
+}
+
+func (a *InventoryApi) DeleteSubstitute(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	ID, err := strconv.ParseUint(chi.URLParam(r, "ID"), 10, 64)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(errors.New("invalid substitute id")))
+		return
+	}
+
+	if err = a.service.DeleteSubstitute(r.Context(), product.Sku, ID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			Render(w, r, ErrNotFound)
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *InventoryApi) GetCrossReferences(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	xrefs, err := a.service.GetCrossReferences(r.Context(), product.Sku)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	RenderList(w, r, NewCrossReferenceListResponse(xrefs))
+}
+
+func (a *InventoryApi) CreateCrossReference(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	data := &CrossReferenceRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	xref := inventory.CrossReference{
+		Sku:        product.Sku,
+		Identifier: data.Identifier,
+		Type:       data.Type,
+		Requester:  data.Requester,
+	}
+	xref, err := a.service.CreateCrossReference(r.Context(), xref)
+	if err != nil {
+		if errors.Is(err, inventory.ErrInvalidCrossReference) {
+			Render(w, r, ErrInvalidRequest(err))
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &CrossReferenceResponse{CrossReference: xref})
+}
+
+func (a *InventoryApi) DeleteCrossReference(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	ID, err := strconv.ParseUint(chi.URLParam(r, "ID"), 10, 64)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(errors.New("invalid cross reference id")))
+		return
+	}
+
+	if err = a.service.DeleteCrossReference(r.Context(), product.Sku, ID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			Render(w, r, ErrNotFound)
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Lookup resolves a SKU, UPC or cross referenced identifier to the SKU it refers to. Cross references scoped to the
+// requester query parameter take precedence.
+func (a *InventoryApi) Lookup(w http.ResponseWriter, r *http.Request) {
+	identifier := r.URL.Query().Get("identifier")
+	if identifier == "" {
+		Render(w, r, ErrInvalidRequest(errors.New("identifier is required")))
+		return
+	}
+
+	xref, err := a.service.ResolveIdentifier(r.Context(), identifier, r.URL.Query().Get("requester"))
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			Render(w, r, ErrNotFound)
+		} else {
+			log.Err(err).Send()
+			Render(w, r, ErrInternalServer)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &CrossReferenceResponse{CrossReference: xref})
+}
+
+func (a *InventoryApi) GetStyles(w http.ResponseWriter, r *http.Request) {
+	limit := r.Context().Value(CtxKeyLimit).(int)
+	offset := r.Context().Value(CtxKeyOffset).(int)
+
+	styles, err := a.service.GetStyles(r.Context(), limit, offset)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	RenderList(w, r, NewStyleListResponse(styles))
+}
+
+func (a *InventoryApi) CreateStyle(w http.ResponseWriter, r *http.Request) {
+	data := &StyleRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	style, err := a.service.CreateStyle(r.Context(), data.Style)
+	if err != nil {
+		renderStyleErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &StyleResponse{Style: style})
+}
+
+func (a *InventoryApi) GetStyleInventory(w http.ResponseWriter, r *http.Request) {
+	style, err := a.service.GetStyleInventory(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		renderStyleErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &StyleInventoryResponse{StyleInventory: style})
+}
+
+func (a *InventoryApi) AssignVariant(w http.ResponseWriter, r *http.Request) {
+	product := r.Context().Value(CtxKeyProduct).(inventory.Product)
+
+	data := &AssignVariantRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	product, err := a.service.AssignVariant(r.Context(), product.Sku, data.Style, data.Variant)
+	if err != nil {
+		renderStyleErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &ProductDetailResponse{Product: product})
+}
+
+func renderStyleErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, inventory.ErrInvalidStyle) {
+		Render(w, r, ErrInvalidRequest(err))
+	} else if errors.Is(err, core.ErrNotFound) {
+		Render(w, r, ErrNotFound)
+	} else {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+	}
+}
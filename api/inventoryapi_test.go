@@ -9,11 +9,13 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gobwas/ws"
 	"github.com/sksmith/go-micro-example/api"
 	"github.com/sksmith/go-micro-example/core"
 	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
 	"github.com/sksmith/go-micro-example/testutil"
 
 	"github.com/go-chi/chi"
@@ -43,7 +45,7 @@ func TestInventorySubscribe(t *testing.T) {
 		unsubscribeCalled = true
 	}
 
-	invApi := api.NewInventoryApi(mockSvc)
+	invApi := api.NewInventoryApi(mockSvc, user.NewMockUserService())
 	r := chi.NewRouter()
 	invApi.ConfigureRouter(r)
 	ts := httptest.NewServer(r)
@@ -77,7 +79,7 @@ func TestInventorySubscribe(t *testing.T) {
 
 func setupInventoryTestServer() (*httptest.Server, *inventory.MockInventoryService) {
 	mockSvc := inventory.NewMockInventoryService()
-	invApi := api.NewInventoryApi(mockSvc)
+	invApi := api.NewInventoryApi(mockSvc, user.NewMockUserService())
 	r := chi.NewRouter()
 	invApi.ConfigureRouter(r)
 	ts := httptest.NewServer(r)
@@ -149,7 +151,7 @@ func TestInventoryList(t *testing.T) {
 	for _, test := range tests {
 		gotLimit := -1
 		gotOffset := -1
-		mockInvSvc.GetAllProductInventoryFunc = func(ctx context.Context, limit int, offset int) ([]inventory.ProductInventory, error) {
+		mockInvSvc.GetAllProductInventoryFunc = func(ctx context.Context, options inventory.GetProductInventoryOptions, limit int, offset int) ([]inventory.ProductInventory, error) {
 			gotLimit = limit
 			gotOffset = offset
 			return test.inventory, test.serviceErr
@@ -209,7 +211,7 @@ func TestInventoryCreateProduct(t *testing.T) {
 		{
 			request:             createProductRequest("name1", "sku1", "upc1"),
 			serviceErr:          nil,
-			wantProductResponse: createProductResponse("name1", "sku1", "upc1", 0),
+			wantProductResponse: createTypedProductResponse("name1", "sku1", "upc1", inventory.Standard),
 			wantErr:             nil,
 			wantStatusCode:      http.StatusCreated,
 		},
@@ -474,29 +476,1507 @@ func TestInventoryGetProductInventory(t *testing.T) {
 	}
 }
 
+func TestInventoryGetValuation(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name             string
+		url              string
+		getValuationFunc func(ctx context.Context, sku string) (inventory.Valuation, error)
+		getInventoryFunc func(ctx context.Context, limit, offset int) (inventory.InventoryValuation, error)
+		wantBody         interface{}
+		wantStatusCode   int
+	}{
+		{
+			name: "product valuation",
+			url:  "/test1sku/valuation",
+			getValuationFunc: func(ctx context.Context, sku string) (inventory.Valuation, error) {
+				return inventory.Valuation{Sku: sku, Quantity: 4, UnitCost: 2.5, Value: 10}, nil
+			},
+			wantBody:       &api.ValuationResponse{Valuation: inventory.Valuation{Sku: "test1sku", Quantity: 4, UnitCost: 2.5, Value: 10}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "unexpected error getting product valuation",
+			url:  "/test1sku/valuation",
+			getValuationFunc: func(ctx context.Context, sku string) (inventory.Valuation, error) {
+				return inventory.Valuation{}, errors.New("some unexpected error")
+			},
+			wantBody:       &api.ErrResponse{StatusText: api.ErrInternalServer.StatusText, ErrorText: api.ErrInternalServer.ErrorText},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "inventory valuation",
+			url:  "/valuation",
+			getInventoryFunc: func(ctx context.Context, limit, offset int) (inventory.InventoryValuation, error) {
+				return inventory.InventoryValuation{Method: inventory.FIFO, Total: 10,
+					Products: []inventory.Valuation{{Sku: "test1sku", Quantity: 4, UnitCost: 2.5, Value: 10}}}, nil
+			},
+			wantBody: &api.InventoryValuationResponse{InventoryValuation: inventory.InventoryValuation{Method: inventory.FIFO, Total: 10,
+				Products: []inventory.Valuation{{Sku: "test1sku", Quantity: 4, UnitCost: 2.5, Value: 10}}}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "unexpected error getting inventory valuation",
+			url:  "/valuation",
+			getInventoryFunc: func(ctx context.Context, limit, offset int) (inventory.InventoryValuation, error) {
+				return inventory.InventoryValuation{}, errors.New("some unexpected error")
+			},
+			wantBody:       &api.ErrResponse{StatusText: api.ErrInternalServer.StatusText, ErrorText: api.ErrInternalServer.ErrorText},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return getTestProductInventory()[0].Product, nil
+			}
+			mockInvSvc.GetValuationFunc = test.getValuationFunc
+			mockInvSvc.GetInventoryValuationFunc = test.getInventoryFunc
+
+			res, err := http.Get(ts.URL + test.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+
+			got := reflect.New(reflect.TypeOf(test.wantBody).Elem()).Interface()
+			testutil.Unmarshal(res, got, t)
+
+			if !reflect.DeepEqual(got, test.wantBody) {
+				t.Errorf("body\n got=%+v\nwant=%+v", got, test.wantBody)
+			}
+		})
+	}
+}
+
+func TestInventoryCreateKit(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	components := []inventory.KitComponent{{Sku: "a", Quantity: 2}, {Sku: "b", Quantity: 1}}
+
+	tests := []struct {
+		name           string
+		request        api.CreateProductRequest
+		serviceErr     error
+		wantErr        *api.ErrResponse
+		wantStatusCode int
+		wantCreateKit  int
+	}{
+		{
+			name:           "kit is created with its components",
+			request:        createKitRequest("kit1", components),
+			wantStatusCode: http.StatusCreated,
+			wantCreateKit:  1,
+		},
+		{
+			name:           "unexpected error creating kit",
+			request:        createKitRequest("kit1", components),
+			serviceErr:     errors.New("some unexpected error"),
+			wantErr:        api.ErrInternalServer,
+			wantStatusCode: http.StatusInternalServerError,
+			wantCreateKit:  1,
+		},
+		{
+			name:           "kit without components",
+			request:        createKitRequest("kit1", nil),
+			wantErr:        api.ErrInvalidRequest(errors.New("kits require at least one component")),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "component without quantity",
+			request:        createKitRequest("kit1", []inventory.KitComponent{{Sku: "a"}}),
+			wantErr:        api.ErrInvalidRequest(errors.New("components require a sku and a quantity greater than zero")),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "standard product with components",
+			request: api.CreateProductRequest{
+				Product:    inventory.Product{Name: "name1", Sku: "sku1", Upc: "upc1"},
+				Components: components,
+			},
+			wantErr:        api.ErrInvalidRequest(errors.New("only kits may have components")),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "unknown product type",
+			request: api.CreateProductRequest{
+				Product: inventory.Product{Name: "name1", Sku: "sku1", Upc: "upc1", Type: "Bundle"},
+			},
+			wantErr:        api.ErrInvalidRequest(errors.New("invalid product type")),
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.CallWatcher = testutil.NewCallWatcher()
+			mockInvSvc.CreateKitFunc = func(ctx context.Context, product inventory.Product, components []inventory.KitComponent) error {
+				return test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL, test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+
+			if test.wantErr == nil {
+				got := api.ProductResponse{}
+				testutil.Unmarshal(res, &got, t)
+
+				if got.Type != inventory.Kit {
+					t.Errorf("product type got=%s want=%s", got.Type, inventory.Kit)
+				}
+			} else {
+				got := &api.ErrResponse{}
+				testutil.Unmarshal(res, got, t)
+
+				if got.ErrorText != test.wantErr.ErrorText {
+					t.Errorf("error text got=%s want=%s", got.ErrorText, test.wantErr.ErrorText)
+				}
+			}
+
+			mockInvSvc.VerifyCount("CreateKit", test.wantCreateKit, t)
+			mockInvSvc.VerifyCount("CreateProduct", 0, t)
+		})
+	}
+}
+
+func TestInventoryGetKitComponents(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	components := []inventory.KitComponent{{Sku: "a", Quantity: 2}, {Sku: "b", Quantity: 1}}
+
+	tests := []struct {
+		name                 string
+		product              inventory.Product
+		getKitComponentsFunc func(ctx context.Context, sku string) ([]inventory.KitComponent, error)
+		wantComponents       []inventory.KitComponent
+		wantStatusCode       int
+	}{
+		{
+			name:    "kit components",
+			product: inventory.Product{Sku: "kit1", Type: inventory.Kit},
+			getKitComponentsFunc: func(ctx context.Context, sku string) ([]inventory.KitComponent, error) {
+				return components, nil
+			},
+			wantComponents: components,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "product is not a kit",
+			product:        inventory.Product{Sku: "sku1", Type: inventory.Standard},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "unexpected error getting components",
+			product: inventory.Product{Sku: "kit1", Type: inventory.Kit},
+			getKitComponentsFunc: func(ctx context.Context, sku string) ([]inventory.KitComponent, error) {
+				return nil, errors.New("some unexpected error")
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return test.product, nil
+			}
+			mockInvSvc.GetKitComponentsFunc = test.getKitComponentsFunc
+
+			res, err := http.Get(ts.URL + "/" + test.product.Sku + "/components")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+
+			if test.wantComponents != nil {
+				got := []inventory.KitComponent{}
+				testutil.Unmarshal(res, &got, t)
+
+				if !reflect.DeepEqual(got, test.wantComponents) {
+					t.Errorf("components\n got=%+v\nwant=%+v", got, test.wantComponents)
+				}
+			}
+		})
+	}
+}
+
+func TestInventoryListAttributeFilter(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		query          string
+		serviceErr     error
+		wantAttributes inventory.Attributes
+		wantStatusCode int
+	}{
+		{
+			name:           "attribute filters are passed to the service",
+			query:          "?attr.color=red&attr.weight=2&sku=ignored",
+			wantAttributes: inventory.Attributes{"color": "red", "weight": "2"},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "no attribute filters",
+			wantAttributes: inventory.Attributes{},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid attribute filter",
+			query:          "?attr.weight=heavy",
+			serviceErr:     inventory.ErrInvalidAttribute,
+			wantAttributes: inventory.Attributes{"weight": "heavy"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var gotAttributes inventory.Attributes
+			mockInvSvc.GetAllProductInventoryFunc = func(ctx context.Context, options inventory.GetProductInventoryOptions, limit int, offset int) ([]inventory.ProductInventory, error) {
+				gotAttributes = options.Attributes
+				return []inventory.ProductInventory{}, test.serviceErr
+			}
+
+			res, err := http.Get(ts.URL + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			if !reflect.DeepEqual(gotAttributes, test.wantAttributes) {
+				t.Errorf("attributes\n got=%+v\nwant=%+v", gotAttributes, test.wantAttributes)
+			}
+		})
+	}
+}
+
+func TestInventoryUpdateProductAttributes(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		request        interface{}
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "attributes are updated",
+			request:        api.UpdateAttributesRequest{Attributes: inventory.Attributes{"color": "red"}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "attributes are required",
+			request:        api.UpdateAttributesRequest{},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "attribute does not match its definition",
+			request:        api.UpdateAttributesRequest{Attributes: inventory.Attributes{"weight": "heavy"}},
+			serviceErr:     inventory.ErrInvalidAttribute,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "unexpected error",
+			request:        api.UpdateAttributesRequest{Attributes: inventory.Attributes{"color": "red"}},
+			serviceErr:     errors.New("some unexpected error"),
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return getTestProductInventory()[0].Product, nil
+			}
+			mockInvSvc.UpdateProductAttributesFunc = func(ctx context.Context, sku string, attributes inventory.Attributes) (inventory.Product, error) {
+				return inventory.Product{Sku: sku, Attributes: attributes}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/test1sku/attributes", test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+
+			if test.wantStatusCode == http.StatusOK {
+				got := api.ProductDetailResponse{}
+				testutil.Unmarshal(res, &got, t)
+
+				want := test.request.(api.UpdateAttributesRequest).Attributes
+				if !reflect.DeepEqual(got.Attributes, want) {
+					t.Errorf("attributes\n got=%+v\nwant=%+v", got.Attributes, want)
+				}
+			}
+		})
+	}
+}
+
+func TestInventoryDefineAttribute(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		request        api.AttributeDefinitionRequest
+		wantStatusCode int
+		wantDefine     int
+	}{
+		{
+			name:           "attribute is defined",
+			request:        api.AttributeDefinitionRequest{AttributeDefinition: inventory.AttributeDefinition{Name: "weight", Type: inventory.AttributeNumber}},
+			wantStatusCode: http.StatusCreated,
+			wantDefine:     1,
+		},
+		{
+			name:           "name is required",
+			request:        api.AttributeDefinitionRequest{AttributeDefinition: inventory.AttributeDefinition{Type: inventory.AttributeNumber}},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "type must be known",
+			request:        api.AttributeDefinitionRequest{AttributeDefinition: inventory.AttributeDefinition{Name: "weight", Type: "date"}},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.CallWatcher = testutil.NewCallWatcher()
+
+			res := testutil.Put(ts.URL+"/attributes", test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockInvSvc.VerifyCount("DefineAttribute", test.wantDefine, t)
+		})
+	}
+}
+
+func TestInventoryCreateProductionEvents(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	events := []inventory.SkuProductionRequest{
+		{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "req1", Quantity: 1}},
+		{Sku: "sku2", ProductionRequest: inventory.ProductionRequest{RequestID: "req2", Quantity: 2}},
+	}
+
+	tests := []struct {
+		name       string
+		request    api.CreateProductionEventsRequest
+		batchFunc  func(ctx context.Context, requests []inventory.SkuProductionRequest) ([]inventory.ProductionResult, error)
+		wantResp   *api.ProductionBatchResponse
+		wantStatus int
+		wantBatch  int
+	}{
+		{
+			name:    "every event is reported on",
+			request: api.CreateProductionEventsRequest{Events: events},
+			batchFunc: func(ctx context.Context, requests []inventory.SkuProductionRequest) ([]inventory.ProductionResult, error) {
+				return []inventory.ProductionResult{
+					{Sku: "sku1", RequestID: "req1", Status: inventory.ProductionProduced},
+					{Sku: "sku2", RequestID: "req2", Status: inventory.ProductionFailed, Error: "product sku2 does not exist"},
+				}, nil
+			},
+			wantResp: &api.ProductionBatchResponse{
+				Produced: 1,
+				Failed:   1,
+				Results: []inventory.ProductionResult{
+					{Sku: "sku1", RequestID: "req1", Status: inventory.ProductionProduced},
+					{Sku: "sku2", RequestID: "req2", Status: inventory.ProductionFailed, Error: "product sku2 does not exist"},
+				},
+			},
+			wantStatus: http.StatusOK,
+			wantBatch:  1,
+		},
+		{
+			name:       "events are required",
+			request:    api.CreateProductionEventsRequest{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "unexpected error",
+			request: api.CreateProductionEventsRequest{Events: events},
+			batchFunc: func(ctx context.Context, requests []inventory.SkuProductionRequest) ([]inventory.ProductionResult, error) {
+				return nil, errors.New("some unexpected error")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBatch:  1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.CallWatcher = testutil.NewCallWatcher()
+			mockInvSvc.ProduceBatchFunc = test.batchFunc
+
+			res := testutil.Put(ts.URL+"/productionEvents", test.request, t)
+
+			if res.StatusCode != test.wantStatus {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatus)
+			}
+			mockInvSvc.VerifyCount("ProduceBatch", test.wantBatch, t)
+
+			if test.wantResp != nil {
+				got := &api.ProductionBatchResponse{}
+				testutil.Unmarshal(res, got, t)
+				if !reflect.DeepEqual(got, test.wantResp) {
+					t.Errorf("unexpected response\n got=%+v\nwant=%+v", got, test.wantResp)
+				}
+			}
+		})
+	}
+}
+
+func TestInventoryPlanProduction(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockInvSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+		return inventory.Product{Sku: sku}, nil
+	}
+
+	tests := []struct {
+		name           string
+		request        api.PlannedProductionRequest
+		serviceErr     error
+		wantStatusCode int
+		wantPlan       int
+	}{
+		{
+			name:           "production is planned",
+			request:        api.PlannedProductionRequest{Quantity: 5, Due: due},
+			wantStatusCode: http.StatusCreated,
+			wantPlan:       1,
+		},
+		{
+			name:           "due is required",
+			request:        api.PlannedProductionRequest{Quantity: 5},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid plan",
+			request:        api.PlannedProductionRequest{Quantity: 5, Due: due},
+			serviceErr:     inventory.ErrInvalidPlan,
+			wantStatusCode: http.StatusBadRequest,
+			wantPlan:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.CallWatcher = testutil.NewCallWatcher()
+			mockInvSvc.PlanProductionFunc = func(ctx context.Context, plan inventory.PlannedProduction) (inventory.PlannedProduction, error) {
+				if plan.Sku != "sku1" || plan.Quantity != test.request.Quantity || !plan.Due.Equal(test.request.Due) {
+					t.Errorf("unexpected plan got=%+v", plan)
+				}
+				return plan, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/sku1/plannedProduction", test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockInvSvc.VerifyCount("PlanProduction", test.wantPlan, t)
+		})
+	}
+}
+
+func TestInventoryCancelPlannedProduction(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		ID             string
+		serviceErr     error
+		wantStatusCode int
+		wantCancel     int
+	}{
+		{name: "plan is cancelled", ID: "1", wantStatusCode: http.StatusNoContent, wantCancel: 1},
+		{name: "invalid id", ID: "abc", wantStatusCode: http.StatusBadRequest},
+		{name: "plan not found", ID: "2", serviceErr: core.ErrNotFound, wantStatusCode: http.StatusNotFound, wantCancel: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.CallWatcher = testutil.NewCallWatcher()
+			mockInvSvc.CancelPlannedProductionFunc = func(ctx context.Context, sku string, ID uint64) error {
+				return test.serviceErr
+			}
+
+			res := testutil.SendRequest(http.MethodDelete, ts.URL+"/sku1/plannedProduction/"+test.ID, nil, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockInvSvc.VerifyCount("CancelPlannedProduction", test.wantCancel, t)
+		})
+	}
+}
+
+func TestInventoryGetAvailableToPromise(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	fillDate := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	want := inventory.AvailableToPromise{
+		Sku:        "sku1",
+		Available:  2,
+		OpenDemand: 3,
+		Profile: []inventory.AtpPeriod{
+			{Date: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), Receipts: 2, Allocated: 2},
+			{Date: fillDate, Receipts: 4, Allocated: 1, Available: 3},
+		},
+		Reservations: []inventory.ReservationPromise{
+			{ReservationID: 1, RequestID: "req1", Created: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Outstanding: 3, FillDate: &fillDate},
+		},
+	}
+	mockInvSvc.GetAvailableToPromiseFunc = func(ctx context.Context, sku string) (inventory.AvailableToPromise, error) {
+		return want, nil
+	}
+
+	res, err := http.Get(ts.URL + "/sku1/atp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("status code got=%d want=%d", res.StatusCode, http.StatusOK)
+	}
+
+	got := &api.AvailableToPromiseResponse{}
+	testutil.Unmarshal(res, got, t)
+	if !reflect.DeepEqual(got.AvailableToPromise, want) {
+		t.Errorf("unexpected atp\n got=%+v\nwant=%+v", got.AvailableToPromise, want)
+	}
+}
+
 func createProductionEventRequest(requestID string, quantity int64) *api.CreateProductionEventRequest {
 	return &api.CreateProductionEventRequest{
 		ProductionRequest: &inventory.ProductionRequest{RequestID: requestID, Quantity: quantity},
 	}
 }
 
-func createProductRequest(name, sku, upc string) api.CreateProductRequest {
-	return api.CreateProductRequest{Product: inventory.Product{Name: name, Sku: sku, Upc: upc}}
+func createProductRequest(name, sku, upc string) api.CreateProductRequest {
+	return api.CreateProductRequest{Product: inventory.Product{Name: name, Sku: sku, Upc: upc}}
+}
+
+func createKitRequest(sku string, components []inventory.KitComponent) api.CreateProductRequest {
+	return api.CreateProductRequest{
+		Product:    inventory.Product{Name: sku + "name", Sku: sku, Upc: sku + "upc", Type: inventory.Kit},
+		Components: components,
+	}
+}
+
+func createProductResponse(name, sku, upc string, available int64) *api.ProductResponse {
+	return &api.ProductResponse{
+		ProductInventory: inventory.ProductInventory{
+			Available: available,
+			Product:   inventory.Product{Name: name, Sku: sku, Upc: upc},
+		},
+	}
+}
+
+func createTypedProductResponse(name, sku, upc string, productType inventory.ProductType) *api.ProductResponse {
+	return &api.ProductResponse{
+		ProductInventory: inventory.ProductInventory{
+			Product: inventory.Product{Name: name, Sku: sku, Upc: upc, Type: productType},
+		},
+	}
+}
+
+func getTestProductInventory() []inventory.ProductInventory {
+	return []inventory.ProductInventory{
+		{Available: 1, Product: inventory.Product{Sku: "test1sku", Upc: "test1upc", Name: "test1name"}},
+		{Available: 2, Product: inventory.Product{Sku: "test2sku", Upc: "test2upc", Name: "test2name"}},
+		{Available: 3, Product: inventory.Product{Sku: "test3sku", Upc: "test3upc", Name: "test3name"}},
+	}
+}
+
+func TestInventoryHold(t *testing.T) {
+	mockSvc := inventory.NewMockInventoryService()
+	usrSvc := user.NewMockUserService()
+	invApi := api.NewInventoryApi(mockSvc, usrSvc)
+	r := chi.NewRouter()
+	invApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		url        string
+		request    interface{}
+		loginErr   error
+		serviceErr error
+
+		wantStatusCode int
+		wantRelease    int
+		wantReject     int
+	}{
+		{
+			name:           "held inventory is released",
+			url:            "/sku1/hold/release",
+			request:        api.HoldRequest{Quantity: 3},
+			wantStatusCode: http.StatusOK,
+			wantRelease:    1,
+		},
+		{
+			name:           "held inventory is rejected",
+			url:            "/sku1/hold/reject",
+			request:        api.HoldRequest{Quantity: 3, Reference: "lot 7"},
+			wantStatusCode: http.StatusOK,
+			wantReject:     1,
+		},
+		{
+			name:           "quantity is required",
+			url:            "/sku1/hold/release",
+			request:        api.HoldRequest{},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "more than is held",
+			url:            "/sku1/hold/reject",
+			request:        api.HoldRequest{Quantity: 30},
+			serviceErr:     inventory.ErrInvalidHold,
+			wantStatusCode: http.StatusBadRequest,
+			wantReject:     1,
+		},
+		{
+			name:           "unknown users cannot release",
+			url:            "/sku1/hold/release",
+			request:        api.HoldRequest{Quantity: 3},
+			loginErr:       core.ErrNotFound,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+				return user.User{Username: username}, test.loginErr
+			}
+			mockSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return inventory.Product{Sku: sku}, nil
+			}
+			var gotUser string
+			mockSvc.ReleaseHeldFunc = func(ctx context.Context, sku string, qty int64, user string) (inventory.ProductInventory, error) {
+				gotUser = user
+				return inventory.ProductInventory{Product: inventory.Product{Sku: sku}}, test.serviceErr
+			}
+			mockSvc.RejectHeldFunc = func(ctx context.Context, sku string, qty int64, reference, user string) (inventory.ProductInventory, error) {
+				gotUser = user
+				return inventory.ProductInventory{Product: inventory.Product{Sku: sku}}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+test.url, test.request, t, testutil.RequestOptions{Username: "inspector", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("ReleaseHeld", test.wantRelease, t)
+			mockSvc.VerifyCount("RejectHeld", test.wantReject, t)
+
+			if test.wantStatusCode == http.StatusOK && gotUser != "inspector" {
+				t.Errorf("user got=%s want=inspector", gotUser)
+			}
+		})
+	}
+}
+
+func TestInventoryCreateSubstitute(t *testing.T) {
+	ts, mockSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		request        api.SubstituteRequest
+		serviceErr     error
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "substitute is created",
+			request:        api.SubstituteRequest{SubstituteSku: "sku2", Quantity: 1, SubstituteQuantity: 2, TwoWay: true},
+			wantStatusCode: http.StatusCreated,
+			wantCall:       1,
+		},
+		{
+			name:           "substitute sku is required",
+			request:        api.SubstituteRequest{Quantity: 1},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "substitute is a kit",
+			request:        api.SubstituteRequest{SubstituteSku: "kit1"},
+			serviceErr:     inventory.ErrInvalidSubstitute,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return inventory.Product{Sku: sku}, nil
+			}
+			var got inventory.SubstituteRule
+			mockSvc.CreateSubstituteFunc = func(ctx context.Context, rule inventory.SubstituteRule) (inventory.SubstituteRule, error) {
+				got = rule
+				return rule, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/sku1/substitutes", test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("CreateSubstitute", test.wantCall, t)
+
+			if test.wantStatusCode == http.StatusCreated {
+				want := inventory.SubstituteRule{Sku: "sku1", SubstituteSku: "sku2", Quantity: 1, SubstituteQuantity: 2, TwoWay: true}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("rule\n got=%+v\nwant=%+v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestInventoryCreateCrossReference(t *testing.T) {
+	ts, mockSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		request        api.CrossReferenceRequest
+		serviceErr     error
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "cross reference is created",
+			request:        api.CrossReferenceRequest{Identifier: "P-1", Type: inventory.IdentifierCustomerPart, Requester: "acme"},
+			wantStatusCode: http.StatusCreated,
+			wantCall:       1,
+		},
+		{
+			name:           "identifier is required",
+			request:        api.CrossReferenceRequest{Type: inventory.IdentifierUpc},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "type must be valid",
+			request:        api.CrossReferenceRequest{Identifier: "P-1", Type: "Other"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "identifier refers to another sku",
+			request:        api.CrossReferenceRequest{Identifier: "P-1", Type: inventory.IdentifierCustomerPart, Requester: "acme"},
+			serviceErr:     inventory.ErrInvalidCrossReference,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return inventory.Product{Sku: sku}, nil
+			}
+			var got inventory.CrossReference
+			mockSvc.CreateCrossReferenceFunc = func(ctx context.Context, xref inventory.CrossReference) (inventory.CrossReference, error) {
+				got = xref
+				return xref, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/sku1/crossReferences", test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("CreateCrossReference", test.wantCall, t)
+
+			if test.wantStatusCode == http.StatusCreated {
+				want := inventory.CrossReference{Sku: "sku1", Identifier: "P-1", Type: inventory.IdentifierCustomerPart, Requester: "acme"}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("cross reference\n got=%+v\nwant=%+v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestInventoryLookup(t *testing.T) {
+	ts, mockSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		query          string
+		serviceErr     error
+		wantStatusCode int
+		wantCall       int
+	}{
+		{name: "identifier is resolved", query: "?identifier=P-1&requester=acme", wantStatusCode: http.StatusOK, wantCall: 1},
+		{name: "identifier is required", query: "?requester=acme", wantStatusCode: http.StatusBadRequest},
+		{name: "unknown identifier", query: "?identifier=P-2", serviceErr: core.ErrNotFound, wantStatusCode: http.StatusNotFound, wantCall: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.ResolveIdentifierFunc = func(ctx context.Context, identifier, requester string) (inventory.CrossReference, error) {
+				if test.serviceErr != nil {
+					return inventory.CrossReference{}, test.serviceErr
+				}
+				return inventory.CrossReference{ID: 1, Sku: "sku1", Identifier: identifier, Type: inventory.IdentifierCustomerPart, Requester: requester}, nil
+			}
+
+			res, err := http.Get(ts.URL + "/lookup" + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("ResolveIdentifier", test.wantCall, t)
+
+			if test.wantStatusCode == http.StatusOK {
+				got := inventory.CrossReference{}
+				testutil.Unmarshal(res, &got, t)
+				if got.Sku != "sku1" || got.Requester != "acme" {
+					t.Errorf("unexpected cross reference got=%+v", got)
+				}
+			}
+		})
+	}
+}
+
+func TestInventoryProductionConsumers(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	pegs := []inventory.Peg{
+		{ProductionEventID: 1, ProductionRequestID: "prod1", ReservationID: 2, ReservationRequestID: "res2", Requester: "someone", State: inventory.Closed, Sku: "sku1", Quantity: 3},
+	}
+
+	tests := []struct {
+		name           string
+		serviceErr     error
+		wantBody       interface{}
+		wantStatusCode int
+	}{
+		{
+			name:           "consumers are listed",
+			wantBody:       &[]inventory.Peg{pegs[0]},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "production event does not exist",
+			serviceErr:     core.ErrNotFound,
+			wantBody:       &api.ErrResponse{StatusText: api.ErrNotFound.StatusText},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var gotRequestID string
+			mockInvSvc.GetProductionConsumersFunc = func(ctx context.Context, requestID string) ([]inventory.Peg, error) {
+				gotRequestID = requestID
+				return pegs, test.serviceErr
+			}
+
+			res, err := http.Get(ts.URL + "/productionEvent/prod1/consumers")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			if gotRequestID != "prod1" {
+				t.Errorf("request id got=%s want=%s", gotRequestID, "prod1")
+			}
+
+			got := reflect.New(reflect.TypeOf(test.wantBody).Elem()).Interface()
+			testutil.Unmarshal(res, got, t)
+
+			if !reflect.DeepEqual(got, test.wantBody) {
+				t.Errorf("body\n got=%+v\nwant=%+v", got, test.wantBody)
+			}
+		})
+	}
+}
+
+func TestInventoryApprovalThreshold(t *testing.T) {
+	mockSvc := inventory.NewMockInventoryService()
+	usrSvc := user.NewMockUserService()
+	invApi := api.NewInventoryApi(mockSvc, usrSvc)
+	r := chi.NewRouter()
+	invApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	threshold := int64(500)
+	tests := []struct {
+		name       string
+		request    api.ApprovalThresholdRequest
+		loginUser  user.User
+		serviceErr error
+
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "admin sets the threshold",
+			request:        api.ApprovalThresholdRequest{ApprovalThreshold: &threshold},
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "threshold is required",
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "non-admin users cannot set the threshold",
+			request:        api.ApprovalThresholdRequest{ApprovalThreshold: &threshold},
+			loginUser:      createUser("someuser", "", false),
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid threshold",
+			request:        api.ApprovalThresholdRequest{ApprovalThreshold: &threshold},
+			loginUser:      createUser("someadmin", "", true),
+			serviceErr:     inventory.ErrInvalidApproval,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+				return test.loginUser, nil
+			}
+			mockSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return inventory.Product{Sku: sku}, nil
+			}
+			mockSvc.SetApprovalThresholdFunc = func(ctx context.Context, sku string, threshold int64) (inventory.Product, error) {
+				return inventory.Product{Sku: sku, ApprovalThreshold: threshold}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/sku1/approvalThreshold", test.request, t, testutil.RequestOptions{Username: "someuser", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("SetApprovalThreshold", test.wantCall, t)
+		})
+	}
 }
 
-func createProductResponse(name, sku, upc string, available int64) *api.ProductResponse {
-	return &api.ProductResponse{
-		ProductInventory: inventory.ProductInventory{
-			Available: available,
-			Product:   inventory.Product{Name: name, Sku: sku, Upc: upc},
+func TestInventoryReservationSla(t *testing.T) {
+	mockSvc := inventory.NewMockInventoryService()
+	usrSvc := user.NewMockUserService()
+	invApi := api.NewInventoryApi(mockSvc, usrSvc)
+	r := chi.NewRouter()
+	invApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	hours := int64(24)
+	tests := []struct {
+		name       string
+		request    api.ReservationSlaRequest
+		loginUser  user.User
+		serviceErr error
+
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "admin sets the sla",
+			request:        api.ReservationSlaRequest{ReservationSlaHours: &hours},
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "sla is required",
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusBadRequest,
 		},
+		{
+			name:           "non-admin users cannot set the sla",
+			request:        api.ReservationSlaRequest{ReservationSlaHours: &hours},
+			loginUser:      createUser("someuser", "", false),
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid sla",
+			request:        api.ReservationSlaRequest{ReservationSlaHours: &hours},
+			loginUser:      createUser("someadmin", "", true),
+			serviceErr:     inventory.ErrInvalidSla,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+				return test.loginUser, nil
+			}
+			mockSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return inventory.Product{Sku: sku}, nil
+			}
+			mockSvc.SetReservationSlaFunc = func(ctx context.Context, sku string, hours int64) (inventory.Product, error) {
+				return inventory.Product{Sku: sku, ReservationSlaHours: hours}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/sku1/reservationSla", test.request, t, testutil.RequestOptions{Username: "someuser", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("SetReservationSla", test.wantCall, t)
+		})
 	}
 }
 
-func getTestProductInventory() []inventory.ProductInventory {
-	return []inventory.ProductInventory{
-		{Available: 1, Product: inventory.Product{Sku: "test1sku", Upc: "test1upc", Name: "test1name"}},
-		{Available: 2, Product: inventory.Product{Sku: "test2sku", Upc: "test2upc", Name: "test2name"}},
-		{Available: 3, Product: inventory.Product{Sku: "test3sku", Upc: "test3upc", Name: "test3name"}},
+func TestInventoryTimeSeries(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	mockInvSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+		return inventory.Product{Sku: sku}, nil
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		serviceErr     error
+		wantInterval   time.Duration
+		wantFrom       time.Time
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "defaults to hourly buckets",
+			wantInterval:   time.Hour,
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "interval and period are given",
+			query:          "?from=2026-03-01&to=2026-03-02&interval=15m",
+			wantInterval:   15 * time.Minute,
+			wantFrom:       time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "invalid interval",
+			query:          "?interval=hourly",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid from",
+			query:          "?from=yesterday",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "too many intervals",
+			query:          "?interval=1m",
+			serviceErr:     inventory.ErrInvalidReport,
+			wantInterval:   time.Minute,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.CallWatcher = testutil.NewCallWatcher()
+			var gotOptions inventory.ReportOptions
+			var gotInterval time.Duration
+			mockInvSvc.GetInventoryTimeSeriesFunc = func(ctx context.Context, options inventory.ReportOptions, interval time.Duration) ([]inventory.InventoryBucket, error) {
+				gotOptions, gotInterval = options, interval
+				return []inventory.InventoryBucket{{Min: 1, Max: 5, Close: 3, Produced: 4, Allocated: 2}}, test.serviceErr
+			}
+
+			res, err := http.Get(ts.URL + "/sku1/timeseries" + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockInvSvc.VerifyCount("GetInventoryTimeSeries", test.wantCall, t)
+
+			if test.wantCall == 0 {
+				return
+			}
+			if gotOptions.Sku != "sku1" || !gotOptions.From.Equal(test.wantFrom) || gotInterval != test.wantInterval {
+				t.Errorf("options got=%+v interval=%v", gotOptions, gotInterval)
+			}
+		})
+	}
+}
+
+func TestInventoryConvert(t *testing.T) {
+	mockSvc := inventory.NewMockInventoryService()
+	usrSvc := user.NewMockUserService()
+	invApi := api.NewInventoryApi(mockSvc, usrSvc)
+	r := chi.NewRouter()
+	invApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		request    *inventory.ConversionRequest
+		loginErr   error
+		serviceErr error
+
+		wantStatusCode int
+		wantConvert    int
+	}{
+		{
+			name:           "stock is converted",
+			request:        &inventory.ConversionRequest{RequestID: "conv1", TargetSku: "sku2", Quantity: 2, Ratio: 12},
+			wantStatusCode: http.StatusCreated,
+			wantConvert:    1,
+		},
+		{
+			name:           "target sku is required",
+			request:        &inventory.ConversionRequest{RequestID: "conv1", Quantity: 2, Ratio: 12},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "ratio is required",
+			request:        &inventory.ConversionRequest{RequestID: "conv1", TargetSku: "sku2", Quantity: 2},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "not enough available",
+			request:        &inventory.ConversionRequest{RequestID: "conv1", TargetSku: "sku2", Quantity: 200, Ratio: 1},
+			serviceErr:     inventory.ErrInvalidConversion,
+			wantStatusCode: http.StatusBadRequest,
+			wantConvert:    1,
+		},
+		{
+			name:           "unexpected error",
+			request:        &inventory.ConversionRequest{RequestID: "conv1", TargetSku: "sku2", Quantity: 2, Ratio: 1},
+			serviceErr:     errors.New("some unexpected error"),
+			wantStatusCode: http.StatusInternalServerError,
+			wantConvert:    1,
+		},
+		{
+			name:           "unknown users cannot convert",
+			request:        &inventory.ConversionRequest{RequestID: "conv1", TargetSku: "sku2", Quantity: 2, Ratio: 1},
+			loginErr:       core.ErrNotFound,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+				return user.User{Username: username}, test.loginErr
+			}
+			mockSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return inventory.Product{Sku: sku}, nil
+			}
+			var got inventory.ConversionRequest
+			var gotUser string
+			mockSvc.ConvertFunc = func(ctx context.Context, cr inventory.ConversionRequest, user string) (inventory.Conversion, error) {
+				got = cr
+				gotUser = user
+				return inventory.Conversion{RequestID: cr.RequestID}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/sku1/conversions", api.ConversionRequest{ConversionRequest: test.request}, t,
+				testutil.RequestOptions{Username: "reworker", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("Convert", test.wantConvert, t)
+
+			if test.wantConvert > 0 && (got.SourceSku != "sku1" || gotUser != "reworker") {
+				t.Errorf("conversion request got=%+v user=%s", got, gotUser)
+			}
+		})
+	}
+}
+
+func TestInventoryListGroupByStyle(t *testing.T) {
+	ts, mockSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantStatusCode int
+		wantProducts   int
+		wantStyles     int
+	}{
+		{name: "products filtered by style", query: "?style=TEE", wantStatusCode: http.StatusOK, wantProducts: 1},
+		{name: "grouped by style", query: "?groupBy=style&style=TEE", wantStatusCode: http.StatusOK, wantStyles: 1},
+		{name: "unknown grouping", query: "?groupBy=color", wantStatusCode: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.GetAllProductInventoryFunc = func(ctx context.Context, options inventory.GetProductInventoryOptions, limit, offset int) ([]inventory.ProductInventory, error) {
+				if options.Style != "TEE" {
+					t.Errorf("style got=%s want=TEE", options.Style)
+				}
+				return []inventory.ProductInventory{{Product: inventory.Product{Sku: "TEE-M-RED", Style: "TEE"}}}, nil
+			}
+			mockSvc.GetAllStyleInventoryFunc = func(ctx context.Context, options inventory.GetProductInventoryOptions, limit, offset int) ([]inventory.StyleInventory, error) {
+				if options.Style != "TEE" {
+					t.Errorf("style got=%s want=TEE", options.Style)
+				}
+				return []inventory.StyleInventory{{Style: inventory.Style{Code: "TEE"}}}, nil
+			}
+
+			res, err := http.Get(ts.URL + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("GetAllProductInventory", test.wantProducts, t)
+			mockSvc.VerifyCount("GetAllStyleInventory", test.wantStyles, t)
+		})
+	}
+}
+
+func TestInventoryGetStyle(t *testing.T) {
+	ts, mockSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{name: "style with its variants", wantStatusCode: http.StatusOK},
+		{name: "unknown style", serviceErr: core.ErrNotFound, wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.GetStyleInventoryFunc = func(ctx context.Context, code string) (inventory.StyleInventory, error) {
+				if test.serviceErr != nil {
+					return inventory.StyleInventory{}, test.serviceErr
+				}
+				return inventory.StyleInventory{
+					Style:    inventory.Style{Code: code, Axes: []string{"size"}},
+					Values:   map[string][]string{"size": {"M"}},
+					Variants: []inventory.ProductInventory{{Product: inventory.Product{Sku: "TEE-M", Style: code, Variant: inventory.Variant{"size": "M"}}, OnHand: 2}},
+					OnHand:   2,
+				}, nil
+			}
+
+			res, err := http.Get(ts.URL + "/styles/TEE")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			if test.wantStatusCode == http.StatusOK {
+				got := inventory.StyleInventory{}
+				testutil.Unmarshal(res, &got, t)
+				if got.Code != "TEE" || len(got.Variants) != 1 || got.Variants[0].Variant["size"] != "M" || got.OnHand != 2 {
+					t.Errorf("unexpected style inventory got=%+v", got)
+				}
+			}
+		})
+	}
+}
+
+func TestInventoryAssignVariant(t *testing.T) {
+	ts, mockSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		request        api.AssignVariantRequest
+		serviceErr     error
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "variant is assigned",
+			request:        api.AssignVariantRequest{Style: "TEE", Variant: inventory.Variant{"size": "M"}},
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "style is removed",
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "variant is required",
+			request:        api.AssignVariantRequest{Style: "TEE"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "variant is taken",
+			request:        api.AssignVariantRequest{Style: "TEE", Variant: inventory.Variant{"size": "M"}},
+			serviceErr:     inventory.ErrInvalidStyle,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return inventory.Product{Sku: sku}, nil
+			}
+			mockSvc.AssignVariantFunc = func(ctx context.Context, sku, code string, variant inventory.Variant) (inventory.Product, error) {
+				return inventory.Product{Sku: sku, Style: code, Variant: variant}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/sku1/variant", test.request, t)
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("AssignVariant", test.wantCall, t)
+		})
+	}
+}
+
+func TestInventoryFreeze(t *testing.T) {
+	mockSvc := inventory.NewMockInventoryService()
+	usrSvc := user.NewMockUserService()
+	invApi := api.NewInventoryApi(mockSvc, usrSvc)
+	r := chi.NewRouter()
+	invApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	frozen := true
+	tests := []struct {
+		name       string
+		request    api.FreezeRequest
+		loginUser  user.User
+		serviceErr error
+
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "admin freezes reservations",
+			request:        api.FreezeRequest{Freeze: inventory.FreezeReservations, Frozen: &frozen, Reason: "incident 42"},
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "freeze must be valid",
+			request:        api.FreezeRequest{Freeze: "Returns", Frozen: &frozen, Reason: "incident 42"},
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "frozen is required",
+			request:        api.FreezeRequest{Freeze: inventory.FreezeIntake, Reason: "incident 42"},
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "reason is required",
+			request:        api.FreezeRequest{Freeze: inventory.FreezeIntake, Frozen: &frozen},
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "non-admin users cannot freeze",
+			request:        api.FreezeRequest{Freeze: inventory.FreezeReservations, Frozen: &frozen, Reason: "incident 42"},
+			loginUser:      createUser("someuser", "", false),
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+				return test.loginUser, nil
+			}
+			mockSvc.GetProductFunc = func(ctx context.Context, sku string) (inventory.Product, error) {
+				return inventory.Product{Sku: sku}, nil
+			}
+			mockSvc.SetFreezeFunc = func(ctx context.Context, sku string, freeze inventory.Freeze, frozen bool, reason, user string) (inventory.Product, error) {
+				if user != test.loginUser.Username {
+					t.Errorf("user got=%s want=%s", user, test.loginUser.Username)
+				}
+				return inventory.Product{Sku: sku, Freezes: inventory.Freezes{freeze: {Reason: reason, User: user}}}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+"/sku1/freeze", test.request, t, testutil.RequestOptions{Username: "someuser", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("SetFreeze", test.wantCall, t)
+
+			if test.wantStatusCode == http.StatusOK {
+				got := inventory.ProductInventory{}
+				testutil.Unmarshal(res, &got, t)
+				if !got.Frozen(inventory.FreezeReservations) {
+					t.Errorf("product freezes got=%+v", got.Freezes)
+				}
+			}
+		})
 	}
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -42,6 +43,7 @@ func NewReservationListResponse(reservations []inventory.Reservation) []render.R
 
 type CreateProductRequest struct {
 	inventory.Product
+	Components []inventory.KitComponent `json:"components,omitempty"`
 }
 
 func (p *CreateProductRequest) Bind(_ *http.Request) error {
@@ -49,6 +51,29 @@ func (p *CreateProductRequest) Bind(_ *http.Request) error {
 		return errors.New("missing required field(s)")
 	}
 
+	productType, err := inventory.ParseProductType(string(p.Type))
+	if err != nil {
+		return err
+	}
+	p.Type = productType
+
+	if p.Type != inventory.Kit && len(p.Components) > 0 {
+		return errors.New("only kits may have components")
+	}
+	if p.Type == inventory.Kit && p.RequiresInspection {
+		return errors.New("kits cannot require inspection")
+	}
+	if p.Type == inventory.Kit {
+		if len(p.Components) == 0 {
+			return errors.New("kits require at least one component")
+		}
+		for _, c := range p.Components {
+			if c.Sku == "" || c.Quantity < 1 {
+				return errors.New("components require a sku and a quantity greater than zero")
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -66,13 +91,61 @@ func (p *CreateProductionEventRequest) Bind(_ *http.Request) error {
 	if p.RequestID == "" {
 		return errors.New("requestId is required")
 	}
-	if p.Quantity < 1 {
-		return errors.New("quantity must be greater than zero")
+	if p.Quantity < 0 || p.Scrapped < 0 {
+		return errors.New("quantity and scrapped must not be negative")
+	}
+	if p.Quantity+p.Scrapped < 1 {
+		return errors.New("quantity or scrapped must be greater than zero")
+	}
+	if p.UnitCost < 0 {
+		return errors.New("unitCost must not be negative")
 	}
 
 	return nil
 }
 
+const maxProductionBatch = 1000
+
+type CreateProductionEventsRequest struct {
+	Events []inventory.SkuProductionRequest `json:"events"`
+}
+
+func (p *CreateProductionEventsRequest) Bind(_ *http.Request) error {
+	if len(p.Events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	if len(p.Events) > maxProductionBatch {
+		return fmt.Errorf("no more than %d events may be sent at once", maxProductionBatch)
+	}
+	return nil
+}
+
+type ProductionBatchResponse struct {
+	Produced  int                          `json:"produced"`
+	Duplicate int                          `json:"duplicate"`
+	Failed    int                          `json:"failed"`
+	Results   []inventory.ProductionResult `json:"results"`
+}
+
+func NewProductionBatchResponse(results []inventory.ProductionResult) *ProductionBatchResponse {
+	resp := &ProductionBatchResponse{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case inventory.ProductionProduced:
+			resp.Produced++
+		case inventory.ProductionDuplicate:
+			resp.Duplicate++
+		default:
+			resp.Failed++
+		}
+	}
+	return resp
+}
+
+func (p *ProductionBatchResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
 type ProductionEventResponse struct {
 }
 
@@ -83,3 +156,429 @@ func (p *ProductionEventResponse) Render(_ http.ResponseWriter, _ *http.Request)
 func (p *ProductionEventResponse) Bind(_ *http.Request) error {
 	return nil
 }
+
+type ValuationResponse struct {
+	inventory.Valuation
+}
+
+func (v *ValuationResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+type InventoryValuationResponse struct {
+	inventory.InventoryValuation
+}
+
+func (v *InventoryValuationResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+type ValuationEntryResponse struct {
+	inventory.ValuationEntry
+}
+
+func (v *ValuationEntryResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewValuationHistoryResponse(entries []inventory.ValuationEntry) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, entry := range entries {
+		list = append(list, &ValuationEntryResponse{ValuationEntry: entry})
+	}
+	return list
+}
+
+type InventoryBucketResponse struct {
+	inventory.InventoryBucket
+}
+
+func (b *InventoryBucketResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewInventoryBucketListResponse(buckets []inventory.InventoryBucket) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, bucket := range buckets {
+		list = append(list, &InventoryBucketResponse{InventoryBucket: bucket})
+	}
+	return list
+}
+
+type KitComponentResponse struct {
+	inventory.KitComponent
+}
+
+func (k *KitComponentResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewKitComponentListResponse(components []inventory.KitComponent) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, c := range components {
+		list = append(list, &KitComponentResponse{KitComponent: c})
+	}
+	return list
+}
+
+type UpdateAttributesRequest struct {
+	Attributes inventory.Attributes `json:"attributes"`
+}
+
+func (u *UpdateAttributesRequest) Bind(_ *http.Request) error {
+	if u.Attributes == nil {
+		return errors.New("attributes are required")
+	}
+	return nil
+}
+
+type ProductDetailResponse struct {
+	inventory.Product
+}
+
+func (p *ProductDetailResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+type AttributeDefinitionRequest struct {
+	inventory.AttributeDefinition
+}
+
+func (a *AttributeDefinitionRequest) Bind(_ *http.Request) error {
+	if a.Name == "" {
+		return errors.New("name is required")
+	}
+	if _, err := inventory.ParseAttributeType(string(a.Type)); err != nil {
+		return err
+	}
+	return nil
+}
+
+type AttributeDefinitionResponse struct {
+	inventory.AttributeDefinition
+}
+
+func (a *AttributeDefinitionResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewAttributeDefinitionListResponse(definitions []inventory.AttributeDefinition) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, d := range definitions {
+		list = append(list, &AttributeDefinitionResponse{AttributeDefinition: d})
+	}
+	return list
+}
+
+type ImportResponse struct {
+	Created int                      `json:"created"`
+	Updated int                      `json:"updated"`
+	Failed  int                      `json:"failed"`
+	Results []inventory.ImportResult `json:"results"`
+}
+
+func NewImportResponse(results []inventory.ImportResult) *ImportResponse {
+	resp := &ImportResponse{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case inventory.ImportCreated:
+			resp.Created++
+		case inventory.ImportUpdated:
+			resp.Updated++
+		default:
+			resp.Failed++
+		}
+	}
+	return resp
+}
+
+func (i *ImportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+type PlannedProductionRequest struct {
+	Quantity  int64     `json:"quantity"`
+	Due       time.Time `json:"due"`
+	Reference string    `json:"reference,omitempty"`
+}
+
+func (p *PlannedProductionRequest) Bind(_ *http.Request) error {
+	if p.Quantity < 1 {
+		return errors.New("quantity must be greater than zero")
+	}
+	if p.Due.IsZero() {
+		return errors.New("due is required")
+	}
+	return nil
+}
+
+type PlannedProductionResponse struct {
+	inventory.PlannedProduction
+}
+
+func (p *PlannedProductionResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewPlannedProductionListResponse(plans []inventory.PlannedProduction) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, plan := range plans {
+		list = append(list, &PlannedProductionResponse{PlannedProduction: plan})
+	}
+	return list
+}
+
+type AvailableToPromiseResponse struct {
+	inventory.AvailableToPromise
+}
+
+func (a *AvailableToPromiseResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+type InspectionRequest struct {
+	RequiresInspection *bool `json:"requiresInspection"`
+}
+
+func (i *InspectionRequest) Bind(_ *http.Request) error {
+	if i.RequiresInspection == nil {
+		return errors.New("requiresInspection is required")
+	}
+	return nil
+}
+
+type ApprovalThresholdRequest struct {
+	ApprovalThreshold *int64 `json:"approvalThreshold"`
+}
+
+func (a *ApprovalThresholdRequest) Bind(_ *http.Request) error {
+	if a.ApprovalThreshold == nil {
+		return errors.New("approvalThreshold is required")
+	}
+	return nil
+}
+
+type ReservationSlaRequest struct {
+	ReservationSlaHours *int64 `json:"reservationSlaHours"`
+}
+
+func (a *ReservationSlaRequest) Bind(_ *http.Request) error {
+	if a.ReservationSlaHours == nil {
+		return errors.New("reservationSlaHours is required")
+	}
+	return nil
+}
+
+// FreezeRequest freezes or unfreezes an activity on a product, a reason is required either way.
+type FreezeRequest struct {
+	Freeze inventory.Freeze `json:"freeze"`
+	Frozen *bool            `json:"frozen"`
+	Reason string           `json:"reason"`
+}
+
+func (f *FreezeRequest) Bind(_ *http.Request) error {
+	if _, err := inventory.ParseFreeze(string(f.Freeze)); err != nil {
+		return errors.New("freeze must be one of Reservations, Allocation or Intake")
+	}
+	if f.Frozen == nil {
+		return errors.New("frozen is required")
+	}
+	if f.Reason == "" {
+		return errors.New("reason is required")
+	}
+	return nil
+}
+
+type HoldRequest struct {
+	Quantity  int64  `json:"quantity"`
+	Reference string `json:"reference"`
+}
+
+func (h *HoldRequest) Bind(_ *http.Request) error {
+	if h.Quantity < 1 {
+		return errors.New("quantity must be greater than zero")
+	}
+	return nil
+}
+
+type ConversionRequest struct {
+	*inventory.ConversionRequest
+}
+
+func (c *ConversionRequest) Bind(_ *http.Request) error {
+	if c.ConversionRequest == nil {
+		return errors.New("missing required ConversionRequest fields")
+	}
+	if c.RequestID == "" {
+		return errors.New("requestId is required")
+	}
+	if c.TargetSku == "" {
+		return errors.New("targetSku is required")
+	}
+	if c.Quantity < 1 {
+		return errors.New("quantity must be greater than zero")
+	}
+	if c.Ratio <= 0 {
+		return errors.New("ratio must be greater than zero")
+	}
+	return nil
+}
+
+type ConversionResponse struct {
+	inventory.Conversion
+}
+
+func (c *ConversionResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewConversionListResponse(conversions []inventory.Conversion) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, conversion := range conversions {
+		list = append(list, &ConversionResponse{Conversion: conversion})
+	}
+	return list
+}
+
+type PegResponse struct {
+	inventory.Peg
+}
+
+func (p *PegResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewPegListResponse(pegs []inventory.Peg) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, peg := range pegs {
+		list = append(list, &PegResponse{Peg: peg})
+	}
+	return list
+}
+
+type SubstituteRequest struct {
+	SubstituteSku      string `json:"substituteSku"`
+	Quantity           int64  `json:"quantity,omitempty"`
+	SubstituteQuantity int64  `json:"substituteQuantity,omitempty"`
+	TwoWay             bool   `json:"twoWay,omitempty"`
+}
+
+func (s *SubstituteRequest) Bind(_ *http.Request) error {
+	if s.SubstituteSku == "" {
+		return errors.New("substituteSku is required")
+	}
+	if s.Quantity < 0 || s.SubstituteQuantity < 0 {
+		return errors.New("quantities must be greater than zero")
+	}
+	return nil
+}
+
+type CrossReferenceRequest struct {
+	Identifier string                   `json:"identifier"`
+	Type       inventory.IdentifierType `json:"type"`
+	Requester  string                   `json:"requester,omitempty"`
+}
+
+func (c *CrossReferenceRequest) Bind(_ *http.Request) error {
+	if c.Identifier == "" {
+		return errors.New("identifier is required")
+	}
+	if _, err := inventory.ParseIdentifierType(string(c.Type)); err != nil {
+		return errors.New("type must be one of UPC, CustomerPart or SupplierPart")
+	}
+	return nil
+}
+
+type CrossReferenceResponse struct {
+	inventory.CrossReference
+}
+
+func (c *CrossReferenceResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewCrossReferenceListResponse(xrefs []inventory.CrossReference) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, xref := range xrefs {
+		list = append(list, &CrossReferenceResponse{CrossReference: xref})
+	}
+	return list
+}
+
+type StyleRequest struct {
+	inventory.Style
+}
+
+func (s *StyleRequest) Bind(_ *http.Request) error {
+	if s.Code == "" {
+		return errors.New("code is required")
+	}
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(s.Axes) == 0 {
+		return errors.New("axes are required")
+	}
+	return nil
+}
+
+type StyleResponse struct {
+	inventory.Style
+}
+
+func (s *StyleResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewStyleListResponse(styles []inventory.Style) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, style := range styles {
+		list = append(list, &StyleResponse{Style: style})
+	}
+	return list
+}
+
+type StyleInventoryResponse struct {
+	inventory.StyleInventory
+}
+
+func (s *StyleInventoryResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewStyleInventoryListResponse(styles []inventory.StyleInventory) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, style := range styles {
+		list = append(list, &StyleInventoryResponse{StyleInventory: style})
+	}
+	return list
+}
+
+// AssignVariantRequest makes a product a variant of Style, an empty Style removes it from its style.
+type AssignVariantRequest struct {
+	Style   string            `json:"style"`
+	Variant inventory.Variant `json:"variant"`
+}
+
+func (a *AssignVariantRequest) Bind(_ *http.Request) error {
+	if a.Style != "" && len(a.Variant) == 0 {
+		return errors.New("variant is required")
+	}
+	return nil
+}
+
+type SubstituteResponse struct {
+	inventory.SubstituteRule
+}
+
+func (s *SubstituteResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewSubstituteListResponse(rules []inventory.SubstituteRule) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, rule := range rules {
+		list = append(list, &SubstituteResponse{SubstituteRule: rule})
+	}
+	return list
+}
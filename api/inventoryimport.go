@@ -0,0 +1,285 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core/inventory"
+)
+
+const (
+	maxImportBytes = 32 << 20
+	exportPageSize = 500
+
+	csvContentType    = "text/csv"
+	ndjsonContentType = "application/x-ndjson"
+)
+
+var exportHeader = []string{"sku", "upc", "name", "type", "categoryId", "attributes", "onHand", "reserved", "available", "openDemand", "held"}
+
+// Import creates or updates products from a CSV or newline delimited JSON body, selected by the Content-Type header.
+// CSV files require a header row naming the sku, upc and name columns. The optional categoryId and attributes columns
+// hold a category ID and a JSON object of attributes, and a column named attr.<name> sets a single attribute. Other
+// columns are ignored, so an export can be imported as it is. The response reports the outcome of every line.
+func (a *InventoryApi) Import(w http.ResponseWriter, r *http.Request) {
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(errors.New("a content type is required")))
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	var imports []inventory.ProductImport
+	var failures []inventory.ImportResult
+	switch contentType {
+	case csvContentType:
+		imports, failures, err = readCsvImport(body)
+	case ndjsonContentType, "application/ndjson":
+		imports, failures, err = readNdjsonImport(body)
+	default:
+		err = errors.Errorf("unsupported content type %s, expected %s or %s", contentType, csvContentType, ndjsonContentType)
+	}
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	results, err := a.service.ImportProducts(r.Context(), imports)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	results = append(results, failures...)
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+
+	Render(w, r, NewImportResponse(results))
+}
+
+func readCsvImport(body io.Reader) ([]inventory.ProductImport, []inventory.ImportResult, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to read csv header")
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"sku", "upc", "name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, errors.Errorf("csv header is missing the %s column", required)
+		}
+	}
+
+	imports := make([]inventory.ProductImport, 0)
+	failures := make([]inventory.ImportResult, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			failures = append(failures, inventory.ImportResult{Line: parseErr.Line, Status: inventory.ImportFailed, Error: parseErr.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, "failed to read csv")
+		}
+
+		line, _ := reader.FieldPos(0)
+		product, err := csvProduct(record, columns)
+		if err != nil {
+			failures = append(failures, inventory.ImportResult{Line: line, Sku: product.Sku, Status: inventory.ImportFailed, Error: err.Error()})
+			continue
+		}
+		imports = append(imports, inventory.ProductImport{Line: line, Product: product})
+	}
+	return imports, failures, nil
+}
+
+func csvProduct(record []string, columns map[string]int) (inventory.Product, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	product := inventory.Product{Sku: field("sku"), Upc: field("upc"), Name: field("name")}
+
+	productType, err := inventory.ParseProductType(field("type"))
+	if err != nil {
+		return product, err
+	}
+	product.Type = productType
+
+	if v := field("categoryId"); v != "" {
+		product.CategoryID, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return product, errors.New("categoryId must be a positive number")
+		}
+	}
+
+	if v := field("attributes"); v != "" {
+		if err = json.Unmarshal([]byte(v), &product.Attributes); err != nil {
+			return product, errors.New("attributes must be a json object")
+		}
+	}
+	for name, i := range columns {
+		if !strings.HasPrefix(name, attributeParamPrefix) || i >= len(record) || record[i] == "" {
+			continue
+		}
+		if product.Attributes == nil {
+			product.Attributes = inventory.Attributes{}
+		}
+		product.Attributes[strings.TrimPrefix(name, attributeParamPrefix)] = record[i]
+	}
+
+	return product, nil
+}
+
+// ndjsonSettings are the product settings an ndjson line gives explicitly, told apart from those it leaves out.
+type ndjsonSettings struct {
+	RequiresInspection  *bool  `json:"requiresInspection"`
+	ApprovalThreshold   *int64 `json:"approvalThreshold"`
+	ReservationSlaHours *int64 `json:"reservationSlaHours"`
+}
+
+func readNdjsonImport(body io.Reader) ([]inventory.ProductImport, []inventory.ImportResult, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	imports := make([]inventory.ProductImport, 0)
+	failures := make([]inventory.ImportResult, 0)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		product := inventory.Product{}
+		if err := json.Unmarshal(text, &product); err != nil {
+			failures = append(failures, inventory.ImportResult{Line: line, Status: inventory.ImportFailed, Error: "invalid json"})
+			continue
+		}
+		settings := ndjsonSettings{}
+		if err := json.Unmarshal(text, &settings); err != nil {
+			failures = append(failures, inventory.ImportResult{Line: line, Sku: product.Sku, Status: inventory.ImportFailed, Error: "invalid json"})
+			continue
+		}
+		productType, err := inventory.ParseProductType(string(product.Type))
+		if err != nil {
+			failures = append(failures, inventory.ImportResult{Line: line, Sku: product.Sku, Status: inventory.ImportFailed, Error: err.Error()})
+			continue
+		}
+		product.Type = productType
+
+		imports = append(imports, inventory.ProductImport{
+			Line:                line,
+			Product:             product,
+			RequiresInspection:  settings.RequiresInspection,
+			ApprovalThreshold:   settings.ApprovalThreshold,
+			ReservationSlaHours: settings.ReservationSlaHours,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to read ndjson")
+	}
+	return imports, failures, nil
+}
+
+// Export streams every product along with its inventory as CSV. The output uses the columns Import reads, so it can
+// be edited and imported again. Kits are left out since they are created with their components and cannot be imported.
+func (a *InventoryApi) Export(w http.ResponseWriter, r *http.Request) {
+	products, err := a.service.GetAllProductInventory(r.Context(), inventory.GetProductInventoryOptions{}, exportPageSize, 0)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	w.Header().Set("Content-Type", csvContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="inventory.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	if err = writer.Write(exportHeader); err != nil {
+		log.Err(err).Msg("failed to write export header")
+		return
+	}
+
+	for offset := 0; ; {
+		for _, pi := range products {
+			if pi.Type == inventory.Kit {
+				continue
+			}
+			if err = writer.Write(exportRecord(pi)); err != nil {
+				log.Err(err).Msg("failed to write export record")
+				return
+			}
+		}
+		writer.Flush()
+		if err = writer.Error(); err != nil {
+			log.Err(err).Msg("failed to flush export")
+			return
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+
+		if len(products) < exportPageSize {
+			return
+		}
+		offset += exportPageSize
+		products, err = a.service.GetAllProductInventory(r.Context(), inventory.GetProductInventoryOptions{}, exportPageSize, offset)
+		if err != nil {
+			log.Err(err).Int("offset", offset).Msg("failed to get products for export")
+			return
+		}
+	}
+}
+
+func exportRecord(pi inventory.ProductInventory) []string {
+	categoryID := ""
+	if pi.CategoryID != 0 {
+		categoryID = strconv.FormatUint(pi.CategoryID, 10)
+	}
+
+	attributes := ""
+	if len(pi.Attributes) > 0 {
+		if b, err := json.Marshal(pi.Attributes); err == nil {
+			attributes = string(b)
+		}
+	}
+
+	return []string{
+		pi.Sku,
+		pi.Upc,
+		pi.Name,
+		string(pi.Type),
+		categoryID,
+		attributes,
+		strconv.FormatInt(pi.OnHand, 10),
+		strconv.FormatInt(pi.Reserved, 10),
+		strconv.FormatInt(pi.Available, 10),
+		strconv.FormatInt(pi.OpenDemand, 10),
+		strconv.FormatInt(pi.Held, 10),
+	}
+}
@@ -0,0 +1,203 @@
+package api_test
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sksmith/go-micro-example/api"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/testutil"
+)
+
+func TestInventoryImport(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	noInspection, noThreshold := false, int64(0)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		serviceErr  error
+
+		wantImports    []inventory.ProductImport
+		wantResults    []inventory.ImportResult
+		wantStatusCode int
+		wantImportCall int
+	}{
+		{
+			name:        "csv rows are imported",
+			contentType: "text/csv",
+			body:        "sku,upc,name,categoryId,attr.color,onHand\nsku1,upc1,name1,3,red,10\nsku2,upc2,name2,,,\n",
+			wantImports: []inventory.ProductImport{
+				{Line: 2, Product: inventory.Product{Sku: "sku1", Upc: "upc1", Name: "name1", Type: inventory.Standard, CategoryID: 3, Attributes: inventory.Attributes{"color": "red"}}},
+				{Line: 3, Product: inventory.Product{Sku: "sku2", Upc: "upc2", Name: "name2", Type: inventory.Standard}},
+			},
+			wantResults: []inventory.ImportResult{
+				{Line: 2, Sku: "sku1", Status: inventory.ImportCreated},
+				{Line: 3, Sku: "sku2", Status: inventory.ImportCreated},
+			},
+			wantStatusCode: http.StatusOK,
+			wantImportCall: 1,
+		},
+		{
+			name:        "csv rows that cannot be read are reported",
+			contentType: "text/csv; charset=utf-8",
+			body:        "sku,upc,name,categoryId\nsku1,upc1,name1,abc\nsku2,upc2,name2,\n",
+			wantImports: []inventory.ProductImport{
+				{Line: 3, Product: inventory.Product{Sku: "sku2", Upc: "upc2", Name: "name2", Type: inventory.Standard}},
+			},
+			wantResults: []inventory.ImportResult{
+				{Line: 2, Sku: "sku1", Status: inventory.ImportFailed, Error: "categoryId must be a positive number"},
+				{Line: 3, Sku: "sku2", Status: inventory.ImportCreated},
+			},
+			wantStatusCode: http.StatusOK,
+			wantImportCall: 1,
+		},
+		{
+			name:           "csv header requires sku, upc and name",
+			contentType:    "text/csv",
+			body:           "sku,name\nsku1,name1\n",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "ndjson lines are imported",
+			contentType: "application/x-ndjson",
+			body:        "{\"sku\":\"sku1\",\"upc\":\"upc1\",\"name\":\"name1\",\"attributes\":{\"weight\":2}}\n\nnot json\n",
+			wantImports: []inventory.ProductImport{
+				{Line: 1, Product: inventory.Product{Sku: "sku1", Upc: "upc1", Name: "name1", Type: inventory.Standard, Attributes: inventory.Attributes{"weight": float64(2)}}},
+			},
+			wantResults: []inventory.ImportResult{
+				{Line: 1, Sku: "sku1", Status: inventory.ImportCreated},
+				{Line: 3, Status: inventory.ImportFailed, Error: "invalid json"},
+			},
+			wantStatusCode: http.StatusOK,
+			wantImportCall: 1,
+		},
+		{
+			name:        "ndjson settings given explicitly are kept apart",
+			contentType: "application/x-ndjson",
+			body:        "{\"sku\":\"sku1\",\"upc\":\"upc1\",\"name\":\"name1\",\"requiresInspection\":false,\"approvalThreshold\":0}\n",
+			wantImports: []inventory.ProductImport{
+				{
+					Line:               1,
+					Product:            inventory.Product{Sku: "sku1", Upc: "upc1", Name: "name1", Type: inventory.Standard},
+					RequiresInspection: &noInspection,
+					ApprovalThreshold:  &noThreshold,
+				},
+			},
+			wantResults: []inventory.ImportResult{
+				{Line: 1, Sku: "sku1", Status: inventory.ImportCreated},
+			},
+			wantStatusCode: http.StatusOK,
+			wantImportCall: 1,
+		},
+		{
+			name:           "content type must be supported",
+			contentType:    "application/json",
+			body:           "[]",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "service error",
+			contentType:    "text/csv",
+			body:           "sku,upc,name\nsku1,upc1,name1\n",
+			serviceErr:     errors.New("some unexpected error"),
+			wantStatusCode: http.StatusInternalServerError,
+			wantImportCall: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockInvSvc.CallWatcher = testutil.NewCallWatcher()
+			mockInvSvc.ImportProductsFunc = func(ctx context.Context, imports []inventory.ProductImport) ([]inventory.ImportResult, error) {
+				if test.serviceErr != nil {
+					return nil, test.serviceErr
+				}
+				if !reflect.DeepEqual(imports, test.wantImports) {
+					t.Errorf("unexpected imports\n got=%+v\nwant=%+v", imports, test.wantImports)
+				}
+				results := make([]inventory.ImportResult, len(imports))
+				for i, imp := range imports {
+					results[i] = inventory.ImportResult{Line: imp.Line, Sku: imp.Product.Sku, Status: inventory.ImportCreated}
+				}
+				return results, nil
+			}
+
+			res, err := http.Post(ts.URL+"/import", test.contentType, strings.NewReader(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockInvSvc.VerifyCount("ImportProducts", test.wantImportCall, t)
+
+			if test.wantStatusCode == http.StatusOK {
+				got := &api.ImportResponse{}
+				testutil.Unmarshal(res, got, t)
+				if !reflect.DeepEqual(got, api.NewImportResponse(test.wantResults)) {
+					t.Errorf("unexpected response\n got=%+v\nwant=%+v", got, api.NewImportResponse(test.wantResults))
+				}
+			}
+		})
+	}
+}
+
+func TestInventoryExport(t *testing.T) {
+	ts, mockInvSvc := setupInventoryTestServer()
+	defer ts.Close()
+
+	mockInvSvc.GetAllProductInventoryFunc = func(ctx context.Context, options inventory.GetProductInventoryOptions, limit, offset int) ([]inventory.ProductInventory, error) {
+		if offset > 0 {
+			return []inventory.ProductInventory{}, nil
+		}
+		return []inventory.ProductInventory{
+			{
+				Product: inventory.Product{Sku: "sku1", Upc: "upc1", Name: "name1", Type: inventory.Standard, CategoryID: 3, Attributes: inventory.Attributes{"color": "red"}},
+				OnHand:  10, Reserved: 4, Available: 6, OpenDemand: 1,
+			},
+			{
+				Product: inventory.Product{Sku: "kit1", Upc: "upc3", Name: "kit", Type: inventory.Kit},
+			},
+			{
+				Product: inventory.Product{Sku: "sku2", Upc: "upc2", Name: "name2", Type: inventory.Standard},
+			},
+		}, nil
+	}
+
+	res, err := http.Get(ts.URL + "/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("status code got=%d want=%d", res.StatusCode, http.StatusOK)
+	}
+	if got := res.Header.Get("Content-Type"); got != "text/csv" {
+		t.Errorf("content type got=%s want=text/csv", got)
+	}
+
+	records, err := csv.NewReader(res.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"sku", "upc", "name", "type", "categoryId", "attributes", "onHand", "reserved", "available", "openDemand", "held"},
+		{"sku1", "upc1", "name1", "Standard", "3", `{"color":"red"}`, "10", "4", "6", "1", "0"},
+		{"sku2", "upc2", "name2", "Standard", "", "", "0", "0", "0", "0", "0"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("unexpected export\n got=%v\nwant=%v", records, want)
+	}
+	mockInvSvc.VerifyCount("GetAllProductInventory", 1, t)
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
+)
+
+type PurchaseOrderService interface {
+	CreatePurchaseOrder(ctx context.Context, po inventory.PurchaseOrder) (inventory.PurchaseOrder, error)
+	ReceivePurchaseOrder(ctx context.Context, ID uint64, rr inventory.ReceiptRequest, user string) (inventory.PurchaseOrder, error)
+	ClosePurchaseOrder(ctx context.Context, ID uint64, user string) (inventory.PurchaseOrder, error)
+
+	GetPurchaseOrder(ctx context.Context, ID uint64) (inventory.PurchaseOrder, error)
+	GetPurchaseOrders(ctx context.Context, options inventory.GetPurchaseOrdersOptions, limit, offset int) ([]inventory.PurchaseOrder, error)
+}
+
+type PurchaseOrderApi struct {
+	service PurchaseOrderService
+	access  UserAccess
+}
+
+func NewPurchaseOrderApi(service PurchaseOrderService, access UserAccess) *PurchaseOrderApi {
+	return &PurchaseOrderApi{service: service, access: access}
+}
+
+const (
+	CtxKeyPurchaseOrder CtxKey = "purchaseOrder"
+)
+
+func (a *PurchaseOrderApi) ConfigureRouter(r chi.Router) {
+	r.With(Paginate).Get("/", a.List)
+	r.With(Authenticate(a.access)).Put("/", a.Create)
+
+	r.Route("/{ID}", func(r chi.Router) {
+		r.Use(a.PurchaseOrderCtx)
+		r.Get("/", a.Get)
+		r.With(Authenticate(a.access)).Put("/receipts", a.Receive)
+		r.With(Authenticate(a.access), AdminOnly).Put("/close", a.Close)
+	})
+}
+
+func (a *PurchaseOrderApi) List(w http.ResponseWriter, r *http.Request) {
+	limit := r.Context().Value(CtxKeyLimit).(int)
+	offset := r.Context().Value(CtxKeyOffset).(int)
+
+	options := inventory.GetPurchaseOrdersOptions{
+		Number:   r.URL.Query().Get("number"),
+		Supplier: r.URL.Query().Get("supplier"),
+	}
+
+	if v := r.URL.Query().Get("state"); v != "" {
+		var err error
+		if options.State, err = inventory.ParsePurchaseOrderState(v); err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("invalid state")))
+			return
+		}
+	}
+
+	orders, err := a.service.GetPurchaseOrders(r.Context(), options, limit, offset)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewPurchaseOrderListResponse(orders))
+}
+
+func (a *PurchaseOrderApi) Create(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	data := &CreatePurchaseOrderRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	po, err := a.service.CreatePurchaseOrder(r.Context(), data.PurchaseOrder(usr.Username))
+	if err != nil {
+		renderPurchaseOrderErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &PurchaseOrderResponse{PurchaseOrder: po})
+}
+
+func (a *PurchaseOrderApi) Get(w http.ResponseWriter, r *http.Request) {
+	po := r.Context().Value(CtxKeyPurchaseOrder).(inventory.PurchaseOrder)
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &PurchaseOrderResponse{PurchaseOrder: po})
+}
+
+func (a *PurchaseOrderApi) Receive(w http.ResponseWriter, r *http.Request) {
+	po := r.Context().Value(CtxKeyPurchaseOrder).(inventory.PurchaseOrder)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	data := &ReceivePurchaseOrderRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	po, err := a.service.ReceivePurchaseOrder(r.Context(), po.ID, *data.ReceiptRequest, usr.Username)
+	if err != nil {
+		renderPurchaseOrderErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &PurchaseOrderResponse{PurchaseOrder: po})
+}
+
+func (a *PurchaseOrderApi) Close(w http.ResponseWriter, r *http.Request) {
+	po := r.Context().Value(CtxKeyPurchaseOrder).(inventory.PurchaseOrder)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	po, err := a.service.ClosePurchaseOrder(r.Context(), po.ID, usr.Username)
+	if err != nil {
+		renderPurchaseOrderErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &PurchaseOrderResponse{PurchaseOrder: po})
+}
+
+func (a *PurchaseOrderApi) PurchaseOrderCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		IDStr := chi.URLParam(r, "ID")
+		ID, err := strconv.ParseUint(IDStr, 10, 64)
+		if err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("invalid purchase order id")))
+			return
+		}
+
+		po, err := a.service.GetPurchaseOrder(r.Context(), ID)
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				Render(w, r, ErrNotFound)
+			} else {
+				log.Error().Err(err).Str("id", IDStr).Msg("error acquiring purchase order")
+				Render(w, r, ErrInternalServer)
+			}
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), CtxKeyPurchaseOrder, po)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func renderPurchaseOrderErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, inventory.ErrInvalidPurchaseOrder) {
+		Render(w, r, ErrInvalidRequest(err))
+	} else if errors.Is(err, inventory.ErrFrozen) {
+		Render(w, r, ErrConflict(err))
+	} else if errors.Is(err, core.ErrNotFound) {
+		Render(w, r, ErrNotFound)
+	} else {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+	}
+}
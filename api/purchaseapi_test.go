@@ -0,0 +1,251 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/sksmith/go-micro-example/api"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
+	"github.com/sksmith/go-micro-example/testutil"
+)
+
+func setupPurchaseOrderTestServer() (*httptest.Server, *inventory.MockPurchaseOrderService, *user.MockUserService) {
+	mockSvc := inventory.NewMockPurchaseOrderService()
+	usrSvc := user.NewMockUserService()
+	poApi := api.NewPurchaseOrderApi(mockSvc, usrSvc)
+	r := chi.NewRouter()
+	poApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+
+	return ts, mockSvc, usrSvc
+}
+
+func TestPurchaseOrderCreate(t *testing.T) {
+	ts, mockSvc, usrSvc := setupPurchaseOrderTestServer()
+	defer ts.Close()
+
+	usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+		return createUser("buyer", "", false), nil
+	}
+
+	expected := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		request        api.CreatePurchaseOrderRequest
+		serviceErr     error
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name: "purchase order is created",
+			request: api.CreatePurchaseOrderRequest{Number: "PO-1", Supplier: "acme", Lines: []api.PurchaseOrderLineRequest{
+				{Sku: "sku1", Quantity: 10, UnitCost: 2, Expected: expected},
+			}},
+			wantStatusCode: http.StatusCreated,
+			wantCall:       1,
+		},
+		{
+			name:           "lines are required",
+			request:        api.CreatePurchaseOrderRequest{Number: "PO-1", Supplier: "acme"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "expected date is required",
+			request: api.CreatePurchaseOrderRequest{Number: "PO-1", Supplier: "acme", Lines: []api.PurchaseOrderLineRequest{
+				{Sku: "sku1", Quantity: 10},
+			}},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "product is a kit",
+			request: api.CreatePurchaseOrderRequest{Number: "PO-1", Supplier: "acme", Lines: []api.PurchaseOrderLineRequest{
+				{Sku: "kit1", Quantity: 10, Expected: expected},
+			}},
+			serviceErr:     inventory.ErrInvalidPurchaseOrder,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+		{
+			name: "unexpected error",
+			request: api.CreatePurchaseOrderRequest{Number: "PO-1", Supplier: "acme", Lines: []api.PurchaseOrderLineRequest{
+				{Sku: "sku1", Quantity: 10, Expected: expected},
+			}},
+			serviceErr:     errors.New("some unexpected error"),
+			wantStatusCode: http.StatusInternalServerError,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			var got inventory.PurchaseOrder
+			mockSvc.CreatePurchaseOrderFunc = func(ctx context.Context, po inventory.PurchaseOrder) (inventory.PurchaseOrder, error) {
+				got = po
+				po.ID = 7
+				po.State = inventory.PurchaseOrderOpen
+				return po, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL, test.request, t, testutil.RequestOptions{Username: "buyer", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("CreatePurchaseOrder", test.wantCall, t)
+
+			if test.wantCall > 0 && (got.CreatedBy != "buyer" || len(got.Lines) != len(test.request.Lines)) {
+				t.Errorf("purchase order got=%+v", got)
+			}
+		})
+	}
+}
+
+func TestPurchaseOrderSteps(t *testing.T) {
+	ts, mockSvc, usrSvc := setupPurchaseOrderTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		url            string
+		request        interface{}
+		isAdmin        bool
+		getErr         error
+		serviceErr     error
+		wantStatusCode int
+		wantReceive    int
+		wantClose      int
+	}{
+		{
+			name:           "line is received",
+			url:            "/7/receipts",
+			request:        inventory.ReceiptRequest{RequestID: "rcpt1", LineNumber: 1, Quantity: 4},
+			wantStatusCode: http.StatusOK,
+			wantReceive:    1,
+		},
+		{
+			name:           "request id is required",
+			url:            "/7/receipts",
+			request:        inventory.ReceiptRequest{LineNumber: 1, Quantity: 4},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "purchase order is closed",
+			url:            "/7/receipts",
+			request:        inventory.ReceiptRequest{RequestID: "rcpt1", LineNumber: 1, Quantity: 4},
+			serviceErr:     inventory.ErrInvalidPurchaseOrder,
+			wantStatusCode: http.StatusBadRequest,
+			wantReceive:    1,
+		},
+		{
+			name:           "admin closes the purchase order",
+			url:            "/7/close",
+			isAdmin:        true,
+			wantStatusCode: http.StatusOK,
+			wantClose:      1,
+		},
+		{
+			name:           "only admins can close",
+			url:            "/7/close",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "purchase order does not exist",
+			url:            "/7/receipts",
+			request:        inventory.ReceiptRequest{RequestID: "rcpt1", LineNumber: 1, Quantity: 4},
+			getErr:         core.ErrNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid purchase order id",
+			url:            "/abc/close",
+			isAdmin:        true,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+				return createUser("receiver", "", test.isAdmin), nil
+			}
+			mockSvc.GetPurchaseOrderFunc = func(ctx context.Context, ID uint64) (inventory.PurchaseOrder, error) {
+				return inventory.PurchaseOrder{ID: ID, State: inventory.PurchaseOrderOpen}, test.getErr
+			}
+			mockSvc.ReceivePurchaseOrderFunc = func(ctx context.Context, ID uint64, rr inventory.ReceiptRequest, user string) (inventory.PurchaseOrder, error) {
+				return inventory.PurchaseOrder{ID: ID, State: inventory.PurchaseOrderPartiallyReceived}, test.serviceErr
+			}
+			mockSvc.ClosePurchaseOrderFunc = func(ctx context.Context, ID uint64, user string) (inventory.PurchaseOrder, error) {
+				return inventory.PurchaseOrder{ID: ID, State: inventory.PurchaseOrderClosed, ClosedBy: user}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+test.url, test.request, t, testutil.RequestOptions{Username: "receiver", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("ReceivePurchaseOrder", test.wantReceive, t)
+			mockSvc.VerifyCount("ClosePurchaseOrder", test.wantClose, t)
+		})
+	}
+}
+
+func TestPurchaseOrderList(t *testing.T) {
+	ts, mockSvc, _ := setupPurchaseOrderTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantOptions    inventory.GetPurchaseOrdersOptions
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "filters are passed to the service",
+			query:          "?supplier=acme&state=PartiallyReceived",
+			wantOptions:    inventory.GetPurchaseOrdersOptions{Supplier: "acme", State: inventory.PurchaseOrderPartiallyReceived},
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "invalid state",
+			query:          "?state=Lost",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			var got inventory.GetPurchaseOrdersOptions
+			mockSvc.GetPurchaseOrdersFunc = func(ctx context.Context, options inventory.GetPurchaseOrdersOptions, limit, offset int) ([]inventory.PurchaseOrder, error) {
+				got = options
+				return []inventory.PurchaseOrder{{ID: 1, Number: "PO-1"}}, nil
+			}
+
+			res, err := http.Get(ts.URL + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("GetPurchaseOrders", test.wantCall, t)
+			if got != test.wantOptions {
+				t.Errorf("options got=%+v want=%+v", got, test.wantOptions)
+			}
+		})
+	}
+}
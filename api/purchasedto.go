@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/sksmith/go-micro-example/core/inventory"
+)
+
+type CreatePurchaseOrderRequest struct {
+	Number   string                     `json:"number"`
+	Supplier string                     `json:"supplier"`
+	Lines    []PurchaseOrderLineRequest `json:"lines"`
+}
+
+type PurchaseOrderLineRequest struct {
+	LineNumber int       `json:"lineNumber"`
+	Sku        string    `json:"sku"`
+	Quantity   int64     `json:"quantity"`
+	UnitCost   float64   `json:"unitCost"`
+	Expected   time.Time `json:"expected"`
+}
+
+func (c *CreatePurchaseOrderRequest) Bind(_ *http.Request) error {
+	if c.Number == "" {
+		return errors.New("number is required")
+	}
+	if c.Supplier == "" {
+		return errors.New("supplier is required")
+	}
+	if len(c.Lines) == 0 {
+		return errors.New("at least one line is required")
+	}
+	for _, line := range c.Lines {
+		if line.Sku == "" {
+			return errors.New("sku is required")
+		}
+		if line.Quantity < 1 {
+			return errors.New("quantity must be greater than zero")
+		}
+		if line.Expected.IsZero() {
+			return errors.New("expected date is required")
+		}
+	}
+	return nil
+}
+
+// PurchaseOrder converts the request into a purchase order created by the user.
+func (c *CreatePurchaseOrderRequest) PurchaseOrder(user string) inventory.PurchaseOrder {
+	po := inventory.PurchaseOrder{Number: c.Number, Supplier: c.Supplier, CreatedBy: user}
+	for _, line := range c.Lines {
+		po.Lines = append(po.Lines, inventory.PurchaseOrderLine{
+			LineNumber: line.LineNumber,
+			Sku:        line.Sku,
+			Quantity:   line.Quantity,
+			UnitCost:   line.UnitCost,
+			Expected:   line.Expected,
+		})
+	}
+	return po
+}
+
+type ReceivePurchaseOrderRequest struct {
+	*inventory.ReceiptRequest
+}
+
+func (r *ReceivePurchaseOrderRequest) Bind(_ *http.Request) error {
+	if r.ReceiptRequest == nil {
+		return errors.New("missing required ReceiptRequest fields")
+	}
+	if r.RequestID == "" {
+		return errors.New("requestId is required")
+	}
+	if r.LineNumber < 1 {
+		return errors.New("lineNumber is required")
+	}
+	if r.Quantity < 1 {
+		return errors.New("quantity must be greater than zero")
+	}
+	return nil
+}
+
+type PurchaseOrderResponse struct {
+	inventory.PurchaseOrder
+}
+
+func (p *PurchaseOrderResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewPurchaseOrderListResponse(orders []inventory.PurchaseOrder) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, po := range orders {
+		list = append(list, &PurchaseOrderResponse{PurchaseOrder: po})
+	}
+	return list
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core/inventory"
+)
+
+type ReportService interface {
+	GetFillRates(ctx context.Context, group inventory.FillRateGroup, options inventory.ReportOptions) ([]inventory.FillRate, error)
+	GetTimeToClose(ctx context.Context, options inventory.ReportOptions) ([]inventory.TimeToClose, error)
+	GetDailyProduction(ctx context.Context, options inventory.ReportOptions) ([]inventory.DailyProduction, error)
+	GetTopOpenDemand(ctx context.Context, options inventory.ReportOptions, limit int) ([]inventory.SkuDemand, error)
+	GetLineYields(ctx context.Context, options inventory.ReportOptions) ([]inventory.LineYield, error)
+	GetLineOutput(ctx context.Context, options inventory.ReportOptions) ([]inventory.LineOutput, error)
+	GetReservationAging(ctx context.Context, options inventory.ReportOptions) ([]inventory.ReservationAging, error)
+	GetInventoryAging(ctx context.Context, options inventory.ReportOptions, slowDays int) ([]inventory.InventoryAge, error)
+}
+
+type ReportApi struct {
+	service ReportService
+}
+
+func NewReportApi(service ReportService) *ReportApi {
+	return &ReportApi{service: service}
+}
+
+const (
+	reportDateFormat       = "2006-01-02"
+	defaultTopDemandLimit  = 10
+	defaultSlowMovingDays  = 90
+	reportFormatParam      = "format"
+	reportFormatCsv        = "csv"
+	defaultFillRateGroupBy = inventory.FillRateBySku
+)
+
+// csvReport is a report able to be written as CSV as well as JSON.
+type csvReport interface {
+	CsvHeader() []string
+	CsvRecords() [][]string
+}
+
+func (a *ReportApi) ConfigureRouter(r chi.Router) {
+	r.Get("/fillRate", a.GetFillRates)
+	r.Get("/timeToClose", a.GetTimeToClose)
+	r.Get("/production", a.GetDailyProduction)
+	r.Get("/openDemand", a.GetTopOpenDemand)
+	r.Get("/lineYield", a.GetLineYields)
+	r.Get("/lineOutput", a.GetLineOutput)
+	r.Get("/reservationAging", a.GetReservationAging)
+	r.Get("/aging", a.GetInventoryAging)
+}
+
+func (a *ReportApi) GetFillRates(w http.ResponseWriter, r *http.Request) {
+	options, err := reportOptions(r)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	group := inventory.FillRateGroup(r.URL.Query().Get("groupBy"))
+	if group == "" {
+		group = defaultFillRateGroupBy
+	}
+
+	rates, err := a.service.GetFillRates(r.Context(), group, options)
+	if err != nil {
+		renderReportErr(w, r, err)
+		return
+	}
+	renderReport(w, r, "fill-rate", FillRateReportResponse(rates))
+}
+
+func (a *ReportApi) GetTimeToClose(w http.ResponseWriter, r *http.Request) {
+	options, err := reportOptions(r)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	times, err := a.service.GetTimeToClose(r.Context(), options)
+	if err != nil {
+		renderReportErr(w, r, err)
+		return
+	}
+	renderReport(w, r, "time-to-close", TimeToCloseReportResponse(times))
+}
+
+func (a *ReportApi) GetDailyProduction(w http.ResponseWriter, r *http.Request) {
+	options, err := reportOptions(r)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	days, err := a.service.GetDailyProduction(r.Context(), options)
+	if err != nil {
+		renderReportErr(w, r, err)
+		return
+	}
+	renderReport(w, r, "production", DailyProductionReportResponse(days))
+}
+
+func (a *ReportApi) GetTopOpenDemand(w http.ResponseWriter, r *http.Request) {
+	options, err := reportOptions(r)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	limit := defaultTopDemandLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("limit must be a number")))
+			return
+		}
+	}
+
+	demand, err := a.service.GetTopOpenDemand(r.Context(), options, limit)
+	if err != nil {
+		renderReportErr(w, r, err)
+		return
+	}
+	renderReport(w, r, "open-demand", OpenDemandReportResponse(demand))
+}
+
+func (a *ReportApi) GetLineYields(w http.ResponseWriter, r *http.Request) {
+	options, err := reportOptions(r)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	yields, err := a.service.GetLineYields(r.Context(), options)
+	if err != nil {
+		renderReportErr(w, r, err)
+		return
+	}
+	renderReport(w, r, "line-yield", LineYieldReportResponse(yields))
+}
+
+func (a *ReportApi) GetLineOutput(w http.ResponseWriter, r *http.Request) {
+	options, err := reportOptions(r)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	output, err := a.service.GetLineOutput(r.Context(), options)
+	if err != nil {
+		renderReportErr(w, r, err)
+		return
+	}
+	renderReport(w, r, "line-output", LineOutputReportResponse(output))
+}
+
+func (a *ReportApi) GetReservationAging(w http.ResponseWriter, r *http.Request) {
+	options, err := reportOptions(r)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	aging, err := a.service.GetReservationAging(r.Context(), options)
+	if err != nil {
+		renderReportErr(w, r, err)
+		return
+	}
+	renderReport(w, r, "reservation-aging", ReservationAgingReportResponse(aging))
+}
+
+// reportOptions reads the from, to and sku query parameters. Dates may be given as a day or as an RFC 3339 time, a
+// to day includes the whole of that day.
+func (a *ReportApi) GetInventoryAging(w http.ResponseWriter, r *http.Request) {
+	options, err := reportOptions(r)
+	if err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	slowDays := defaultSlowMovingDays
+	if v := r.URL.Query().Get("slowDays"); v != "" {
+		if slowDays, err = strconv.Atoi(v); err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("slowDays must be a number")))
+			return
+		}
+	}
+
+	aging, err := a.service.GetInventoryAging(r.Context(), options, slowDays)
+	if err != nil {
+		renderReportErr(w, r, err)
+		return
+	}
+	renderReport(w, r, "inventory-aging", InventoryAgingReportResponse(aging))
+}
+
+func reportOptions(r *http.Request) (inventory.ReportOptions, error) {
+	options := inventory.ReportOptions{Sku: r.URL.Query().Get("sku")}
+
+	var err error
+	if v := r.URL.Query().Get("from"); v != "" {
+		if options.From, err = parseReportTime(v, false); err != nil {
+			return options, errors.New("from must be a date or an RFC 3339 time")
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if options.To, err = parseReportTime(v, true); err != nil {
+			return options, errors.New("to must be a date or an RFC 3339 time")
+		}
+	}
+	return options, nil
+}
+
+func parseReportTime(v string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(reportDateFormat, v); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// renderReport writes the report as CSV when asked to with the format query parameter or the Accept header, and as
+// JSON otherwise.
+func renderReport(w http.ResponseWriter, r *http.Request, name string, report interface {
+	render.Renderer
+	csvReport
+}) {
+	format := r.URL.Query().Get(reportFormatParam)
+	if format == "" && strings.Contains(r.Header.Get("Accept"), csvContentType) {
+		format = reportFormatCsv
+	}
+	if format != reportFormatCsv {
+		Render(w, r, report)
+		return
+	}
+
+	w.Header().Set("Content-Type", csvContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(report.CsvHeader()); err != nil {
+		log.Err(err).Str("report", name).Msg("failed to write report header")
+		return
+	}
+	if err := writer.WriteAll(report.CsvRecords()); err != nil {
+		log.Err(err).Str("report", name).Msg("failed to write report")
+	}
+}
+
+func renderReportErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, inventory.ErrInvalidReport) {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	log.Err(err).Send()
+	Render(w, r, ErrInternalServer)
+}
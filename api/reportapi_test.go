@@ -0,0 +1,391 @@
+package api_test
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/sksmith/go-micro-example/api"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/testutil"
+)
+
+func setupReportTestServer() (*httptest.Server, *inventory.MockReportService) {
+	mockSvc := inventory.NewMockReportService()
+	rptApi := api.NewReportApi(mockSvc)
+	r := chi.NewRouter()
+	rptApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+
+	return ts, mockSvc
+}
+
+func TestReportFillRates(t *testing.T) {
+	ts, mockSvc := setupReportTestServer()
+	defer ts.Close()
+
+	rates := []inventory.FillRate{{Key: "sku1", Reservations: 2, Closed: 1, Requested: 10, Reserved: 5, FillRate: 0.5}}
+
+	tests := []struct {
+		name       string
+		query      string
+		serviceErr error
+
+		wantGroup   inventory.FillRateGroup
+		wantOptions inventory.ReportOptions
+		wantStatus  int
+		wantCall    int
+	}{
+		{
+			name:       "fill rates default to sku",
+			wantGroup:  inventory.FillRateBySku,
+			wantStatus: http.StatusOK,
+			wantCall:   1,
+		},
+		{
+			name:      "date range includes the whole to day",
+			query:     "?groupBy=requester&from=2026-01-01&to=2026-01-31",
+			wantGroup: inventory.FillRateByRequester,
+			wantOptions: inventory.ReportOptions{
+				From: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantStatus: http.StatusOK,
+			wantCall:   1,
+		},
+		{
+			name:      "times are accepted",
+			query:     "?from=2026-01-01T06:00:00Z&sku=sku1",
+			wantGroup: inventory.FillRateBySku,
+			wantOptions: inventory.ReportOptions{
+				From: time.Date(2026, 1, 1, 6, 0, 0, 0, time.UTC),
+				Sku:  "sku1",
+			},
+			wantStatus: http.StatusOK,
+			wantCall:   1,
+		},
+		{
+			name:       "invalid date",
+			query:      "?from=yesterday",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid report",
+			query:      "?groupBy=color",
+			serviceErr: inventory.ErrInvalidReport,
+			wantGroup:  "color",
+			wantStatus: http.StatusBadRequest,
+			wantCall:   1,
+		},
+		{
+			name:       "unexpected error",
+			serviceErr: errors.New("some unexpected error"),
+			wantGroup:  inventory.FillRateBySku,
+			wantStatus: http.StatusInternalServerError,
+			wantCall:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.GetFillRatesFunc = func(ctx context.Context, group inventory.FillRateGroup, options inventory.ReportOptions) ([]inventory.FillRate, error) {
+				if group != test.wantGroup {
+					t.Errorf("unexpected group got=%s want=%s", group, test.wantGroup)
+				}
+				if !options.From.Equal(test.wantOptions.From) || !options.To.Equal(test.wantOptions.To) || options.Sku != test.wantOptions.Sku {
+					t.Errorf("unexpected options got=%+v want=%+v", options, test.wantOptions)
+				}
+				return rates, test.serviceErr
+			}
+
+			res, err := http.Get(ts.URL + "/fillRate" + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatus {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatus)
+			}
+			mockSvc.VerifyCount("GetFillRates", test.wantCall, t)
+
+			if test.wantStatus == http.StatusOK {
+				got := api.FillRateReportResponse{}
+				testutil.Unmarshal(res, &got, t)
+				if !reflect.DeepEqual(got, api.FillRateReportResponse(rates)) {
+					t.Errorf("unexpected response got=%+v want=%+v", got, rates)
+				}
+			}
+		})
+	}
+}
+
+func TestReportCsv(t *testing.T) {
+	ts, mockSvc := setupReportTestServer()
+	defer ts.Close()
+
+	mockSvc.GetDailyProductionFunc = func(ctx context.Context, options inventory.ReportOptions) ([]inventory.DailyProduction, error) {
+		return []inventory.DailyProduction{
+			{Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Events: 3, Quantity: 30},
+			{Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), Events: 1, Quantity: 5},
+		}, nil
+	}
+
+	want := [][]string{
+		{"date", "events", "quantity"},
+		{"2026-01-01", "3", "30"},
+		{"2026-01-02", "1", "5"},
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+	}{
+		{name: "format parameter", query: "?format=csv"},
+		{name: "accept header", accept: "text/csv"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, ts.URL+"/production"+test.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			if got := res.Header.Get("Content-Type"); got != "text/csv" {
+				t.Errorf("content type got=%s want=text/csv", got)
+			}
+			records, err := csv.NewReader(res.Body).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(records, want) {
+				t.Errorf("unexpected report got=%v want=%v", records, want)
+			}
+		})
+	}
+}
+
+func TestReportLineYieldCsv(t *testing.T) {
+	ts, mockSvc := setupReportTestServer()
+	defer ts.Close()
+
+	mockSvc.GetLineYieldsFunc = func(ctx context.Context, options inventory.ReportOptions) ([]inventory.LineYield, error) {
+		if options.From != time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) {
+			t.Errorf("unexpected from got=%v", options.From)
+		}
+		return []inventory.LineYield{
+			{Line: "L1", Shift: "A", Events: 3, Good: 80, Scrapped: 20, Yield: 80},
+		}, nil
+	}
+
+	res, err := http.Get(ts.URL + "/lineYield?format=csv&from=2026-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	records, err := csv.NewReader(res.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"line", "shift", "events", "good", "scrapped", "yield"},
+		{"L1", "A", "3", "80", "20", "80.00"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("unexpected report got=%v want=%v", records, want)
+	}
+	mockSvc.VerifyCount("GetLineYields", 1, t)
+}
+
+func TestReportReservationAgingCsv(t *testing.T) {
+	ts, mockSvc := setupReportTestServer()
+	defer ts.Close()
+
+	mockSvc.GetReservationAgingFunc = func(ctx context.Context, options inventory.ReportOptions) ([]inventory.ReservationAging, error) {
+		if options.Sku != "sku1" {
+			t.Errorf("unexpected sku got=%v", options.Sku)
+		}
+		return []inventory.ReservationAging{
+			{Sku: "sku1", Requester: "acme", Reservations: 3, Outstanding: 40, UnderOneDay: 1, ThreeToSeven: 1, Over14: 1,
+				Oldest: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		}, nil
+	}
+
+	res, err := http.Get(ts.URL + "/reservationAging?format=csv&sku=sku1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	records, err := csv.NewReader(res.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"sku", "requester", "reservations", "outstanding", "underOneDay", "oneToThreeDays", "threeToSevenDays", "sevenToFourteenDays", "overFourteenDays", "oldest"},
+		{"sku1", "acme", "3", "40", "1", "0", "1", "0", "1", "2026-01-02T03:04:05Z"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("unexpected report got=%v want=%v", records, want)
+	}
+	mockSvc.VerifyCount("GetReservationAging", 1, t)
+}
+
+func TestReportInventoryAging(t *testing.T) {
+	ts, mockSvc := setupReportTestServer()
+	defer ts.Close()
+
+	lastAllocated := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	aging := []inventory.InventoryAge{
+		{Sku: "sku1", Quantity: 15, Value: 37.5, UnderThirty: 5, OverNinety: 10, Oldest: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+			LastAllocated: &lastAllocated, SlowMoving: true},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantSlowDays   int
+		wantStatusCode int
+		wantCall       int
+	}{
+		{name: "default slow moving window", query: "", wantSlowDays: 90, wantStatusCode: http.StatusOK, wantCall: 1},
+		{name: "slow moving window", query: "?slowDays=30", wantSlowDays: 30, wantStatusCode: http.StatusOK, wantCall: 1},
+		{name: "slow moving window must be a number", query: "?slowDays=x", wantStatusCode: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.GetInventoryAgingFunc = func(ctx context.Context, options inventory.ReportOptions, slowDays int) ([]inventory.InventoryAge, error) {
+				if slowDays != test.wantSlowDays {
+					t.Errorf("slow days got=%d want=%d", slowDays, test.wantSlowDays)
+				}
+				return aging, nil
+			}
+
+			res, err := http.Get(ts.URL + "/aging" + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("GetInventoryAging", test.wantCall, t)
+		})
+	}
+}
+
+func TestReportInventoryAgingCsv(t *testing.T) {
+	ts, mockSvc := setupReportTestServer()
+	defer ts.Close()
+
+	mockSvc.GetInventoryAgingFunc = func(ctx context.Context, options inventory.ReportOptions, slowDays int) ([]inventory.InventoryAge, error) {
+		return []inventory.InventoryAge{
+			{Sku: "sku1", Quantity: 15, Value: 37.5, UnderThirty: 5, OverNinety: 10, Oldest: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+				SlowMoving: true},
+		}, nil
+	}
+
+	res, err := http.Get(ts.URL + "/aging?format=csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	records, err := csv.NewReader(res.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"sku", "quantity", "value", "underThirtyDays", "thirtyToSixtyDays", "sixtyToNinetyDays", "overNinetyDays", "oldest", "lastAllocated", "slowMoving"},
+		{"sku1", "15", "37.50", "5", "0", "0", "10", "2025-09-01T00:00:00Z", "", "true"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("unexpected report got=%v want=%v", records, want)
+	}
+}
+
+func TestReportTopOpenDemand(t *testing.T) {
+	ts, mockSvc := setupReportTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int
+		wantStatus int
+		wantCall   int
+	}{
+		{name: "limit defaults to ten", wantLimit: 10, wantStatus: http.StatusOK, wantCall: 1},
+		{name: "limit is passed on", query: "?limit=3", wantLimit: 3, wantStatus: http.StatusOK, wantCall: 1},
+		{name: "limit must be a number", query: "?limit=all", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.GetTopOpenDemandFunc = func(ctx context.Context, options inventory.ReportOptions, limit int) ([]inventory.SkuDemand, error) {
+				if limit != test.wantLimit {
+					t.Errorf("unexpected limit got=%d want=%d", limit, test.wantLimit)
+				}
+				return []inventory.SkuDemand{}, nil
+			}
+
+			res, err := http.Get(ts.URL + "/openDemand" + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatus {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatus)
+			}
+			mockSvc.VerifyCount("GetTopOpenDemand", test.wantCall, t)
+		})
+	}
+}
+
+func TestReportRoutes(t *testing.T) {
+	ts := httptest.NewServer(getRouter())
+	defer ts.Close()
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: api.InventoryPath + api.ReportPath + "/timeToClose", wantStatus: http.StatusOK},
+		{path: api.InventoryPath + "/sku1", wantStatus: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			res, err := http.Get(fmt.Sprintf("%s%s%s", ts.URL, api.ApiPath, test.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != test.wantStatus {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sksmith/go-micro-example/core/inventory"
+)
+
+type FillRateReportResponse []inventory.FillRate
+
+func (f FillRateReportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (f FillRateReportResponse) CsvHeader() []string {
+	return []string{"key", "reservations", "closed", "requested", "reserved", "fillRate"}
+}
+
+func (f FillRateReportResponse) CsvRecords() [][]string {
+	records := make([][]string, 0, len(f))
+	for _, rate := range f {
+		records = append(records, []string{
+			rate.Key,
+			strconv.FormatInt(rate.Reservations, 10),
+			strconv.FormatInt(rate.Closed, 10),
+			strconv.FormatInt(rate.Requested, 10),
+			strconv.FormatInt(rate.Reserved, 10),
+			strconv.FormatFloat(rate.FillRate, 'f', 4, 64),
+		})
+	}
+	return records
+}
+
+type TimeToCloseReportResponse []inventory.TimeToClose
+
+func (t TimeToCloseReportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (t TimeToCloseReportResponse) CsvHeader() []string {
+	return []string{"sku", "closed", "averageSeconds", "p95Seconds"}
+}
+
+func (t TimeToCloseReportResponse) CsvRecords() [][]string {
+	records := make([][]string, 0, len(t))
+	for _, c := range t {
+		records = append(records, []string{
+			c.Sku,
+			strconv.FormatInt(c.Closed, 10),
+			strconv.FormatFloat(c.AverageSeconds, 'f', 1, 64),
+			strconv.FormatFloat(c.P95Seconds, 'f', 1, 64),
+		})
+	}
+	return records
+}
+
+type DailyProductionReportResponse []inventory.DailyProduction
+
+func (d DailyProductionReportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (d DailyProductionReportResponse) CsvHeader() []string {
+	return []string{"date", "events", "quantity"}
+}
+
+func (d DailyProductionReportResponse) CsvRecords() [][]string {
+	records := make([][]string, 0, len(d))
+	for _, day := range d {
+		records = append(records, []string{
+			day.Date.Format(reportDateFormat),
+			strconv.FormatInt(day.Events, 10),
+			strconv.FormatInt(day.Quantity, 10),
+		})
+	}
+	return records
+}
+
+type OpenDemandReportResponse []inventory.SkuDemand
+
+func (o OpenDemandReportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (o OpenDemandReportResponse) CsvHeader() []string {
+	return []string{"sku", "reservations", "openDemand"}
+}
+
+func (o OpenDemandReportResponse) CsvRecords() [][]string {
+	records := make([][]string, 0, len(o))
+	for _, demand := range o {
+		records = append(records, []string{
+			demand.Sku,
+			strconv.FormatInt(demand.Reservations, 10),
+			strconv.FormatInt(demand.OpenDemand, 10),
+		})
+	}
+	return records
+}
+
+type LineYieldReportResponse []inventory.LineYield
+
+func (l LineYieldReportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (l LineYieldReportResponse) CsvHeader() []string {
+	return []string{"line", "shift", "events", "good", "scrapped", "yield"}
+}
+
+func (l LineYieldReportResponse) CsvRecords() [][]string {
+	records := make([][]string, 0, len(l))
+	for _, y := range l {
+		records = append(records, []string{
+			y.Line,
+			y.Shift,
+			strconv.FormatInt(y.Events, 10),
+			strconv.FormatInt(y.Good, 10),
+			strconv.FormatInt(y.Scrapped, 10),
+			strconv.FormatFloat(y.Yield, 'f', 2, 64),
+		})
+	}
+	return records
+}
+
+type LineOutputReportResponse []inventory.LineOutput
+
+func (l LineOutputReportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (l LineOutputReportResponse) CsvHeader() []string {
+	return []string{"date", "line", "shift", "events", "good", "scrapped"}
+}
+
+func (l LineOutputReportResponse) CsvRecords() [][]string {
+	records := make([][]string, 0, len(l))
+	for _, o := range l {
+		records = append(records, []string{
+			o.Date.Format(reportDateFormat),
+			o.Line,
+			o.Shift,
+			strconv.FormatInt(o.Events, 10),
+			strconv.FormatInt(o.Good, 10),
+			strconv.FormatInt(o.Scrapped, 10),
+		})
+	}
+	return records
+}
+
+type ReservationAgingReportResponse []inventory.ReservationAging
+
+func (a ReservationAgingReportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (a ReservationAgingReportResponse) CsvHeader() []string {
+	return []string{"sku", "requester", "reservations", "outstanding", "underOneDay", "oneToThreeDays", "threeToSevenDays",
+		"sevenToFourteenDays", "overFourteenDays", "oldest"}
+}
+
+func (a ReservationAgingReportResponse) CsvRecords() [][]string {
+	records := make([][]string, 0, len(a))
+	for _, g := range a {
+		records = append(records, []string{
+			g.Sku,
+			g.Requester,
+			strconv.FormatInt(g.Reservations, 10),
+			strconv.FormatInt(g.Outstanding, 10),
+			strconv.FormatInt(g.UnderOneDay, 10),
+			strconv.FormatInt(g.OneToThree, 10),
+			strconv.FormatInt(g.ThreeToSeven, 10),
+			strconv.FormatInt(g.SevenTo14, 10),
+			strconv.FormatInt(g.Over14, 10),
+			g.Oldest.UTC().Format(time.RFC3339),
+		})
+	}
+	return records
+}
+
+type InventoryAgingReportResponse []inventory.InventoryAge
+
+func (a InventoryAgingReportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func (a InventoryAgingReportResponse) CsvHeader() []string {
+	return []string{"sku", "quantity", "value", "underThirtyDays", "thirtyToSixtyDays", "sixtyToNinetyDays", "overNinetyDays",
+		"oldest", "lastAllocated", "slowMoving"}
+}
+
+func (a InventoryAgingReportResponse) CsvRecords() [][]string {
+	records := make([][]string, 0, len(a))
+	for _, g := range a {
+		lastAllocated := ""
+		if g.LastAllocated != nil {
+			lastAllocated = g.LastAllocated.UTC().Format(time.RFC3339)
+		}
+		records = append(records, []string{
+			g.Sku,
+			strconv.FormatInt(g.Quantity, 10),
+			strconv.FormatFloat(g.Value, 'f', 2, 64),
+			strconv.FormatInt(g.UnderThirty, 10),
+			strconv.FormatInt(g.ThirtyToSixty, 10),
+			strconv.FormatInt(g.SixtyToNinety, 10),
+			strconv.FormatInt(g.OverNinety, 10),
+			g.Oldest.UTC().Format(time.RFC3339),
+			lastAllocated,
+			strconv.FormatBool(g.SlowMoving),
+		})
+	}
+	return records
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/sksmith/go-micro-example/core"
 	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
 )
 
 type ReservationService interface {
@@ -21,17 +22,24 @@ type ReservationService interface {
 
 	GetReservations(ctx context.Context, options inventory.GetReservationsOptions, limit, offset int) ([]inventory.Reservation, error)
 	GetReservation(ctx context.Context, ID uint64) (inventory.Reservation, error)
+	GetReservationSources(ctx context.Context, ID uint64) ([]inventory.Peg, error)
+
+	ApproveReservation(ctx context.Context, ID uint64, approver string) (inventory.Reservation, error)
+	RejectReservation(ctx context.Context, ID uint64, approver, reason string) (inventory.Reservation, error)
 
 	SubscribeReservations(ch chan<- inventory.Reservation) (id inventory.ReservationsSubID)
 	UnsubscribeReservations(id inventory.ReservationsSubID)
+	SubscribeAlerts(ch chan<- inventory.ReservationAlert) (id inventory.AlertsSubID)
+	UnsubscribeAlerts(id inventory.AlertsSubID)
 }
 
 type ReservationApi struct {
 	service ReservationService
+	access  UserAccess
 }
 
-func NewReservationApi(service ReservationService) *ReservationApi {
-	return &ReservationApi{service: service}
+func NewReservationApi(service ReservationService, access UserAccess) *ReservationApi {
+	return &ReservationApi{service: service, access: access}
 }
 
 const (
@@ -40,6 +48,7 @@ const (
 
 func (ra *ReservationApi) ConfigureRouter(r chi.Router) {
 	r.HandleFunc("/subscribe", ra.Subscribe)
+	r.HandleFunc("/alerts/subscribe", ra.SubscribeAlerts)
 
 	r.Route("/", func(r chi.Router) {
 		r.With(Paginate).Get("/", ra.List)
@@ -48,6 +57,9 @@ func (ra *ReservationApi) ConfigureRouter(r chi.Router) {
 		r.Route("/{ID}", func(r chi.Router) {
 			r.Use(ra.ReservationCtx)
 			r.Get("/", ra.Get)
+			r.Get("/sources", ra.GetSources)
+			r.With(Authenticate(ra.access), AdminOnly).Put("/approve", ra.Approve)
+			r.With(Authenticate(ra.access), AdminOnly).Put("/reject", ra.Reject)
 			r.Delete("/", ra.Cancel)
 		})
 	})
@@ -90,6 +102,43 @@ func (a *ReservationApi) Subscribe(w http.ResponseWriter, r *http.Request) {
 	}()
 }
 
+// SubscribeAlerts streams alerts about reservations, such as those left open past their SLA, to a websocket client.
+func (a *ReservationApi) SubscribeAlerts(w http.ResponseWriter, r *http.Request) {
+	log.Info().Msg("client requesting alert subscription")
+
+	conn, _, _, err := ws.UpgradeHTTP(r, w)
+	if err != nil {
+		log.Err(err).Msg("failed to establish alert subscription connection")
+		Render(w, r, ErrInternalServer)
+		return
+	}
+	go func() {
+		defer conn.Close()
+
+		ch := make(chan inventory.ReservationAlert, 1)
+
+		id := a.service.SubscribeAlerts(ch)
+		defer func() {
+			a.service.UnsubscribeAlerts(id)
+		}()
+
+		for alert := range ch {
+			body, err := json.Marshal(alert)
+			if err != nil {
+				log.Err(err).Interface("clientId", id).Msg("failed to marshal reservation alert")
+				continue
+			}
+
+			log.Debug().Interface("clientId", id).Interface("alert", alert).Msg("sending reservation alert to client")
+			err = wsutil.WriteServerText(conn, body)
+			if err != nil {
+				log.Err(err).Interface("clientId", id).Msg("failed to write server message, disconnecting client")
+				return
+			}
+		}
+	}()
+}
+
 func (a *ReservationApi) Get(w http.ResponseWriter, r *http.Request) {
 	res := r.Context().Value(CtxKeyReservation).(inventory.Reservation)
 
@@ -98,6 +147,19 @@ func (a *ReservationApi) Get(w http.ResponseWriter, r *http.Request) {
 	Render(w, r, resp)
 }
 
+func (a *ReservationApi) GetSources(w http.ResponseWriter, r *http.Request) {
+	res := r.Context().Value(CtxKeyReservation).(inventory.Reservation)
+
+	pegs, err := a.service.GetReservationSources(r.Context(), res.ID)
+	if err != nil {
+		log.Err(err).Uint64("id", res.ID).Msg("failed to get reservation sources")
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	RenderList(w, r, NewPegListResponse(pegs))
+}
+
 func (a *ReservationApi) Create(w http.ResponseWriter, r *http.Request) {
 	data := &ReservationRequest{}
 	if err := render.Bind(r, data); err != nil {
@@ -110,6 +172,8 @@ func (a *ReservationApi) Create(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if errors.Is(err, core.ErrNotFound) {
 			Render(w, r, ErrNotFound)
+		} else if errors.Is(err, inventory.ErrFrozen) {
+			Render(w, r, ErrConflict(err))
 		} else {
 			log.Error().Err(err).Interface("reservationRequest", data).Msg("failed to reserve")
 			Render(w, r, ErrInternalServer)
@@ -122,6 +186,51 @@ func (a *ReservationApi) Create(w http.ResponseWriter, r *http.Request) {
 	Render(w, r, resp)
 }
 
+func (a *ReservationApi) Approve(w http.ResponseWriter, r *http.Request) {
+	res := r.Context().Value(CtxKeyReservation).(inventory.Reservation)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	res, err := a.service.ApproveReservation(r.Context(), res.ID, usr.Username)
+	if err != nil {
+		renderApprovalErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &ReservationResponse{Reservation: res})
+}
+
+func (a *ReservationApi) Reject(w http.ResponseWriter, r *http.Request) {
+	res := r.Context().Value(CtxKeyReservation).(inventory.Reservation)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	data := &RejectReservationRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	res, err := a.service.RejectReservation(r.Context(), res.ID, usr.Username, data.Reason)
+	if err != nil {
+		renderApprovalErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &ReservationResponse{Reservation: res})
+}
+
+func renderApprovalErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, inventory.ErrInvalidApproval) {
+		Render(w, r, ErrInvalidRequest(err))
+	} else if errors.Is(err, core.ErrNotFound) {
+		Render(w, r, ErrNotFound)
+	} else {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+	}
+}
+
 func (a *ReservationApi) Cancel(_ http.ResponseWriter, _ *http.Request) {
 	// TODO Not implemented
 }
@@ -171,7 +280,15 @@ func (a *ReservationApi) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := a.service.GetReservations(r.Context(), inventory.GetReservationsOptions{Sku: sku, State: state}, limit, offset)
+	var parentID uint64
+	if p := r.URL.Query().Get("parentId"); p != "" {
+		if parentID, err = strconv.ParseUint(p, 10, 64); err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("invalid parent id")))
+			return
+		}
+	}
+
+	res, err := a.service.GetReservations(r.Context(), inventory.GetReservationsOptions{Sku: sku, State: state, ParentID: parentID}, limit, offset)
 
 	if err != nil {
 		if errors.Is(err, core.ErrNotFound) {
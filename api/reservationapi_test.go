@@ -15,6 +15,7 @@ import (
 	"github.com/sksmith/go-micro-example/api"
 	"github.com/sksmith/go-micro-example/core"
 	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
 	"github.com/sksmith/go-micro-example/testutil"
 )
 
@@ -42,7 +43,7 @@ func TestReservationSubscribe(t *testing.T) {
 		unsubscribeCalled = true
 	}
 
-	resApi := api.NewReservationApi(mockSvc)
+	resApi := api.NewReservationApi(mockSvc, user.NewMockUserService())
 	r := chi.NewRouter()
 	resApi.ConfigureRouter(r)
 	ts := httptest.NewServer(r)
@@ -73,7 +74,7 @@ func TestReservationSubscribe(t *testing.T) {
 }
 
 func TestReservationGet(t *testing.T) {
-	ts, mockResSvc := setupReservationTestServer()
+	ts, mockResSvc, _ := setupReservationTestServer()
 	defer ts.Close()
 
 	tests := []struct {
@@ -147,7 +148,7 @@ func TestReservationGet(t *testing.T) {
 }
 
 func TestReservationCreate(t *testing.T) {
-	ts, mockResSvc := setupReservationTestServer()
+	ts, mockResSvc, _ := setupReservationTestServer()
 	defer ts.Close()
 
 	tests := []struct {
@@ -184,6 +185,22 @@ func TestReservationCreate(t *testing.T) {
 			wantErr:        api.ErrInternalServer,
 			wantStatusCode: http.StatusInternalServerError,
 		},
+		{
+			reserveFunc: func(ctx context.Context, rr inventory.ReservationRequest) (inventory.Reservation, error) {
+				return inventory.Reservation{}, inventory.ErrFrozen
+			},
+			request:        createReservationRequest("requestid1", "requester1", "sku1", 1),
+			wantResponse:   nil,
+			wantErr:        api.ErrConflict(inventory.ErrFrozen),
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			reserveFunc:    nil,
+			request:        createReservationRequest("requestid1", "requester1", "", 1),
+			wantResponse:   nil,
+			wantErr:        api.ErrInvalidRequest(errors.New("sku or customerPartNumber is required")),
+			wantStatusCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, test := range tests {
@@ -218,7 +235,7 @@ func TestReservationCreate(t *testing.T) {
 }
 
 func TestReservationList(t *testing.T) {
-	ts, mockResSvc := setupReservationTestServer()
+	ts, mockResSvc, _ := setupReservationTestServer()
 	defer ts.Close()
 
 	tests := []struct {
@@ -278,12 +295,29 @@ func TestReservationList(t *testing.T) {
 			wantResponse:   getTestReservationResponses(),
 			wantStatusCode: http.StatusOK,
 		},
+		{
+			getReservationsFunc: func(ctx context.Context, options inventory.GetReservationsOptions, limit int, offset int) ([]inventory.Reservation, error) {
+				if options.ParentID != 7 {
+					t.Errorf("parent id got=%d want=%d", options.ParentID, 7)
+				}
+				return getTestReservations(), nil
+			},
+			url:            ts.URL + "?parentId=7",
+			wantResponse:   getTestReservationResponses(),
+			wantStatusCode: http.StatusOK,
+		},
 		{
 			getReservationsFunc: nil,
 			url:                 ts.URL + "?state=SomeInvalidState",
 			wantResponse:        api.ErrInvalidRequest(errors.New("invalid state")),
 			wantStatusCode:      http.StatusBadRequest,
 		},
+		{
+			getReservationsFunc: nil,
+			url:                 ts.URL + "?parentId=abc",
+			wantResponse:        api.ErrInvalidRequest(errors.New("invalid parent id")),
+			wantStatusCode:      http.StatusBadRequest,
+		},
 		{
 			getReservationsFunc: func(ctx context.Context, options inventory.GetReservationsOptions, limit int, offset int) ([]inventory.Reservation, error) {
 				return []inventory.Reservation{}, core.ErrNotFound
@@ -354,14 +388,15 @@ func createReservationRequest(requestID, requester, sku string, quantity int64)
 	}
 }
 
-func setupReservationTestServer() (*httptest.Server, *inventory.MockReservationService) {
+func setupReservationTestServer() (*httptest.Server, *inventory.MockReservationService, *user.MockUserService) {
 	mockSvc := inventory.NewMockReservationService()
-	invApi := api.NewReservationApi(mockSvc)
+	usrSvc := user.NewMockUserService()
+	invApi := api.NewReservationApi(mockSvc, usrSvc)
 	r := chi.NewRouter()
 	invApi.ConfigureRouter(r)
 	ts := httptest.NewServer(r)
 
-	return ts, mockSvc
+	return ts, mockSvc, usrSvc
 }
 
 var testReservations = []inventory.Reservation{
@@ -401,3 +436,158 @@ func getTime(t string) time.Time {
 	}
 	return tm
 }
+
+func TestReservationSources(t *testing.T) {
+	ts, mockResSvc, _ := setupReservationTestServer()
+	defer ts.Close()
+
+	pegs := []inventory.Peg{
+		{ProductionEventID: 1, ProductionRequestID: "prod1", ReservationID: 1, ReservationRequestID: "requestID1", Sku: "sku1", Quantity: 1},
+	}
+
+	tests := []struct {
+		name               string
+		getReservationFunc func(ctx context.Context, ID uint64) (inventory.Reservation, error)
+		wantBody           interface{}
+		wantStatusCode     int
+		wantSourcesCalls   int
+	}{
+		{
+			name: "sources are listed",
+			getReservationFunc: func(ctx context.Context, ID uint64) (inventory.Reservation, error) {
+				return getTestReservations()[0], nil
+			},
+			wantBody:         &[]inventory.Peg{pegs[0]},
+			wantStatusCode:   http.StatusOK,
+			wantSourcesCalls: 1,
+		},
+		{
+			name: "reservation does not exist",
+			getReservationFunc: func(ctx context.Context, ID uint64) (inventory.Reservation, error) {
+				return inventory.Reservation{}, core.ErrNotFound
+			},
+			wantBody:       &api.ErrResponse{StatusText: api.ErrNotFound.StatusText},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockResSvc.CallWatcher = testutil.NewCallWatcher()
+			mockResSvc.GetReservationFunc = test.getReservationFunc
+			mockResSvc.GetReservationSourcesFunc = func(ctx context.Context, ID uint64) ([]inventory.Peg, error) {
+				if ID != 1 {
+					t.Errorf("id got=%d want=%d", ID, 1)
+				}
+				return pegs, nil
+			}
+
+			res, err := http.Get(ts.URL + "/1/sources")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockResSvc.VerifyCount("GetReservationSources", test.wantSourcesCalls, t)
+
+			got := reflect.New(reflect.TypeOf(test.wantBody).Elem()).Interface()
+			testutil.Unmarshal(res, got, t)
+
+			if !reflect.DeepEqual(got, test.wantBody) {
+				t.Errorf("body\n got=%+v\nwant=%+v", got, test.wantBody)
+			}
+		})
+	}
+}
+
+func TestReservationApproval(t *testing.T) {
+	ts, mockResSvc, usrSvc := setupReservationTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		url            string
+		request        interface{}
+		loginUser      user.User
+		serviceErr     error
+		wantStatusCode int
+		wantApprove    int
+		wantReject     int
+		wantState      inventory.ReserveState
+	}{
+		{
+			name:           "admin approves the reservation",
+			url:            "/1/approve",
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusOK,
+			wantApprove:    1,
+			wantState:      inventory.Open,
+		},
+		{
+			name:           "admin rejects the reservation",
+			url:            "/1/reject",
+			request:        api.RejectReservationRequest{Reason: "too large"},
+			loginUser:      createUser("someadmin", "", true),
+			wantStatusCode: http.StatusOK,
+			wantReject:     1,
+			wantState:      inventory.Rejected,
+		},
+		{
+			name:           "non-admin users cannot approve",
+			url:            "/1/approve",
+			loginUser:      createUser("someuser", "", false),
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "reservation is not waiting for approval",
+			url:            "/1/approve",
+			loginUser:      createUser("someadmin", "", true),
+			serviceErr:     inventory.ErrInvalidApproval,
+			wantStatusCode: http.StatusBadRequest,
+			wantApprove:    1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockResSvc.CallWatcher = testutil.NewCallWatcher()
+			usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+				return test.loginUser, nil
+			}
+			mockResSvc.GetReservationFunc = func(ctx context.Context, ID uint64) (inventory.Reservation, error) {
+				return inventory.Reservation{ID: ID, State: inventory.PendingApproval}, nil
+			}
+			mockResSvc.ApproveReservationFunc = func(ctx context.Context, ID uint64, approver string) (inventory.Reservation, error) {
+				return inventory.Reservation{ID: ID, State: inventory.Open, DecidedBy: approver}, test.serviceErr
+			}
+			mockResSvc.RejectReservationFunc = func(ctx context.Context, ID uint64, approver, reason string) (inventory.Reservation, error) {
+				if reason != "too large" {
+					t.Errorf("reason got=%s want=%s", reason, "too large")
+				}
+				return inventory.Reservation{ID: ID, State: inventory.Rejected, DecidedBy: approver, DecisionReason: reason}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+test.url, test.request, t, testutil.RequestOptions{Username: "someuser", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockResSvc.VerifyCount("ApproveReservation", test.wantApprove, t)
+			mockResSvc.VerifyCount("RejectReservation", test.wantReject, t)
+
+			if test.wantStatusCode != http.StatusOK {
+				return
+			}
+			got := &inventory.Reservation{}
+			testutil.Unmarshal(res, got, t)
+			if got.State != test.wantState {
+				t.Errorf("state got=%s want=%s", got.State, test.wantState)
+			}
+			if got.DecidedBy != test.loginUser.Username {
+				t.Errorf("decided by got=%s want=%s", got.DecidedBy, test.loginUser.Username)
+			}
+		})
+	}
+}
@@ -18,6 +18,9 @@ func (r *ReservationRequest) Bind(_ *http.Request) error {
 	if r.Requester == "" {
 		return errors.New("requester is required")
 	}
+	if r.Sku == "" && r.CustomerPartNumber == "" {
+		return errors.New("sku or customerPartNumber is required")
+	}
 	if r.Quantity < 1 {
 		return errors.New("requested quantity must be greater than zero")
 	}
@@ -32,3 +35,11 @@ type ReservationResponse struct {
 func (r *ReservationResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
 	return nil
 }
+
+type RejectReservationRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (r *RejectReservationRequest) Bind(_ *http.Request) error {
+	return nil
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
+)
+
+type ReturnService interface {
+	CreateReturn(ctx context.Context, rma inventory.ReturnAuthorization) (inventory.ReturnAuthorization, error)
+	ReceiveReturn(ctx context.Context, ID uint64, user string) (inventory.ReturnAuthorization, error)
+	InspectReturn(ctx context.Context, ID uint64, outcome inventory.InspectionOutcome, note, user string) (inventory.ReturnAuthorization, error)
+
+	GetReturn(ctx context.Context, ID uint64) (inventory.ReturnAuthorization, error)
+	GetReturns(ctx context.Context, options inventory.GetReturnsOptions, limit, offset int) ([]inventory.ReturnAuthorization, error)
+}
+
+type ReturnApi struct {
+	service ReturnService
+	access  UserAccess
+}
+
+func NewReturnApi(service ReturnService, access UserAccess) *ReturnApi {
+	return &ReturnApi{service: service, access: access}
+}
+
+const (
+	CtxKeyReturn CtxKey = "return"
+)
+
+func (a *ReturnApi) ConfigureRouter(r chi.Router) {
+	r.With(Paginate).Get("/", a.List)
+	r.With(Authenticate(a.access)).Put("/", a.Create)
+
+	r.Route("/{ID}", func(r chi.Router) {
+		r.Use(a.ReturnCtx)
+		r.Get("/", a.Get)
+		r.With(Authenticate(a.access)).Put("/receive", a.Receive)
+		r.With(Authenticate(a.access)).Put("/inspect", a.Inspect)
+	})
+}
+
+func (a *ReturnApi) List(w http.ResponseWriter, r *http.Request) {
+	limit := r.Context().Value(CtxKeyLimit).(int)
+	offset := r.Context().Value(CtxKeyOffset).(int)
+
+	options := inventory.GetReturnsOptions{Sku: r.URL.Query().Get("sku")}
+
+	if v := r.URL.Query().Get("state"); v != "" {
+		var err error
+		if options.State, err = inventory.ParseReturnState(v); err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("invalid state")))
+			return
+		}
+	}
+
+	if v := r.URL.Query().Get("reservationId"); v != "" {
+		var err error
+		if options.ReservationID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("invalid reservation id")))
+			return
+		}
+	}
+
+	returns, err := a.service.GetReturns(r.Context(), options, limit, offset)
+	if err != nil {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	RenderList(w, r, NewReturnListResponse(returns))
+}
+
+func (a *ReturnApi) Create(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	data := &CreateReturnRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	rma, err := a.service.CreateReturn(r.Context(), inventory.ReturnAuthorization{
+		Sku:           data.Sku,
+		ReservationID: data.ReservationID,
+		Quantity:      data.Quantity,
+		Reason:        data.Reason,
+		CreatedBy:     usr.Username,
+	})
+	if err != nil {
+		renderReturnErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	Render(w, r, &ReturnResponse{ReturnAuthorization: rma})
+}
+
+func (a *ReturnApi) Get(w http.ResponseWriter, r *http.Request) {
+	rma := r.Context().Value(CtxKeyReturn).(inventory.ReturnAuthorization)
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &ReturnResponse{ReturnAuthorization: rma})
+}
+
+func (a *ReturnApi) Receive(w http.ResponseWriter, r *http.Request) {
+	rma := r.Context().Value(CtxKeyReturn).(inventory.ReturnAuthorization)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	rma, err := a.service.ReceiveReturn(r.Context(), rma.ID, usr.Username)
+	if err != nil {
+		renderReturnErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &ReturnResponse{ReturnAuthorization: rma})
+}
+
+func (a *ReturnApi) Inspect(w http.ResponseWriter, r *http.Request) {
+	rma := r.Context().Value(CtxKeyReturn).(inventory.ReturnAuthorization)
+	usr := r.Context().Value(CtxKeyUser).(user.User)
+
+	data := &InspectReturnRequest{}
+	if err := render.Bind(r, data); err != nil {
+		Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	rma, err := a.service.InspectReturn(r.Context(), rma.ID, data.Outcome, data.Note, usr.Username)
+	if err != nil {
+		renderReturnErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	Render(w, r, &ReturnResponse{ReturnAuthorization: rma})
+}
+
+func (a *ReturnApi) ReturnCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		IDStr := chi.URLParam(r, "ID")
+		ID, err := strconv.ParseUint(IDStr, 10, 64)
+		if err != nil {
+			Render(w, r, ErrInvalidRequest(errors.New("invalid return id")))
+			return
+		}
+
+		rma, err := a.service.GetReturn(r.Context(), ID)
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				Render(w, r, ErrNotFound)
+			} else {
+				log.Error().Err(err).Str("id", IDStr).Msg("error acquiring return")
+				Render(w, r, ErrInternalServer)
+			}
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), CtxKeyReturn, rma)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func renderReturnErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, inventory.ErrInvalidReturn) {
+		Render(w, r, ErrInvalidRequest(err))
+	} else if errors.Is(err, core.ErrNotFound) {
+		Render(w, r, ErrNotFound)
+	} else {
+		log.Err(err).Send()
+		Render(w, r, ErrInternalServer)
+	}
+}
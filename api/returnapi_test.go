@@ -0,0 +1,251 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/sksmith/go-micro-example/api"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/core/user"
+	"github.com/sksmith/go-micro-example/testutil"
+)
+
+func setupReturnTestServer() (*httptest.Server, *inventory.MockReturnService, *user.MockUserService) {
+	mockSvc := inventory.NewMockReturnService()
+	usrSvc := user.NewMockUserService()
+	rtnApi := api.NewReturnApi(mockSvc, usrSvc)
+	r := chi.NewRouter()
+	rtnApi.ConfigureRouter(r)
+	ts := httptest.NewServer(r)
+
+	return ts, mockSvc, usrSvc
+}
+
+func TestReturnCreate(t *testing.T) {
+	ts, mockSvc, usrSvc := setupReturnTestServer()
+	defer ts.Close()
+
+	usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+		return createUser("agent", "", false), nil
+	}
+
+	tests := []struct {
+		name           string
+		request        api.CreateReturnRequest
+		serviceErr     error
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "return is created against a reservation",
+			request:        api.CreateReturnRequest{ReservationID: 3, Quantity: 2, Reason: "damaged"},
+			wantStatusCode: http.StatusCreated,
+			wantCall:       1,
+		},
+		{
+			name:           "sku or reservation is required",
+			request:        api.CreateReturnRequest{Quantity: 2, Reason: "damaged"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "reason is required",
+			request:        api.CreateReturnRequest{Sku: "sku1", Quantity: 2},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "more than was reserved",
+			request:        api.CreateReturnRequest{ReservationID: 3, Quantity: 20, Reason: "damaged"},
+			serviceErr:     inventory.ErrInvalidReturn,
+			wantStatusCode: http.StatusBadRequest,
+			wantCall:       1,
+		},
+		{
+			name:           "unexpected error",
+			request:        api.CreateReturnRequest{Sku: "sku1", Quantity: 2, Reason: "damaged"},
+			serviceErr:     errors.New("some unexpected error"),
+			wantStatusCode: http.StatusInternalServerError,
+			wantCall:       1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			var got inventory.ReturnAuthorization
+			mockSvc.CreateReturnFunc = func(ctx context.Context, rma inventory.ReturnAuthorization) (inventory.ReturnAuthorization, error) {
+				got = rma
+				rma.ID = 7
+				rma.State = inventory.ReturnRequested
+				return rma, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL, test.request, t, testutil.RequestOptions{Username: "agent", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("CreateReturn", test.wantCall, t)
+
+			if test.wantCall > 0 && got.CreatedBy != "agent" {
+				t.Errorf("created by got=%s want=%s", got.CreatedBy, "agent")
+			}
+		})
+	}
+}
+
+func TestReturnSteps(t *testing.T) {
+	ts, mockSvc, usrSvc := setupReturnTestServer()
+	defer ts.Close()
+
+	usrSvc.LoginFunc = func(ctx context.Context, username, password string) (user.User, error) {
+		return createUser("inspector", "", false), nil
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		request        interface{}
+		getErr         error
+		serviceErr     error
+		wantStatusCode int
+		wantReceive    int
+		wantInspect    int
+		wantOutcome    inventory.InspectionOutcome
+	}{
+		{
+			name:           "return is received",
+			url:            "/7/receive",
+			wantStatusCode: http.StatusOK,
+			wantReceive:    1,
+		},
+		{
+			name:           "return is restocked",
+			url:            "/7/inspect",
+			request:        api.InspectReturnRequest{Outcome: inventory.OutcomeRestock, Note: "unopened"},
+			wantStatusCode: http.StatusOK,
+			wantInspect:    1,
+			wantOutcome:    inventory.OutcomeRestock,
+		},
+		{
+			name:           "invalid outcome",
+			url:            "/7/inspect",
+			request:        api.InspectReturnRequest{Outcome: "Resell"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "return has not been received",
+			url:            "/7/inspect",
+			request:        api.InspectReturnRequest{Outcome: inventory.OutcomeScrap},
+			serviceErr:     inventory.ErrInvalidReturn,
+			wantStatusCode: http.StatusBadRequest,
+			wantInspect:    1,
+			wantOutcome:    inventory.OutcomeScrap,
+		},
+		{
+			name:           "return does not exist",
+			url:            "/7/receive",
+			getErr:         core.ErrNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid return id",
+			url:            "/abc/receive",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			mockSvc.GetReturnFunc = func(ctx context.Context, ID uint64) (inventory.ReturnAuthorization, error) {
+				return inventory.ReturnAuthorization{ID: ID, State: inventory.ReturnReceived}, test.getErr
+			}
+			mockSvc.ReceiveReturnFunc = func(ctx context.Context, ID uint64, user string) (inventory.ReturnAuthorization, error) {
+				return inventory.ReturnAuthorization{ID: ID, State: inventory.ReturnReceived, ReceivedBy: user}, test.serviceErr
+			}
+			var gotOutcome inventory.InspectionOutcome
+			mockSvc.InspectReturnFunc = func(ctx context.Context, ID uint64, outcome inventory.InspectionOutcome, note, user string) (inventory.ReturnAuthorization, error) {
+				gotOutcome = outcome
+				return inventory.ReturnAuthorization{ID: ID, State: inventory.ReturnRestocked, InspectedBy: user, Note: note}, test.serviceErr
+			}
+
+			res := testutil.Put(ts.URL+test.url, test.request, t, testutil.RequestOptions{Username: "inspector", Password: "somepass"})
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("ReceiveReturn", test.wantReceive, t)
+			mockSvc.VerifyCount("InspectReturn", test.wantInspect, t)
+
+			if gotOutcome != test.wantOutcome {
+				t.Errorf("outcome got=%s want=%s", gotOutcome, test.wantOutcome)
+			}
+		})
+	}
+}
+
+func TestReturnList(t *testing.T) {
+	ts, mockSvc, _ := setupReturnTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantOptions    inventory.GetReturnsOptions
+		wantStatusCode int
+		wantCall       int
+	}{
+		{
+			name:           "all returns",
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "returns by sku, state and reservation",
+			query:          "?sku=sku1&state=Received&reservationId=3",
+			wantOptions:    inventory.GetReturnsOptions{Sku: "sku1", State: inventory.ReturnReceived, ReservationID: 3},
+			wantStatusCode: http.StatusOK,
+			wantCall:       1,
+		},
+		{
+			name:           "invalid state",
+			query:          "?state=Bogus",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid reservation id",
+			query:          "?reservationId=abc",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc.CallWatcher = testutil.NewCallWatcher()
+			var gotOptions inventory.GetReturnsOptions
+			mockSvc.GetReturnsFunc = func(ctx context.Context, options inventory.GetReturnsOptions, limit, offset int) ([]inventory.ReturnAuthorization, error) {
+				gotOptions = options
+				return []inventory.ReturnAuthorization{{ID: 1, State: inventory.ReturnReceived}}, nil
+			}
+
+			res, err := http.Get(ts.URL + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.wantStatusCode {
+				t.Errorf("status code got=%d want=%d", res.StatusCode, test.wantStatusCode)
+			}
+			mockSvc.VerifyCount("GetReturns", test.wantCall, t)
+
+			if gotOptions != test.wantOptions {
+				t.Errorf("options got=%+v want=%+v", gotOptions, test.wantOptions)
+			}
+		})
+	}
+}
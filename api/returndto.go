@@ -0,0 +1,59 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/sksmith/go-micro-example/core/inventory"
+)
+
+type CreateReturnRequest struct {
+	Sku           string `json:"sku"`
+	ReservationID uint64 `json:"reservationId"`
+	Quantity      int64  `json:"quantity"`
+	Reason        string `json:"reason"`
+}
+
+func (c *CreateReturnRequest) Bind(_ *http.Request) error {
+	if c.Sku == "" && c.ReservationID == 0 {
+		return errors.New("sku or reservationId is required")
+	}
+	if c.Quantity < 1 {
+		return errors.New("quantity must be greater than zero")
+	}
+	if c.Reason == "" {
+		return errors.New("reason is required")
+	}
+	return nil
+}
+
+type InspectReturnRequest struct {
+	Outcome inventory.InspectionOutcome `json:"outcome"`
+	Note    string                      `json:"note"`
+}
+
+func (i *InspectReturnRequest) Bind(_ *http.Request) error {
+	outcome, err := inventory.ParseInspectionOutcome(string(i.Outcome))
+	if err != nil {
+		return err
+	}
+	i.Outcome = outcome
+	return nil
+}
+
+type ReturnResponse struct {
+	inventory.ReturnAuthorization
+}
+
+func (rr *ReturnResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewReturnListResponse(returns []inventory.ReturnAuthorization) []render.Renderer {
+	list := make([]render.Renderer, 0)
+	for _, rma := range returns {
+		list = append(list, &ReturnResponse{ReturnAuthorization: rma})
+	}
+	return list
+}
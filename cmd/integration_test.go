@@ -56,7 +56,7 @@ func TestMain(m *testing.M) {
 
 	userService := user.NewService(ur)
 
-	r := api.ConfigureRouter(cfg, invService, invService, userService)
+	r := api.ConfigureRouter(cfg, invService, invService, invService, invService, invService, invService, invService, userService)
 
 	_ = queue.NewProductQueue(ctx, cfg, invService)
 
@@ -38,13 +38,24 @@ func main() {
 
 	ir := invrepo.NewPostgresRepo(dbPool)
 
-	invService := inventory.NewService(ir, iq)
+	costingMethod, err := inventory.ParseCostingMethod(cfg.Inventory.CostingMethod.Value)
+	if err != nil {
+		log.Fatal().Err(err).Str("costingMethod", cfg.Inventory.CostingMethod.Value).Msg("invalid costing method")
+	}
+
+	invService := inventory.NewService(ir, iq,
+		inventory.Costing(costingMethod),
+		inventory.SplitBackorders(cfg.Inventory.SplitBackorders.Value),
+		inventory.ApprovalThreshold(cfg.Inventory.ApprovalThreshold.Value),
+		inventory.ReservationSla(cfg.Inventory.ReservationSla.Value))
+
+	go invService.MonitorReservationSla(ctx, time.Duration(cfg.Inventory.SlaCheckInterval.Value)*time.Minute)
 
 	ur := usrrepo.NewPostgresRepo(dbPool)
 
 	userService := user.NewService(ur)
 
-	r := api.ConfigureRouter(cfg, invService, invService, userService)
+	r := api.ConfigureRouter(cfg, invService, invService, invService, invService, invService, invService, invService, userService)
 
 	_ = queue.NewProductQueue(ctx, cfg, invService)
 
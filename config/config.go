@@ -58,17 +58,18 @@ type FloatConfig struct {
 }
 
 type Config struct {
-	AppName     StringConfig `json:"appName"     yaml:"appName"`
-	AppVersion  StringConfig `json:"appVersion"  yaml:"appVersion"`
-	Sha1Version StringConfig `json:"sha1Version" yaml:"sha1Version"`
-	BuildTime   StringConfig `json:"buildTime"   yaml:"buildTime"`
-	Profile     StringConfig `json:"profile"     yaml:"profile"`
-	Revision    StringConfig `json:"revision"    yaml:"revision"`
-	Port        StringConfig `json:"port"        yaml:"port"`
-	Config      ConfigSource `json:"config"      yaml:"config"`
-	Log         LogConfig    `json:"log"         yaml:"log"`
-	Db          DbConfig     `json:"db"          yaml:"db"`
-	RabbitMQ    QueueConfig  `json:"rabbitmq"    yaml:"rabbitmq"`
+	AppName     StringConfig    `json:"appName"     yaml:"appName"`
+	AppVersion  StringConfig    `json:"appVersion"  yaml:"appVersion"`
+	Sha1Version StringConfig    `json:"sha1Version" yaml:"sha1Version"`
+	BuildTime   StringConfig    `json:"buildTime"   yaml:"buildTime"`
+	Profile     StringConfig    `json:"profile"     yaml:"profile"`
+	Revision    StringConfig    `json:"revision"    yaml:"revision"`
+	Port        StringConfig    `json:"port"        yaml:"port"`
+	Config      ConfigSource    `json:"config"      yaml:"config"`
+	Log         LogConfig       `json:"log"         yaml:"log"`
+	Db          DbConfig        `json:"db"          yaml:"db"`
+	RabbitMQ    QueueConfig     `json:"rabbitmq"    yaml:"rabbitmq"`
+	Inventory   InventoryConfig `json:"inventory"   yaml:"inventory"`
 }
 
 type ConfigSource struct {
@@ -122,6 +123,8 @@ type QueueConfig struct {
 	Pass        StringConfig           `json:"pass"        yaml:"pass"`
 	Inventory   InventoryQueueConfig   `json:"inventory"   yaml:"inventory"`
 	Reservation ReservationQueueConfig `json:"reservation" yaml:"reservation"`
+	Returns     ReturnsQueueConfig     `json:"returns"     yaml:"returns"`
+	Freeze      FreezeQueueConfig      `json:"freeze"      yaml:"freeze"`
 	Product     ProductQueueConfig     `json:"product"     yaml:"product"`
 	Description string                 `json:"description" yaml:"description"`
 }
@@ -136,6 +139,16 @@ type ReservationQueueConfig struct {
 	Description string       `json:"description" yaml:"description"`
 }
 
+type ReturnsQueueConfig struct {
+	Exchange    StringConfig `json:"exchange" yaml:"exchange"`
+	Description string       `json:"description" yaml:"description"`
+}
+
+type FreezeQueueConfig struct {
+	Exchange    StringConfig `json:"exchange" yaml:"exchange"`
+	Description string       `json:"description" yaml:"description"`
+}
+
 type ProductQueueConfig struct {
 	Queue       StringConfig          `json:"queue" yaml:"queue"`
 	Dlt         ProductQueueDltConfig `json:"dlt"   yaml:"dlt"`
@@ -147,6 +160,15 @@ type ProductQueueDltConfig struct {
 	Description string       `json:"description" yaml:"description"`
 }
 
+type InventoryConfig struct {
+	CostingMethod     StringConfig `json:"costingMethod"   yaml:"costingMethod"`
+	SplitBackorders   BoolConfig   `json:"splitBackorders"   yaml:"splitBackorders"`
+	ApprovalThreshold IntConfig    `json:"approvalThreshold" yaml:"approvalThreshold"`
+	ReservationSla    IntConfig    `json:"reservationSla"    yaml:"reservationSla"`
+	SlaCheckInterval  IntConfig    `json:"slaCheckInterval"  yaml:"slaCheckInterval"`
+	Description       string       `json:"description"       yaml:"description"`
+}
+
 func (c *Config) Print() {
 	if c.Config.Print.Value {
 		log.Info().Interface("config", c).Msg("the following configurations have successfully loaded")
@@ -191,8 +213,16 @@ func init() {
 	viper.SetDefault("rabbitmq.pass", def.RabbitMQ.Pass.Default)
 	viper.SetDefault("rabbitmq.inventory.exchange", def.RabbitMQ.Inventory.Exchange.Default)
 	viper.SetDefault("rabbitmq.reservation.exchange", def.RabbitMQ.Reservation.Exchange.Default)
+	viper.SetDefault("rabbitmq.returns.exchange", def.RabbitMQ.Returns.Exchange.Default)
+	viper.SetDefault("rabbitmq.freeze.exchange", def.RabbitMQ.Freeze.Exchange.Default)
 	viper.SetDefault("rabbitmq.product.queue", def.RabbitMQ.Product.Queue.Default)
 	viper.SetDefault("rabbitmq.product.dlt.exchange", def.RabbitMQ.Product.Dlt.Exchange.Default)
+
+	viper.SetDefault("inventory.costingMethod", def.Inventory.CostingMethod.Default)
+	viper.SetDefault("inventory.splitBackorders", def.Inventory.SplitBackorders.Default)
+	viper.SetDefault("inventory.approvalThreshold", def.Inventory.ApprovalThreshold.Default)
+	viper.SetDefault("inventory.reservationSla", def.Inventory.ReservationSla.Default)
+	viper.SetDefault("inventory.slaCheckInterval", def.Inventory.SlaCheckInterval.Default)
 }
 
 func LoadDefaults() *Config {
@@ -489,9 +519,22 @@ func setupDefaults(config *Config) {
 	config.RabbitMQ.Reservation.Description = "RabbitMQ settings for reservation related updates."
 	config.RabbitMQ.Reservation.Exchange = StringConfig{Value: "reservation.exchange", Default: "reservation.exchange", Description: "RabbitMQ exchange to use for posting reservation updates."}
 
+	config.RabbitMQ.Returns.Description = "RabbitMQ settings for customer return updates."
+	config.RabbitMQ.Returns.Exchange = StringConfig{Value: "returns.exchange", Default: "returns.exchange", Description: "RabbitMQ exchange to use for posting customer return updates."}
+
+	config.RabbitMQ.Freeze.Description = "RabbitMQ settings for product freeze changes."
+	config.RabbitMQ.Freeze.Exchange = StringConfig{Value: "freeze.exchange", Default: "freeze.exchange", Description: "RabbitMQ exchange to use for posting product freeze changes."}
+
 	config.RabbitMQ.Product.Description = "RabbitMQ settings for product related updates."
 	config.RabbitMQ.Product.Queue = StringConfig{Value: "product.queue", Default: "product.queue", Description: "Queue used for listening to product updates coming from a theoretical product management system."}
 
 	config.RabbitMQ.Product.Dlt.Description = "Configurations for the product dead letter topic, where messages that fail to be read from the queue are written."
 	config.RabbitMQ.Product.Dlt.Exchange = StringConfig{Value: "product.dlt.exchange", Default: "product.dlt.exchange", Description: "Exchange used for posting messages to the dead letter topic."}
+
+	config.Inventory.Description = "Settings for how inventory is managed."
+	config.Inventory.CostingMethod = StringConfig{Value: "fifo", Default: "fifo", Description: "Method used to value inventory and compute the cost of goods allocated. Examples: fifo, average"}
+	config.Inventory.SplitBackorders = BoolConfig{Value: false, Default: false, Description: "When true a partly filled reservation is closed at the quantity reserved and the remainder is moved to a new backorder reservation. Reservation requests may override this."}
+	config.Inventory.ApprovalThreshold = IntConfig{Value: 0, Default: 0, Description: "Reservations of more than this quantity wait for an admin to approve them unless the product sets its own threshold. Zero never requires approval."}
+	config.Inventory.ReservationSla = IntConfig{Value: 0, Default: 0, Description: "Hours a reservation may stay open or pending approval before an alert is raised, unless the product sets its own SLA. Zero never raises alerts."}
+	config.Inventory.SlaCheckInterval = IntConfig{Value: 15, Default: 15, Description: "Minutes between checks for reservations open past their SLA."}
 }
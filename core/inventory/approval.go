@@ -0,0 +1,152 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidApproval is returned when a reservation cannot be approved or rejected as requested.
+var ErrInvalidApproval = errors.New("invalid approval")
+
+// requiresApproval reports whether a reservation of qty units of the product must wait for approval. The product's
+// own threshold takes precedence over the configured default, a threshold of zero never requires approval.
+func (s *service) requiresApproval(product Product, qty int64) bool {
+	threshold := product.ApprovalThreshold
+	if threshold == 0 {
+		threshold = s.approvalThreshold
+	}
+	return threshold > 0 && qty > threshold
+}
+
+// SetApprovalThreshold changes the quantity above which reservations of the product wait for approval. Reservations
+// already waiting are unaffected.
+func (s *service) SetApprovalThreshold(ctx context.Context, sku string, threshold int64) (Product, error) {
+	const funcName = "SetApprovalThreshold"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Int64("threshold", threshold).Msg("setting approval threshold")
+
+	if threshold < 0 {
+		return Product{}, errors.Wrap(ErrInvalidApproval, "approval threshold must not be negative")
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	product, err := s.repo.GetProduct(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	product.ApprovalThreshold = threshold
+	if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	return product, nil
+}
+
+// ApproveReservation opens a reservation that is waiting for approval, adds it to the product's open demand and fills
+// it from available inventory.
+func (s *service) ApproveReservation(ctx context.Context, ID uint64, approver string) (Reservation, error) {
+	const funcName = "ApproveReservation"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Str("approver", approver).Msg("approving reservation")
+
+	reservation, err := s.decideReservation(ctx, ID, Open, approver, "")
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	product, err := s.repo.GetProduct(ctx, reservation.Sku)
+	if err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+	if err = s.FillReserves(ctx, product); err != nil {
+		return Reservation{}, errors.WithMessage(err, "failed to fill reserves after approval")
+	}
+
+	return s.GetReservation(ctx, ID)
+}
+
+// RejectReservation rejects a reservation that is waiting for approval. It is never filled.
+func (s *service) RejectReservation(ctx context.Context, ID uint64, approver, reason string) (Reservation, error) {
+	const funcName = "RejectReservation"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Str("approver", approver).Msg("rejecting reservation")
+
+	return s.decideReservation(ctx, ID, Rejected, approver, reason)
+}
+
+func (s *service) decideReservation(ctx context.Context, ID uint64, state ReserveState, approver, reason string) (Reservation, error) {
+	if approver == "" {
+		return Reservation{}, errors.Wrap(ErrInvalidApproval, "approver is required")
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	reservation, err := s.repo.GetReservation(ctx, ID, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+	if reservation.State != PendingApproval {
+		err = errors.Wrapf(ErrInvalidApproval, "reservation %d is %s, not waiting for approval", ID, reservation.State)
+		return Reservation{}, err
+	}
+
+	now := time.Now()
+	reservation.State = state
+	reservation.DecidedBy = approver
+	reservation.Decided = &now
+	reservation.DecisionReason = reason
+	if err = s.repo.DecideReservation(ctx, reservation, core.UpdateOptions{Tx: tx}); err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+
+	var productInventory ProductInventory
+	if state == Open {
+		productInventory, err = s.repo.GetProductInventory(ctx, reservation.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+		if err != nil {
+			return Reservation{}, errors.WithStack(err)
+		}
+		productInventory.OpenDemand += reservation.RequestedQuantity
+		if err = s.repo.SaveProductInventory(ctx, productInventory, core.UpdateOptions{Tx: tx}); err != nil {
+			return Reservation{}, errors.WithStack(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+
+	if state == Open {
+		if err = s.publishInventory(ctx, productInventory); err != nil {
+			return Reservation{}, errors.WithStack(err)
+		}
+	}
+	if err = s.publishReservation(ctx, reservation); err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+	return reservation, nil
+}
@@ -0,0 +1,187 @@
+package inventory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidPlan is returned when planned production fails validation.
+var ErrInvalidPlan = errors.New("invalid planned production")
+
+const atpReservationPage = 100
+
+// PlanProduction schedules production of a SKU to be counted as supply when calculating available to promise. Planned
+// production is not received into inventory, it should be cancelled once the production it stands for is produced.
+func (s *service) PlanProduction(ctx context.Context, plan PlannedProduction) (PlannedProduction, error) {
+	const funcName = "PlanProduction"
+
+	if plan.Quantity < 1 {
+		return PlannedProduction{}, errors.Wrap(ErrInvalidPlan, "quantity must be greater than zero")
+	}
+	if plan.Due.IsZero() {
+		return PlannedProduction{}, errors.Wrap(ErrInvalidPlan, "due date is required")
+	}
+
+	product, err := s.repo.GetProduct(ctx, plan.Sku)
+	if err != nil {
+		return PlannedProduction{}, errors.WithStack(err)
+	}
+	if product.Type == Kit {
+		return PlannedProduction{}, errors.Wrap(ErrInvalidPlan, "kits cannot be produced, plan production of their components instead")
+	}
+
+	log.Debug().Str("func", funcName).Str("sku", plan.Sku).Int64("quantity", plan.Quantity).Time("due", plan.Due).Msg("planning production")
+
+	plan.Created = time.Now()
+	if err = s.repo.SavePlannedProduction(ctx, &plan); err != nil {
+		return PlannedProduction{}, errors.WithStack(err)
+	}
+	return plan, nil
+}
+
+func (s *service) GetPlannedProduction(ctx context.Context, sku string) ([]PlannedProduction, error) {
+	const funcName = "GetPlannedProduction"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Msg("getting planned production")
+
+	plans, err := s.repo.GetPlannedProduction(ctx, sku)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return plans, nil
+}
+
+func (s *service) CancelPlannedProduction(ctx context.Context, sku string, ID uint64) error {
+	const funcName = "CancelPlannedProduction"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Uint64("id", ID).Msg("cancelling planned production")
+
+	if err := s.repo.DeletePlannedProduction(ctx, sku, ID); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// GetAvailableToPromise allocates current available stock followed by planned production and the outstanding lines of
+// open purchase orders, in order of when they are due, to open reservations in the order FillReserves fills them. The
+// first period of the profile is the stock available now, supply that is past due is expected now as well.
+func (s *service) GetAvailableToPromise(ctx context.Context, sku string) (AvailableToPromise, error) {
+	const funcName = "GetAvailableToPromise"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Msg("getting available to promise")
+
+	pi, err := s.GetProductInventory(ctx, sku)
+	if err != nil {
+		return AvailableToPromise{}, err
+	}
+
+	reservations, err := s.openReservations(ctx, sku)
+	if err != nil {
+		return AvailableToPromise{}, err
+	}
+
+	plans, err := s.repo.GetPlannedProduction(ctx, sku)
+	if err != nil {
+		return AvailableToPromise{}, errors.WithStack(err)
+	}
+
+	inbound, err := s.repo.GetInboundLines(ctx, sku)
+	if err != nil {
+		return AvailableToPromise{}, errors.WithStack(err)
+	}
+	for _, line := range inbound {
+		plans = append(plans, PlannedProduction{Sku: sku, Quantity: line.Outstanding(), Due: line.Expected})
+	}
+	sort.SliceStable(plans, func(i, j int) bool { return plans[i].Due.Before(plans[j].Due) })
+
+	now := time.Now()
+	atp := AvailableToPromise{
+		Sku:          sku,
+		Available:    pi.Available,
+		Profile:      make([]AtpPeriod, 0, len(plans)+1),
+		Reservations: make([]ReservationPromise, 0, len(reservations)),
+	}
+
+	for _, r := range reservations {
+		outstanding := r.RequestedQuantity - r.ReservedQuantity
+		if outstanding <= 0 {
+			continue
+		}
+		atp.OpenDemand += outstanding
+		atp.Reservations = append(atp.Reservations, ReservationPromise{
+			ReservationID: r.ID,
+			RequestID:     r.RequestID,
+			Requester:     r.Requester,
+			Created:       r.Created,
+			Outstanding:   outstanding,
+		})
+	}
+
+	supply := []AtpPeriod{{Date: now, Receipts: pi.Available}}
+	for _, plan := range plans {
+		due := plan.Due
+		if due.Before(now) {
+			due = now
+		}
+		if last := &supply[len(supply)-1]; last.Date.Equal(due) {
+			last.Receipts += plan.Quantity
+			continue
+		}
+		supply = append(supply, AtpPeriod{Date: due, Receipts: plan.Quantity})
+	}
+
+	next := 0
+	unfilled := make([]int64, len(atp.Reservations))
+	for i, promise := range atp.Reservations {
+		unfilled[i] = promise.Outstanding
+	}
+
+	free := int64(0)
+	for _, period := range supply {
+		remaining := period.Receipts
+		for remaining > 0 && next < len(unfilled) {
+			take := unfilled[next]
+			if take > remaining {
+				take = remaining
+			}
+			unfilled[next] -= take
+			remaining -= take
+			period.Allocated += take
+
+			if unfilled[next] == 0 {
+				date := period.Date
+				atp.Reservations[next].FillDate = &date
+				next++
+			}
+		}
+		free += remaining
+		period.Available = free
+		atp.Profile = append(atp.Profile, period)
+	}
+
+	for ; next < len(unfilled); next++ {
+		atp.Shortfall += unfilled[next]
+	}
+
+	return atp, nil
+}
+
+// openReservations gets every open reservation for the SKU in the order they are filled.
+func (s *service) openReservations(ctx context.Context, sku string) ([]Reservation, error) {
+	reservations := make([]Reservation, 0)
+	for offset := 0; ; offset += atpReservationPage {
+		page, err := s.repo.GetReservations(ctx, GetReservationsOptions{Sku: sku, State: Open}, atpReservationPage, offset)
+		if err != nil && !errors.Is(err, core.ErrNotFound) {
+			return nil, errors.WithStack(err)
+		}
+		reservations = append(reservations, page...)
+		if len(page) < atpReservationPage {
+			return reservations, nil
+		}
+	}
+}
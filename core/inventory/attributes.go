@@ -0,0 +1,185 @@
+package inventory
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidAttribute is returned when an attribute value does not match its definition.
+var ErrInvalidAttribute = errors.New("inventory: invalid attribute")
+
+func (s *service) DefineAttribute(ctx context.Context, definition AttributeDefinition) error {
+	const funcName = "DefineAttribute"
+
+	if definition.Name == "" {
+		return errors.Wrap(ErrInvalidAttribute, "attribute name is required")
+	}
+	if _, err := ParseAttributeType(string(definition.Type)); err != nil {
+		return errors.Wrap(ErrInvalidAttribute, err.Error())
+	}
+
+	log.Debug().Str("func", funcName).Str("name", definition.Name).Str("type", string(definition.Type)).Msg("defining attribute")
+
+	if err := s.repo.SaveAttributeDefinition(ctx, definition); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (s *service) GetAttributeDefinitions(ctx context.Context) ([]AttributeDefinition, error) {
+	const funcName = "GetAttributeDefinitions"
+
+	log.Debug().Str("func", funcName).Msg("getting attribute definitions")
+
+	definitions, err := s.repo.GetAttributeDefinitions(ctx)
+	if err != nil {
+		return definitions, errors.WithStack(err)
+	}
+	return definitions, nil
+}
+
+// UpdateProductAttributes replaces the attributes of a product.
+func (s *service) UpdateProductAttributes(ctx context.Context, sku string, attributes Attributes) (Product, error) {
+	const funcName = "UpdateProductAttributes"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Int("attributes", len(attributes)).Msg("updating product attributes")
+
+	if err := s.validateAttributes(ctx, attributes); err != nil {
+		return Product{}, err
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	product, err := s.repo.GetProduct(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	product.Attributes = attributes
+	if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	return product, nil
+}
+
+func (s *service) validateAttributes(ctx context.Context, attributes Attributes) error {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	definitions, err := s.attributeDefinitions(ctx)
+	if err != nil {
+		return err
+	}
+	return checkAttributes(attributes, definitions)
+}
+
+// typedAttributeFilter converts attribute filter values, which arrive as strings, to the type of their definition so
+// that they match the stored JSON values. Undefined attributes are matched as strings.
+func (s *service) typedAttributeFilter(ctx context.Context, filter Attributes) (Attributes, error) {
+	if len(filter) == 0 {
+		return filter, nil
+	}
+
+	definitions, err := s.attributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return coerceAttributes(filter, definitions)
+}
+
+func checkAttributes(attributes Attributes, definitions map[string]AttributeType) error {
+	for name, value := range attributes {
+		if name == "" {
+			return errors.Wrap(ErrInvalidAttribute, "attribute name is required")
+		}
+		attrType, ok := definitions[name]
+		if !ok {
+			continue
+		}
+		if !isAttributeType(value, attrType) {
+			return errors.Wrapf(ErrInvalidAttribute, "attribute %s must be a %s", name, attrType)
+		}
+	}
+	return nil
+}
+
+// coerceAttributes converts string values to the type of their definition. Values of undefined attributes and values
+// that are not strings are left as they are.
+func coerceAttributes(attributes Attributes, definitions map[string]AttributeType) (Attributes, error) {
+	typed := make(Attributes)
+	for name, value := range attributes {
+		str, ok := value.(string)
+		if !ok {
+			typed[name] = value
+			continue
+		}
+
+		switch definitions[name] {
+		case AttributeNumber:
+			n, err := strconv.ParseFloat(str, 64)
+			if err != nil {
+				return nil, errors.Wrapf(ErrInvalidAttribute, "attribute %s must be a number", name)
+			}
+			typed[name] = n
+		case AttributeBoolean:
+			b, err := strconv.ParseBool(str)
+			if err != nil {
+				return nil, errors.Wrapf(ErrInvalidAttribute, "attribute %s must be a boolean", name)
+			}
+			typed[name] = b
+		default:
+			typed[name] = str
+		}
+	}
+	return typed, nil
+}
+
+func (s *service) attributeDefinitions(ctx context.Context) (map[string]AttributeType, error) {
+	definitions, err := s.repo.GetAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	types := make(map[string]AttributeType)
+	for _, d := range definitions {
+		types[d.Name] = d.Type
+	}
+	return types, nil
+}
+
+func isAttributeType(value interface{}, attrType AttributeType) bool {
+	switch attrType {
+	case AttributeString:
+		_, ok := value.(string)
+		return ok
+	case AttributeNumber:
+		switch value.(type) {
+		case float64, float32, int, int32, int64:
+			return true
+		}
+		return false
+	case AttributeBoolean:
+		_, ok := value.(bool)
+		return ok
+	default:
+		return true
+	}
+}
@@ -0,0 +1,42 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// splitBackorder closes a partly filled reservation that is marked for splitting at the quantity reserved so far and
+// saves a backorder for the remainder, linked to it by ParentID. The backorder keeps the reservation's created time so
+// it holds its place in line. A zero reservation is returned when the reservation is not split.
+func (s *service) splitBackorder(ctx context.Context, reservation *Reservation, tx core.Transaction) (Reservation, error) {
+	remaining := reservation.RequestedQuantity - reservation.ReservedQuantity
+	if !reservation.SplitBackorder || reservation.ReservedQuantity == 0 || remaining == 0 {
+		return Reservation{}, nil
+	}
+
+	backorder := Reservation{
+		RequestID:         fmt.Sprintf("%s-bo%d", reservation.RequestID, reservation.ID),
+		Requester:         reservation.Requester,
+		Sku:               reservation.Sku,
+		State:             Open,
+		RequestedQuantity: remaining,
+		Created:           reservation.Created,
+		AllowSubstitutes:  reservation.AllowSubstitutes,
+		ParentID:          reservation.ID,
+		SplitBackorder:    true,
+	}
+	if err := s.repo.SaveReservation(ctx, &backorder, core.UpdateOptions{Tx: tx}); err != nil {
+		return Reservation{}, errors.WithMessagef(err, "failed to save backorder for %s", reservation.RequestID)
+	}
+
+	reservation.RequestedQuantity = reservation.ReservedQuantity
+	reservation.State = Closed
+	if err := s.repo.UpdateReservationRequested(ctx, reservation.ID, reservation.RequestedQuantity, core.UpdateOptions{Tx: tx}); err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+
+	return backorder, nil
+}
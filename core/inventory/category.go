@@ -0,0 +1,209 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidCategory is returned when a category change would leave the category tree in an invalid state.
+var ErrInvalidCategory = errors.New("inventory: invalid category")
+
+func (s *service) CreateCategory(ctx context.Context, category Category) (Category, error) {
+	const funcName = "CreateCategory"
+
+	if category.Name == "" {
+		return Category{}, errors.Wrap(ErrInvalidCategory, "name is required")
+	}
+	category.ID = 0
+	if err := s.validateCategory(ctx, category.ParentID); err != nil {
+		return Category{}, err
+	}
+
+	log.Debug().Str("func", funcName).Str("name", category.Name).Uint64("parentId", category.ParentID).Msg("creating category")
+
+	if err := s.repo.SaveCategory(ctx, &category); err != nil {
+		return Category{}, errors.WithStack(err)
+	}
+	return category, nil
+}
+
+// UpdateCategory renames or moves a category. A category cannot be moved underneath itself or one of its descendants.
+func (s *service) UpdateCategory(ctx context.Context, category Category) (Category, error) {
+	const funcName = "UpdateCategory"
+
+	if category.Name == "" {
+		return Category{}, errors.Wrap(ErrInvalidCategory, "name is required")
+	}
+
+	log.Debug().Str("func", funcName).Uint64("id", category.ID).Uint64("parentId", category.ParentID).Msg("updating category")
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Category{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	if _, err = s.repo.GetCategory(ctx, category.ID, core.QueryOptions{Tx: tx, ForUpdate: true}); err != nil {
+		return Category{}, errors.WithStack(err)
+	}
+
+	for parentID := category.ParentID; parentID != 0; {
+		if parentID == category.ID {
+			err = errors.Wrap(ErrInvalidCategory, "a category cannot be its own ancestor")
+			return Category{}, err
+		}
+
+		var parent Category
+		parent, err = s.repo.GetCategory(ctx, parentID, core.QueryOptions{Tx: tx})
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				err = errors.Wrapf(ErrInvalidCategory, "parent category %d does not exist", parentID)
+			}
+			return Category{}, errors.WithStack(err)
+		}
+		parentID = parent.ParentID
+	}
+
+	if err = s.repo.SaveCategory(ctx, &category, core.UpdateOptions{Tx: tx}); err != nil {
+		return Category{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Category{}, errors.WithStack(err)
+	}
+
+	return category, nil
+}
+
+// DeleteCategory removes an empty branch of the tree. Products in the category are left uncategorized.
+func (s *service) DeleteCategory(ctx context.Context, ID uint64) error {
+	const funcName = "DeleteCategory"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Msg("deleting category")
+
+	children, err := s.repo.GetChildCategories(ctx, ID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(children) > 0 {
+		return errors.Wrap(ErrInvalidCategory, "category has child categories")
+	}
+
+	if err = s.repo.DeleteCategory(ctx, ID); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (s *service) GetCategory(ctx context.Context, ID uint64) (Category, error) {
+	const funcName = "GetCategory"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Msg("getting category")
+
+	category, err := s.repo.GetCategory(ctx, ID)
+	if err != nil {
+		return category, errors.WithStack(err)
+	}
+	return category, nil
+}
+
+func (s *service) GetCategories(ctx context.Context, limit, offset int) ([]Category, error) {
+	const funcName = "GetCategories"
+
+	log.Debug().Str("func", funcName).Int("limit", limit).Int("offset", offset).Msg("getting categories")
+
+	categories, err := s.repo.GetCategories(ctx, limit, offset)
+	if err != nil {
+		return categories, errors.WithStack(err)
+	}
+	return categories, nil
+}
+
+func (s *service) GetChildCategories(ctx context.Context, ID uint64) ([]Category, error) {
+	const funcName = "GetChildCategories"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Msg("getting child categories")
+
+	categories, err := s.repo.GetChildCategories(ctx, ID)
+	if err != nil {
+		return categories, errors.WithStack(err)
+	}
+	return categories, nil
+}
+
+// GetCategoryInventory rolls up the inventory of every product in the category and all of its descendants.
+func (s *service) GetCategoryInventory(ctx context.Context, ID uint64) (CategoryInventory, error) {
+	const funcName = "GetCategoryInventory"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Msg("getting category inventory")
+
+	category, err := s.repo.GetCategory(ctx, ID)
+	if err != nil {
+		return CategoryInventory{}, errors.WithStack(err)
+	}
+
+	ci, err := s.repo.GetCategoryInventory(ctx, ID)
+	if err != nil {
+		return CategoryInventory{}, errors.WithStack(err)
+	}
+	ci.Category = category
+	return ci, nil
+}
+
+// AssignProductCategory moves a product into a category. A category ID of zero leaves the product uncategorized.
+func (s *service) AssignProductCategory(ctx context.Context, sku string, categoryID uint64) (Product, error) {
+	const funcName = "AssignProductCategory"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Uint64("categoryId", categoryID).Msg("assigning product category")
+
+	if err := s.validateCategory(ctx, categoryID); err != nil {
+		return Product{}, err
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	product, err := s.repo.GetProduct(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	product.CategoryID = categoryID
+	if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	return product, nil
+}
+
+func (s *service) validateCategory(ctx context.Context, ID uint64) error {
+	if ID == 0 {
+		return nil
+	}
+
+	if _, err := s.repo.GetCategory(ctx, ID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return errors.Wrapf(ErrInvalidCategory, "category %d does not exist", ID)
+		}
+		return errors.WithStack(err)
+	}
+	return nil
+}
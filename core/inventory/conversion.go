@@ -0,0 +1,247 @@
+package inventory
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidConversion is returned when stock cannot be converted from one SKU to another as requested.
+var ErrInvalidConversion = errors.New("invalid conversion")
+
+// Convert reworks or relabels available stock of the source SKU into the target SKU in a single transaction. The cost
+// relieved from the source is carried to the target, both changes are recorded as adjustments and open reservations of
+// the target are filled with the new stock, which becomes a lot of its own. A repeated request ID returns the original
+// conversion.
+func (s *service) Convert(ctx context.Context, cr ConversionRequest, user string) (Conversion, error) {
+	const funcName = "Convert"
+
+	log.Debug().
+		Str("func", funcName).
+		Str("requestId", cr.RequestID).
+		Str("sourceSku", cr.SourceSku).
+		Str("targetSku", cr.TargetSku).
+		Int64("quantity", cr.Quantity).
+		Float64("ratio", cr.Ratio).
+		Msg("converting inventory")
+
+	targetQty, err := validateConversionRequest(cr, user)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	conversion, err := s.repo.GetConversionByRequestID(ctx, cr.RequestID)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		return Conversion{}, errors.WithStack(err)
+	}
+	if conversion.RequestID != "" {
+		log.Debug().Str("func", funcName).Str("requestId", cr.RequestID).Msg("conversion already exists")
+		return conversion, nil
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Conversion{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	target, err := s.conversionProduct(ctx, cr.TargetSku, tx)
+	if err != nil {
+		return Conversion{}, err
+	}
+	sourceProduct, err := s.conversionProduct(ctx, cr.SourceSku, tx)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	counted, err := s.repo.GetFrozenSkus(ctx, []string{cr.SourceSku, cr.TargetSku}, core.QueryOptions{Tx: tx})
+	if err != nil {
+		return Conversion{}, errors.WithStack(err)
+	}
+	if len(counted) > 0 {
+		err = errors.Wrap(ErrInvalidConversion, "inventory is being counted")
+		return Conversion{}, err
+	}
+	if sourceProduct.Frozen(FreezeAllocation) {
+		err = errors.Wrapf(ErrFrozen, "allocation of %s is frozen: %s", sourceProduct.Sku, sourceProduct.Freezes[FreezeAllocation].Reason)
+		return Conversion{}, err
+	}
+	if target.Frozen(FreezeIntake) {
+		err = errors.Wrapf(ErrFrozen, "intake of %s is frozen: %s", target.Sku, target.Freezes[FreezeIntake].Reason)
+		return Conversion{}, err
+	}
+
+	source, dest, err := s.lockConversionInventory(ctx, cr.SourceSku, cr.TargetSku, tx)
+	if err != nil {
+		return Conversion{}, err
+	}
+	if source.Available < cr.Quantity {
+		err = errors.Wrapf(ErrInvalidConversion, "only %d of %s is available", source.Available, cr.SourceSku)
+		return Conversion{}, err
+	}
+
+	conversion = Conversion{
+		RequestID:      cr.RequestID,
+		SourceSku:      cr.SourceSku,
+		SourceQuantity: cr.Quantity,
+		TargetSku:      cr.TargetSku,
+		TargetQuantity: targetQty,
+		Ratio:          cr.Ratio,
+		User:           user,
+		Created:        time.Now(),
+	}
+
+	conversion.Cost, err = s.relieveCost(ctx, cr.SourceSku, cr.Quantity, ValuationConversion, cr.RequestID, tx)
+	if err != nil {
+		err = errors.WithMessage(err, "failed to relieve conversion cost")
+		return Conversion{}, err
+	}
+	if err = s.consumeLots(ctx, cr.SourceSku, cr.Quantity, 0, tx); err != nil {
+		err = errors.WithMessage(err, "failed to write off converted production lots")
+		return Conversion{}, err
+	}
+	source.OnHand -= cr.Quantity
+	source.Available -= cr.Quantity
+
+	event := ProductionEvent{
+		RequestID: cr.RequestID,
+		Sku:       cr.TargetSku,
+		Quantity:  targetQty,
+		UnitCost:  conversion.Cost / float64(targetQty),
+		Remaining: targetQty,
+		Created:   conversion.Created,
+	}
+	if dest.RequiresInspection {
+		event.Held = targetQty
+	}
+	if err = s.repo.SaveProductionEvent(ctx, &event, core.UpdateOptions{Tx: tx}); err != nil {
+		err = errors.WithMessage(err, "failed to save converted lot")
+		return Conversion{}, err
+	}
+	if err = s.receiveCost(ctx, event, ValuationConversion, tx); err != nil {
+		err = errors.WithMessage(err, "failed to receive conversion cost")
+		return Conversion{}, err
+	}
+	addProduced(&dest, targetQty)
+
+	for _, pi := range []ProductInventory{source, dest} {
+		if err = s.repo.SaveProductInventory(ctx, pi, core.UpdateOptions{Tx: tx}); err != nil {
+			return Conversion{}, errors.WithStack(err)
+		}
+	}
+
+	adjustments := []InventoryAdjustment{
+		{Sku: cr.SourceSku, Quantity: -cr.Quantity},
+		{Sku: cr.TargetSku, Quantity: targetQty},
+	}
+	for _, adjustment := range adjustments {
+		adjustment.Reason = AdjustmentConversion
+		adjustment.Reference = cr.RequestID
+		adjustment.User = user
+		adjustment.Created = conversion.Created
+		if err = s.repo.SaveAdjustment(ctx, &adjustment, core.UpdateOptions{Tx: tx}); err != nil {
+			return Conversion{}, errors.WithStack(err)
+		}
+	}
+
+	if err = s.repo.SaveConversion(ctx, &conversion, core.UpdateOptions{Tx: tx}); err != nil {
+		return Conversion{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Conversion{}, errors.WithStack(err)
+	}
+
+	if err = s.publishInventory(ctx, source); err != nil {
+		return Conversion{}, errors.WithMessage(err, "failed to publish inventory")
+	}
+	if err = s.refreshKits(ctx, cr.SourceSku); err != nil {
+		return Conversion{}, errors.WithMessage(err, "failed to refresh kits after conversion")
+	}
+	if err = s.releaseProduction(ctx, target, dest); err != nil {
+		return Conversion{}, err
+	}
+	return conversion, nil
+}
+
+// validateConversionRequest checks the request and returns the quantity of the target it produces, which must be a
+// whole number of units.
+func validateConversionRequest(cr ConversionRequest, user string) (int64, error) {
+	if cr.RequestID == "" {
+		return 0, errors.Wrap(ErrInvalidConversion, "request id is required")
+	}
+	if cr.SourceSku == "" || cr.TargetSku == "" {
+		return 0, errors.Wrap(ErrInvalidConversion, "source and target sku are required")
+	}
+	if cr.SourceSku == cr.TargetSku {
+		return 0, errors.Wrap(ErrInvalidConversion, "source and target sku must differ")
+	}
+	if cr.Quantity < 1 {
+		return 0, errors.Wrap(ErrInvalidConversion, "quantity must be greater than zero")
+	}
+	if cr.Ratio <= 0 {
+		return 0, errors.Wrap(ErrInvalidConversion, "ratio must be greater than zero")
+	}
+	if user == "" {
+		return 0, errors.Wrap(ErrInvalidConversion, "user is required")
+	}
+
+	exact := float64(cr.Quantity) * cr.Ratio
+	targetQty := math.Round(exact)
+	if targetQty < 1 || math.Abs(exact-targetQty) > 1e-6 {
+		return 0, errors.Wrapf(ErrInvalidConversion, "%d at a ratio of %g is not a whole quantity", cr.Quantity, cr.Ratio)
+	}
+	return int64(targetQty), nil
+}
+
+func (s *service) conversionProduct(ctx context.Context, sku string, tx core.Transaction) (Product, error) {
+	product, err := s.repo.GetProduct(ctx, sku, core.QueryOptions{Tx: tx})
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return Product{}, errors.Wrapf(ErrInvalidConversion, "product %s does not exist", sku)
+		}
+		return Product{}, errors.WithStack(err)
+	}
+	if product.Type == Kit {
+		return Product{}, errors.Wrapf(ErrInvalidConversion, "%s is a kit and has no stock of its own", sku)
+	}
+	return product, nil
+}
+
+// lockConversionInventory locks the inventory of both SKUs in SKU order so that opposing conversions cannot deadlock.
+func (s *service) lockConversionInventory(ctx context.Context, sourceSku, targetSku string, tx core.Transaction) (source, target ProductInventory, err error) {
+	skus := []string{sourceSku, targetSku}
+	if targetSku < sourceSku {
+		skus = []string{targetSku, sourceSku}
+	}
+
+	locked := make(map[string]ProductInventory)
+	for _, sku := range skus {
+		pi, err := s.repo.GetProductInventory(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+		if err != nil {
+			return ProductInventory{}, ProductInventory{}, errors.WithStack(err)
+		}
+		locked[sku] = pi
+	}
+	return locked[sourceSku], locked[targetSku], nil
+}
+
+func (s *service) GetConversions(ctx context.Context, sku string, limit, offset int) ([]Conversion, error) {
+	const funcName = "GetConversions"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Int("limit", limit).Int("offset", offset).Msg("getting conversions")
+
+	conversions, err := s.repo.GetConversions(ctx, sku, limit, offset)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return conversions, nil
+}
@@ -0,0 +1,205 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// receiveCost adds the cost of a production event to the product's cost layers. Under weighted average costing the
+// existing layers are folded into the new one so that there is only ever a single layer at the average unit cost.
+func (s *service) receiveCost(ctx context.Context, event ProductionEvent, reason ValuationReason, tx core.Transaction) error {
+	const funcName = "receiveCost"
+
+	layers, err := s.repo.GetCostLayers(ctx, event.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	balanceQty, balanceValue := layerTotals(layers)
+
+	layer := CostLayer{
+		Sku:               event.Sku,
+		ProductionEventID: event.ID,
+		UnitCost:          event.UnitCost,
+		Quantity:          event.Quantity,
+		Remaining:         event.Quantity,
+		Created:           event.Created,
+	}
+
+	if s.costingMethod == WeightedAverage && balanceQty > 0 {
+		for _, l := range layers {
+			if err = s.repo.UpdateCostLayer(ctx, l.ID, 0, core.UpdateOptions{Tx: tx}); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		layer.Quantity = balanceQty + event.Quantity
+		layer.Remaining = layer.Quantity
+		layer.UnitCost = (balanceValue + float64(event.Quantity)*event.UnitCost) / float64(layer.Quantity)
+	}
+
+	log.Debug().
+		Str("func", funcName).
+		Str("sku", event.Sku).
+		Str("method", string(s.costingMethod)).
+		Float64("unitCost", layer.UnitCost).
+		Msg("saving cost layer")
+
+	if err = s.repo.SaveCostLayer(ctx, &layer, core.UpdateOptions{Tx: tx}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	cost := float64(event.Quantity) * event.UnitCost
+	entry := ValuationEntry{
+		Sku:             event.Sku,
+		Method:          s.costingMethod,
+		Reason:          reason,
+		Reference:       event.RequestID,
+		Quantity:        event.Quantity,
+		Cost:            cost,
+		BalanceQuantity: balanceQty + event.Quantity,
+		BalanceValue:    balanceValue + cost,
+		Created:         event.Created,
+	}
+	if err = s.repo.SaveValuationEntry(ctx, &entry, core.UpdateOptions{Tx: tx}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// relieveCost removes qty units from the product's cost layers, oldest first, and returns the cost of the goods
+// removed. Inventory produced before costing was tracked has no layers and is relieved at no cost.
+func (s *service) relieveCost(ctx context.Context, sku string, qty int64, reason ValuationReason, reference string, tx core.Transaction) (float64, error) {
+	return s.relieveLotCost(ctx, sku, qty, nil, reason, reference, tx)
+}
+
+// relieveLotCost removes qty units from the product's cost layers and returns the cost of the goods removed. Units
+// known to come from particular production lots, keyed by production event ID, are relieved from the layers of those
+// lots first and the rest from the oldest layers.
+func (s *service) relieveLotCost(ctx context.Context, sku string, qty int64, lots map[uint64]int64, reason ValuationReason, reference string, tx core.Transaction) (float64, error) {
+	const funcName = "relieveCost"
+
+	layers, err := s.repo.GetCostLayers(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	balanceQty, balanceValue := layerTotals(layers)
+
+	remaining := make([]int64, len(layers))
+	for i, layer := range layers {
+		remaining[i] = layer.Remaining
+	}
+
+	var cost float64
+	relieved := int64(0)
+	relieve := func(i int, want int64) {
+		take := qty - relieved
+		if take > want {
+			take = want
+		}
+		if take > remaining[i] {
+			take = remaining[i]
+		}
+		remaining[i] -= take
+		relieved += take
+		cost += float64(take) * layers[i].UnitCost
+	}
+	for i, layer := range layers {
+		if want := lots[layer.ProductionEventID]; want > 0 {
+			relieve(i, want)
+		}
+	}
+	for i := range layers {
+		if relieved == qty {
+			break
+		}
+		relieve(i, qty-relieved)
+	}
+
+	for i, layer := range layers {
+		if remaining[i] == layer.Remaining {
+			continue
+		}
+		if err = s.repo.UpdateCostLayer(ctx, layer.ID, remaining[i], core.UpdateOptions{Tx: tx}); err != nil {
+			return 0, errors.WithStack(err)
+		}
+	}
+
+	log.Debug().
+		Str("func", funcName).
+		Str("sku", sku).
+		Str("reference", reference).
+		Int64("relieved", relieved).
+		Float64("cost", cost).
+		Msg("relieved cost")
+
+	entry := ValuationEntry{
+		Sku:             sku,
+		Method:          s.costingMethod,
+		Reason:          reason,
+		Reference:       reference,
+		Quantity:        -relieved,
+		Cost:            -cost,
+		BalanceQuantity: balanceQty - relieved,
+		BalanceValue:    balanceValue - cost,
+		Created:         time.Now(),
+	}
+	if err = s.repo.SaveValuationEntry(ctx, &entry, core.UpdateOptions{Tx: tx}); err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return cost, nil
+}
+
+func layerTotals(layers []CostLayer) (qty int64, value float64) {
+	for _, layer := range layers {
+		qty += layer.Remaining
+		value += float64(layer.Remaining) * layer.UnitCost
+	}
+	return qty, value
+}
+
+func (s *service) GetInventoryValuation(ctx context.Context, limit, offset int) (InventoryValuation, error) {
+	const funcName = "GetInventoryValuation"
+
+	log.Debug().Str("func", funcName).Int("limit", limit).Int("offset", offset).Msg("getting inventory valuation")
+
+	products, err := s.repo.GetValuations(ctx, limit, offset)
+	if err != nil {
+		return InventoryValuation{}, errors.WithStack(err)
+	}
+
+	total, err := s.repo.GetTotalValuation(ctx)
+	if err != nil {
+		return InventoryValuation{}, errors.WithStack(err)
+	}
+
+	return InventoryValuation{Method: s.costingMethod, Total: total, Products: products}, nil
+}
+
+func (s *service) GetValuation(ctx context.Context, sku string) (Valuation, error) {
+	const funcName = "GetValuation"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Msg("getting valuation")
+
+	valuation, err := s.repo.GetValuation(ctx, sku)
+	if err != nil {
+		return valuation, errors.WithStack(err)
+	}
+	return valuation, nil
+}
+
+func (s *service) GetValuationHistory(ctx context.Context, sku string, limit, offset int) ([]ValuationEntry, error) {
+	const funcName = "GetValuationHistory"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Msg("getting valuation history")
+
+	entries, err := s.repo.GetValuationHistory(ctx, sku, limit, offset)
+	if err != nil {
+		return entries, errors.WithStack(err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,414 @@
+package inventory
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidCount is returned when a count session cannot be started, recorded or closed as requested.
+var ErrInvalidCount = errors.New("invalid count")
+
+const maxCountSkus = 500
+
+// StartCount opens a count session for the SKUs, freezing their allocation until the session is approved or
+// cancelled. A SKU can only be counted in one open session at a time.
+func (s *service) StartCount(ctx context.Context, skus []string) (CountSession, error) {
+	const funcName = "StartCount"
+
+	if len(skus) == 0 {
+		return CountSession{}, errors.Wrap(ErrInvalidCount, "at least one sku is required")
+	}
+	if len(skus) > maxCountSkus {
+		return CountSession{}, errors.Wrapf(ErrInvalidCount, "no more than %d skus may be counted at once", maxCountSkus)
+	}
+
+	session := CountSession{State: CountOpen, Created: time.Now(), Lines: make([]CountLine, 0, len(skus))}
+	seen := make(map[string]bool)
+	for _, sku := range skus {
+		if sku == "" {
+			return CountSession{}, errors.Wrap(ErrInvalidCount, "sku is required")
+		}
+		if seen[sku] {
+			return CountSession{}, errors.Wrapf(ErrInvalidCount, "%s is listed more than once", sku)
+		}
+		seen[sku] = true
+
+		product, err := s.repo.GetProduct(ctx, sku)
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				return CountSession{}, errors.Wrapf(ErrInvalidCount, "product %s does not exist", sku)
+			}
+			return CountSession{}, errors.WithStack(err)
+		}
+		if product.Type == Kit {
+			return CountSession{}, errors.Wrapf(ErrInvalidCount, "%s is a kit, count its components instead", sku)
+		}
+		session.Lines = append(session.Lines, CountLine{Sku: sku})
+	}
+
+	log.Debug().Str("func", funcName).Strs("skus", skus).Msg("starting count")
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	// Locking the inventory waits for any allocation already in progress and keeps concurrent counts from starting
+	// for the same SKUs.
+	for _, sku := range skus {
+		if _, err = s.repo.GetProductInventory(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true}); err != nil {
+			return CountSession{}, errors.WithStack(err)
+		}
+	}
+
+	frozen, err := s.repo.GetFrozenSkus(ctx, skus, core.QueryOptions{Tx: tx})
+	if err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+	if len(frozen) > 0 {
+		err = errors.Wrapf(ErrInvalidCount, "%v already being counted", frozen)
+		return CountSession{}, err
+	}
+
+	if err = s.repo.SaveCountSession(ctx, &session, core.UpdateOptions{Tx: tx}); err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+	return session, nil
+}
+
+// RecordCount records counted quantities in an open session and calculates their variance against the current
+// on-hand quantity. A SKU may be recounted any number of times before the session is closed.
+func (s *service) RecordCount(ctx context.Context, ID uint64, counts []CountEntry) (CountSession, error) {
+	const funcName = "RecordCount"
+
+	if len(counts) == 0 {
+		return CountSession{}, errors.Wrap(ErrInvalidCount, "at least one count is required")
+	}
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Int("counts", len(counts)).Msg("recording count")
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	session, err := s.openCountSession(ctx, ID, tx)
+	if err != nil {
+		return CountSession{}, err
+	}
+
+	lines := make(map[string]int)
+	for i, line := range session.Lines {
+		lines[line.Sku] = i
+	}
+
+	now := time.Now()
+	for _, count := range counts {
+		i, ok := lines[count.Sku]
+		if !ok {
+			err = errors.Wrapf(ErrInvalidCount, "%s is not part of count %d", count.Sku, ID)
+			return CountSession{}, err
+		}
+		if count.Quantity < 0 {
+			err = errors.Wrapf(ErrInvalidCount, "%s count must not be negative", count.Sku)
+			return CountSession{}, err
+		}
+
+		var pi ProductInventory
+		pi, err = s.repo.GetProductInventory(ctx, count.Sku, core.QueryOptions{Tx: tx})
+		if err != nil {
+			return CountSession{}, errors.WithStack(err)
+		}
+
+		counted := count.Quantity
+		line := CountLine{
+			Sku:          count.Sku,
+			Counted:      &counted,
+			SystemOnHand: pi.OnHand,
+			Variance:     counted - pi.OnHand,
+			CountedAt:    &now,
+		}
+		if err = s.repo.UpdateCountLine(ctx, ID, line, core.UpdateOptions{Tx: tx}); err != nil {
+			return CountSession{}, errors.WithStack(err)
+		}
+		session.Lines[i] = line
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+	return session, nil
+}
+
+// ApproveCount adjusts the inventory of every counted SKU by its variance, records an adjustment for each one against
+// the approver and closes the session, unfreezing allocation. Every SKU in the session must have been counted.
+func (s *service) ApproveCount(ctx context.Context, ID uint64, approver string) (CountSession, error) {
+	const funcName = "ApproveCount"
+
+	if approver == "" {
+		return CountSession{}, errors.Wrap(ErrInvalidCount, "approver is required")
+	}
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Str("approver", approver).Msg("approving count")
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	session, err := s.openCountSession(ctx, ID, tx)
+	if err != nil {
+		return CountSession{}, err
+	}
+
+	now := time.Now()
+	reference := "count-" + strconv.FormatUint(ID, 10)
+	adjusted := make([]ProductInventory, 0)
+	for _, line := range session.Lines {
+		if line.Counted == nil {
+			err = errors.Wrapf(ErrInvalidCount, "%s has not been counted", line.Sku)
+			return CountSession{}, err
+		}
+		if line.Variance == 0 {
+			continue
+		}
+
+		var pi ProductInventory
+		pi, err = s.repo.GetProductInventory(ctx, line.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+		if err != nil {
+			return CountSession{}, errors.WithStack(err)
+		}
+		if pi.OnHand+line.Variance < pi.Reserved+pi.Held {
+			err = errors.Wrapf(ErrInvalidCount, "%s would have less on hand than is reserved and held", line.Sku)
+			return CountSession{}, err
+		}
+
+		if err = s.adjustCost(ctx, line.Sku, line.Variance, reference, now, tx); err != nil {
+			return CountSession{}, err
+		}
+
+		pi.OnHand += line.Variance
+		pi.Available += line.Variance
+		if err = s.repo.SaveProductInventory(ctx, pi, core.UpdateOptions{Tx: tx}); err != nil {
+			return CountSession{}, errors.WithStack(err)
+		}
+
+		adjustment := InventoryAdjustment{
+			Sku:       line.Sku,
+			Quantity:  line.Variance,
+			Reason:    AdjustmentCycleCount,
+			Reference: reference,
+			User:      approver,
+			Created:   now,
+		}
+		if err = s.repo.SaveAdjustment(ctx, &adjustment, core.UpdateOptions{Tx: tx}); err != nil {
+			return CountSession{}, errors.WithStack(err)
+		}
+		adjusted = append(adjusted, pi)
+	}
+
+	session.State = CountApproved
+	session.ClosedBy = approver
+	session.Closed = &now
+	if err = s.repo.UpdateCountSession(ctx, session, core.UpdateOptions{Tx: tx}); err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+
+	for _, pi := range adjusted {
+		if err = s.publishInventory(ctx, pi); err != nil {
+			log.Err(err).Str("func", funcName).Str("sku", pi.Sku).Msg("failed to publish inventory")
+		}
+	}
+	s.unfreeze(ctx, session)
+
+	return session, nil
+}
+
+// CancelCount closes a session without adjusting inventory, unfreezing allocation.
+func (s *service) CancelCount(ctx context.Context, ID uint64, user string) (CountSession, error) {
+	const funcName = "CancelCount"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Str("user", user).Msg("cancelling count")
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	session, err := s.openCountSession(ctx, ID, tx)
+	if err != nil {
+		return CountSession{}, err
+	}
+
+	now := time.Now()
+	session.State = CountCancelled
+	session.ClosedBy = user
+	session.Closed = &now
+	if err = s.repo.UpdateCountSession(ctx, session, core.UpdateOptions{Tx: tx}); err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+
+	s.unfreeze(ctx, session)
+	return session, nil
+}
+
+func (s *service) GetCountSession(ctx context.Context, ID uint64) (CountSession, error) {
+	const funcName = "GetCountSession"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Msg("getting count session")
+
+	session, err := s.repo.GetCountSession(ctx, ID)
+	if err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+	return session, nil
+}
+
+func (s *service) GetCountSessions(ctx context.Context, state CountState, limit, offset int) ([]CountSession, error) {
+	const funcName = "GetCountSessions"
+
+	log.Debug().Str("func", funcName).Str("state", string(state)).Int("limit", limit).Int("offset", offset).Msg("getting count sessions")
+
+	sessions, err := s.repo.GetCountSessions(ctx, state, limit, offset)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return sessions, nil
+}
+
+func (s *service) GetAdjustments(ctx context.Context, sku string, limit, offset int) ([]InventoryAdjustment, error) {
+	const funcName = "GetAdjustments"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Int("limit", limit).Int("offset", offset).Msg("getting adjustments")
+
+	adjustments, err := s.repo.GetAdjustments(ctx, sku, limit, offset)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return adjustments, nil
+}
+
+func (s *service) openCountSession(ctx context.Context, ID uint64, tx core.Transaction) (CountSession, error) {
+	session, err := s.repo.GetCountSession(ctx, ID, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return CountSession{}, errors.WithStack(err)
+	}
+	if session.State != CountOpen {
+		return CountSession{}, errors.Wrapf(ErrInvalidCount, "count %d is %s", ID, session.State)
+	}
+	return session, nil
+}
+
+// adjustCost relieves the cost of a shortage or carries an overage at the product's current unit cost. An overage
+// becomes a lot of its own, named after the count and the SKU since a count covers many SKUs.
+func (s *service) adjustCost(ctx context.Context, sku string, variance int64, reference string, created time.Time, tx core.Transaction) error {
+	if variance < 0 {
+		if _, err := s.relieveCost(ctx, sku, -variance, ValuationAdjustment, reference, tx); err != nil {
+			return errors.WithMessage(err, "failed to relieve adjustment cost")
+		}
+		if err := s.consumeLots(ctx, sku, -variance, 0, tx); err != nil {
+			return errors.WithMessage(err, "failed to write off production lots")
+		}
+		return nil
+	}
+
+	layers, err := s.repo.GetCostLayers(ctx, sku, core.QueryOptions{Tx: tx})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	unitCost := 0.0
+	if qty, value := layerTotals(layers); qty > 0 {
+		unitCost = value / float64(qty)
+	}
+
+	event := ProductionEvent{
+		RequestID: reference + "-" + sku,
+		Sku:       sku,
+		Quantity:  variance,
+		UnitCost:  unitCost,
+		Remaining: variance,
+		Created:   created,
+	}
+	if err = s.repo.SaveProductionEvent(ctx, &event, core.UpdateOptions{Tx: tx}); err != nil {
+		return errors.WithMessage(err, "failed to save counted lot")
+	}
+	event.RequestID = reference
+	if err = s.receiveCost(ctx, event, ValuationAdjustment, tx); err != nil {
+		return errors.WithMessage(err, "failed to receive adjustment cost")
+	}
+	return nil
+}
+
+// unfreeze fills the reserves of every SKU in a closed session now that allocation is no longer frozen.
+func (s *service) unfreeze(ctx context.Context, session CountSession) {
+	for _, line := range session.Lines {
+		product, err := s.repo.GetProduct(ctx, line.Sku)
+		if err != nil {
+			log.Err(err).Str("sku", line.Sku).Msg("failed to get product to fill reserves after count")
+			continue
+		}
+		if err = s.FillReserves(ctx, product); err != nil {
+			log.Err(err).Str("sku", line.Sku).Msg("failed to fill reserves after count")
+		}
+	}
+}
+
+// allocationFrozen reports whether any of the SKUs is being counted or has allocation frozen.
+func (s *service) allocationFrozen(ctx context.Context, skus []string, options ...core.QueryOptions) (bool, error) {
+	frozen, err := s.frozenSkus(ctx, skus, options...)
+	if err != nil {
+		return false, err
+	}
+	return len(frozen) > 0, nil
+}
+
+// frozenSkus gets those of the SKUs whose stock may not be allocated, either because they are being counted or because
+// allocation of them is frozen.
+func (s *service) frozenSkus(ctx context.Context, skus []string, options ...core.QueryOptions) ([]string, error) {
+	counted, err := s.repo.GetFrozenSkus(ctx, skus, options...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	paused, err := s.repo.GetFrozenProducts(ctx, skus, FreezeAllocation, options...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return append(counted, paused...), nil
+}
@@ -0,0 +1,93 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidCrossReference is returned when a cross reference cannot be created as requested.
+var ErrInvalidCrossReference = errors.New("invalid cross reference")
+
+// CreateCrossReference maps another identifier to the SKU. An identifier may only refer to one SKU for the same
+// requester and may not be another product's SKU or UPC. Creating a cross reference that already exists replaces its
+// type.
+func (s *service) CreateCrossReference(ctx context.Context, xref CrossReference) (CrossReference, error) {
+	const funcName = "CreateCrossReference"
+
+	if xref.Identifier == "" {
+		return CrossReference{}, errors.Wrap(ErrInvalidCrossReference, "identifier is required")
+	}
+	if _, err := ParseIdentifierType(string(xref.Type)); err != nil {
+		return CrossReference{}, errors.Wrapf(ErrInvalidCrossReference, "%s is not a valid identifier type", xref.Type)
+	}
+
+	if _, err := s.repo.GetProduct(ctx, xref.Sku); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return CrossReference{}, errors.Wrapf(ErrInvalidCrossReference, "product %s does not exist", xref.Sku)
+		}
+		return CrossReference{}, errors.WithStack(err)
+	}
+
+	existing, err := s.repo.ResolveIdentifier(ctx, xref.Identifier, xref.Requester)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		return CrossReference{}, errors.WithStack(err)
+	}
+	if err == nil && existing.Sku != xref.Sku && (existing.ID == 0 || existing.Requester == xref.Requester) {
+		return CrossReference{}, errors.Wrapf(ErrInvalidCrossReference, "%s already refers to %s", xref.Identifier, existing.Sku)
+	}
+
+	log.Debug().
+		Str("func", funcName).
+		Str("sku", xref.Sku).
+		Str("identifier", xref.Identifier).
+		Str("type", string(xref.Type)).
+		Str("requester", xref.Requester).
+		Msg("creating cross reference")
+
+	xref.Created = time.Now()
+	if err = s.repo.SaveCrossReference(ctx, &xref); err != nil {
+		return CrossReference{}, errors.WithStack(err)
+	}
+	return xref, nil
+}
+
+func (s *service) GetCrossReferences(ctx context.Context, sku string) ([]CrossReference, error) {
+	const funcName = "GetCrossReferences"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Msg("getting cross references")
+
+	xrefs, err := s.repo.GetCrossReferences(ctx, sku)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return xrefs, nil
+}
+
+func (s *service) DeleteCrossReference(ctx context.Context, sku string, ID uint64) error {
+	const funcName = "DeleteCrossReference"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Uint64("id", ID).Msg("deleting cross reference")
+
+	if err := s.repo.DeleteCrossReference(ctx, sku, ID); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// ResolveIdentifier finds the SKU a SKU, UPC or cross referenced identifier refers to, preferring cross references
+// scoped to the requester when one is given.
+func (s *service) ResolveIdentifier(ctx context.Context, identifier, requester string) (CrossReference, error) {
+	const funcName = "ResolveIdentifier"
+
+	log.Debug().Str("func", funcName).Str("identifier", identifier).Str("requester", requester).Msg("resolving identifier")
+
+	xref, err := s.repo.ResolveIdentifier(ctx, identifier, requester)
+	if err != nil {
+		return CrossReference{}, errors.WithStack(err)
+	}
+	return xref, nil
+}
@@ -0,0 +1,92 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidFreeze is returned when a freeze cannot be changed as requested.
+var ErrInvalidFreeze = errors.New("invalid freeze")
+
+// ErrFrozen is returned when an activity is attempted on a product that has it frozen.
+var ErrFrozen = errors.New("product is frozen")
+
+// SetFreeze freezes or unfreezes an activity on the product, recording why and by whom. Every change is published.
+// Unfreezing allocation fills the product's open reservations with any stock that built up while it was frozen.
+func (s *service) SetFreeze(ctx context.Context, sku string, freeze Freeze, frozen bool, reason, user string) (Product, error) {
+	const funcName = "SetFreeze"
+
+	log.Debug().
+		Str("func", funcName).
+		Str("sku", sku).
+		Str("freeze", string(freeze)).
+		Bool("frozen", frozen).
+		Str("reason", reason).
+		Str("user", user).
+		Msg("setting freeze")
+
+	if _, err := ParseFreeze(string(freeze)); err != nil {
+		return Product{}, errors.Wrapf(ErrInvalidFreeze, "%s is not a valid freeze", freeze)
+	}
+	if reason == "" {
+		return Product{}, errors.Wrap(ErrInvalidFreeze, "reason is required")
+	}
+	if user == "" {
+		return Product{}, errors.Wrap(ErrInvalidFreeze, "user is required")
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	product, err := s.repo.GetProduct(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	if !frozen && !product.Frozen(freeze) {
+		log.Debug().Str("func", funcName).Str("sku", sku).Str("freeze", string(freeze)).Msg("activity is not frozen")
+		rollback(ctx, tx, err)
+		return product, nil
+	}
+
+	now := time.Now()
+	if frozen {
+		if product.Freezes == nil {
+			product.Freezes = Freezes{}
+		}
+		product.Freezes[freeze] = FreezeSetting{Reason: reason, User: user, Since: now}
+	} else {
+		delete(product.Freezes, freeze)
+	}
+
+	if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	change := FreezeChange{Sku: sku, Freeze: freeze, Frozen: frozen, Reason: reason, User: user, Changed: now}
+	if err = s.queue.PublishFreeze(ctx, change); err != nil {
+		return Product{}, errors.WithMessage(err, "failed to publish freeze to queue")
+	}
+
+	if !frozen && freeze == FreezeAllocation {
+		if err = s.FillReserves(ctx, product); err != nil {
+			return Product{}, errors.WithMessage(err, "failed to fill reserves after unfreezing allocation")
+		}
+	}
+	return product, nil
+}
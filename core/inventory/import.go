@@ -0,0 +1,194 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidImport is returned when an imported product fails validation.
+var ErrInvalidImport = errors.New("invalid import")
+
+const importBatchSize = 100
+
+// ImportProducts validates and upserts every product, returning one result per product in the order they were given.
+// Products are saved in batches, each in its own transaction. When a batch fails to save its products are retried one
+// at a time so a single bad product does not fail the rest of the batch. New products start with no inventory, the
+// inventory of existing products is left as it is. An existing product's category, attributes and settings are only
+// replaced when the import provides them, its style and variant are only changed by assigning it a variant and its
+// freezes only by setting them.
+func (s *service) ImportProducts(ctx context.Context, imports []ProductImport) ([]ImportResult, error) {
+	const funcName = "ImportProducts"
+
+	log.Debug().Str("func", funcName).Int("products", len(imports)).Msg("importing products")
+
+	definitions, err := s.attributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories := make(map[uint64]error)
+
+	results := make([]ImportResult, len(imports))
+	valid := make([]int, 0, len(imports))
+	for i := range imports {
+		imp := &imports[i]
+		results[i] = ImportResult{Line: imp.Line, Sku: imp.Product.Sku}
+		if err := s.validateImport(ctx, &imp.Product, definitions, categories); err != nil {
+			results[i].Status = ImportFailed
+			results[i].Error = err.Error()
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	for start := 0; start < len(valid); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[start:end]
+
+		statuses, err := s.importBatch(ctx, imports, batch)
+		if err == nil {
+			for n, i := range batch {
+				results[i].Status = statuses[n]
+			}
+			continue
+		}
+
+		log.Warn().Err(err).Str("func", funcName).Int("products", len(batch)).Msg("failed to import batch, retrying individually")
+		for _, i := range batch {
+			statuses, err := s.importBatch(ctx, imports, []int{i})
+			if err != nil {
+				results[i].Status = ImportFailed
+				results[i].Error = err.Error()
+				continue
+			}
+			results[i].Status = statuses[0]
+		}
+	}
+
+	return results, nil
+}
+
+func (s *service) importBatch(ctx context.Context, imports []ProductImport, batch []int) ([]ImportStatus, error) {
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	statuses := make([]ImportStatus, len(batch))
+	for n, i := range batch {
+		statuses[n], err = s.importProduct(ctx, imports[i], tx)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to import %s", imports[i].Product.Sku)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return statuses, nil
+}
+
+func (s *service) importProduct(ctx context.Context, imp ProductImport, tx core.Transaction) (ImportStatus, error) {
+	product := imp.Product
+	existing, err := s.repo.GetProduct(ctx, product.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		return ImportFailed, errors.WithStack(err)
+	}
+
+	if err == nil {
+		if existing.Type == Kit {
+			return ImportFailed, errors.Wrapf(ErrInvalidImport, "%s is a kit, kits cannot be imported", product.Sku)
+		}
+		if product.CategoryID == 0 {
+			product.CategoryID = existing.CategoryID
+		}
+		if product.Attributes == nil {
+			product.Attributes = existing.Attributes
+		}
+		if imp.RequiresInspection == nil {
+			product.RequiresInspection = existing.RequiresInspection
+		}
+		if imp.ApprovalThreshold == nil {
+			product.ApprovalThreshold = existing.ApprovalThreshold
+		}
+		if imp.ReservationSlaHours == nil {
+			product.ReservationSlaHours = existing.ReservationSlaHours
+		}
+		product.Style, product.Variant = existing.Style, existing.Variant
+		product.Freezes = existing.Freezes
+		if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+			return ImportFailed, errors.WithStack(err)
+		}
+		return ImportUpdated, nil
+	}
+
+	if product.Style != "" {
+		if err = s.validateVariantUnique(ctx, product.Sku, product.Style, product.Variant, core.QueryOptions{Tx: tx}); err != nil {
+			return ImportFailed, err
+		}
+	}
+	if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+		return ImportFailed, errors.WithStack(err)
+	}
+	if err = s.repo.SaveProductInventory(ctx, ProductInventory{Product: product}, core.UpdateOptions{Tx: tx}); err != nil {
+		return ImportFailed, errors.WithStack(err)
+	}
+	return ImportCreated, nil
+}
+
+// validateImport checks an imported product and converts its string attribute values to their defined types. The
+// existence of each category is looked up once and remembered in categories.
+func (s *service) validateImport(ctx context.Context, product *Product, definitions map[string]AttributeType, categories map[uint64]error) error {
+	if product.Sku == "" {
+		return errors.Wrap(ErrInvalidImport, "sku is required")
+	}
+	if product.Upc == "" {
+		return errors.Wrap(ErrInvalidImport, "upc is required")
+	}
+	if product.Name == "" {
+		return errors.Wrap(ErrInvalidImport, "name is required")
+	}
+	if product.Type == Kit {
+		return errors.Wrap(ErrInvalidImport, "kits cannot be imported")
+	}
+	product.Type = Standard
+	product.Freezes = nil
+
+	if product.Attributes != nil {
+		attributes, err := coerceAttributes(product.Attributes, definitions)
+		if err != nil {
+			return err
+		}
+		if err = checkAttributes(attributes, definitions); err != nil {
+			return err
+		}
+		product.Attributes = attributes
+	}
+
+	if product.CategoryID != 0 {
+		err, ok := categories[product.CategoryID]
+		if !ok {
+			err = s.validateCategory(ctx, product.CategoryID)
+			categories[product.CategoryID] = err
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if product.Style == "" {
+		product.Variant = nil
+		return nil
+	}
+	return s.validateVariant(ctx, product.Style, product.Variant)
+}
@@ -0,0 +1,327 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// CreateKit creates a kit product made up of the provided components. Components must be existing standard products.
+func (s *service) CreateKit(ctx context.Context, product Product, components []KitComponent) error {
+	const funcName = "CreateKit"
+
+	product.Type = Kit
+	if err := s.validateKitComponents(ctx, product, components); err != nil {
+		return err
+	}
+	if product.Style != "" {
+		return errors.Wrapf(ErrInvalidStyle, "%s is a kit, kits cannot be variants", product.Sku)
+	}
+	product.Variant = nil
+	product.Freezes = nil
+	if err := s.validateAttributes(ctx, product.Attributes); err != nil {
+		return err
+	}
+	if err := s.validateCategory(ctx, product.CategoryID); err != nil {
+		return err
+	}
+
+	dbProduct, err := s.repo.GetProduct(ctx, product.Sku)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		return errors.WithStack(err)
+	}
+	if err == nil {
+		log.Debug().Str("func", funcName).Str("sku", dbProduct.Sku).Msg("product already exists")
+		return nil
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	log.Debug().Str("func", funcName).Str("sku", product.Sku).Int("components", len(components)).Msg("creating kit")
+	if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = s.repo.SaveProductInventory(ctx, ProductInventory{Product: product}, core.UpdateOptions{Tx: tx}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = s.repo.SaveKitComponents(ctx, product.Sku, components, core.UpdateOptions{Tx: tx}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func (s *service) validateKitComponents(ctx context.Context, kit Product, components []KitComponent) error {
+	if len(components) == 0 {
+		return errors.New("a kit requires at least one component")
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range components {
+		if c.Sku == "" {
+			return errors.New("component sku is required")
+		}
+		if c.Sku == kit.Sku {
+			return errors.New("a kit cannot contain itself")
+		}
+		if seen[c.Sku] {
+			return errors.Errorf("component %s is listed more than once", c.Sku)
+		}
+		seen[c.Sku] = true
+		if c.Quantity < 1 {
+			return errors.Errorf("component %s quantity must be greater than zero", c.Sku)
+		}
+
+		component, err := s.repo.GetProduct(ctx, c.Sku)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to get component %s", c.Sku)
+		}
+		if component.Type == Kit {
+			return errors.Errorf("component %s is a kit, kits cannot be nested", c.Sku)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetKitComponents(ctx context.Context, sku string) ([]KitComponent, error) {
+	const funcName = "GetKitComponents"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Msg("getting kit components")
+
+	components, err := s.repo.GetKitComponents(ctx, sku)
+	if err != nil {
+		return components, errors.WithStack(err)
+	}
+	return components, nil
+}
+
+// deriveKitInventory sets a kit's available quantity to the number of whole kits that could be assembled from the
+// available quantity of its components.
+func (s *service) deriveKitInventory(ctx context.Context, pi *ProductInventory, options ...core.QueryOptions) error {
+	components, err := s.repo.GetKitComponents(ctx, pi.Sku, options...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	inventories := make(map[string]ProductInventory)
+	for _, c := range components {
+		ci, err := s.repo.GetProductInventory(ctx, c.Sku, options...)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		inventories[c.Sku] = ci
+	}
+
+	pi.Available = kitAvailable(components, inventories)
+	pi.OnHand = pi.Reserved + pi.Available
+	return nil
+}
+
+func kitAvailable(components []KitComponent, inventories map[string]ProductInventory) int64 {
+	if len(components) == 0 {
+		return 0
+	}
+
+	available := int64(-1)
+	for _, c := range components {
+		kits := inventories[c.Sku].Available / c.Quantity
+		if available == -1 || kits < available {
+			available = kits
+		}
+	}
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// refreshKits fills the open reservations of every kit that contains the component and publishes their updated
+// availability.
+func (s *service) refreshKits(ctx context.Context, componentSku string) error {
+	kits, err := s.repo.GetKitsContaining(ctx, componentSku)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, kit := range kits {
+		if err = s.fillKitReserves(ctx, kit); err != nil {
+			return errors.WithMessagef(err, "failed to fill reserves for kit %s", kit.Sku)
+		}
+	}
+	return nil
+}
+
+// fillKitReserves fills open kit reservations in whole kits. Every component's share is moved from available to
+// reserved in the same transaction as the kit reservation so a kit is never partially allocated. While allocation of
+// the kit or a component is frozen nothing is filled but the kit's availability is still brought up to date.
+func (s *service) fillKitReserves(ctx context.Context, kit Product) error {
+	const funcName = "fillKitReserves"
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	components, err := s.repo.GetKitComponents(ctx, kit.Sku, core.QueryOptions{Tx: tx})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	openReservations, err := s.repo.GetReservations(ctx, GetReservationsOptions{Sku: kit.Sku, State: Open}, 100, 0, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	kitInventory, err := s.repo.GetProductInventory(ctx, kit.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	inventories := make(map[string]ProductInventory)
+	for _, c := range components {
+		var ci ProductInventory
+		ci, err = s.repo.GetProductInventory(ctx, c.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		inventories[c.Sku] = ci
+	}
+
+	skus := make([]string, 0, len(components))
+	for _, c := range components {
+		skus = append(skus, c.Sku)
+	}
+	frozen, err := s.allocationFrozen(ctx, append(skus, kit.Sku), core.QueryOptions{Tx: tx})
+	if err != nil {
+		return err
+	}
+	if frozen {
+		log.Debug().Str("func", funcName).Str("sku", kit.Sku).Msg("allocation of the kit or a component is frozen, skipping fill")
+		openReservations = nil
+	}
+
+	filled := make([]Reservation, 0)
+	for _, reservation := range openReservations {
+		available := kitAvailable(components, inventories)
+		if available == 0 {
+			break
+		}
+
+		reserveAmount := reservation.RequestedQuantity - reservation.ReservedQuantity
+		if reserveAmount > available {
+			reserveAmount = available
+		}
+
+		log.Debug().
+			Str("func", funcName).
+			Str("sku", kit.Sku).
+			Str("reservation.RequestID", reservation.RequestID).
+			Int64("reserveAmount", reserveAmount).
+			Msg("reserving kit components")
+
+		for _, c := range components {
+			qty := reserveAmount * c.Quantity
+			ci := inventories[c.Sku]
+			ci.Available -= qty
+			ci.Reserved += qty
+			inventories[c.Sku] = ci
+
+			var cost float64
+			cost, err = s.relieveCost(ctx, c.Sku, qty, ValuationAllocation, reservation.RequestID, tx)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			reservation.CostOfGoods += cost
+
+			if err = s.consumeLots(ctx, c.Sku, qty, reservation.ID, tx); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		kitInventory.Reserved += reserveAmount
+		kitInventory.OpenDemand -= reserveAmount
+		reservation.ReservedQuantity += reserveAmount
+		if reservation.ReservedQuantity == reservation.RequestedQuantity {
+			reservation.State = Closed
+		}
+
+		var backorder Reservation
+		if backorder, err = s.splitBackorder(ctx, &reservation, tx); err != nil {
+			return err
+		}
+
+		err = s.repo.UpdateReservation(ctx, reservation.ID, reservation.State, reservation.ReservedQuantity, core.UpdateOptions{Tx: tx})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		err = s.repo.UpdateReservationCost(ctx, reservation.ID, reservation.CostOfGoods, core.UpdateOptions{Tx: tx})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		filled = append(filled, reservation)
+		if backorder.ID != 0 {
+			filled = append(filled, backorder)
+		}
+	}
+
+	if len(filled) > 0 {
+		for _, c := range components {
+			if err = s.repo.SaveProductInventory(ctx, inventories[c.Sku], core.UpdateOptions{Tx: tx}); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+
+	kitInventory.Available = kitAvailable(components, inventories)
+	kitInventory.OnHand = kitInventory.Reserved + kitInventory.Available
+	if err = s.repo.SaveProductInventory(ctx, kitInventory, core.UpdateOptions{Tx: tx}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(filled) > 0 {
+		for _, c := range components {
+			if err = s.publishInventory(ctx, inventories[c.Sku]); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		for _, reservation := range filled {
+			if err = s.publishReservation(ctx, reservation); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+
+	if err = s.publishInventory(ctx, kitInventory); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
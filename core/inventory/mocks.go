@@ -2,30 +2,181 @@ package inventory
 
 import (
 	"context"
+	"time"
 
 	"github.com/sksmith/go-micro-example/testutil"
 )
 
 type MockInventoryService struct {
-	ProduceFunc                func(ctx context.Context, product Product, event ProductionRequest) error
-	CreateProductFunc          func(ctx context.Context, product Product) error
-	GetProductFunc             func(ctx context.Context, sku string) (Product, error)
-	GetAllProductInventoryFunc func(ctx context.Context, limit, offset int) ([]ProductInventory, error)
-	GetProductInventoryFunc    func(ctx context.Context, sku string) (ProductInventory, error)
-	SubscribeInventoryFunc     func(ch chan<- ProductInventory) (id InventorySubID)
-	UnsubscribeInventoryFunc   func(id InventorySubID)
+	ProduceFunc                 func(ctx context.Context, product Product, event ProductionRequest) error
+	ProduceBatchFunc            func(ctx context.Context, requests []SkuProductionRequest) ([]ProductionResult, error)
+	CreateProductFunc           func(ctx context.Context, product Product) error
+	CreateKitFunc               func(ctx context.Context, product Product, components []KitComponent) error
+	GetProductFunc              func(ctx context.Context, sku string) (Product, error)
+	GetAllProductInventoryFunc  func(ctx context.Context, options GetProductInventoryOptions, limit, offset int) ([]ProductInventory, error)
+	GetProductInventoryFunc     func(ctx context.Context, sku string) (ProductInventory, error)
+	GetKitComponentsFunc        func(ctx context.Context, sku string) ([]KitComponent, error)
+	UpdateProductAttributesFunc func(ctx context.Context, sku string, attributes Attributes) (Product, error)
+	DefineAttributeFunc         func(ctx context.Context, definition AttributeDefinition) error
+	GetAttributeDefinitionsFunc func(ctx context.Context) ([]AttributeDefinition, error)
+	AssignProductCategoryFunc   func(ctx context.Context, sku string, categoryID uint64) (Product, error)
+	SetRequiresInspectionFunc   func(ctx context.Context, sku string, requiresInspection bool) (Product, error)
+	SetApprovalThresholdFunc    func(ctx context.Context, sku string, threshold int64) (Product, error)
+	SetReservationSlaFunc       func(ctx context.Context, sku string, hours int64) (Product, error)
+	SetFreezeFunc               func(ctx context.Context, sku string, freeze Freeze, frozen bool, reason, user string) (Product, error)
+	ReleaseHeldFunc             func(ctx context.Context, sku string, qty int64, user string) (ProductInventory, error)
+	RejectHeldFunc              func(ctx context.Context, sku string, qty int64, reference, user string) (ProductInventory, error)
+	PlanProductionFunc          func(ctx context.Context, plan PlannedProduction) (PlannedProduction, error)
+	GetPlannedProductionFunc    func(ctx context.Context, sku string) ([]PlannedProduction, error)
+	CancelPlannedProductionFunc func(ctx context.Context, sku string, ID uint64) error
+	GetAvailableToPromiseFunc   func(ctx context.Context, sku string) (AvailableToPromise, error)
+	CreateSubstituteFunc        func(ctx context.Context, rule SubstituteRule) (SubstituteRule, error)
+	GetSubstitutesFunc          func(ctx context.Context, sku string) ([]SubstituteRule, error)
+	DeleteSubstituteFunc        func(ctx context.Context, sku string, ID uint64) error
+	GetProductionConsumersFunc  func(ctx context.Context, requestID string) ([]Peg, error)
+	ImportProductsFunc          func(ctx context.Context, imports []ProductImport) ([]ImportResult, error)
+	GetInventoryValuationFunc   func(ctx context.Context, limit, offset int) (InventoryValuation, error)
+	GetValuationFunc            func(ctx context.Context, sku string) (Valuation, error)
+	GetValuationHistoryFunc     func(ctx context.Context, sku string, limit, offset int) ([]ValuationEntry, error)
+	GetAdjustmentsFunc          func(ctx context.Context, sku string, limit, offset int) ([]InventoryAdjustment, error)
+	GetInventoryTimeSeriesFunc  func(ctx context.Context, options ReportOptions, interval time.Duration) ([]InventoryBucket, error)
+	ConvertFunc                 func(ctx context.Context, cr ConversionRequest, user string) (Conversion, error)
+	GetConversionsFunc          func(ctx context.Context, sku string, limit, offset int) ([]Conversion, error)
+	CreateCrossReferenceFunc    func(ctx context.Context, xref CrossReference) (CrossReference, error)
+	GetCrossReferencesFunc      func(ctx context.Context, sku string) ([]CrossReference, error)
+	DeleteCrossReferenceFunc    func(ctx context.Context, sku string, ID uint64) error
+	ResolveIdentifierFunc       func(ctx context.Context, identifier, requester string) (CrossReference, error)
+	CreateStyleFunc             func(ctx context.Context, style Style) (Style, error)
+	GetStylesFunc               func(ctx context.Context, limit, offset int) ([]Style, error)
+	GetStyleInventoryFunc       func(ctx context.Context, code string) (StyleInventory, error)
+	GetAllStyleInventoryFunc    func(ctx context.Context, options GetProductInventoryOptions, limit, offset int) ([]StyleInventory, error)
+	AssignVariantFunc           func(ctx context.Context, sku, code string, variant Variant) (Product, error)
+	SubscribeInventoryFunc      func(ch chan<- ProductInventory) (id InventorySubID)
+	UnsubscribeInventoryFunc    func(id InventorySubID)
 	*testutil.CallWatcher
 }
 
 func NewMockInventoryService() *MockInventoryService {
 	return &MockInventoryService{
-		ProduceFunc:       func(ctx context.Context, product Product, event ProductionRequest) error { return nil },
+		ProduceFunc: func(ctx context.Context, product Product, event ProductionRequest) error { return nil },
+		ProduceBatchFunc: func(ctx context.Context, requests []SkuProductionRequest) ([]ProductionResult, error) {
+			results := make([]ProductionResult, len(requests))
+			for i, pr := range requests {
+				results[i] = ProductionResult{Sku: pr.Sku, RequestID: pr.RequestID, Status: ProductionProduced}
+			}
+			return results, nil
+		},
 		CreateProductFunc: func(ctx context.Context, product Product) error { return nil },
 		GetProductFunc:    func(ctx context.Context, sku string) (Product, error) { return Product{}, nil },
-		GetAllProductInventoryFunc: func(ctx context.Context, limit, offset int) ([]ProductInventory, error) {
+		CreateKitFunc: func(ctx context.Context, product Product, components []KitComponent) error {
+			return nil
+		},
+		GetAllProductInventoryFunc: func(ctx context.Context, options GetProductInventoryOptions, limit, offset int) ([]ProductInventory, error) {
 			return []ProductInventory{}, nil
 		},
-		GetProductInventoryFunc:  func(ctx context.Context, sku string) (ProductInventory, error) { return ProductInventory{}, nil },
+		GetProductInventoryFunc: func(ctx context.Context, sku string) (ProductInventory, error) { return ProductInventory{}, nil },
+		GetKitComponentsFunc: func(ctx context.Context, sku string) ([]KitComponent, error) {
+			return []KitComponent{}, nil
+		},
+		UpdateProductAttributesFunc: func(ctx context.Context, sku string, attributes Attributes) (Product, error) {
+			return Product{Sku: sku, Attributes: attributes}, nil
+		},
+		DefineAttributeFunc: func(ctx context.Context, definition AttributeDefinition) error { return nil },
+		GetAttributeDefinitionsFunc: func(ctx context.Context) ([]AttributeDefinition, error) {
+			return []AttributeDefinition{}, nil
+		},
+		AssignProductCategoryFunc: func(ctx context.Context, sku string, categoryID uint64) (Product, error) {
+			return Product{Sku: sku, CategoryID: categoryID}, nil
+		},
+		SetRequiresInspectionFunc: func(ctx context.Context, sku string, requiresInspection bool) (Product, error) {
+			return Product{Sku: sku, RequiresInspection: requiresInspection}, nil
+		}, SetApprovalThresholdFunc: func(ctx context.Context, sku string, threshold int64) (Product, error) {
+			return Product{Sku: sku, ApprovalThreshold: threshold}, nil
+		},
+		SetReservationSlaFunc: func(ctx context.Context, sku string, hours int64) (Product, error) {
+			return Product{Sku: sku, ReservationSlaHours: hours}, nil
+		},
+		SetFreezeFunc: func(ctx context.Context, sku string, freeze Freeze, frozen bool, reason, user string) (Product, error) {
+			return Product{Sku: sku}, nil
+		},
+
+		ReleaseHeldFunc: func(ctx context.Context, sku string, qty int64, user string) (ProductInventory, error) {
+			return ProductInventory{Product: Product{Sku: sku}, Available: qty}, nil
+		},
+		RejectHeldFunc: func(ctx context.Context, sku string, qty int64, reference, user string) (ProductInventory, error) {
+			return ProductInventory{Product: Product{Sku: sku}}, nil
+		},
+		PlanProductionFunc: func(ctx context.Context, plan PlannedProduction) (PlannedProduction, error) {
+			return plan, nil
+		},
+		GetPlannedProductionFunc: func(ctx context.Context, sku string) ([]PlannedProduction, error) {
+			return []PlannedProduction{}, nil
+		},
+		CancelPlannedProductionFunc: func(ctx context.Context, sku string, ID uint64) error { return nil },
+		GetAvailableToPromiseFunc: func(ctx context.Context, sku string) (AvailableToPromise, error) {
+			return AvailableToPromise{Sku: sku}, nil
+		},
+		CreateSubstituteFunc: func(ctx context.Context, rule SubstituteRule) (SubstituteRule, error) {
+			return rule, nil
+		},
+		GetSubstitutesFunc: func(ctx context.Context, sku string) ([]SubstituteRule, error) {
+			return []SubstituteRule{}, nil
+		},
+		DeleteSubstituteFunc: func(ctx context.Context, sku string, ID uint64) error { return nil },
+		GetProductionConsumersFunc: func(ctx context.Context, requestID string) ([]Peg, error) {
+			return []Peg{}, nil
+		},
+		ImportProductsFunc: func(ctx context.Context, imports []ProductImport) ([]ImportResult, error) {
+			results := make([]ImportResult, len(imports))
+			for i, imp := range imports {
+				results[i] = ImportResult{Line: imp.Line, Sku: imp.Product.Sku, Status: ImportCreated}
+			}
+			return results, nil
+		},
+		GetInventoryValuationFunc: func(ctx context.Context, limit, offset int) (InventoryValuation, error) {
+			return InventoryValuation{}, nil
+		},
+		GetValuationFunc: func(ctx context.Context, sku string) (Valuation, error) { return Valuation{}, nil },
+		GetValuationHistoryFunc: func(ctx context.Context, sku string, limit, offset int) ([]ValuationEntry, error) {
+			return []ValuationEntry{}, nil
+		},
+		GetAdjustmentsFunc: func(ctx context.Context, sku string, limit, offset int) ([]InventoryAdjustment, error) {
+			return []InventoryAdjustment{}, nil
+		},
+		GetInventoryTimeSeriesFunc: func(ctx context.Context, options ReportOptions, interval time.Duration) ([]InventoryBucket, error) {
+			return []InventoryBucket{}, nil
+		},
+		ConvertFunc: func(ctx context.Context, cr ConversionRequest, user string) (Conversion, error) {
+			return Conversion{RequestID: cr.RequestID, SourceSku: cr.SourceSku, TargetSku: cr.TargetSku, User: user}, nil
+		},
+		GetConversionsFunc: func(ctx context.Context, sku string, limit, offset int) ([]Conversion, error) {
+			return []Conversion{}, nil
+		},
+		CreateCrossReferenceFunc: func(ctx context.Context, xref CrossReference) (CrossReference, error) {
+			return xref, nil
+		},
+		GetCrossReferencesFunc: func(ctx context.Context, sku string) ([]CrossReference, error) {
+			return []CrossReference{}, nil
+		},
+		DeleteCrossReferenceFunc: func(ctx context.Context, sku string, ID uint64) error { return nil },
+		ResolveIdentifierFunc: func(ctx context.Context, identifier, requester string) (CrossReference, error) {
+			return CrossReference{Sku: identifier, Identifier: identifier, Type: IdentifierSku}, nil
+		},
+		CreateStyleFunc: func(ctx context.Context, style Style) (Style, error) {
+			return style, nil
+		},
+		GetStylesFunc: func(ctx context.Context, limit, offset int) ([]Style, error) {
+			return []Style{}, nil
+		},
+		GetStyleInventoryFunc: func(ctx context.Context, code string) (StyleInventory, error) {
+			return StyleInventory{Style: Style{Code: code}}, nil
+		},
+		GetAllStyleInventoryFunc: func(ctx context.Context, options GetProductInventoryOptions, limit, offset int) ([]StyleInventory, error) {
+			return []StyleInventory{}, nil
+		},
+		AssignVariantFunc: func(ctx context.Context, sku, code string, variant Variant) (Product, error) {
+			return Product{Sku: sku, Style: code, Variant: variant}, nil
+		},
 		SubscribeInventoryFunc:   func(ch chan<- ProductInventory) (id InventorySubID) { return "" },
 		UnsubscribeInventoryFunc: func(id InventorySubID) {},
 		CallWatcher:              testutil.NewCallWatcher(),
@@ -37,19 +188,29 @@ func (i *MockInventoryService) Produce(ctx context.Context, product Product, eve
 	return i.ProduceFunc(ctx, product, event)
 }
 
+func (i *MockInventoryService) ProduceBatch(ctx context.Context, requests []SkuProductionRequest) ([]ProductionResult, error) {
+	i.AddCall(ctx, requests)
+	return i.ProduceBatchFunc(ctx, requests)
+}
+
 func (i *MockInventoryService) CreateProduct(ctx context.Context, product Product) error {
 	i.AddCall(ctx, product)
 	return i.CreateProductFunc(ctx, product)
 }
 
+func (i *MockInventoryService) CreateKit(ctx context.Context, product Product, components []KitComponent) error {
+	i.AddCall(ctx, product, components)
+	return i.CreateKitFunc(ctx, product, components)
+}
+
 func (i *MockInventoryService) GetProduct(ctx context.Context, sku string) (Product, error) {
 	i.AddCall(ctx, sku)
 	return i.GetProductFunc(ctx, sku)
 }
 
-func (i *MockInventoryService) GetAllProductInventory(ctx context.Context, limit, offset int) ([]ProductInventory, error) {
-	i.AddCall(ctx, limit, offset)
-	return i.GetAllProductInventoryFunc(ctx, limit, offset)
+func (i *MockInventoryService) GetAllProductInventory(ctx context.Context, options GetProductInventoryOptions, limit, offset int) ([]ProductInventory, error) {
+	i.AddCall(ctx, options, limit, offset)
+	return i.GetAllProductInventoryFunc(ctx, options, limit, offset)
 }
 
 func (i *MockInventoryService) GetProductInventory(ctx context.Context, sku string) (ProductInventory, error) {
@@ -57,6 +218,186 @@ func (i *MockInventoryService) GetProductInventory(ctx context.Context, sku stri
 	return i.GetProductInventoryFunc(ctx, sku)
 }
 
+func (i *MockInventoryService) GetKitComponents(ctx context.Context, sku string) ([]KitComponent, error) {
+	i.AddCall(ctx, sku)
+	return i.GetKitComponentsFunc(ctx, sku)
+}
+
+func (i *MockInventoryService) UpdateProductAttributes(ctx context.Context, sku string, attributes Attributes) (Product, error) {
+	i.AddCall(ctx, sku, attributes)
+	return i.UpdateProductAttributesFunc(ctx, sku, attributes)
+}
+
+func (i *MockInventoryService) DefineAttribute(ctx context.Context, definition AttributeDefinition) error {
+	i.AddCall(ctx, definition)
+	return i.DefineAttributeFunc(ctx, definition)
+}
+
+func (i *MockInventoryService) GetAttributeDefinitions(ctx context.Context) ([]AttributeDefinition, error) {
+	i.AddCall(ctx)
+	return i.GetAttributeDefinitionsFunc(ctx)
+}
+
+func (i *MockInventoryService) AssignProductCategory(ctx context.Context, sku string, categoryID uint64) (Product, error) {
+	i.AddCall(ctx, sku, categoryID)
+	return i.AssignProductCategoryFunc(ctx, sku, categoryID)
+}
+
+func (i *MockInventoryService) SetRequiresInspection(ctx context.Context, sku string, requiresInspection bool) (Product, error) {
+	i.AddCall(ctx, sku, requiresInspection)
+	return i.SetRequiresInspectionFunc(ctx, sku, requiresInspection)
+}
+
+func (i *MockInventoryService) SetApprovalThreshold(ctx context.Context, sku string, threshold int64) (Product, error) {
+	i.AddCall(ctx, sku, threshold)
+	return i.SetApprovalThresholdFunc(ctx, sku, threshold)
+}
+
+func (i *MockInventoryService) SetReservationSla(ctx context.Context, sku string, hours int64) (Product, error) {
+	i.AddCall(ctx, sku, hours)
+	return i.SetReservationSlaFunc(ctx, sku, hours)
+}
+
+func (i *MockInventoryService) SetFreeze(ctx context.Context, sku string, freeze Freeze, frozen bool, reason, user string) (Product, error) {
+	i.AddCall(ctx, sku, freeze, frozen, reason, user)
+	return i.SetFreezeFunc(ctx, sku, freeze, frozen, reason, user)
+}
+
+func (i *MockInventoryService) ReleaseHeld(ctx context.Context, sku string, qty int64, user string) (ProductInventory, error) {
+	i.AddCall(ctx, sku, qty, user)
+	return i.ReleaseHeldFunc(ctx, sku, qty, user)
+}
+
+func (i *MockInventoryService) RejectHeld(ctx context.Context, sku string, qty int64, reference, user string) (ProductInventory, error) {
+	i.AddCall(ctx, sku, qty, reference, user)
+	return i.RejectHeldFunc(ctx, sku, qty, reference, user)
+}
+
+func (i *MockInventoryService) PlanProduction(ctx context.Context, plan PlannedProduction) (PlannedProduction, error) {
+	i.AddCall(ctx, plan)
+	return i.PlanProductionFunc(ctx, plan)
+}
+
+func (i *MockInventoryService) GetPlannedProduction(ctx context.Context, sku string) ([]PlannedProduction, error) {
+	i.AddCall(ctx, sku)
+	return i.GetPlannedProductionFunc(ctx, sku)
+}
+
+func (i *MockInventoryService) CancelPlannedProduction(ctx context.Context, sku string, ID uint64) error {
+	i.AddCall(ctx, sku, ID)
+	return i.CancelPlannedProductionFunc(ctx, sku, ID)
+}
+
+func (i *MockInventoryService) GetAvailableToPromise(ctx context.Context, sku string) (AvailableToPromise, error) {
+	i.AddCall(ctx, sku)
+	return i.GetAvailableToPromiseFunc(ctx, sku)
+}
+
+func (i *MockInventoryService) CreateSubstitute(ctx context.Context, rule SubstituteRule) (SubstituteRule, error) {
+	i.AddCall(ctx, rule)
+	return i.CreateSubstituteFunc(ctx, rule)
+}
+
+func (i *MockInventoryService) GetSubstitutes(ctx context.Context, sku string) ([]SubstituteRule, error) {
+	i.AddCall(ctx, sku)
+	return i.GetSubstitutesFunc(ctx, sku)
+}
+
+func (i *MockInventoryService) DeleteSubstitute(ctx context.Context, sku string, ID uint64) error {
+	i.AddCall(ctx, sku, ID)
+	return i.DeleteSubstituteFunc(ctx, sku, ID)
+}
+
+func (i *MockInventoryService) GetProductionConsumers(ctx context.Context, requestID string) ([]Peg, error) {
+	i.AddCall(ctx, requestID)
+	return i.GetProductionConsumersFunc(ctx, requestID)
+}
+
+func (i *MockInventoryService) ImportProducts(ctx context.Context, imports []ProductImport) ([]ImportResult, error) {
+	i.AddCall(ctx, imports)
+	return i.ImportProductsFunc(ctx, imports)
+}
+
+func (i *MockInventoryService) GetInventoryValuation(ctx context.Context, limit, offset int) (InventoryValuation, error) {
+	i.AddCall(ctx, limit, offset)
+	return i.GetInventoryValuationFunc(ctx, limit, offset)
+}
+
+func (i *MockInventoryService) GetValuation(ctx context.Context, sku string) (Valuation, error) {
+	i.AddCall(ctx, sku)
+	return i.GetValuationFunc(ctx, sku)
+}
+
+func (i *MockInventoryService) GetValuationHistory(ctx context.Context, sku string, limit, offset int) ([]ValuationEntry, error) {
+	i.AddCall(ctx, sku, limit, offset)
+	return i.GetValuationHistoryFunc(ctx, sku, limit, offset)
+}
+
+func (i *MockInventoryService) GetAdjustments(ctx context.Context, sku string, limit, offset int) ([]InventoryAdjustment, error) {
+	i.AddCall(ctx, sku, limit, offset)
+	return i.GetAdjustmentsFunc(ctx, sku, limit, offset)
+}
+
+func (i *MockInventoryService) GetInventoryTimeSeries(ctx context.Context, options ReportOptions, interval time.Duration) ([]InventoryBucket, error) {
+	i.AddCall(ctx, options, interval)
+	return i.GetInventoryTimeSeriesFunc(ctx, options, interval)
+}
+
+func (i *MockInventoryService) Convert(ctx context.Context, cr ConversionRequest, user string) (Conversion, error) {
+	i.AddCall(ctx, cr, user)
+	return i.ConvertFunc(ctx, cr, user)
+}
+
+func (i *MockInventoryService) CreateCrossReference(ctx context.Context, xref CrossReference) (CrossReference, error) {
+	i.AddCall(ctx, xref)
+	return i.CreateCrossReferenceFunc(ctx, xref)
+}
+
+func (i *MockInventoryService) GetCrossReferences(ctx context.Context, sku string) ([]CrossReference, error) {
+	i.AddCall(ctx, sku)
+	return i.GetCrossReferencesFunc(ctx, sku)
+}
+
+func (i *MockInventoryService) DeleteCrossReference(ctx context.Context, sku string, ID uint64) error {
+	i.AddCall(ctx, sku, ID)
+	return i.DeleteCrossReferenceFunc(ctx, sku, ID)
+}
+
+func (i *MockInventoryService) ResolveIdentifier(ctx context.Context, identifier, requester string) (CrossReference, error) {
+	i.AddCall(ctx, identifier, requester)
+	return i.ResolveIdentifierFunc(ctx, identifier, requester)
+}
+
+func (i *MockInventoryService) CreateStyle(ctx context.Context, style Style) (Style, error) {
+	i.AddCall(ctx, style)
+	return i.CreateStyleFunc(ctx, style)
+}
+
+func (i *MockInventoryService) GetStyles(ctx context.Context, limit, offset int) ([]Style, error) {
+	i.AddCall(ctx, limit, offset)
+	return i.GetStylesFunc(ctx, limit, offset)
+}
+
+func (i *MockInventoryService) GetStyleInventory(ctx context.Context, code string) (StyleInventory, error) {
+	i.AddCall(ctx, code)
+	return i.GetStyleInventoryFunc(ctx, code)
+}
+
+func (i *MockInventoryService) GetAllStyleInventory(ctx context.Context, options GetProductInventoryOptions, limit, offset int) ([]StyleInventory, error) {
+	i.AddCall(ctx, options, limit, offset)
+	return i.GetAllStyleInventoryFunc(ctx, options, limit, offset)
+}
+
+func (i *MockInventoryService) AssignVariant(ctx context.Context, sku, code string, variant Variant) (Product, error) {
+	i.AddCall(ctx, sku, code, variant)
+	return i.AssignVariantFunc(ctx, sku, code, variant)
+}
+
+func (i *MockInventoryService) GetConversions(ctx context.Context, sku string, limit, offset int) ([]Conversion, error) {
+	i.AddCall(ctx, sku, limit, offset)
+	return i.GetConversionsFunc(ctx, sku, limit, offset)
+}
+
 func (i *MockInventoryService) SubscribeInventory(ch chan<- ProductInventory) (id InventorySubID) {
 	i.AddCall(ch)
 	return i.SubscribeInventoryFunc(ch)
@@ -73,8 +414,14 @@ type MockReservationService struct {
 	GetReservationsFunc func(ctx context.Context, options GetReservationsOptions, limit, offset int) ([]Reservation, error)
 	GetReservationFunc  func(ctx context.Context, ID uint64) (Reservation, error)
 
+	GetReservationSourcesFunc func(ctx context.Context, ID uint64) ([]Peg, error)
+	ApproveReservationFunc    func(ctx context.Context, ID uint64, approver string) (Reservation, error)
+	RejectReservationFunc     func(ctx context.Context, ID uint64, approver, reason string) (Reservation, error)
+
 	SubscribeReservationsFunc   func(ch chan<- Reservation) (id ReservationsSubID)
 	UnsubscribeReservationsFunc func(id ReservationsSubID)
+	SubscribeAlertsFunc         func(ch chan<- ReservationAlert) (id AlertsSubID)
+	UnsubscribeAlertsFunc       func(id AlertsSubID)
 	*testutil.CallWatcher
 }
 
@@ -84,9 +431,18 @@ func NewMockReservationService() *MockReservationService {
 		GetReservationsFunc: func(ctx context.Context, options GetReservationsOptions, limit, offset int) ([]Reservation, error) {
 			return []Reservation{}, nil
 		},
-		GetReservationFunc:          func(ctx context.Context, ID uint64) (Reservation, error) { return Reservation{}, nil },
+		GetReservationFunc:        func(ctx context.Context, ID uint64) (Reservation, error) { return Reservation{}, nil },
+		GetReservationSourcesFunc: func(ctx context.Context, ID uint64) ([]Peg, error) { return []Peg{}, nil },
+		ApproveReservationFunc: func(ctx context.Context, ID uint64, approver string) (Reservation, error) {
+			return Reservation{ID: ID, State: Open, DecidedBy: approver}, nil
+		},
+		RejectReservationFunc: func(ctx context.Context, ID uint64, approver, reason string) (Reservation, error) {
+			return Reservation{ID: ID, State: Rejected, DecidedBy: approver, DecisionReason: reason}, nil
+		},
 		SubscribeReservationsFunc:   func(ch chan<- Reservation) (id ReservationsSubID) { return "" },
 		UnsubscribeReservationsFunc: func(id ReservationsSubID) {},
+		SubscribeAlertsFunc:         func(ch chan<- ReservationAlert) (id AlertsSubID) { return "" },
+		UnsubscribeAlertsFunc:       func(id AlertsSubID) {},
 		CallWatcher:                 testutil.NewCallWatcher(),
 	}
 }
@@ -106,6 +462,21 @@ func (r *MockReservationService) GetReservation(ctx context.Context, ID uint64)
 	return r.GetReservationFunc(ctx, ID)
 }
 
+func (r *MockReservationService) GetReservationSources(ctx context.Context, ID uint64) ([]Peg, error) {
+	r.CallWatcher.AddCall(ctx, ID)
+	return r.GetReservationSourcesFunc(ctx, ID)
+}
+
+func (r *MockReservationService) ApproveReservation(ctx context.Context, ID uint64, approver string) (Reservation, error) {
+	r.CallWatcher.AddCall(ctx, ID, approver)
+	return r.ApproveReservationFunc(ctx, ID, approver)
+}
+
+func (r *MockReservationService) RejectReservation(ctx context.Context, ID uint64, approver, reason string) (Reservation, error) {
+	r.CallWatcher.AddCall(ctx, ID, approver, reason)
+	return r.RejectReservationFunc(ctx, ID, approver, reason)
+}
+
 func (r *MockReservationService) SubscribeReservations(ch chan<- Reservation) (id ReservationsSubID) {
 	r.CallWatcher.AddCall(ch)
 	return r.SubscribeReservationsFunc(ch)
@@ -115,3 +486,334 @@ func (r *MockReservationService) UnsubscribeReservations(id ReservationsSubID) {
 	r.CallWatcher.AddCall(id)
 	r.UnsubscribeReservationsFunc(id)
 }
+
+func (r *MockReservationService) SubscribeAlerts(ch chan<- ReservationAlert) (id AlertsSubID) {
+	r.CallWatcher.AddCall(ch)
+	return r.SubscribeAlertsFunc(ch)
+}
+
+func (r *MockReservationService) UnsubscribeAlerts(id AlertsSubID) {
+	r.CallWatcher.AddCall(id)
+	r.UnsubscribeAlertsFunc(id)
+}
+
+type MockCategoryService struct {
+	CreateCategoryFunc       func(ctx context.Context, category Category) (Category, error)
+	UpdateCategoryFunc       func(ctx context.Context, category Category) (Category, error)
+	DeleteCategoryFunc       func(ctx context.Context, ID uint64) error
+	GetCategoryFunc          func(ctx context.Context, ID uint64) (Category, error)
+	GetCategoriesFunc        func(ctx context.Context, limit, offset int) ([]Category, error)
+	GetChildCategoriesFunc   func(ctx context.Context, ID uint64) ([]Category, error)
+	GetCategoryInventoryFunc func(ctx context.Context, ID uint64) (CategoryInventory, error)
+	*testutil.CallWatcher
+}
+
+func NewMockCategoryService() *MockCategoryService {
+	return &MockCategoryService{
+		CreateCategoryFunc: func(ctx context.Context, category Category) (Category, error) { return category, nil },
+		UpdateCategoryFunc: func(ctx context.Context, category Category) (Category, error) { return category, nil },
+		DeleteCategoryFunc: func(ctx context.Context, ID uint64) error { return nil },
+		GetCategoryFunc:    func(ctx context.Context, ID uint64) (Category, error) { return Category{ID: ID}, nil },
+		GetCategoriesFunc: func(ctx context.Context, limit, offset int) ([]Category, error) {
+			return []Category{}, nil
+		},
+		GetChildCategoriesFunc: func(ctx context.Context, ID uint64) ([]Category, error) { return []Category{}, nil },
+		GetCategoryInventoryFunc: func(ctx context.Context, ID uint64) (CategoryInventory, error) {
+			return CategoryInventory{}, nil
+		},
+		CallWatcher: testutil.NewCallWatcher(),
+	}
+}
+
+func (c *MockCategoryService) CreateCategory(ctx context.Context, category Category) (Category, error) {
+	c.AddCall(ctx, category)
+	return c.CreateCategoryFunc(ctx, category)
+}
+
+func (c *MockCategoryService) UpdateCategory(ctx context.Context, category Category) (Category, error) {
+	c.AddCall(ctx, category)
+	return c.UpdateCategoryFunc(ctx, category)
+}
+
+func (c *MockCategoryService) DeleteCategory(ctx context.Context, ID uint64) error {
+	c.AddCall(ctx, ID)
+	return c.DeleteCategoryFunc(ctx, ID)
+}
+
+func (c *MockCategoryService) GetCategory(ctx context.Context, ID uint64) (Category, error) {
+	c.AddCall(ctx, ID)
+	return c.GetCategoryFunc(ctx, ID)
+}
+
+func (c *MockCategoryService) GetCategories(ctx context.Context, limit, offset int) ([]Category, error) {
+	c.AddCall(ctx, limit, offset)
+	return c.GetCategoriesFunc(ctx, limit, offset)
+}
+
+func (c *MockCategoryService) GetChildCategories(ctx context.Context, ID uint64) ([]Category, error) {
+	c.AddCall(ctx, ID)
+	return c.GetChildCategoriesFunc(ctx, ID)
+}
+
+func (c *MockCategoryService) GetCategoryInventory(ctx context.Context, ID uint64) (CategoryInventory, error) {
+	c.AddCall(ctx, ID)
+	return c.GetCategoryInventoryFunc(ctx, ID)
+}
+
+type MockReportService struct {
+	GetFillRatesFunc        func(ctx context.Context, group FillRateGroup, options ReportOptions) ([]FillRate, error)
+	GetTimeToCloseFunc      func(ctx context.Context, options ReportOptions) ([]TimeToClose, error)
+	GetDailyProductionFunc  func(ctx context.Context, options ReportOptions) ([]DailyProduction, error)
+	GetTopOpenDemandFunc    func(ctx context.Context, options ReportOptions, limit int) ([]SkuDemand, error)
+	GetLineYieldsFunc       func(ctx context.Context, options ReportOptions) ([]LineYield, error)
+	GetLineOutputFunc       func(ctx context.Context, options ReportOptions) ([]LineOutput, error)
+	GetReservationAgingFunc func(ctx context.Context, options ReportOptions) ([]ReservationAging, error)
+	GetInventoryAgingFunc   func(ctx context.Context, options ReportOptions, slowDays int) ([]InventoryAge, error)
+	*testutil.CallWatcher
+}
+
+func NewMockReportService() *MockReportService {
+	return &MockReportService{
+		GetFillRatesFunc: func(ctx context.Context, group FillRateGroup, options ReportOptions) ([]FillRate, error) {
+			return []FillRate{}, nil
+		},
+		GetTimeToCloseFunc: func(ctx context.Context, options ReportOptions) ([]TimeToClose, error) {
+			return []TimeToClose{}, nil
+		},
+		GetDailyProductionFunc: func(ctx context.Context, options ReportOptions) ([]DailyProduction, error) {
+			return []DailyProduction{}, nil
+		},
+		GetTopOpenDemandFunc: func(ctx context.Context, options ReportOptions, limit int) ([]SkuDemand, error) {
+			return []SkuDemand{}, nil
+		},
+		GetLineYieldsFunc: func(ctx context.Context, options ReportOptions) ([]LineYield, error) {
+			return []LineYield{}, nil
+		},
+		GetLineOutputFunc: func(ctx context.Context, options ReportOptions) ([]LineOutput, error) {
+			return []LineOutput{}, nil
+		},
+		GetReservationAgingFunc: func(ctx context.Context, options ReportOptions) ([]ReservationAging, error) {
+			return []ReservationAging{}, nil
+		},
+		GetInventoryAgingFunc: func(ctx context.Context, options ReportOptions, slowDays int) ([]InventoryAge, error) {
+			return []InventoryAge{}, nil
+		},
+		CallWatcher: testutil.NewCallWatcher(),
+	}
+}
+
+func (r *MockReportService) GetFillRates(ctx context.Context, group FillRateGroup, options ReportOptions) ([]FillRate, error) {
+	r.AddCall(ctx, group, options)
+	return r.GetFillRatesFunc(ctx, group, options)
+}
+
+func (r *MockReportService) GetTimeToClose(ctx context.Context, options ReportOptions) ([]TimeToClose, error) {
+	r.AddCall(ctx, options)
+	return r.GetTimeToCloseFunc(ctx, options)
+}
+
+func (r *MockReportService) GetDailyProduction(ctx context.Context, options ReportOptions) ([]DailyProduction, error) {
+	r.AddCall(ctx, options)
+	return r.GetDailyProductionFunc(ctx, options)
+}
+
+func (r *MockReportService) GetTopOpenDemand(ctx context.Context, options ReportOptions, limit int) ([]SkuDemand, error) {
+	r.AddCall(ctx, options, limit)
+	return r.GetTopOpenDemandFunc(ctx, options, limit)
+}
+
+func (r *MockReportService) GetLineYields(ctx context.Context, options ReportOptions) ([]LineYield, error) {
+	r.AddCall(ctx, options)
+	return r.GetLineYieldsFunc(ctx, options)
+}
+
+func (r *MockReportService) GetLineOutput(ctx context.Context, options ReportOptions) ([]LineOutput, error) {
+	r.AddCall(ctx, options)
+	return r.GetLineOutputFunc(ctx, options)
+}
+
+func (r *MockReportService) GetReservationAging(ctx context.Context, options ReportOptions) ([]ReservationAging, error) {
+	r.AddCall(ctx, options)
+	return r.GetReservationAgingFunc(ctx, options)
+}
+
+func (r *MockReportService) GetInventoryAging(ctx context.Context, options ReportOptions, slowDays int) ([]InventoryAge, error) {
+	r.AddCall(ctx, options, slowDays)
+	return r.GetInventoryAgingFunc(ctx, options, slowDays)
+}
+
+type MockCountService struct {
+	StartCountFunc       func(ctx context.Context, skus []string) (CountSession, error)
+	RecordCountFunc      func(ctx context.Context, ID uint64, counts []CountEntry) (CountSession, error)
+	ApproveCountFunc     func(ctx context.Context, ID uint64, approver string) (CountSession, error)
+	CancelCountFunc      func(ctx context.Context, ID uint64, user string) (CountSession, error)
+	GetCountSessionFunc  func(ctx context.Context, ID uint64) (CountSession, error)
+	GetCountSessionsFunc func(ctx context.Context, state CountState, limit, offset int) ([]CountSession, error)
+	*testutil.CallWatcher
+}
+
+func NewMockCountService() *MockCountService {
+	return &MockCountService{
+		StartCountFunc: func(ctx context.Context, skus []string) (CountSession, error) {
+			return CountSession{State: CountOpen}, nil
+		},
+		RecordCountFunc: func(ctx context.Context, ID uint64, counts []CountEntry) (CountSession, error) {
+			return CountSession{ID: ID, State: CountOpen}, nil
+		},
+		ApproveCountFunc: func(ctx context.Context, ID uint64, approver string) (CountSession, error) {
+			return CountSession{ID: ID, State: CountApproved, ClosedBy: approver}, nil
+		},
+		CancelCountFunc: func(ctx context.Context, ID uint64, user string) (CountSession, error) {
+			return CountSession{ID: ID, State: CountCancelled, ClosedBy: user}, nil
+		},
+		GetCountSessionFunc: func(ctx context.Context, ID uint64) (CountSession, error) {
+			return CountSession{ID: ID, State: CountOpen}, nil
+		},
+		GetCountSessionsFunc: func(ctx context.Context, state CountState, limit, offset int) ([]CountSession, error) {
+			return []CountSession{}, nil
+		},
+		CallWatcher: testutil.NewCallWatcher(),
+	}
+}
+
+func (c *MockCountService) StartCount(ctx context.Context, skus []string) (CountSession, error) {
+	c.AddCall(ctx, skus)
+	return c.StartCountFunc(ctx, skus)
+}
+
+func (c *MockCountService) RecordCount(ctx context.Context, ID uint64, counts []CountEntry) (CountSession, error) {
+	c.AddCall(ctx, ID, counts)
+	return c.RecordCountFunc(ctx, ID, counts)
+}
+
+func (c *MockCountService) ApproveCount(ctx context.Context, ID uint64, approver string) (CountSession, error) {
+	c.AddCall(ctx, ID, approver)
+	return c.ApproveCountFunc(ctx, ID, approver)
+}
+
+func (c *MockCountService) CancelCount(ctx context.Context, ID uint64, user string) (CountSession, error) {
+	c.AddCall(ctx, ID, user)
+	return c.CancelCountFunc(ctx, ID, user)
+}
+
+func (c *MockCountService) GetCountSession(ctx context.Context, ID uint64) (CountSession, error) {
+	c.AddCall(ctx, ID)
+	return c.GetCountSessionFunc(ctx, ID)
+}
+
+func (c *MockCountService) GetCountSessions(ctx context.Context, state CountState, limit, offset int) ([]CountSession, error) {
+	c.AddCall(ctx, state, limit, offset)
+	return c.GetCountSessionsFunc(ctx, state, limit, offset)
+}
+
+type MockReturnService struct {
+	CreateReturnFunc  func(ctx context.Context, rma ReturnAuthorization) (ReturnAuthorization, error)
+	ReceiveReturnFunc func(ctx context.Context, ID uint64, user string) (ReturnAuthorization, error)
+	InspectReturnFunc func(ctx context.Context, ID uint64, outcome InspectionOutcome, note, user string) (ReturnAuthorization, error)
+	GetReturnFunc     func(ctx context.Context, ID uint64) (ReturnAuthorization, error)
+	GetReturnsFunc    func(ctx context.Context, options GetReturnsOptions, limit, offset int) ([]ReturnAuthorization, error)
+	*testutil.CallWatcher
+}
+
+func NewMockReturnService() *MockReturnService {
+	return &MockReturnService{
+		CreateReturnFunc: func(ctx context.Context, rma ReturnAuthorization) (ReturnAuthorization, error) {
+			rma.State = ReturnRequested
+			return rma, nil
+		},
+		ReceiveReturnFunc: func(ctx context.Context, ID uint64, user string) (ReturnAuthorization, error) {
+			return ReturnAuthorization{ID: ID, State: ReturnReceived, ReceivedBy: user}, nil
+		},
+		InspectReturnFunc: func(ctx context.Context, ID uint64, outcome InspectionOutcome, note, user string) (ReturnAuthorization, error) {
+			return ReturnAuthorization{ID: ID, State: ReturnRestocked, InspectedBy: user, Note: note}, nil
+		},
+		GetReturnFunc: func(ctx context.Context, ID uint64) (ReturnAuthorization, error) {
+			return ReturnAuthorization{ID: ID, State: ReturnRequested}, nil
+		},
+		GetReturnsFunc: func(ctx context.Context, options GetReturnsOptions, limit, offset int) ([]ReturnAuthorization, error) {
+			return []ReturnAuthorization{}, nil
+		},
+		CallWatcher: testutil.NewCallWatcher(),
+	}
+}
+
+func (r *MockReturnService) CreateReturn(ctx context.Context, rma ReturnAuthorization) (ReturnAuthorization, error) {
+	r.AddCall(ctx, rma)
+	return r.CreateReturnFunc(ctx, rma)
+}
+
+func (r *MockReturnService) ReceiveReturn(ctx context.Context, ID uint64, user string) (ReturnAuthorization, error) {
+	r.AddCall(ctx, ID, user)
+	return r.ReceiveReturnFunc(ctx, ID, user)
+}
+
+func (r *MockReturnService) InspectReturn(ctx context.Context, ID uint64, outcome InspectionOutcome, note, user string) (ReturnAuthorization, error) {
+	r.AddCall(ctx, ID, outcome, note, user)
+	return r.InspectReturnFunc(ctx, ID, outcome, note, user)
+}
+
+func (r *MockReturnService) GetReturn(ctx context.Context, ID uint64) (ReturnAuthorization, error) {
+	r.AddCall(ctx, ID)
+	return r.GetReturnFunc(ctx, ID)
+}
+
+func (r *MockReturnService) GetReturns(ctx context.Context, options GetReturnsOptions, limit, offset int) ([]ReturnAuthorization, error) {
+	r.AddCall(ctx, options, limit, offset)
+	return r.GetReturnsFunc(ctx, options, limit, offset)
+}
+
+type MockPurchaseOrderService struct {
+	CreatePurchaseOrderFunc  func(ctx context.Context, po PurchaseOrder) (PurchaseOrder, error)
+	ReceivePurchaseOrderFunc func(ctx context.Context, ID uint64, rr ReceiptRequest, user string) (PurchaseOrder, error)
+	ClosePurchaseOrderFunc   func(ctx context.Context, ID uint64, user string) (PurchaseOrder, error)
+	GetPurchaseOrderFunc     func(ctx context.Context, ID uint64) (PurchaseOrder, error)
+	GetPurchaseOrdersFunc    func(ctx context.Context, options GetPurchaseOrdersOptions, limit, offset int) ([]PurchaseOrder, error)
+	*testutil.CallWatcher
+}
+
+func NewMockPurchaseOrderService() *MockPurchaseOrderService {
+	return &MockPurchaseOrderService{
+		CreatePurchaseOrderFunc: func(ctx context.Context, po PurchaseOrder) (PurchaseOrder, error) {
+			po.State = PurchaseOrderOpen
+			return po, nil
+		},
+		ReceivePurchaseOrderFunc: func(ctx context.Context, ID uint64, rr ReceiptRequest, user string) (PurchaseOrder, error) {
+			return PurchaseOrder{ID: ID, State: PurchaseOrderPartiallyReceived}, nil
+		},
+		ClosePurchaseOrderFunc: func(ctx context.Context, ID uint64, user string) (PurchaseOrder, error) {
+			return PurchaseOrder{ID: ID, State: PurchaseOrderClosed, ClosedBy: user}, nil
+		},
+		GetPurchaseOrderFunc: func(ctx context.Context, ID uint64) (PurchaseOrder, error) {
+			return PurchaseOrder{ID: ID, State: PurchaseOrderOpen}, nil
+		},
+		GetPurchaseOrdersFunc: func(ctx context.Context, options GetPurchaseOrdersOptions, limit, offset int) ([]PurchaseOrder, error) {
+			return []PurchaseOrder{}, nil
+		},
+		CallWatcher: testutil.NewCallWatcher(),
+	}
+}
+
+func (p *MockPurchaseOrderService) CreatePurchaseOrder(ctx context.Context, po PurchaseOrder) (PurchaseOrder, error) {
+	p.AddCall(ctx, po)
+	return p.CreatePurchaseOrderFunc(ctx, po)
+}
+
+func (p *MockPurchaseOrderService) ReceivePurchaseOrder(ctx context.Context, ID uint64, rr ReceiptRequest, user string) (PurchaseOrder, error) {
+	p.AddCall(ctx, ID, rr, user)
+	return p.ReceivePurchaseOrderFunc(ctx, ID, rr, user)
+}
+
+func (p *MockPurchaseOrderService) ClosePurchaseOrder(ctx context.Context, ID uint64, user string) (PurchaseOrder, error) {
+	p.AddCall(ctx, ID, user)
+	return p.ClosePurchaseOrderFunc(ctx, ID, user)
+}
+
+func (p *MockPurchaseOrderService) GetPurchaseOrder(ctx context.Context, ID uint64) (PurchaseOrder, error) {
+	p.AddCall(ctx, ID)
+	return p.GetPurchaseOrderFunc(ctx, ID)
+}
+
+func (p *MockPurchaseOrderService) GetPurchaseOrders(ctx context.Context, options GetPurchaseOrdersOptions, limit, offset int) ([]PurchaseOrder, error) {
+	p.AddCall(ctx, options, limit, offset)
+	return p.GetPurchaseOrdersFunc(ctx, options, limit, offset)
+}
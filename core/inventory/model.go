@@ -8,40 +8,250 @@ import (
 	"github.com/pkg/errors"
 )
 
-// ProductionRequest is a value object. A request to produce inventory.
+// ProductionRequest is a value object. A request to produce inventory. Quantity is the good output of the run and is
+// the only part added to inventory, Scrapped is the output lost on the line.
 type ProductionRequest struct {
-	RequestID string `json:"requestID"`
-	Quantity  int64  `json:"quantity"`
+	RequestID string  `json:"requestID"`
+	Quantity  int64   `json:"quantity"`
+	Scrapped  int64   `json:"scrapped"`
+	UnitCost  float64 `json:"unitCost"`
+	Line      string  `json:"line"`
+	Shift     string  `json:"shift"`
+	Operator  string  `json:"operator"`
+}
+
+// SkuProductionRequest is a value object. A production request for a given SKU, submitted as part of a batch.
+type SkuProductionRequest struct {
+	Sku string `json:"sku"`
+	ProductionRequest
+}
+
+type ProductionStatus string
+
+const (
+	ProductionProduced  ProductionStatus = "Produced"
+	ProductionDuplicate ProductionStatus = "Duplicate"
+	ProductionFailed    ProductionStatus = "Failed"
+)
+
+// ProductionResult is a value object. The outcome of a single production request in a batch.
+type ProductionResult struct {
+	Sku       string           `json:"sku"`
+	RequestID string           `json:"requestID"`
+	Status    ProductionStatus `json:"status"`
+	Error     string           `json:"error,omitempty"`
 }
 
-// ProductionEvent is an entity. An addition to inventory through production of a Product.
+// ProductionEvent is an entity. An addition to inventory through production of a Product. Remaining is the part of
+// the event's output not yet allocated or written off, taken oldest first, and Held the part of that still waiting on
+// inspection. Scrapped units never became inventory and are kept only to report the yield of the line and shift that
+// produced them.
 type ProductionEvent struct {
 	ID        uint64    `json:"id"`
 	RequestID string    `json:"requestID"`
 	Sku       string    `json:"sku"`
 	Quantity  int64     `json:"quantity"`
+	Scrapped  int64     `json:"scrapped"`
+	UnitCost  float64   `json:"unitCost"`
+	Remaining int64     `json:"remaining"`
+	Held      int64     `json:"held"`
+	Line      string    `json:"line,omitempty"`
+	Shift     string    `json:"shift,omitempty"`
+	Operator  string    `json:"operator,omitempty"`
 	Created   time.Time `json:"created"`
 }
 
-// Product is a value object. A SKU able to be produced by the factory.
+// Product is a value object. A SKU able to be produced by the factory. Production of a product that RequiresInspection
+// is held until it passes inspection. Reservations of more than ApprovalThreshold units wait for approval and
+// reservations left open for more than ReservationSlaHours raise an alert, a value of zero for either defers to the
+// configured default. A product belonging to a Style is one of its variants, told apart from the others by the Variant
+// value it has for each of the style's axes. Freezes halt activity on the product while an incident is dealt with.
 type Product struct {
-	Sku  string `json:"sku"`
-	Upc  string `json:"upc"`
-	Name string `json:"name"`
+	Sku                 string      `json:"sku"`
+	Upc                 string      `json:"upc"`
+	Name                string      `json:"name"`
+	Type                ProductType `json:"type"`
+	Attributes          Attributes  `json:"attributes,omitempty"`
+	CategoryID          uint64      `json:"categoryId,omitempty"`
+	RequiresInspection  bool        `json:"requiresInspection,omitempty"`
+	ApprovalThreshold   int64       `json:"approvalThreshold,omitempty"`
+	ReservationSlaHours int64       `json:"reservationSlaHours,omitempty"`
+	Style               string      `json:"style,omitempty"`
+	Variant             Variant     `json:"variant,omitempty"`
+	Freezes             Freezes     `json:"freezes,omitempty"`
+}
+
+// Frozen reports whether the given activity is frozen on the product.
+func (p Product) Frozen(freeze Freeze) bool {
+	_, ok := p.Freezes[freeze]
+	return ok
+}
+
+// Freeze is an activity on a product that an administrator can halt without a deploy.
+type Freeze string
+
+const (
+	// FreezeReservations rejects new reservations of the product.
+	FreezeReservations Freeze = "Reservations"
+	// FreezeAllocation leaves open reservations unfilled, the product's stock is not allocated to them or to any
+	// reservation it substitutes for.
+	FreezeAllocation Freeze = "Allocation"
+	// FreezeIntake rejects production of the product.
+	FreezeIntake Freeze = "Intake"
+)
+
+func ParseFreeze(v string) (Freeze, error) {
+	switch Freeze(v) {
+	case FreezeReservations, FreezeAllocation, FreezeIntake:
+		return Freeze(v), nil
+	}
+	return "", errors.Errorf("unknown freeze %s", v)
+}
+
+// FreezeSetting records who froze an activity on a product, why and when.
+type FreezeSetting struct {
+	Reason string    `json:"reason"`
+	User   string    `json:"user"`
+	Since  time.Time `json:"since"`
+}
+
+// Freezes are the activities frozen on a product.
+type Freezes map[Freeze]FreezeSetting
+
+// FreezeChange is a value object. An activity being frozen or unfrozen on a product, published so that other systems
+// can react to it.
+type FreezeChange struct {
+	Sku     string    `json:"sku"`
+	Freeze  Freeze    `json:"freeze"`
+	Frozen  bool      `json:"frozen"`
+	Reason  string    `json:"reason"`
+	User    string    `json:"user"`
+	Changed time.Time `json:"changed"`
+}
+
+// Attributes are arbitrary values attached to a product such as weight, color or hazmat class. They are schema-less
+// unless an AttributeDefinition exists for the name, in which case the value must be of the defined type.
+type Attributes map[string]interface{}
+
+type AttributeType string
+
+const (
+	AttributeString  AttributeType = "string"
+	AttributeNumber  AttributeType = "number"
+	AttributeBoolean AttributeType = "boolean"
+)
+
+func ParseAttributeType(v string) (AttributeType, error) {
+	switch v {
+	case string(AttributeString):
+		return AttributeString, nil
+	case string(AttributeNumber):
+		return AttributeNumber, nil
+	case string(AttributeBoolean):
+		return AttributeBoolean, nil
+	default:
+		return AttributeString, errors.New("invalid attribute type")
+	}
+}
+
+// AttributeDefinition is a value object. It constrains the type of a named product attribute.
+type AttributeDefinition struct {
+	Name string        `json:"name"`
+	Type AttributeType `json:"type"`
 }
 
-// ProductInventory is an entity. It represents current inventory levels for the associated product.
+type ProductType string
+
+const (
+	// Standard products are produced by the factory and hold their own inventory.
+	Standard ProductType = "Standard"
+	// Kit products are never produced. They are bundles of other products and their availability is derived from
+	// the availability of their components.
+	Kit ProductType = "Kit"
+)
+
+func ParseProductType(v string) (ProductType, error) {
+	switch v {
+	case string(Standard), "":
+		return Standard, nil
+	case string(Kit):
+		return Kit, nil
+	default:
+		return Standard, errors.New("invalid product type")
+	}
+}
+
+// KitComponent is a value object. The quantity of a component product that goes into a single kit.
+type KitComponent struct {
+	Sku      string `json:"sku"`
+	Quantity int64  `json:"quantity"`
+}
+
+// ProductInventory is an entity. It represents current inventory levels for the associated product. OnHand is the
+// quantity physically present, Reserved is the portion of OnHand already promised to reservations, Held is the portion
+// awaiting inspection and Available is what is left to allocate. OpenDemand is the requested quantity of open
+// reservations that has not been filled yet.
 type ProductInventory struct {
 	Product
-	Available int64 `json:"available"`
+	OnHand     int64 `json:"onHand"`
+	Reserved   int64 `json:"reserved"`
+	Available  int64 `json:"available"`
+	Held       int64 `json:"held"`
+	OpenDemand int64 `json:"openDemand"`
+}
+
+// Category is an entity. A node in the tree used to organize products. Root categories have no parent.
+type Category struct {
+	ID       uint64 `json:"id"`
+	Name     string `json:"name"`
+	ParentID uint64 `json:"parentId,omitempty"`
+}
+
+// CategoryInventory is a value object. Inventory totals across every product in a category and all of its
+// descendants. Kits only contribute their open demand since their stock is held by their components. Stock held for
+// inspection is on hand but neither reserved nor available.
+type CategoryInventory struct {
+	Category
+	Products   int64 `json:"products"`
+	OnHand     int64 `json:"onHand"`
+	Reserved   int64 `json:"reserved"`
+	Available  int64 `json:"available"`
+	Held       int64 `json:"held"`
+	OpenDemand int64 `json:"openDemand"`
+}
+
+// Style is an entity. A parent product such as a shirt that is sold as several variant SKUs, one for each combination
+// of values along its Axes such as size and color.
+type Style struct {
+	Code    string    `json:"code"`
+	Name    string    `json:"name"`
+	Axes    []string  `json:"axes"`
+	Created time.Time `json:"created"`
+}
+
+// Variant is the value a product has for each axis of its style, such as {"size": "M", "color": "red"}.
+type Variant map[string]string
+
+// StyleInventory is a value object. A style with the inventory of each of its variants and totals across them. Values
+// lists the values found along each axis in order of appearance so that the variants can be laid out as a matrix.
+type StyleInventory struct {
+	Style
+	Values     map[string][]string `json:"values"`
+	Variants   []ProductInventory  `json:"variants"`
+	OnHand     int64               `json:"onHand"`
+	Reserved   int64               `json:"reserved"`
+	Available  int64               `json:"available"`
+	OpenDemand int64               `json:"openDemand"`
 }
 
 type ReserveState string
 
 const (
-	Open   ReserveState = "Open"
-	Closed ReserveState = "Closed"
-	None   ReserveState = ""
+	Open            ReserveState = "Open"
+	Closed          ReserveState = "Closed"
+	PendingApproval ReserveState = "PendingApproval"
+	Rejected        ReserveState = "Rejected"
+	None            ReserveState = ""
 )
 
 func ParseReserveState(v string) (ReserveState, error) {
@@ -50,6 +260,10 @@ func ParseReserveState(v string) (ReserveState, error) {
 		return Open, nil
 	case string(Closed):
 		return Closed, nil
+	case string(PendingApproval):
+		return PendingApproval, nil
+	case string(Rejected):
+		return Rejected, nil
 	case string(None):
 		return None, nil
 	default:
@@ -62,16 +276,631 @@ type ReservationRequest struct {
 	RequestID string `json:"requestId"`
 	Requester string `json:"requester"`
 	Quantity  int64  `json:"quantity"`
+	// CustomerPartNumber identifies the product by one of its cross references when Sku is not given, references
+	// scoped to the Requester taking precedence.
+	CustomerPartNumber string `json:"customerPartNumber,omitempty"`
+	// AllowSubstitutes lets the remainder be filled from the SKU's substitutes when it is short.
+	AllowSubstitutes bool `json:"allowSubstitutes,omitempty"`
+	// SplitBackorder overrides the configured choice of whether a partly filled reservation is split into a closed
+	// reservation and a backorder for the remainder.
+	SplitBackorder *bool `json:"splitBackorder,omitempty"`
 }
 
-// Reservation is an entity. An amount of inventory set aside for a given Customer.
+// Reservation is an entity. An amount of inventory set aside for a given Customer. ReservedQuantity is counted in
+// units of the reserved SKU even when some of it was filled by substitutes, Fills records the quantity of each SKU
+// actually set aside. A backorder split from a partly filled reservation refers to it by ParentID. A reservation
+// waiting for approval is not filled until it is approved, DecidedBy records who approved or rejected it.
 type Reservation struct {
-	ID                uint64       `json:"id"`
-	RequestID         string       `json:"requestId"`
-	Requester         string       `json:"requester"`
-	Sku               string       `json:"sku"`
-	State             ReserveState `json:"state"`
-	ReservedQuantity  int64        `json:"reservedQuantity"`
-	RequestedQuantity int64        `json:"requestedQuantity"`
-	Created           time.Time    `json:"created"`
+	ID                uint64            `json:"id"`
+	RequestID         string            `json:"requestId"`
+	Requester         string            `json:"requester"`
+	Sku               string            `json:"sku"`
+	State             ReserveState      `json:"state"`
+	ReservedQuantity  int64             `json:"reservedQuantity"`
+	RequestedQuantity int64             `json:"requestedQuantity"`
+	CostOfGoods       float64           `json:"costOfGoods"`
+	Created           time.Time         `json:"created"`
+	AllowSubstitutes  bool              `json:"allowSubstitutes,omitempty"`
+	Fills             []ReservationFill `json:"fills,omitempty"`
+	ParentID          uint64            `json:"parentId,omitempty"`
+	SplitBackorder    bool              `json:"splitBackorder,omitempty"`
+	DecidedBy         string            `json:"decidedBy,omitempty"`
+	Decided           *time.Time        `json:"decided,omitempty"`
+	DecisionReason    string            `json:"decisionReason,omitempty"`
+}
+
+// ReservationFill is a value object. The quantity of a SKU set aside for a reservation.
+type ReservationFill struct {
+	Sku      string `json:"sku"`
+	Quantity int64  `json:"quantity"`
+}
+
+// Peg is a value object. A quantity of a production event's output that was allocated to a reservation.
+type Peg struct {
+	ProductionEventID    uint64       `json:"productionEventId"`
+	ProductionRequestID  string       `json:"productionRequestId"`
+	ReservationID        uint64       `json:"reservationId"`
+	ReservationRequestID string       `json:"reservationRequestId"`
+	Requester            string       `json:"requester"`
+	State                ReserveState `json:"state"`
+	Sku                  string       `json:"sku"`
+	Quantity             int64        `json:"quantity"`
+	Created              time.Time    `json:"created"`
+}
+
+// SubstituteRule is an entity. It allows Quantity of Sku to be replaced by SubstituteQuantity of SubstituteSku when Sku
+// is short. A TwoWay rule also allows the reverse.
+type SubstituteRule struct {
+	ID                 uint64    `json:"id"`
+	Sku                string    `json:"sku"`
+	SubstituteSku      string    `json:"substituteSku"`
+	Quantity           int64     `json:"quantity"`
+	SubstituteQuantity int64     `json:"substituteQuantity"`
+	TwoWay             bool      `json:"twoWay"`
+	Created            time.Time `json:"created"`
+}
+
+type IdentifierType string
+
+const (
+	IdentifierSku          IdentifierType = "Sku"
+	IdentifierUpc          IdentifierType = "UPC"
+	IdentifierCustomerPart IdentifierType = "CustomerPart"
+	IdentifierSupplierPart IdentifierType = "SupplierPart"
+)
+
+func ParseIdentifierType(v string) (IdentifierType, error) {
+	switch v {
+	case string(IdentifierUpc):
+		return IdentifierUpc, nil
+	case string(IdentifierCustomerPart):
+		return IdentifierCustomerPart, nil
+	case string(IdentifierSupplierPart):
+		return IdentifierSupplierPart, nil
+	default:
+		return "", errors.New("invalid identifier type")
+	}
+}
+
+// CrossReference is an entity. Another identifier for a SKU such as an additional UPC or a customer's part number. A
+// cross reference with a Requester only applies to that requester and takes precedence over one without for the same
+// identifier. Resolving an identifier that is a SKU or a product's own UPC gives a cross reference with no ID.
+type CrossReference struct {
+	ID         uint64         `json:"id,omitempty"`
+	Sku        string         `json:"sku"`
+	Identifier string         `json:"identifier"`
+	Type       IdentifierType `json:"type"`
+	Requester  string         `json:"requester,omitempty"`
+	Created    time.Time      `json:"created"`
+}
+
+// CostingMethod determines how the cost of produced inventory is carried and relieved.
+type CostingMethod string
+
+const (
+	// FIFO keeps a cost layer per production event and relieves the oldest layers first.
+	FIFO CostingMethod = "fifo"
+	// WeightedAverage folds every production event into a single layer at the average unit cost.
+	WeightedAverage CostingMethod = "average"
+)
+
+func ParseCostingMethod(v string) (CostingMethod, error) {
+	switch v {
+	case string(FIFO):
+		return FIFO, nil
+	case string(WeightedAverage):
+		return WeightedAverage, nil
+	default:
+		return FIFO, errors.New("invalid costing method")
+	}
+}
+
+// CostLayer is an entity. A quantity of unallocated inventory carried at a single unit cost.
+type CostLayer struct {
+	ID                uint64    `json:"id"`
+	Sku               string    `json:"sku"`
+	ProductionEventID uint64    `json:"productionEventId"`
+	UnitCost          float64   `json:"unitCost"`
+	Quantity          int64     `json:"quantity"`
+	Remaining         int64     `json:"remaining"`
+	Created           time.Time `json:"created"`
+}
+
+// Valuation is a value object. The quantity and value of a product's unallocated inventory.
+type Valuation struct {
+	Sku      string  `json:"sku"`
+	Quantity int64   `json:"quantity"`
+	UnitCost float64 `json:"unitCost"`
+	Value    float64 `json:"value"`
+}
+
+// InventoryValuation is a value object. The valuation of every product along with the total across all of them.
+type InventoryValuation struct {
+	Method   CostingMethod `json:"method"`
+	Total    float64       `json:"total"`
+	Products []Valuation   `json:"products"`
+}
+
+type ValuationReason string
+
+const (
+	ValuationProduction ValuationReason = "Production"
+	ValuationAllocation ValuationReason = "Allocation"
+	ValuationAdjustment ValuationReason = "Adjustment"
+	ValuationScrap      ValuationReason = "Scrap"
+	ValuationReturn     ValuationReason = "Return"
+	ValuationConversion ValuationReason = "Conversion"
+)
+
+// ValuationEntry is an entity. A change to a product's valuation, recorded each time cost is received or relieved.
+// Quantity and Cost are the signed change, the balance fields are the product's valuation after the change.
+type ValuationEntry struct {
+	ID              uint64          `json:"id"`
+	Sku             string          `json:"sku"`
+	Method          CostingMethod   `json:"method"`
+	Reason          ValuationReason `json:"reason"`
+	Reference       string          `json:"reference"`
+	Quantity        int64           `json:"quantity"`
+	Cost            float64         `json:"cost"`
+	BalanceQuantity int64           `json:"balanceQuantity"`
+	BalanceValue    float64         `json:"balanceValue"`
+	Created         time.Time       `json:"created"`
+}
+
+// ProductImport is a value object. A product read from an import file along with the line it was read from. The
+// settings that are set were given explicitly by the import and are applied even when false or zero, those left unset
+// are kept from an existing product.
+type ProductImport struct {
+	Line    int
+	Product Product
+
+	RequiresInspection  *bool
+	ApprovalThreshold   *int64
+	ReservationSlaHours *int64
+}
+
+type ImportStatus string
+
+const (
+	ImportCreated ImportStatus = "Created"
+	ImportUpdated ImportStatus = "Updated"
+	ImportFailed  ImportStatus = "Failed"
+)
+
+// ImportResult is a value object. The outcome of importing a single line of an import file.
+type ImportResult struct {
+	Line   int          `json:"line"`
+	Sku    string       `json:"sku,omitempty"`
+	Status ImportStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// InventoryLevel is a value object. The inventory of a product as it stood after a change was saved.
+type InventoryLevel struct {
+	Sku       string    `json:"sku"`
+	OnHand    int64     `json:"onHand"`
+	Available int64     `json:"available"`
+	Reserved  int64     `json:"reserved"`
+	Held      int64     `json:"held"`
+	Created   time.Time `json:"created"`
+}
+
+// InventoryBucket is a value object. How the available inventory of a product moved during a period, with the lowest,
+// highest and closing levels and the quantity produced and allocated in the period.
+type InventoryBucket struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Min       int64     `json:"min"`
+	Max       int64     `json:"max"`
+	Close     int64     `json:"close"`
+	Produced  int64     `json:"produced"`
+	Allocated int64     `json:"allocated"`
+}
+
+// ReportOptions limits a report to a period of time. From is inclusive and To is exclusive, a zero value leaves that
+// end of the period open.
+type ReportOptions struct {
+	From time.Time
+	To   time.Time
+	Sku  string
+}
+
+type FillRateGroup string
+
+const (
+	FillRateBySku       FillRateGroup = "sku"
+	FillRateByRequester FillRateGroup = "requester"
+)
+
+// FillRate is a value object. How much of the quantity requested by reservations for a SKU or requester was reserved.
+type FillRate struct {
+	Key          string  `json:"key"`
+	Reservations int64   `json:"reservations"`
+	Closed       int64   `json:"closed"`
+	Requested    int64   `json:"requested"`
+	Reserved     int64   `json:"reserved"`
+	FillRate     float64 `json:"fillRate"`
+}
+
+// TimeToClose is a value object. How long reservations for a SKU took to be filled, in seconds from creation.
+type TimeToClose struct {
+	Sku            string  `json:"sku"`
+	Closed         int64   `json:"closed"`
+	AverageSeconds float64 `json:"averageSeconds"`
+	P95Seconds     float64 `json:"p95Seconds"`
+}
+
+// DailyProduction is a value object. The production recorded on a single UTC day.
+type DailyProduction struct {
+	Date     time.Time `json:"date"`
+	Events   int64     `json:"events"`
+	Quantity int64     `json:"quantity"`
+}
+
+// LineYield is a value object. The good and scrapped output of a production line and shift, Yield being the percentage
+// of the output that was good.
+type LineYield struct {
+	Line     string  `json:"line"`
+	Shift    string  `json:"shift"`
+	Events   int64   `json:"events"`
+	Good     int64   `json:"good"`
+	Scrapped int64   `json:"scrapped"`
+	Yield    float64 `json:"yield"`
+}
+
+// LineOutput is a value object. The output of a production line and shift on a single UTC day.
+type LineOutput struct {
+	Date     time.Time `json:"date"`
+	Line     string    `json:"line"`
+	Shift    string    `json:"shift"`
+	Events   int64     `json:"events"`
+	Good     int64     `json:"good"`
+	Scrapped int64     `json:"scrapped"`
+}
+
+// SkuDemand is a value object. The quantity requested by open reservations for a SKU that is not yet reserved.
+type SkuDemand struct {
+	Sku          string `json:"sku"`
+	Reservations int64  `json:"reservations"`
+	OpenDemand   int64  `json:"openDemand"`
+}
+
+// ReservationAging is a value object. The reservations of a SKU by a requester that are still open, counted by how
+// long ago they were created, and the creation time of the oldest.
+type ReservationAging struct {
+	Sku          string    `json:"sku"`
+	Requester    string    `json:"requester"`
+	Reservations int64     `json:"reservations"`
+	Outstanding  int64     `json:"outstanding"`
+	UnderOneDay  int64     `json:"underOneDay"`
+	OneToThree   int64     `json:"oneToThreeDays"`
+	ThreeToSeven int64     `json:"threeToSevenDays"`
+	SevenTo14    int64     `json:"sevenToFourteenDays"`
+	Over14       int64     `json:"overFourteenDays"`
+	Oldest       time.Time `json:"oldest"`
+}
+
+// InventoryAge is a value object. The unallocated stock of a SKU counted by how long ago the production it came from was
+// received, oldest first as it is allocated. A SKU is SlowMoving when none of it has been allocated recently.
+type InventoryAge struct {
+	Sku           string     `json:"sku"`
+	Quantity      int64      `json:"quantity"`
+	Value         float64    `json:"value"`
+	UnderThirty   int64      `json:"underThirtyDays"`
+	ThirtyToSixty int64      `json:"thirtyToSixtyDays"`
+	SixtyToNinety int64      `json:"sixtyToNinetyDays"`
+	OverNinety    int64      `json:"overNinetyDays"`
+	Oldest        time.Time  `json:"oldest"`
+	LastAllocated *time.Time `json:"lastAllocated,omitempty"`
+	SlowMoving    bool       `json:"slowMoving"`
+}
+
+// AgedLot is a value object. The unallocated stock of a production lot along with when it was received, the unit cost
+// its SKU is carried at and when its SKU was last allocated.
+type AgedLot struct {
+	Sku           string
+	Remaining     int64
+	UnitCost      float64
+	Received      time.Time
+	LastAllocated *time.Time
+}
+
+type ReservationAlertType string
+
+const (
+	SlaBreached ReservationAlertType = "SlaBreached"
+)
+
+// ReservationAlert is a value object. Raised once for a reservation that has been open for longer than the SLA of its
+// product, Outstanding being the quantity still to be reserved when the alert was raised.
+type ReservationAlert struct {
+	Type           ReservationAlertType `json:"type"`
+	ReservationID  uint64               `json:"reservationId"`
+	RequestID      string               `json:"requestId"`
+	Requester      string               `json:"requester"`
+	Sku            string               `json:"sku"`
+	State          ReserveState         `json:"state"`
+	Outstanding    int64                `json:"outstanding"`
+	Created        time.Time            `json:"created"`
+	ThresholdHours int64                `json:"thresholdHours"`
+	Alerted        time.Time            `json:"alerted"`
+}
+
+// PlannedProduction is an entity. Production of a SKU expected to be completed by the due date.
+type PlannedProduction struct {
+	ID        uint64    `json:"id"`
+	Sku       string    `json:"sku"`
+	Quantity  int64     `json:"quantity"`
+	Due       time.Time `json:"due"`
+	Reference string    `json:"reference,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// AvailableToPromise is a value object. A time-phased view of how much of a SKU can be promised and when the open
+// reservations for it are expected to be filled.
+type AvailableToPromise struct {
+	Sku          string               `json:"sku"`
+	Available    int64                `json:"available"`
+	OpenDemand   int64                `json:"openDemand"`
+	Shortfall    int64                `json:"shortfall"`
+	Profile      []AtpPeriod          `json:"profile"`
+	Reservations []ReservationPromise `json:"reservations"`
+}
+
+// AtpPeriod is a value object. The supply arriving at a point in time, how much of it goes to open reservations and the
+// cumulative quantity that is free to promise from then on.
+type AtpPeriod struct {
+	Date      time.Time `json:"date"`
+	Receipts  int64     `json:"receipts"`
+	Allocated int64     `json:"allocated"`
+	Available int64     `json:"available"`
+}
+
+// ReservationPromise is a value object. The estimated date an open reservation will be filled, nil when current and
+// planned supply is not enough to fill it.
+type ReservationPromise struct {
+	ReservationID uint64     `json:"reservationId"`
+	RequestID     string     `json:"requestID"`
+	Requester     string     `json:"requester"`
+	Created       time.Time  `json:"created"`
+	Outstanding   int64      `json:"outstanding"`
+	FillDate      *time.Time `json:"fillDate"`
+}
+
+type CountState string
+
+const (
+	CountOpen      CountState = "Open"
+	CountApproved  CountState = "Approved"
+	CountCancelled CountState = "Cancelled"
+)
+
+func ParseCountState(v string) (CountState, error) {
+	switch v {
+	case string(CountOpen):
+		return CountOpen, nil
+	case string(CountApproved):
+		return CountApproved, nil
+	case string(CountCancelled):
+		return CountCancelled, nil
+	default:
+		return "", errors.New("invalid count state")
+	}
+}
+
+// CountSession is an entity. A physical count of a set of SKUs. Allocation of the SKUs is frozen while the session is
+// open, approving it adjusts their inventory by the variance that was counted.
+type CountSession struct {
+	ID       uint64      `json:"id"`
+	State    CountState  `json:"state"`
+	Created  time.Time   `json:"created"`
+	ClosedBy string      `json:"closedBy,omitempty"`
+	Closed   *time.Time  `json:"closed,omitempty"`
+	Lines    []CountLine `json:"lines,omitempty"`
+}
+
+// CountLine is a value object. The counted quantity of a SKU in a count session. SystemOnHand is the on-hand quantity
+// at the time of the count and Variance is the counted quantity less SystemOnHand.
+type CountLine struct {
+	Sku          string     `json:"sku"`
+	Counted      *int64     `json:"counted"`
+	SystemOnHand int64      `json:"systemOnHand"`
+	Variance     int64      `json:"variance"`
+	CountedAt    *time.Time `json:"countedAt,omitempty"`
+}
+
+// CountEntry is a value object. A quantity counted for a SKU.
+type CountEntry struct {
+	Sku      string `json:"sku"`
+	Quantity int64  `json:"quantity"`
+}
+
+type AdjustmentReason string
+
+const (
+	AdjustmentCycleCount    AdjustmentReason = "CycleCount"
+	AdjustmentQualityReject AdjustmentReason = "QualityReject"
+	AdjustmentReturn        AdjustmentReason = "Return"
+	AdjustmentConversion    AdjustmentReason = "Conversion"
+)
+
+// InventoryAdjustment is an entity. An audited change to a product's on-hand quantity made outside of production and
+// allocation.
+type InventoryAdjustment struct {
+	ID        uint64           `json:"id"`
+	Sku       string           `json:"sku"`
+	Quantity  int64            `json:"quantity"`
+	Reason    AdjustmentReason `json:"reason"`
+	Reference string           `json:"reference"`
+	User      string           `json:"user"`
+	Created   time.Time        `json:"created"`
+}
+
+type ReturnState string
+
+const (
+	ReturnRequested ReturnState = "Requested"
+	ReturnReceived  ReturnState = "Received"
+	ReturnRestocked ReturnState = "Restocked"
+	ReturnScrapped  ReturnState = "Scrapped"
+)
+
+func ParseReturnState(v string) (ReturnState, error) {
+	switch v {
+	case string(ReturnRequested):
+		return ReturnRequested, nil
+	case string(ReturnReceived):
+		return ReturnReceived, nil
+	case string(ReturnRestocked):
+		return ReturnRestocked, nil
+	case string(ReturnScrapped):
+		return ReturnScrapped, nil
+	default:
+		return "", errors.New("invalid return state")
+	}
+}
+
+// InspectionOutcome is the decision made when inspecting returned goods.
+type InspectionOutcome string
+
+const (
+	OutcomeRestock InspectionOutcome = "Restock"
+	OutcomeScrap   InspectionOutcome = "Scrap"
+)
+
+func ParseInspectionOutcome(v string) (InspectionOutcome, error) {
+	switch v {
+	case string(OutcomeRestock):
+		return OutcomeRestock, nil
+	case string(OutcomeScrap):
+		return OutcomeScrap, nil
+	default:
+		return "", errors.New("invalid inspection outcome")
+	}
+}
+
+// ReturnAuthorization is an entity. A customer return (RMA) of a SKU, optionally against the reservation the goods
+// were shipped for. Returned goods are not inventory until they are received and inspected, restocking adds them to
+// available inventory while scrapping only records their loss.
+type ReturnAuthorization struct {
+	ID            uint64      `json:"id"`
+	Sku           string      `json:"sku"`
+	ReservationID uint64      `json:"reservationId,omitempty"`
+	Quantity      int64       `json:"quantity"`
+	Reason        string      `json:"reason"`
+	State         ReturnState `json:"state"`
+	CreatedBy     string      `json:"createdBy"`
+	Created       time.Time   `json:"created"`
+	ReceivedBy    string      `json:"receivedBy,omitempty"`
+	Received      *time.Time  `json:"received,omitempty"`
+	InspectedBy   string      `json:"inspectedBy,omitempty"`
+	Inspected     *time.Time  `json:"inspected,omitempty"`
+	Note          string      `json:"note,omitempty"`
+}
+
+type PurchaseOrderState string
+
+const (
+	PurchaseOrderOpen              PurchaseOrderState = "Open"
+	PurchaseOrderPartiallyReceived PurchaseOrderState = "PartiallyReceived"
+	PurchaseOrderReceived          PurchaseOrderState = "Received"
+	PurchaseOrderClosed            PurchaseOrderState = "Closed"
+	PurchaseOrderCancelled         PurchaseOrderState = "Cancelled"
+)
+
+func ParsePurchaseOrderState(v string) (PurchaseOrderState, error) {
+	switch v {
+	case string(PurchaseOrderOpen):
+		return PurchaseOrderOpen, nil
+	case string(PurchaseOrderPartiallyReceived):
+		return PurchaseOrderPartiallyReceived, nil
+	case string(PurchaseOrderReceived):
+		return PurchaseOrderReceived, nil
+	case string(PurchaseOrderClosed):
+		return PurchaseOrderClosed, nil
+	case string(PurchaseOrderCancelled):
+		return PurchaseOrderCancelled, nil
+	default:
+		return "", errors.New("invalid purchase order state")
+	}
+}
+
+// PurchaseOrder is an entity. Stock ordered from a supplier. A purchase order is received line by line until every
+// line is received or it is closed short, stock received in excess of a line is accepted and tracked as its variance.
+type PurchaseOrder struct {
+	ID        uint64              `json:"id"`
+	Number    string              `json:"number"`
+	Supplier  string              `json:"supplier"`
+	State     PurchaseOrderState  `json:"state"`
+	CreatedBy string              `json:"createdBy"`
+	Created   time.Time           `json:"created"`
+	ClosedBy  string              `json:"closedBy,omitempty"`
+	Closed    *time.Time          `json:"closed,omitempty"`
+	Lines     []PurchaseOrderLine `json:"lines,omitempty"`
+	Receipts  []PurchaseReceipt   `json:"receipts,omitempty"`
+}
+
+// PurchaseOrderLine is a value object. A quantity of a SKU ordered on a purchase order and expected on a date.
+// Variance is the quantity received less the quantity ordered, negative while the line is short.
+type PurchaseOrderLine struct {
+	PurchaseOrderID uint64    `json:"purchaseOrderId,omitempty"`
+	LineNumber      int       `json:"lineNumber"`
+	Sku             string    `json:"sku"`
+	Quantity        int64     `json:"quantity"`
+	Received        int64     `json:"received"`
+	Variance        int64     `json:"variance"`
+	UnitCost        float64   `json:"unitCost"`
+	Expected        time.Time `json:"expected"`
+}
+
+// Outstanding is the quantity of the line still to be received.
+func (l PurchaseOrderLine) Outstanding() int64 {
+	if l.Received >= l.Quantity {
+		return 0
+	}
+	return l.Quantity - l.Received
+}
+
+// PurchaseReceipt is a value object. Stock received against a line of a purchase order, recorded as a production
+// event so that it shares production's idempotency, costing and lot tracking.
+type PurchaseReceipt struct {
+	RequestID  string    `json:"requestID"`
+	LineNumber int       `json:"lineNumber"`
+	Sku        string    `json:"sku"`
+	Quantity   int64     `json:"quantity"`
+	User       string    `json:"user"`
+	Created    time.Time `json:"created"`
+}
+
+// ReceiptRequest is a value object. A request to receive stock against a line of a purchase order. RequestID makes
+// the receipt idempotent in the same way as a production request.
+type ReceiptRequest struct {
+	RequestID  string `json:"requestID"`
+	LineNumber int    `json:"lineNumber"`
+	Quantity   int64  `json:"quantity"`
+}
+
+// Conversion is an entity. Stock of one SKU reworked or relabelled into another. SourceQuantity units of the source
+// are removed and SourceQuantity times Ratio units of the target are added, carrying the cost of the source.
+type Conversion struct {
+	ID             uint64    `json:"id"`
+	RequestID      string    `json:"requestID"`
+	SourceSku      string    `json:"sourceSku"`
+	SourceQuantity int64     `json:"sourceQuantity"`
+	TargetSku      string    `json:"targetSku"`
+	TargetQuantity int64     `json:"targetQuantity"`
+	Ratio          float64   `json:"ratio"`
+	Cost           float64   `json:"cost"`
+	User           string    `json:"user"`
+	Created        time.Time `json:"created"`
+}
+
+// ConversionRequest is a value object. A request to convert a quantity of one SKU into another at a ratio of target
+// units per source unit. RequestID makes the conversion idempotent.
+type ConversionRequest struct {
+	RequestID string  `json:"requestID"`
+	SourceSku string  `json:"sourceSku"`
+	TargetSku string  `json:"targetSku"`
+	Quantity  int64   `json:"quantity"`
+	Ratio     float64 `json:"ratio"`
 }
@@ -0,0 +1,156 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// consumeLots takes qty units of a SKU from its production lots, oldest first. Units allocated to a reservation are
+// pegged to the lots they came from, a reservationID of zero writes them off without pegging. Units held for
+// inspection are passed over and inventory produced before lots were tracked has none and is taken untraced.
+func (s *service) consumeLots(ctx context.Context, sku string, qty int64, reservationID uint64, tx core.Transaction) error {
+	const funcName = "consumeLots"
+
+	lots, err := s.repo.GetProductionLots(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, lot := range lots {
+		if qty == 0 {
+			break
+		}
+
+		take := qty
+		if take > lot.Remaining-lot.Held {
+			take = lot.Remaining - lot.Held
+		}
+		if take == 0 {
+			continue
+		}
+		qty -= take
+
+		if err = s.repo.UpdateProductionLot(ctx, lot.ID, lot.Remaining-take, lot.Held, core.UpdateOptions{Tx: tx}); err != nil {
+			return errors.WithStack(err)
+		}
+		if reservationID == 0 {
+			continue
+		}
+
+		peg := Peg{ProductionEventID: lot.ID, ReservationID: reservationID, Sku: sku, Quantity: take, Created: time.Now()}
+		if err = s.repo.SavePeg(ctx, peg, core.UpdateOptions{Tx: tx}); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if qty > 0 {
+		log.Debug().Str("func", funcName).Str("sku", sku).Int64("untraced", qty).Msg("not enough production lots to trace")
+	}
+	return nil
+}
+
+// releaseLots releases qty units of a SKU held for inspection in its production lots, oldest first, leaving them
+// free to be allocated.
+func (s *service) releaseLots(ctx context.Context, sku string, qty int64, tx core.Transaction) error {
+	const funcName = "releaseLots"
+
+	lots, err := s.repo.GetProductionLots(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, lot := range lots {
+		if qty == 0 {
+			break
+		}
+
+		take := qty
+		if take > lot.Held {
+			take = lot.Held
+		}
+		if take == 0 {
+			continue
+		}
+		qty -= take
+
+		if err = s.repo.UpdateProductionLot(ctx, lot.ID, lot.Remaining, lot.Held-take, core.UpdateOptions{Tx: tx}); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if qty > 0 {
+		log.Debug().Str("func", funcName).Str("sku", sku).Int64("untraced", qty).Msg("not enough held production lots to trace")
+	}
+	return nil
+}
+
+// rejectLots writes off qty units of a SKU held for inspection in its production lots, oldest first, and returns how
+// many units were taken from each lot.
+func (s *service) rejectLots(ctx context.Context, sku string, qty int64, tx core.Transaction) (map[uint64]int64, error) {
+	const funcName = "rejectLots"
+
+	lots, err := s.repo.GetProductionLots(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	taken := make(map[uint64]int64)
+	for _, lot := range lots {
+		if qty == 0 {
+			break
+		}
+
+		take := qty
+		if take > lot.Held {
+			take = lot.Held
+		}
+		if take == 0 {
+			continue
+		}
+		qty -= take
+		taken[lot.ID] = take
+
+		if err = s.repo.UpdateProductionLot(ctx, lot.ID, lot.Remaining-take, lot.Held-take, core.UpdateOptions{Tx: tx}); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	if qty > 0 {
+		log.Debug().Str("func", funcName).Str("sku", sku).Int64("untraced", qty).Msg("not enough held production lots to trace")
+	}
+	return taken, nil
+}
+
+// GetProductionConsumers returns every reservation filled from a production event and how much of it each received.
+func (s *service) GetProductionConsumers(ctx context.Context, requestID string) ([]Peg, error) {
+	const funcName = "GetProductionConsumers"
+
+	log.Debug().Str("func", funcName).Str("requestId", requestID).Msg("getting production consumers")
+
+	if _, err := s.repo.GetProductionEventByRequestID(ctx, requestID); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	pegs, err := s.repo.GetPegsByProductionEvent(ctx, requestID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return pegs, nil
+}
+
+// GetReservationSources returns the production events a reservation was filled from.
+func (s *service) GetReservationSources(ctx context.Context, ID uint64) ([]Peg, error) {
+	const funcName = "GetReservationSources"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Msg("getting reservation sources")
+
+	pegs, err := s.repo.GetPegsByReservation(ctx, ID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return pegs, nil
+}
@@ -0,0 +1,182 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ProduceBatch records many production requests at once, returning one result per request in the order they were
+// given. Requests are grouped by SKU and each SKU's requests are saved in a single transaction, after which reserves
+// are filled once for the SKU. When a SKU's transaction fails its requests are retried one at a time so a single bad
+// request does not fail the others, unless the SKU's intake is frozen which fails them all. A request whose ID has
+// already been produced is reported as a duplicate.
+func (s *service) ProduceBatch(ctx context.Context, requests []SkuProductionRequest) ([]ProductionResult, error) {
+	const funcName = "ProduceBatch"
+
+	log.Debug().Str("func", funcName).Int("requests", len(requests)).Msg("producing batch")
+
+	results := make([]ProductionResult, len(requests))
+	skus := make([]string, 0)
+	bySku := make(map[string][]int)
+	requestIDs := make(map[string]bool)
+	for i, pr := range requests {
+		results[i] = ProductionResult{Sku: pr.Sku, RequestID: pr.RequestID}
+		if err := validateProductionRequest(pr); err != nil {
+			results[i].Status = ProductionFailed
+			results[i].Error = err.Error()
+			continue
+		}
+		if requestIDs[pr.RequestID] {
+			results[i].Status = ProductionDuplicate
+			continue
+		}
+		requestIDs[pr.RequestID] = true
+
+		if _, ok := bySku[pr.Sku]; !ok {
+			skus = append(skus, pr.Sku)
+		}
+		bySku[pr.Sku] = append(bySku[pr.Sku], i)
+	}
+
+	for _, sku := range skus {
+		batch := bySku[sku]
+
+		product, err := s.repo.GetProduct(ctx, sku)
+		if err == nil && product.Type == Kit {
+			err = errors.New("kits cannot be produced, produce their components instead")
+		} else if errors.Is(err, core.ErrNotFound) {
+			err = errors.Errorf("product %s does not exist", sku)
+		}
+		if err != nil {
+			for _, i := range batch {
+				results[i].Status = ProductionFailed
+				results[i].Error = err.Error()
+			}
+			continue
+		}
+
+		produced, held := false, false
+		statuses, pi, err := s.produceSku(ctx, product, requests, batch)
+		switch {
+		case err == nil:
+			for n, i := range batch {
+				results[i].Status = statuses[n]
+				produced = produced || statuses[n] == ProductionProduced
+			}
+			held = pi.RequiresInspection
+		case errors.Is(err, ErrFrozen):
+			for _, i := range batch {
+				results[i].Status = ProductionFailed
+				results[i].Error = err.Error()
+			}
+		default:
+			log.Warn().Err(err).Str("func", funcName).Str("sku", sku).Msg("failed to produce batch, retrying individually")
+			for _, i := range batch {
+				statuses, pi, err := s.produceSku(ctx, product, requests, []int{i})
+				if err != nil {
+					results[i].Status = ProductionFailed
+					results[i].Error = err.Error()
+					continue
+				}
+				results[i].Status = statuses[0]
+				produced = produced || statuses[0] == ProductionProduced
+				held = pi.RequiresInspection
+			}
+		}
+
+		if !produced || held {
+			continue
+		}
+		if err = s.FillReserves(ctx, product); err != nil {
+			log.Err(err).Str("func", funcName).Str("sku", sku).Msg("failed to fill reserves after production")
+		}
+	}
+
+	return results, nil
+}
+
+func validateProductionRequest(pr SkuProductionRequest) error {
+	if pr.Sku == "" {
+		return errors.New("sku is required")
+	}
+	return validateProduction(pr.ProductionRequest)
+}
+
+// produceSku saves the production events of a single product in one transaction and publishes its inventory, which
+// it returns as locked for the transaction. Intake of a product with intake frozen is rejected.
+func (s *service) produceSku(ctx context.Context, product Product, requests []SkuProductionRequest, batch []int) ([]ProductionStatus, ProductInventory, error) {
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, ProductInventory{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	productInventory, err := s.repo.GetProductInventory(ctx, product.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return nil, ProductInventory{}, errors.WithMessage(err, "failed to get product inventory")
+	}
+	if productInventory.Frozen(FreezeIntake) {
+		err = errors.Wrapf(ErrFrozen, "intake of %s is frozen: %s", product.Sku, productInventory.Freezes[FreezeIntake].Reason)
+		return nil, ProductInventory{}, err
+	}
+
+	produced := int64(0)
+	statuses := make([]ProductionStatus, len(batch))
+	for n, i := range batch {
+		pr := requests[i]
+
+		var existing ProductionEvent
+		existing, err = s.repo.GetProductionEventByRequestID(ctx, pr.RequestID, core.QueryOptions{Tx: tx})
+		if err != nil && !errors.Is(err, core.ErrNotFound) {
+			return nil, ProductInventory{}, errors.WithStack(err)
+		}
+		if existing.RequestID != "" {
+			statuses[n] = ProductionDuplicate
+			continue
+		}
+
+		event := newProductionEvent(product.Sku, pr.ProductionRequest)
+		if productInventory.RequiresInspection {
+			event.Held = event.Quantity
+		}
+		if err = s.repo.SaveProductionEvent(ctx, &event, core.UpdateOptions{Tx: tx}); err != nil {
+			return nil, ProductInventory{}, errors.WithMessagef(err, "failed to save production event %s", pr.RequestID)
+		}
+		if event.Quantity > 0 {
+			if err = s.receiveCost(ctx, event, ValuationProduction, tx); err != nil {
+				return nil, ProductInventory{}, errors.WithMessagef(err, "failed to receive production cost %s", pr.RequestID)
+			}
+		}
+		produced += event.Quantity
+		statuses[n] = ProductionProduced
+	}
+
+	if produced == 0 {
+		if err = tx.Commit(ctx); err != nil {
+			return nil, ProductInventory{}, errors.WithStack(err)
+		}
+		return statuses, productInventory, nil
+	}
+
+	addProduced(&productInventory, produced)
+	if err = s.repo.SaveProductInventory(ctx, productInventory, core.UpdateOptions{Tx: tx}); err != nil {
+		return nil, ProductInventory{}, errors.WithMessage(err, "failed to add production to product")
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, ProductInventory{}, errors.WithMessage(err, "failed to commit production transaction")
+	}
+
+	if err = s.publishInventory(ctx, productInventory); err != nil {
+		log.Err(err).Str("sku", product.Sku).Msg("failed to publish inventory")
+	}
+
+	return statuses, productInventory, nil
+}
@@ -0,0 +1,305 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidPurchaseOrder is returned when a purchase order cannot be created, received or closed as requested.
+var ErrInvalidPurchaseOrder = errors.New("invalid purchase order")
+
+// CreatePurchaseOrder records stock ordered from a supplier. Lines are numbered in the order given unless they are
+// numbered already. Creating a purchase order whose number already exists returns the existing order.
+func (s *service) CreatePurchaseOrder(ctx context.Context, po PurchaseOrder) (PurchaseOrder, error) {
+	const funcName = "CreatePurchaseOrder"
+
+	if err := s.validatePurchaseOrder(ctx, &po); err != nil {
+		return PurchaseOrder{}, err
+	}
+
+	existing, err := s.repo.GetPurchaseOrders(ctx, GetPurchaseOrdersOptions{Number: po.Number}, 1, 0)
+	if err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	if len(existing) > 0 {
+		log.Debug().Str("func", funcName).Str("number", po.Number).Msg("purchase order already exists, returning it")
+		return s.GetPurchaseOrder(ctx, existing[0].ID)
+	}
+
+	log.Debug().
+		Str("func", funcName).
+		Str("number", po.Number).
+		Str("supplier", po.Supplier).
+		Int("lines", len(po.Lines)).
+		Msg("creating purchase order")
+
+	po.State = PurchaseOrderOpen
+	po.Created = time.Now()
+	po.Receipts = nil
+	if err = s.repo.SavePurchaseOrder(ctx, &po); err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	return po, nil
+}
+
+func (s *service) validatePurchaseOrder(ctx context.Context, po *PurchaseOrder) error {
+	if po.Number == "" {
+		return errors.Wrap(ErrInvalidPurchaseOrder, "number is required")
+	}
+	if po.Supplier == "" {
+		return errors.Wrap(ErrInvalidPurchaseOrder, "supplier is required")
+	}
+	if po.CreatedBy == "" {
+		return errors.Wrap(ErrInvalidPurchaseOrder, "user is required")
+	}
+	if len(po.Lines) == 0 {
+		return errors.Wrap(ErrInvalidPurchaseOrder, "at least one line is required")
+	}
+
+	numbers := make(map[int]bool)
+	for i := range po.Lines {
+		line := &po.Lines[i]
+		if line.LineNumber == 0 {
+			line.LineNumber = i + 1
+		}
+		if line.LineNumber < 0 || numbers[line.LineNumber] {
+			return errors.Wrapf(ErrInvalidPurchaseOrder, "line number %d is invalid or repeated", line.LineNumber)
+		}
+		numbers[line.LineNumber] = true
+
+		if line.Quantity < 1 {
+			return errors.Wrapf(ErrInvalidPurchaseOrder, "line %d quantity must be greater than zero", line.LineNumber)
+		}
+		if line.UnitCost < 0 {
+			return errors.Wrapf(ErrInvalidPurchaseOrder, "line %d unit cost must not be negative", line.LineNumber)
+		}
+		if line.Expected.IsZero() {
+			return errors.Wrapf(ErrInvalidPurchaseOrder, "line %d expected date is required", line.LineNumber)
+		}
+		line.Received = 0
+		line.Variance = -line.Quantity
+
+		product, err := s.repo.GetProduct(ctx, line.Sku)
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				return errors.Wrapf(ErrInvalidPurchaseOrder, "product %s does not exist", line.Sku)
+			}
+			return errors.WithStack(err)
+		}
+		if product.Type == Kit {
+			return errors.Wrapf(ErrInvalidPurchaseOrder, "%s is a kit, order its components instead", line.Sku)
+		}
+	}
+	return nil
+}
+
+// ReceivePurchaseOrder receives stock against a line of a purchase order. The receipt is saved as a production event
+// so a repeated request ID is ignored, and the stock is costed at the line's unit cost and used to fill open
+// reservations just as production is. Receiving more than is outstanding is accepted and shows as the line's variance.
+func (s *service) ReceivePurchaseOrder(ctx context.Context, ID uint64, rr ReceiptRequest, user string) (PurchaseOrder, error) {
+	const funcName = "ReceivePurchaseOrder"
+
+	log.Debug().
+		Str("func", funcName).
+		Uint64("id", ID).
+		Str("requestId", rr.RequestID).
+		Int("lineNumber", rr.LineNumber).
+		Int64("quantity", rr.Quantity).
+		Msg("receiving purchase order")
+
+	if rr.RequestID == "" {
+		return PurchaseOrder{}, errors.Wrap(ErrInvalidPurchaseOrder, "request id is required")
+	}
+	if rr.Quantity < 1 {
+		return PurchaseOrder{}, errors.Wrap(ErrInvalidPurchaseOrder, "quantity must be greater than zero")
+	}
+	if user == "" {
+		return PurchaseOrder{}, errors.Wrap(ErrInvalidPurchaseOrder, "user is required")
+	}
+
+	event, err := s.repo.GetProductionEventByRequestID(ctx, rr.RequestID)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	if event.RequestID != "" {
+		log.Debug().Str("func", funcName).Str("requestId", rr.RequestID).Msg("receipt already exists")
+		return s.GetPurchaseOrder(ctx, ID)
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	po, err := s.repo.GetPurchaseOrder(ctx, ID, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	if po.State == PurchaseOrderClosed || po.State == PurchaseOrderCancelled {
+		err = errors.Wrapf(ErrInvalidPurchaseOrder, "purchase order %s is %s", po.Number, po.State)
+		return PurchaseOrder{}, err
+	}
+
+	line := findPurchaseOrderLine(po.Lines, rr.LineNumber)
+	if line == nil {
+		err = errors.Wrapf(ErrInvalidPurchaseOrder, "purchase order %s has no line %d", po.Number, rr.LineNumber)
+		return PurchaseOrder{}, err
+	}
+
+	product, err := s.repo.GetProduct(ctx, line.Sku, core.QueryOptions{Tx: tx})
+	if err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+
+	event = ProductionEvent{
+		RequestID: rr.RequestID,
+		Sku:       line.Sku,
+		Quantity:  rr.Quantity,
+		UnitCost:  line.UnitCost,
+		Remaining: rr.Quantity,
+		Created:   time.Now(),
+	}
+	productInventory, err := s.receiveProduction(ctx, &event, tx)
+	if err != nil {
+		return PurchaseOrder{}, err
+	}
+
+	receipt := PurchaseReceipt{
+		RequestID:  rr.RequestID,
+		LineNumber: line.LineNumber,
+		Sku:        line.Sku,
+		Quantity:   rr.Quantity,
+		User:       user,
+		Created:    event.Created,
+	}
+	if err = s.repo.SavePurchaseReceipt(ctx, po.ID, event.ID, receipt, core.UpdateOptions{Tx: tx}); err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+
+	line.Received += rr.Quantity
+	line.Variance = line.Received - line.Quantity
+	if err = s.repo.UpdatePurchaseOrderLine(ctx, po.ID, *line, core.UpdateOptions{Tx: tx}); err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+
+	po.State = receivedState(po.Lines)
+	if err = s.repo.UpdatePurchaseOrder(ctx, po, core.UpdateOptions{Tx: tx}); err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	po.Receipts = append(po.Receipts, receipt)
+
+	if err = tx.Commit(ctx); err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+
+	if err = s.releaseProduction(ctx, product, productInventory); err != nil {
+		return PurchaseOrder{}, err
+	}
+	return po, nil
+}
+
+func findPurchaseOrderLine(lines []PurchaseOrderLine, lineNumber int) *PurchaseOrderLine {
+	for i := range lines {
+		if lines[i].LineNumber == lineNumber {
+			return &lines[i]
+		}
+	}
+	return nil
+}
+
+// receivedState is the state of a purchase order whose lines have been received as given.
+func receivedState(lines []PurchaseOrderLine) PurchaseOrderState {
+	state := PurchaseOrderReceived
+	for _, line := range lines {
+		if line.Outstanding() > 0 {
+			state = PurchaseOrderPartiallyReceived
+		}
+	}
+	return state
+}
+
+// ClosePurchaseOrder stops a purchase order from being received. An order with nothing received is cancelled, any
+// other is closed and the quantity its lines were short is left as their variance.
+func (s *service) ClosePurchaseOrder(ctx context.Context, ID uint64, user string) (PurchaseOrder, error) {
+	const funcName = "ClosePurchaseOrder"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Str("user", user).Msg("closing purchase order")
+
+	if user == "" {
+		return PurchaseOrder{}, errors.Wrap(ErrInvalidPurchaseOrder, "user is required")
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	po, err := s.repo.GetPurchaseOrder(ctx, ID, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	if po.State == PurchaseOrderClosed || po.State == PurchaseOrderCancelled {
+		err = errors.Wrapf(ErrInvalidPurchaseOrder, "purchase order %s is already %s", po.Number, po.State)
+		return PurchaseOrder{}, err
+	}
+
+	now := time.Now()
+	po.State = PurchaseOrderClosed
+	if len(po.Receipts) == 0 {
+		po.State = PurchaseOrderCancelled
+	}
+	po.ClosedBy = user
+	po.Closed = &now
+	if err = s.repo.UpdatePurchaseOrder(ctx, po, core.UpdateOptions{Tx: tx}); err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	return po, nil
+}
+
+func (s *service) GetPurchaseOrder(ctx context.Context, ID uint64) (PurchaseOrder, error) {
+	const funcName = "GetPurchaseOrder"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Msg("getting purchase order")
+
+	po, err := s.repo.GetPurchaseOrder(ctx, ID)
+	if err != nil {
+		return PurchaseOrder{}, errors.WithStack(err)
+	}
+	return po, nil
+}
+
+func (s *service) GetPurchaseOrders(ctx context.Context, options GetPurchaseOrdersOptions, limit, offset int) ([]PurchaseOrder, error) {
+	const funcName = "GetPurchaseOrders"
+
+	log.Debug().
+		Str("func", funcName).
+		Str("supplier", options.Supplier).
+		Str("state", string(options.State)).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("getting purchase orders")
+
+	orders, err := s.repo.GetPurchaseOrders(ctx, options, limit, offset)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return orders, nil
+}
@@ -0,0 +1,164 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidHold is returned when held inventory cannot be released or rejected as requested.
+var ErrInvalidHold = errors.New("invalid hold")
+
+// addProduced adds produced quantity to the inventory, holding it for inspection if the product requires it.
+func addProduced(pi *ProductInventory, qty int64) {
+	pi.OnHand += qty
+	if pi.RequiresInspection {
+		pi.Held += qty
+	} else {
+		pi.Available += qty
+	}
+}
+
+// SetRequiresInspection changes whether production of the product is held for inspection. Inventory already held is
+// unaffected and must still be released or rejected.
+func (s *service) SetRequiresInspection(ctx context.Context, sku string, requiresInspection bool) (Product, error) {
+	const funcName = "SetRequiresInspection"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Bool("requiresInspection", requiresInspection).Msg("setting inspection")
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	product, err := s.repo.GetProduct(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	if product.Type == Kit {
+		err = errors.Wrap(ErrInvalidHold, "kits are never produced and cannot require inspection")
+		return Product{}, err
+	}
+
+	product.RequiresInspection = requiresInspection
+	if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	return product, nil
+}
+
+// ReleaseHeld moves inventory that passed inspection from held to available and fills open reservations with it.
+func (s *service) ReleaseHeld(ctx context.Context, sku string, qty int64, user string) (ProductInventory, error) {
+	const funcName = "ReleaseHeld"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Int64("quantity", qty).Str("user", user).Msg("releasing held inventory")
+
+	pi, err := s.updateHeld(ctx, sku, qty, func(pi *ProductInventory, tx core.Transaction) error {
+		if err := s.releaseLots(ctx, sku, qty, tx); err != nil {
+			return errors.WithMessage(err, "failed to release production lots")
+		}
+		pi.Held -= qty
+		pi.Available += qty
+		return nil
+	})
+	if err != nil {
+		return ProductInventory{}, err
+	}
+
+	if err = s.FillReserves(ctx, pi.Product); err != nil {
+		return ProductInventory{}, errors.WithMessage(err, "failed to fill reserves after release")
+	}
+	return pi, nil
+}
+
+// RejectHeld scraps inventory that failed inspection. The scrapped quantity is written off the held production lots,
+// relieved from valuation at the cost of those lots and recorded as an adjustment against the user.
+func (s *service) RejectHeld(ctx context.Context, sku string, qty int64, reference, user string) (ProductInventory, error) {
+	const funcName = "RejectHeld"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Int64("quantity", qty).Str("user", user).Msg("rejecting held inventory")
+
+	if user == "" {
+		return ProductInventory{}, errors.Wrap(ErrInvalidHold, "user is required")
+	}
+
+	return s.updateHeld(ctx, sku, qty, func(pi *ProductInventory, tx core.Transaction) error {
+		lots, err := s.rejectLots(ctx, sku, qty, tx)
+		if err != nil {
+			return errors.WithMessage(err, "failed to write off production lots")
+		}
+		if _, err = s.relieveLotCost(ctx, sku, qty, lots, ValuationScrap, reference, tx); err != nil {
+			return errors.WithMessage(err, "failed to relieve scrap cost")
+		}
+
+		pi.Held -= qty
+		pi.OnHand -= qty
+
+		adjustment := InventoryAdjustment{
+			Sku:       sku,
+			Quantity:  -qty,
+			Reason:    AdjustmentQualityReject,
+			Reference: reference,
+			User:      user,
+			Created:   time.Now(),
+		}
+		if err := s.repo.SaveAdjustment(ctx, &adjustment, core.UpdateOptions{Tx: tx}); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	})
+}
+
+// updateHeld applies a change to held inventory in a transaction and publishes the result.
+func (s *service) updateHeld(ctx context.Context, sku string, qty int64, update func(pi *ProductInventory, tx core.Transaction) error) (ProductInventory, error) {
+	if qty < 1 {
+		return ProductInventory{}, errors.Wrap(ErrInvalidHold, "quantity must be greater than zero")
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return ProductInventory{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	pi, err := s.repo.GetProductInventory(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return ProductInventory{}, errors.WithStack(err)
+	}
+	if qty > pi.Held {
+		err = errors.Wrapf(ErrInvalidHold, "only %d of %s is held", pi.Held, sku)
+		return ProductInventory{}, err
+	}
+
+	if err = update(&pi, tx); err != nil {
+		return ProductInventory{}, err
+	}
+	if err = s.repo.SaveProductInventory(ctx, pi, core.UpdateOptions{Tx: tx}); err != nil {
+		return ProductInventory{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return ProductInventory{}, errors.WithStack(err)
+	}
+
+	if err = s.publishInventory(ctx, pi); err != nil {
+		return ProductInventory{}, errors.WithMessage(err, "failed to publish inventory")
+	}
+	return pi, nil
+}
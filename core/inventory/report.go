@@ -0,0 +1,220 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidReport is returned when a report is requested with invalid options.
+var ErrInvalidReport = errors.New("invalid report")
+
+const maxTopOpenDemand = 100
+
+const maxSlowMovingDays = 3650
+
+// GetFillRates reports how much of the quantity requested by reservations created in the period was reserved, grouped
+// by SKU or by requester.
+func (s *service) GetFillRates(ctx context.Context, group FillRateGroup, options ReportOptions) ([]FillRate, error) {
+	const funcName = "GetFillRates"
+
+	if group != FillRateBySku && group != FillRateByRequester {
+		return nil, errors.Wrapf(ErrInvalidReport, "fill rates cannot be grouped by %s", group)
+	}
+	if err := validateReportOptions(options); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("func", funcName).Str("group", string(group)).Time("from", options.From).Time("to", options.To).Msg("getting fill rates")
+
+	rates, err := s.repo.GetFillRates(ctx, group, options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range rates {
+		if rates[i].Requested > 0 {
+			rates[i].FillRate = float64(rates[i].Reserved) / float64(rates[i].Requested)
+		}
+	}
+	return rates, nil
+}
+
+// GetTimeToClose reports the average and 95th percentile time taken to fill reservations, per SKU, for reservations
+// closed in the period.
+func (s *service) GetTimeToClose(ctx context.Context, options ReportOptions) ([]TimeToClose, error) {
+	const funcName = "GetTimeToClose"
+
+	if err := validateReportOptions(options); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("func", funcName).Time("from", options.From).Time("to", options.To).Msg("getting time to close")
+
+	times, err := s.repo.GetTimeToClose(ctx, options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return times, nil
+}
+
+// GetDailyProduction reports the number of production events and the quantity produced on each day of the period.
+func (s *service) GetDailyProduction(ctx context.Context, options ReportOptions) ([]DailyProduction, error) {
+	const funcName = "GetDailyProduction"
+
+	if err := validateReportOptions(options); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("func", funcName).Time("from", options.From).Time("to", options.To).Msg("getting daily production")
+
+	days, err := s.repo.GetDailyProduction(ctx, options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return days, nil
+}
+
+// GetTopOpenDemand reports the SKUs with the most unreserved quantity on open reservations created in the period.
+func (s *service) GetTopOpenDemand(ctx context.Context, options ReportOptions, limit int) ([]SkuDemand, error) {
+	const funcName = "GetTopOpenDemand"
+
+	if limit < 1 || limit > maxTopOpenDemand {
+		return nil, errors.Wrapf(ErrInvalidReport, "limit must be between 1 and %d", maxTopOpenDemand)
+	}
+	if err := validateReportOptions(options); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("func", funcName).Int("limit", limit).Time("from", options.From).Time("to", options.To).Msg("getting top open demand")
+
+	demand, err := s.repo.GetTopOpenDemand(ctx, options, limit)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return demand, nil
+}
+
+// GetLineYields reports the good and scrapped output of each production line and shift in the period and the
+// percentage of it that was good.
+func (s *service) GetLineYields(ctx context.Context, options ReportOptions) ([]LineYield, error) {
+	const funcName = "GetLineYields"
+
+	if err := validateReportOptions(options); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("func", funcName).Time("from", options.From).Time("to", options.To).Msg("getting line yields")
+
+	yields, err := s.repo.GetLineYields(ctx, options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range yields {
+		if total := yields[i].Good + yields[i].Scrapped; total > 0 {
+			yields[i].Yield = float64(yields[i].Good) / float64(total) * 100
+		}
+	}
+	return yields, nil
+}
+
+// GetLineOutput reports the good and scrapped output of each production line and shift on each day of the period.
+func (s *service) GetLineOutput(ctx context.Context, options ReportOptions) ([]LineOutput, error) {
+	const funcName = "GetLineOutput"
+
+	if err := validateReportOptions(options); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("func", funcName).Time("from", options.From).Time("to", options.To).Msg("getting line output")
+
+	output, err := s.repo.GetLineOutput(ctx, options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return output, nil
+}
+
+// GetReservationAging reports the open and pending reservations created in the period by SKU and requester, counted by
+// how long they have been waiting.
+func (s *service) GetReservationAging(ctx context.Context, options ReportOptions) ([]ReservationAging, error) {
+	const funcName = "GetReservationAging"
+
+	if err := validateReportOptions(options); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("func", funcName).Time("from", options.From).Time("to", options.To).Msg("getting reservation aging")
+
+	aging, err := s.repo.GetReservationAging(ctx, options, time.Now())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return aging, nil
+}
+
+// GetInventoryAging reports the unallocated stock of each SKU received in the period by age and flags the SKUs that
+// have had nothing allocated in the last slowDays days as slow moving.
+func (s *service) GetInventoryAging(ctx context.Context, options ReportOptions, slowDays int) ([]InventoryAge, error) {
+	const funcName = "GetInventoryAging"
+
+	if slowDays < 1 || slowDays > maxSlowMovingDays {
+		return nil, errors.Wrapf(ErrInvalidReport, "slow moving days must be between 1 and %d", maxSlowMovingDays)
+	}
+	if err := validateReportOptions(options); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("func", funcName).Int("slowDays", slowDays).Time("from", options.From).Time("to", options.To).Msg("getting inventory aging")
+
+	lots, err := s.repo.GetAgedLots(ctx, options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	now := time.Now()
+	aging := make([]InventoryAge, 0)
+	bySku := make(map[string]int)
+	for _, lot := range lots {
+		i, ok := bySku[lot.Sku]
+		if !ok {
+			i = len(aging)
+			bySku[lot.Sku] = i
+			aging = append(aging, InventoryAge{Sku: lot.Sku, Oldest: lot.Received, LastAllocated: lot.LastAllocated})
+		}
+		addAgedLot(&aging[i], lot, now)
+	}
+
+	slowSince := now.AddDate(0, 0, -slowDays)
+	for i := range aging {
+		last := aging[i].LastAllocated
+		aging[i].SlowMoving = last == nil || last.Before(slowSince)
+	}
+	return aging, nil
+}
+
+// addAgedLot counts the stock of a lot in the age bucket it falls in as of now.
+func addAgedLot(a *InventoryAge, lot AgedLot, now time.Time) {
+	a.Quantity += lot.Remaining
+	a.Value += float64(lot.Remaining) * lot.UnitCost
+
+	const day = 24 * time.Hour
+	switch age := now.Sub(lot.Received); {
+	case age < 30*day:
+		a.UnderThirty += lot.Remaining
+	case age < 60*day:
+		a.ThirtyToSixty += lot.Remaining
+	case age < 90*day:
+		a.SixtyToNinety += lot.Remaining
+	default:
+		a.OverNinety += lot.Remaining
+	}
+}
+
+func validateReportOptions(options ReportOptions) error {
+	if !options.From.IsZero() && !options.To.IsZero() && !options.From.Before(options.To) {
+		return errors.Wrap(ErrInvalidReport, "from must be before to")
+	}
+	return nil
+}
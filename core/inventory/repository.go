@@ -2,6 +2,7 @@ package inventory
 
 import (
 	"context"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/sksmith/go-micro-example/core"
@@ -26,11 +27,24 @@ type Repository interface {
 	ReservationRepository
 	InventoryRepository
 	ProductRepository
+	CostRepository
+	CategoryRepository
+	ReportRepository
+	PlanningRepository
+	CountRepository
+	SubstituteRepository
+	PeggingRepository
+	ReturnRepository
+	PurchaseOrderRepository
+	ConversionRepository
+	CrossReferenceRepository
+	StyleRepository
 }
 
 type ProductionEventRepository interface {
 	Transactional
 	GetProductionEventByRequestID(ctx context.Context, requestID string, options ...core.QueryOptions) (pe ProductionEvent, err error)
+	GetProductionEvents(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]ProductionEvent, error)
 
 	SaveProductionEvent(ctx context.Context, event *ProductionEvent, options ...core.UpdateOptions) error
 }
@@ -43,12 +57,19 @@ type ReservationRepository interface {
 
 	SaveReservation(ctx context.Context, reservation *Reservation, options ...core.UpdateOptions) error
 	UpdateReservation(ctx context.Context, ID uint64, state ReserveState, qty int64, options ...core.UpdateOptions) error
+	DecideReservation(ctx context.Context, reservation Reservation, options ...core.UpdateOptions) error
+	UpdateReservationRequested(ctx context.Context, ID uint64, qty int64, options ...core.UpdateOptions) error
+	UpdateReservationCost(ctx context.Context, ID uint64, costOfGoods float64, options ...core.UpdateOptions) error
+
+	GetSlaBreaches(ctx context.Context, defaultHours int64, asOf time.Time, options ...core.QueryOptions) ([]ReservationAlert, error)
+	MarkSlaAlerted(ctx context.Context, ID uint64, alerted time.Time, options ...core.UpdateOptions) error
 }
 
 type InventoryRepository interface {
 	Transactional
 	GetProductInventory(ctx context.Context, sku string, options ...core.QueryOptions) (pi ProductInventory, err error)
-	GetAllProductInventory(ctx context.Context, limit int, offset int, options ...core.QueryOptions) ([]ProductInventory, error)
+	GetAllProductInventory(ctx context.Context, piOptions GetProductInventoryOptions, limit int, offset int, options ...core.QueryOptions) ([]ProductInventory, error)
+	GetInventoryHistory(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]InventoryLevel, error)
 
 	SaveProductInventory(ctx context.Context, productInventory ProductInventory, options ...core.UpdateOptions) error
 }
@@ -56,11 +77,140 @@ type InventoryRepository interface {
 type ProductRepository interface {
 	Transactional
 	GetProduct(ctx context.Context, sku string, options ...core.QueryOptions) (Product, error)
+	GetKitComponents(ctx context.Context, kitSku string, options ...core.QueryOptions) ([]KitComponent, error)
+	GetKitsContaining(ctx context.Context, componentSku string, options ...core.QueryOptions) ([]Product, error)
+	GetFrozenProducts(ctx context.Context, skus []string, freeze Freeze, options ...core.QueryOptions) ([]string, error)
 
 	SaveProduct(ctx context.Context, product Product, options ...core.UpdateOptions) error
+	SaveKitComponents(ctx context.Context, kitSku string, components []KitComponent, options ...core.UpdateOptions) error
+
+	GetAttributeDefinitions(ctx context.Context, options ...core.QueryOptions) ([]AttributeDefinition, error)
+	SaveAttributeDefinition(ctx context.Context, definition AttributeDefinition, options ...core.UpdateOptions) error
+}
+
+type CategoryRepository interface {
+	Transactional
+	GetCategory(ctx context.Context, ID uint64, options ...core.QueryOptions) (Category, error)
+	GetCategories(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]Category, error)
+	GetChildCategories(ctx context.Context, parentID uint64, options ...core.QueryOptions) ([]Category, error)
+	GetCategoryInventory(ctx context.Context, ID uint64, options ...core.QueryOptions) (CategoryInventory, error)
+
+	SaveCategory(ctx context.Context, category *Category, options ...core.UpdateOptions) error
+	DeleteCategory(ctx context.Context, ID uint64, options ...core.UpdateOptions) error
+}
+
+type PlanningRepository interface {
+	GetPlannedProduction(ctx context.Context, sku string, options ...core.QueryOptions) ([]PlannedProduction, error)
+
+	SavePlannedProduction(ctx context.Context, plan *PlannedProduction, options ...core.UpdateOptions) error
+	DeletePlannedProduction(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error
+}
+
+type CountRepository interface {
+	Transactional
+	GetCountSession(ctx context.Context, ID uint64, options ...core.QueryOptions) (CountSession, error)
+	GetCountSessions(ctx context.Context, state CountState, limit, offset int, options ...core.QueryOptions) ([]CountSession, error)
+	GetFrozenSkus(ctx context.Context, skus []string, options ...core.QueryOptions) ([]string, error)
+	GetAdjustments(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]InventoryAdjustment, error)
+
+	SaveCountSession(ctx context.Context, session *CountSession, options ...core.UpdateOptions) error
+	UpdateCountSession(ctx context.Context, session CountSession, options ...core.UpdateOptions) error
+	UpdateCountLine(ctx context.Context, sessionID uint64, line CountLine, options ...core.UpdateOptions) error
+	SaveAdjustment(ctx context.Context, adjustment *InventoryAdjustment, options ...core.UpdateOptions) error
+}
+
+type SubstituteRepository interface {
+	GetSubstituteRules(ctx context.Context, sku string, options ...core.QueryOptions) ([]SubstituteRule, error)
+	GetReservationFills(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]ReservationFill, error)
+
+	SaveSubstituteRule(ctx context.Context, rule *SubstituteRule, options ...core.UpdateOptions) error
+	DeleteSubstituteRule(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error
+	AddReservationFill(ctx context.Context, reservationID uint64, fill ReservationFill, options ...core.UpdateOptions) error
+}
+
+type PeggingRepository interface {
+	GetProductionLots(ctx context.Context, sku string, options ...core.QueryOptions) ([]ProductionEvent, error)
+	GetPegsByProductionEvent(ctx context.Context, requestID string, options ...core.QueryOptions) ([]Peg, error)
+	GetPegsByReservation(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]Peg, error)
+
+	UpdateProductionLot(ctx context.Context, ID uint64, remaining, held int64, options ...core.UpdateOptions) error
+	SavePeg(ctx context.Context, peg Peg, options ...core.UpdateOptions) error
+}
+
+type ReturnRepository interface {
+	Transactional
+	GetReturn(ctx context.Context, ID uint64, options ...core.QueryOptions) (ReturnAuthorization, error)
+	GetReturns(ctx context.Context, rtnOptions GetReturnsOptions, limit, offset int, options ...core.QueryOptions) ([]ReturnAuthorization, error)
+	GetReturnedQuantity(ctx context.Context, reservationID uint64, options ...core.QueryOptions) (int64, error)
+
+	SaveReturn(ctx context.Context, rma *ReturnAuthorization, options ...core.UpdateOptions) error
+	UpdateReturn(ctx context.Context, rma ReturnAuthorization, options ...core.UpdateOptions) error
+}
+
+type PurchaseOrderRepository interface {
+	Transactional
+	GetPurchaseOrder(ctx context.Context, ID uint64, options ...core.QueryOptions) (PurchaseOrder, error)
+	GetPurchaseOrders(ctx context.Context, poOptions GetPurchaseOrdersOptions, limit, offset int, options ...core.QueryOptions) ([]PurchaseOrder, error)
+	GetInboundLines(ctx context.Context, sku string, options ...core.QueryOptions) ([]PurchaseOrderLine, error)
+
+	SavePurchaseOrder(ctx context.Context, po *PurchaseOrder, options ...core.UpdateOptions) error
+	UpdatePurchaseOrder(ctx context.Context, po PurchaseOrder, options ...core.UpdateOptions) error
+	UpdatePurchaseOrderLine(ctx context.Context, ID uint64, line PurchaseOrderLine, options ...core.UpdateOptions) error
+	SavePurchaseReceipt(ctx context.Context, ID uint64, eventID uint64, receipt PurchaseReceipt, options ...core.UpdateOptions) error
+}
+
+type ConversionRepository interface {
+	Transactional
+	GetConversionByRequestID(ctx context.Context, requestID string, options ...core.QueryOptions) (Conversion, error)
+	GetConversions(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]Conversion, error)
+
+	SaveConversion(ctx context.Context, conversion *Conversion, options ...core.UpdateOptions) error
+}
+
+type CrossReferenceRepository interface {
+	GetCrossReferences(ctx context.Context, sku string, options ...core.QueryOptions) ([]CrossReference, error)
+	ResolveIdentifier(ctx context.Context, identifier, requester string, options ...core.QueryOptions) (CrossReference, error)
+
+	SaveCrossReference(ctx context.Context, xref *CrossReference, options ...core.UpdateOptions) error
+	DeleteCrossReference(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error
+}
+
+type StyleRepository interface {
+	GetStyle(ctx context.Context, code string, options ...core.QueryOptions) (Style, error)
+	GetStyles(ctx context.Context, attributes Attributes, limit, offset int, options ...core.QueryOptions) ([]Style, error)
+	GetStyleVariants(ctx context.Context, codes []string, attributes Attributes, options ...core.QueryOptions) ([]ProductInventory, error)
+
+	SaveStyle(ctx context.Context, style Style, options ...core.UpdateOptions) error
+}
+
+type ReportRepository interface {
+	GetFillRates(ctx context.Context, group FillRateGroup, reportOptions ReportOptions, options ...core.QueryOptions) ([]FillRate, error)
+	GetTimeToClose(ctx context.Context, reportOptions ReportOptions, options ...core.QueryOptions) ([]TimeToClose, error)
+	GetDailyProduction(ctx context.Context, reportOptions ReportOptions, options ...core.QueryOptions) ([]DailyProduction, error)
+	GetTopOpenDemand(ctx context.Context, reportOptions ReportOptions, limit int, options ...core.QueryOptions) ([]SkuDemand, error)
+	GetLineYields(ctx context.Context, reportOptions ReportOptions, options ...core.QueryOptions) ([]LineYield, error)
+	GetLineOutput(ctx context.Context, reportOptions ReportOptions, options ...core.QueryOptions) ([]LineOutput, error)
+	GetReservationAging(ctx context.Context, reportOptions ReportOptions, asOf time.Time, options ...core.QueryOptions) ([]ReservationAging, error)
+	GetAgedLots(ctx context.Context, reportOptions ReportOptions, options ...core.QueryOptions) ([]AgedLot, error)
+}
+
+type CostRepository interface {
+	Transactional
+	GetCostLayers(ctx context.Context, sku string, options ...core.QueryOptions) ([]CostLayer, error)
+	GetValuation(ctx context.Context, sku string, options ...core.QueryOptions) (Valuation, error)
+	GetValuations(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]Valuation, error)
+	GetTotalValuation(ctx context.Context, options ...core.QueryOptions) (float64, error)
+	GetValuationHistory(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]ValuationEntry, error)
+
+	SaveCostLayer(ctx context.Context, layer *CostLayer, options ...core.UpdateOptions) error
+	UpdateCostLayer(ctx context.Context, ID uint64, remaining int64, options ...core.UpdateOptions) error
+	SaveValuationEntry(ctx context.Context, entry *ValuationEntry, options ...core.UpdateOptions) error
 }
 
 type InventoryQueue interface {
 	PublishInventory(ctx context.Context, productInventory ProductInventory) error
 	PublishReservation(ctx context.Context, reservation Reservation) error
+	PublishReturn(ctx context.Context, rma ReturnAuthorization) error
+	PublishReservationAlert(ctx context.Context, alert ReservationAlert) error
+	PublishFreeze(ctx context.Context, change FreezeChange) error
 }
@@ -0,0 +1,330 @@
+package inventory
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidReturn is returned when a customer return cannot be created, received or inspected as requested.
+var ErrInvalidReturn = errors.New("invalid return")
+
+// CreateReturn authorizes a customer to return a quantity of a SKU. A return made against a reservation takes its SKU
+// from the reservation and, together with the reservation's earlier returns, may not exceed the quantity reserved.
+func (s *service) CreateReturn(ctx context.Context, rma ReturnAuthorization) (ReturnAuthorization, error) {
+	const funcName = "CreateReturn"
+
+	if rma.Quantity < 1 {
+		return ReturnAuthorization{}, errors.Wrap(ErrInvalidReturn, "quantity must be greater than zero")
+	}
+	if rma.Reason == "" {
+		return ReturnAuthorization{}, errors.Wrap(ErrInvalidReturn, "reason is required")
+	}
+	if rma.CreatedBy == "" {
+		return ReturnAuthorization{}, errors.Wrap(ErrInvalidReturn, "user is required")
+	}
+	if rma.Sku == "" && rma.ReservationID == 0 {
+		return ReturnAuthorization{}, errors.Wrap(ErrInvalidReturn, "sku or reservation is required")
+	}
+
+	log.Debug().
+		Str("func", funcName).
+		Str("sku", rma.Sku).
+		Uint64("reservationId", rma.ReservationID).
+		Int64("quantity", rma.Quantity).
+		Msg("creating return")
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return ReturnAuthorization{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	if rma.ReservationID != 0 {
+		if err = s.validateReservationReturn(ctx, &rma, tx); err != nil {
+			return ReturnAuthorization{}, err
+		}
+	}
+
+	product, err := s.repo.GetProduct(ctx, rma.Sku, core.QueryOptions{Tx: tx})
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			err = errors.Wrapf(ErrInvalidReturn, "product %s does not exist", rma.Sku)
+			return ReturnAuthorization{}, err
+		}
+		return ReturnAuthorization{}, errors.WithStack(err)
+	}
+	if product.Type == Kit {
+		err = errors.Wrapf(ErrInvalidReturn, "%s is a kit, return its components instead", rma.Sku)
+		return ReturnAuthorization{}, err
+	}
+
+	rma.State = ReturnRequested
+	rma.Created = time.Now()
+	if err = s.repo.SaveReturn(ctx, &rma, core.UpdateOptions{Tx: tx}); err != nil {
+		return ReturnAuthorization{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return ReturnAuthorization{}, errors.WithStack(err)
+	}
+
+	if err = s.publishReturn(ctx, rma); err != nil {
+		return ReturnAuthorization{}, err
+	}
+	return rma, nil
+}
+
+// validateReservationReturn checks that the return fits within what was reserved, locking the reservation so that
+// concurrent returns against it are counted.
+func (s *service) validateReservationReturn(ctx context.Context, rma *ReturnAuthorization, tx core.Transaction) error {
+	res, err := s.repo.GetReservation(ctx, rma.ReservationID, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return errors.Wrapf(ErrInvalidReturn, "reservation %d does not exist", rma.ReservationID)
+		}
+		return errors.WithStack(err)
+	}
+	if rma.Sku == "" {
+		rma.Sku = res.Sku
+	}
+	if rma.Sku != res.Sku {
+		return errors.Wrapf(ErrInvalidReturn, "reservation %d is for %s", res.ID, res.Sku)
+	}
+
+	returned, err := s.repo.GetReturnedQuantity(ctx, res.ID, core.QueryOptions{Tx: tx})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if returned+rma.Quantity > res.ReservedQuantity {
+		return errors.Wrapf(ErrInvalidReturn, "only %d of reservation %d may still be returned",
+			res.ReservedQuantity-returned, res.ID)
+	}
+	return nil
+}
+
+// ReceiveReturn records that the goods of a return have arrived. They are not inventory until they are inspected.
+func (s *service) ReceiveReturn(ctx context.Context, ID uint64, user string) (ReturnAuthorization, error) {
+	const funcName = "ReceiveReturn"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Str("user", user).Msg("receiving return")
+
+	return s.updateReturn(ctx, ID, user, ReturnRequested, func(rma *ReturnAuthorization, tx core.Transaction) error {
+		now := time.Now()
+		rma.State = ReturnReceived
+		rma.ReceivedBy = user
+		rma.Received = &now
+		return nil
+	})
+}
+
+// InspectReturn applies the outcome of inspecting received goods. Restocked goods are added to available inventory at
+// the unit cost they left at, or the current unit cost when the return is not against a reservation, and open
+// reservations are filled with them. Scrapped goods are only recorded.
+func (s *service) InspectReturn(ctx context.Context, ID uint64, outcome InspectionOutcome, note, user string) (ReturnAuthorization, error) {
+	const funcName = "InspectReturn"
+
+	log.Debug().
+		Str("func", funcName).
+		Uint64("id", ID).
+		Str("outcome", string(outcome)).
+		Str("user", user).
+		Msg("inspecting return")
+
+	if outcome != OutcomeRestock && outcome != OutcomeScrap {
+		return ReturnAuthorization{}, errors.Wrapf(ErrInvalidReturn, "invalid outcome %s", outcome)
+	}
+
+	var pi *ProductInventory
+	rma, err := s.updateReturn(ctx, ID, user, ReturnReceived, func(rma *ReturnAuthorization, tx core.Transaction) error {
+		now := time.Now()
+		rma.InspectedBy = user
+		rma.Inspected = &now
+		rma.Note = note
+
+		if outcome == OutcomeScrap {
+			rma.State = ReturnScrapped
+			return nil
+		}
+
+		rma.State = ReturnRestocked
+		restocked, err := s.restock(ctx, *rma, tx)
+		if err != nil {
+			return err
+		}
+		pi = &restocked
+		return nil
+	})
+	if err != nil {
+		return ReturnAuthorization{}, err
+	}
+
+	if pi != nil {
+		if err = s.publishInventory(ctx, *pi); err != nil {
+			return ReturnAuthorization{}, errors.WithMessage(err, "failed to publish inventory")
+		}
+		if err = s.FillReserves(ctx, pi.Product); err != nil {
+			return ReturnAuthorization{}, errors.WithMessage(err, "failed to fill reserves after restock")
+		}
+	}
+	return rma, nil
+}
+
+// restock adds the returned goods to available inventory as a new lot, carrying their cost and recording the
+// adjustment.
+func (s *service) restock(ctx context.Context, rma ReturnAuthorization, tx core.Transaction) (ProductInventory, error) {
+	pi, err := s.repo.GetProductInventory(ctx, rma.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return ProductInventory{}, errors.WithStack(err)
+	}
+
+	unitCost, err := s.returnUnitCost(ctx, rma, tx)
+	if err != nil {
+		return ProductInventory{}, err
+	}
+
+	reference := "rma-" + strconv.FormatUint(rma.ID, 10)
+	event := ProductionEvent{
+		RequestID: reference,
+		Sku:       rma.Sku,
+		Quantity:  rma.Quantity,
+		UnitCost:  unitCost,
+		Remaining: rma.Quantity,
+		Created:   *rma.Inspected,
+	}
+	if err = s.repo.SaveProductionEvent(ctx, &event, core.UpdateOptions{Tx: tx}); err != nil {
+		return ProductInventory{}, errors.WithMessage(err, "failed to save restocked lot")
+	}
+	if err = s.receiveCost(ctx, event, ValuationReturn, tx); err != nil {
+		return ProductInventory{}, errors.WithMessage(err, "failed to receive return cost")
+	}
+
+	pi.OnHand += rma.Quantity
+	pi.Available += rma.Quantity
+	if err = s.repo.SaveProductInventory(ctx, pi, core.UpdateOptions{Tx: tx}); err != nil {
+		return ProductInventory{}, errors.WithStack(err)
+	}
+
+	adjustment := InventoryAdjustment{
+		Sku:       rma.Sku,
+		Quantity:  rma.Quantity,
+		Reason:    AdjustmentReturn,
+		Reference: reference,
+		User:      rma.InspectedBy,
+		Created:   *rma.Inspected,
+	}
+	if err = s.repo.SaveAdjustment(ctx, &adjustment, core.UpdateOptions{Tx: tx}); err != nil {
+		return ProductInventory{}, errors.WithStack(err)
+	}
+	return pi, nil
+}
+
+// returnUnitCost is the unit cost the reservation was relieved at, falling back to the product's current unit cost.
+func (s *service) returnUnitCost(ctx context.Context, rma ReturnAuthorization, tx core.Transaction) (float64, error) {
+	if rma.ReservationID != 0 {
+		res, err := s.repo.GetReservation(ctx, rma.ReservationID, core.QueryOptions{Tx: tx})
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		if res.ReservedQuantity > 0 && res.CostOfGoods > 0 {
+			return res.CostOfGoods / float64(res.ReservedQuantity), nil
+		}
+	}
+
+	layers, err := s.repo.GetCostLayers(ctx, rma.Sku, core.QueryOptions{Tx: tx})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if qty, value := layerTotals(layers); qty > 0 {
+		return value / float64(qty), nil
+	}
+	return 0, nil
+}
+
+// updateReturn applies a change to a return in the given state in a transaction and publishes the result.
+func (s *service) updateReturn(ctx context.Context, ID uint64, user string, state ReturnState, update func(rma *ReturnAuthorization, tx core.Transaction) error) (ReturnAuthorization, error) {
+	if user == "" {
+		return ReturnAuthorization{}, errors.Wrap(ErrInvalidReturn, "user is required")
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return ReturnAuthorization{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	rma, err := s.repo.GetReturn(ctx, ID, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return ReturnAuthorization{}, errors.WithStack(err)
+	}
+	if rma.State != state {
+		err = errors.Wrapf(ErrInvalidReturn, "return %d is %s", ID, rma.State)
+		return ReturnAuthorization{}, err
+	}
+
+	if err = update(&rma, tx); err != nil {
+		return ReturnAuthorization{}, err
+	}
+	if err = s.repo.UpdateReturn(ctx, rma, core.UpdateOptions{Tx: tx}); err != nil {
+		return ReturnAuthorization{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return ReturnAuthorization{}, errors.WithStack(err)
+	}
+
+	if err = s.publishReturn(ctx, rma); err != nil {
+		return ReturnAuthorization{}, err
+	}
+	return rma, nil
+}
+
+func (s *service) GetReturn(ctx context.Context, ID uint64) (ReturnAuthorization, error) {
+	const funcName = "GetReturn"
+
+	log.Debug().Str("func", funcName).Uint64("id", ID).Msg("getting return")
+
+	rma, err := s.repo.GetReturn(ctx, ID)
+	if err != nil {
+		return ReturnAuthorization{}, errors.WithStack(err)
+	}
+	return rma, nil
+}
+
+func (s *service) GetReturns(ctx context.Context, options GetReturnsOptions, limit, offset int) ([]ReturnAuthorization, error) {
+	const funcName = "GetReturns"
+
+	log.Debug().
+		Str("func", funcName).
+		Str("sku", options.Sku).
+		Str("state", string(options.State)).
+		Uint64("reservationId", options.ReservationID).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("getting returns")
+
+	returns, err := s.repo.GetReturns(ctx, options, limit, offset)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return returns, nil
+}
+
+func (s *service) publishReturn(ctx context.Context, rma ReturnAuthorization) error {
+	if err := s.queue.PublishReturn(ctx, rma); err != nil {
+		return errors.WithMessage(err, "failed to publish return to queue")
+	}
+	return nil
+}
@@ -11,34 +11,123 @@ import (
 	"github.com/sksmith/go-micro-example/core"
 )
 
-func NewService(repo Repository, q InventoryQueue) *service {
+func NewService(repo Repository, q InventoryQueue, options ...serviceOption) *service {
 	log.Info().Msg("creating inventory service...")
-	return &service{
+	s := &service{
 		repo:            repo,
 		queue:           q,
+		costingMethod:   FIFO,
 		inventorySubs:   make(map[InventorySubID]chan<- ProductInventory),
 		reservationSubs: make(map[ReservationsSubID]chan<- Reservation),
+		alertSubs:       make(map[AlertsSubID]chan<- ReservationAlert),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+type serviceOption func(s *service)
+
+// SplitBackorders sets whether partly filled reservations are split into a closed reservation and a backorder for the
+// remainder when their request does not say. Defaults to false.
+func SplitBackorders(split bool) func(s *service) {
+	return func(s *service) {
+		s.splitBackorders = split
+	}
+}
+
+// ApprovalThreshold sets the quantity above which reservations wait for approval unless their product has a threshold
+// of its own. Defaults to zero, which never requires approval.
+func ApprovalThreshold(threshold int64) func(s *service) {
+	return func(s *service) {
+		s.approvalThreshold = threshold
+	}
+}
+
+// ReservationSla sets how many hours a reservation may stay open before an alert is raised, unless its product has an
+// SLA of its own. Defaults to zero, which never raises alerts.
+func ReservationSla(hours int64) func(s *service) {
+	return func(s *service) {
+		s.reservationSlaHours = hours
+	}
+}
+
+// Costing sets the method used to carry and relieve the cost of inventory. Defaults to FIFO.
+func Costing(method CostingMethod) func(s *service) {
+	return func(s *service) {
+		s.costingMethod = method
 	}
 }
 
 type InventorySubID string
 type ReservationsSubID string
+type AlertsSubID string
 
 type GetReservationsOptions struct {
 	Sku   string
 	State ReserveState
+	// ParentID limits results to the backorders split from the given reservation.
+	ParentID uint64
+}
+
+type GetReturnsOptions struct {
+	Sku           string
+	State         ReturnState
+	ReservationID uint64
+}
+
+type GetPurchaseOrdersOptions struct {
+	Number   string
+	Supplier string
+	State    PurchaseOrderState
+}
+
+type GetProductInventoryOptions struct {
+	// Attributes limits results to products having every one of the given attribute values.
+	Attributes Attributes
+	// Style limits results to the variants of the given style.
+	Style string
 }
 
 type service struct {
-	repo            Repository
-	queue           InventoryQueue
-	inventorySubs   map[InventorySubID]chan<- ProductInventory
-	reservationSubs map[ReservationsSubID]chan<- Reservation
+	repo                Repository
+	queue               InventoryQueue
+	costingMethod       CostingMethod
+	splitBackorders     bool
+	approvalThreshold   int64
+	reservationSlaHours int64
+	inventorySubs       map[InventorySubID]chan<- ProductInventory
+	reservationSubs     map[ReservationsSubID]chan<- Reservation
+	alertSubs           map[AlertsSubID]chan<- ReservationAlert
 }
 
 func (s *service) CreateProduct(ctx context.Context, product Product) error {
 	const funcName = "CreateProduct"
 
+	if product.Type == Kit {
+		return errors.New("kits must be created with their components")
+	}
+	product.Type = Standard
+	product.Freezes = nil
+
+	if err := s.validateAttributes(ctx, product.Attributes); err != nil {
+		return err
+	}
+	if err := s.validateCategory(ctx, product.CategoryID); err != nil {
+		return err
+	}
+	if product.Style != "" {
+		if err := s.validateVariant(ctx, product.Style, product.Variant); err != nil {
+			return err
+		}
+		if err := s.validateVariantUnique(ctx, product.Sku, product.Style, product.Variant); err != nil {
+			return err
+		}
+	} else {
+		product.Variant = nil
+	}
+
 	dbProduct, err := s.repo.GetProduct(ctx, product.Sku)
 	if err != nil != errors.Is(err, core.ErrNotFound) {
 		return errors.WithStack(err)
@@ -87,11 +176,11 @@ func (s *service) Produce(ctx context.Context, product Product, pr ProductionReq
 		Int64("quantity", pr.Quantity).
 		Msg("producing inventory")
 
-	if pr.RequestID == "" {
-		return errors.New("request id is required")
+	if err := validateProduction(pr); err != nil {
+		return err
 	}
-	if pr.Quantity < 1 {
-		return errors.New("quantity must be greater than zero")
+	if product.Type == Kit {
+		return errors.New("kits cannot be produced, produce their components instead")
 	}
 
 	event, err := s.repo.GetProductionEventByRequestID(ctx, pr.RequestID)
@@ -104,12 +193,7 @@ func (s *service) Produce(ctx context.Context, product Product, pr ProductionReq
 		return nil
 	}
 
-	event = ProductionEvent{
-		RequestID: pr.RequestID,
-		Sku:       product.Sku,
-		Quantity:  pr.Quantity,
-		Created:   time.Now(),
-	}
+	event = newProductionEvent(product.Sku, pr)
 
 	tx, err := s.repo.BeginTransaction(ctx)
 	if err != nil {
@@ -121,33 +205,98 @@ func (s *service) Produce(ctx context.Context, product Product, pr ProductionReq
 		}
 	}()
 
-	if err = s.repo.SaveProductionEvent(ctx, &event, core.UpdateOptions{Tx: tx}); err != nil {
-		return errors.WithMessage(err, "failed to save production event")
+	productInventory, err := s.receiveProduction(ctx, &event, tx)
+	if err != nil {
+		return err
 	}
 
-	productInventory, err := s.repo.GetProductInventory(ctx, product.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
-	if err != nil {
-		return errors.WithMessage(err, "failed to get product inventory")
+	if err = tx.Commit(ctx); err != nil {
+		return errors.WithMessage(err, "failed to commit production transaction")
 	}
 
-	productInventory.Available += event.Quantity
-	if err = s.repo.SaveProductInventory(ctx, productInventory, core.UpdateOptions{Tx: tx}); err != nil {
-		return errors.WithMessage(err, "failed to add production to product")
+	return s.releaseProduction(ctx, product, productInventory)
+}
+
+func validateProduction(pr ProductionRequest) error {
+	if pr.RequestID == "" {
+		return errors.New("request id is required")
+	}
+	if pr.Quantity < 0 || pr.Scrapped < 0 {
+		return errors.New("quantity and scrapped must not be negative")
+	}
+	if pr.Quantity+pr.Scrapped < 1 {
+		return errors.New("quantity or scrapped must be greater than zero")
+	}
+	if pr.UnitCost < 0 {
+		return errors.New("unit cost must not be negative")
 	}
+	return nil
+}
 
-	if err = tx.Commit(ctx); err != nil {
-		return errors.WithMessage(err, "failed to commit production transaction")
+// newProductionEvent is the event recording a production request. Only the good quantity remains to be allocated.
+func newProductionEvent(sku string, pr ProductionRequest) ProductionEvent {
+	return ProductionEvent{
+		RequestID: pr.RequestID,
+		Sku:       sku,
+		Quantity:  pr.Quantity,
+		Scrapped:  pr.Scrapped,
+		UnitCost:  pr.UnitCost,
+		Remaining: pr.Quantity,
+		Line:      pr.Line,
+		Shift:     pr.Shift,
+		Operator:  pr.Operator,
+		Created:   time.Now(),
 	}
+}
 
-	err = s.publishInventory(ctx, productInventory)
+// receiveProduction saves a production event, carries its cost and adds its good quantity to the product's inventory.
+// Intake of a product with intake frozen is rejected.
+func (s *service) receiveProduction(ctx context.Context, event *ProductionEvent, tx core.Transaction) (ProductInventory, error) {
+	productInventory, err := s.repo.GetProductInventory(ctx, event.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
 	if err != nil {
+		return ProductInventory{}, errors.WithMessage(err, "failed to get product inventory")
+	}
+	if productInventory.Frozen(FreezeIntake) {
+		return ProductInventory{}, errors.Wrapf(ErrFrozen, "intake of %s is frozen: %s", event.Sku, productInventory.Freezes[FreezeIntake].Reason)
+	}
+
+	if productInventory.RequiresInspection {
+		event.Held = event.Quantity
+	}
+	if err = s.repo.SaveProductionEvent(ctx, event, core.UpdateOptions{Tx: tx}); err != nil {
+		return ProductInventory{}, errors.WithMessage(err, "failed to save production event")
+	}
+
+	if event.Quantity > 0 {
+		if err = s.receiveCost(ctx, *event, ValuationProduction, tx); err != nil {
+			return ProductInventory{}, errors.WithMessage(err, "failed to receive production cost")
+		}
+	}
+
+	addProduced(&productInventory, event.Quantity)
+	if err = s.repo.SaveProductInventory(ctx, productInventory, core.UpdateOptions{Tx: tx}); err != nil {
+		return ProductInventory{}, errors.WithMessage(err, "failed to add production to product")
+	}
+	return productInventory, nil
+}
+
+// releaseProduction publishes inventory once its production is committed and fills open reservations with it, unless
+// it is held for inspection.
+func (s *service) releaseProduction(ctx context.Context, product Product, productInventory ProductInventory) error {
+	const funcName = "releaseProduction"
+
+	if err := s.publishInventory(ctx, productInventory); err != nil {
 		return errors.WithMessage(err, "failed to publish inventory")
 	}
 
-	if err = s.FillReserves(ctx, product); err != nil {
-		return errors.WithMessage(err, "failed to fill reserves after production")
+	if productInventory.RequiresInspection {
+		log.Debug().Str("func", funcName).Str("sku", product.Sku).Msg("production held for inspection")
+		return nil
 	}
 
+	if err := s.FillReserves(ctx, product); err != nil {
+		return errors.WithMessage(err, "failed to fill reserves after production")
+	}
 	return nil
 }
 
@@ -158,6 +307,7 @@ func (s *service) Reserve(ctx context.Context, rr ReservationRequest) (Reservati
 		Str("func", funcName).
 		Str("requestID", rr.RequestID).
 		Str("sku", rr.Sku).
+		Str("customerPartNumber", rr.CustomerPartNumber).
 		Str("requester", rr.Requester).
 		Int64("quantity", rr.Quantity).
 		Msg("reserving inventory")
@@ -166,6 +316,15 @@ func (s *service) Reserve(ctx context.Context, rr ReservationRequest) (Reservati
 		return Reservation{}, err
 	}
 
+	if rr.Sku == "" {
+		xref, err := s.repo.ResolveIdentifier(ctx, rr.CustomerPartNumber, rr.Requester)
+		if err != nil {
+			log.Error().Err(err).Str("requestId", rr.RequestID).Msg("failed to resolve customer part number")
+			return Reservation{}, errors.WithStack(err)
+		}
+		rr.Sku = xref.Sku
+	}
+
 	tx, err := s.repo.BeginTransaction(ctx)
 	defer func() {
 		if err != nil {
@@ -192,6 +351,10 @@ func (s *service) Reserve(ctx context.Context, rr ReservationRequest) (Reservati
 		rollback(ctx, tx, err)
 		return res, nil
 	}
+	if pr.Frozen(FreezeReservations) {
+		err = errors.Wrapf(ErrFrozen, "reservations of %s are frozen: %s", pr.Sku, pr.Freezes[FreezeReservations].Reason)
+		return Reservation{}, err
+	}
 
 	res = Reservation{
 		RequestID:         rr.RequestID,
@@ -200,16 +363,50 @@ func (s *service) Reserve(ctx context.Context, rr ReservationRequest) (Reservati
 		State:             Open,
 		RequestedQuantity: rr.Quantity,
 		Created:           time.Now(),
+		// Kits are filled in whole kits from their components and are never substituted.
+		AllowSubstitutes: rr.AllowSubstitutes && pr.Type != Kit,
+		SplitBackorder:   s.splitBackorders,
+	}
+	if rr.SplitBackorder != nil {
+		res.SplitBackorder = *rr.SplitBackorder
+	}
+	if s.requiresApproval(pr, res.RequestedQuantity) {
+		res.State = PendingApproval
 	}
 
 	if err = s.repo.SaveReservation(ctx, &res, core.UpdateOptions{Tx: tx}); err != nil {
 		return Reservation{}, errors.WithStack(err)
 	}
 
+	if res.State == PendingApproval {
+		log.Debug().Str("func", funcName).Str("requestId", rr.RequestID).Msg("reservation is waiting for approval")
+		if err = tx.Commit(ctx); err != nil {
+			return Reservation{}, errors.WithStack(err)
+		}
+		if err = s.publishReservation(ctx, res); err != nil {
+			return Reservation{}, errors.WithStack(err)
+		}
+		return res, nil
+	}
+
+	productInventory, err := s.repo.GetProductInventory(ctx, rr.Sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+
+	productInventory.OpenDemand += res.RequestedQuantity
+	if err = s.repo.SaveProductInventory(ctx, productInventory, core.UpdateOptions{Tx: tx}); err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return Reservation{}, errors.WithStack(err)
 	}
 
+	if err = s.publishInventory(ctx, productInventory); err != nil {
+		return Reservation{}, errors.WithStack(err)
+	}
+
 	if err = s.FillReserves(ctx, pr); err != nil {
 		return Reservation{}, errors.WithStack(err)
 	}
@@ -224,8 +421,8 @@ func validateReservationRequest(rr ReservationRequest) error {
 	if rr.Requester == "" {
 		return errors.New("requester is required")
 	}
-	if rr.Sku == "" {
-		return errors.New("sku is requred")
+	if rr.Sku == "" && rr.CustomerPartNumber == "" {
+		return errors.New("sku or customer part number is required")
 	}
 	if rr.Quantity < 1 {
 		return errors.New("quantity is required")
@@ -233,8 +430,27 @@ func validateReservationRequest(rr ReservationRequest) error {
 	return nil
 }
 
-func (s *service) GetAllProductInventory(ctx context.Context, limit, offset int) ([]ProductInventory, error) {
-	return s.repo.GetAllProductInventory(ctx, limit, offset)
+func (s *service) GetAllProductInventory(ctx context.Context, options GetProductInventoryOptions, limit, offset int) ([]ProductInventory, error) {
+	filter, err := s.typedAttributeFilter(ctx, options.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	options.Attributes = filter
+
+	products, err := s.repo.GetAllProductInventory(ctx, options, limit, offset)
+	if err != nil {
+		return products, err
+	}
+
+	for i := range products {
+		if products[i].Type != Kit {
+			continue
+		}
+		if err = s.deriveKitInventory(ctx, &products[i]); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return products, nil
 }
 
 func (s *service) GetProduct(ctx context.Context, sku string) (Product, error) {
@@ -258,6 +474,12 @@ func (s *service) GetProductInventory(ctx context.Context, sku string) (ProductI
 	if err != nil {
 		return product, errors.WithStack(err)
 	}
+
+	if product.Type == Kit {
+		if err = s.deriveKitInventory(ctx, &product); err != nil {
+			return product, errors.WithStack(err)
+		}
+	}
 	return product, nil
 }
 
@@ -270,6 +492,10 @@ func (s *service) GetReservation(ctx context.Context, ID uint64) (Reservation, e
 	if err != nil {
 		return rsv, errors.WithStack(err)
 	}
+
+	if rsv.Fills, err = s.repo.GetReservationFills(ctx, ID); err != nil {
+		return rsv, errors.WithStack(err)
+	}
 	return rsv, nil
 }
 
@@ -318,6 +544,10 @@ func (s *service) UnsubscribeReservations(id ReservationsSubID) {
 func (s *service) FillReserves(ctx context.Context, product Product) error {
 	const funcName = "fillReserves"
 
+	if product.Type == Kit {
+		return s.fillKitReserves(ctx, product)
+	}
+
 	tx, err := s.repo.BeginTransaction(ctx)
 	defer func() {
 		if err != nil {
@@ -338,6 +568,21 @@ func (s *service) FillReserves(ctx context.Context, product Product) error {
 		return errors.WithStack(err)
 	}
 
+	frozen, err := s.allocationFrozen(ctx, []string{product.Sku}, core.QueryOptions{Tx: tx})
+	if err != nil {
+		return err
+	}
+	if frozen {
+		log.Debug().Str("func", funcName).Str("sku", product.Sku).Msg("allocation is frozen, skipping fill")
+		if err = tx.Commit(ctx); err != nil {
+			return errors.WithStack(err)
+		}
+		if err = s.refreshKits(ctx, product.Sku); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+
 	for _, reservation := range openReservations {
 		var subtx pgx.Tx
 		subtx, err = tx.Begin(ctx)
@@ -367,12 +612,20 @@ func (s *service) FillReserves(ctx context.Context, product Product) error {
 			reserveAmount = productInventory.Available
 		}
 		productInventory.Available -= reserveAmount
+		productInventory.Reserved += reserveAmount
+		productInventory.OpenDemand -= reserveAmount
 		reservation.ReservedQuantity += reserveAmount
 
 		if reservation.ReservedQuantity == reservation.RequestedQuantity {
 			reservation.State = Closed
 		}
 
+		var backorder Reservation
+		backorder, err = s.splitBackorder(ctx, &reservation, tx)
+		if err != nil {
+			return err
+		}
+
 		log.Debug().
 			Str("func", funcName).
 			Str("sku", product.Sku).
@@ -396,6 +649,28 @@ func (s *service) FillReserves(ctx context.Context, product Product) error {
 			return errors.WithStack(err)
 		}
 
+		var cost float64
+		cost, err = s.relieveCost(ctx, product.Sku, reserveAmount, ValuationAllocation, reservation.RequestID, tx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		reservation.CostOfGoods += cost
+
+		err = s.consumeLots(ctx, product.Sku, reserveAmount, reservation.ID, tx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		err = s.repo.UpdateReservationCost(ctx, reservation.ID, reservation.CostOfGoods, core.UpdateOptions{Tx: tx})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		err = s.repo.AddReservationFill(ctx, reservation.ID, ReservationFill{Sku: product.Sku, Quantity: reserveAmount}, core.UpdateOptions{Tx: tx})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
 		if err = subtx.Commit(ctx); err != nil {
 			return errors.WithStack(err)
 		}
@@ -409,12 +684,26 @@ func (s *service) FillReserves(ctx context.Context, product Product) error {
 		if err != nil {
 			return errors.WithStack(err)
 		}
+
+		if backorder.ID != 0 {
+			if err = s.publishReservation(ctx, backorder); err != nil {
+				return errors.WithStack(err)
+			}
+		}
 	}
 
 	if err = tx.Commit(ctx); err != nil {
 		return errors.WithStack(err)
 	}
 
+	if err = s.fillFromSubstitutes(ctx, product.Sku, productInventory.Available); err != nil {
+		return errors.WithMessage(err, "failed to fill reserves from substitutes")
+	}
+
+	if err = s.refreshKits(ctx, product.Sku); err != nil {
+		return errors.WithStack(err)
+	}
+
 	return nil
 }
 
@@ -48,6 +48,22 @@ func TestCreateProduct(t *testing.T) {
 			wantTxCallCnt:   map[string]int{"Commit": 1, "Rollback": 0},
 			wantErr:         false,
 		},
+		{
+			name: "new product cannot be created frozen",
+			product: inventory.Product{Name: "productname", Sku: "productsku", Upc: "productupc",
+				Freezes: inventory.Freezes{inventory.FreezeIntake: inventory.FreezeSetting{Reason: "recall"}}},
+
+			saveProductFunc: func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+				if product.Freezes != nil {
+					return errors.New("product saved frozen")
+				}
+				return nil
+			},
+
+			wantRepoCallCnt: map[string]int{"SaveProduct": 1, "SaveProductInventory": 1},
+			wantTxCallCnt:   map[string]int{"Commit": 1, "Rollback": 0},
+			wantErr:         false,
+		},
 		{
 			name:    "product already exists",
 			product: inventory.Product{Name: "productname", Sku: "productsku", Upc: "productupc"},
@@ -319,7 +335,7 @@ func TestProduce(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		productInventory = &inventory.ProductInventory{Product: product, Available: 1}
+		productInventory = &inventory.ProductInventory{Product: product, OnHand: 1, Available: 1}
 
 		mockTx := db.NewMockTransaction()
 		if test.commitFunc != nil {
@@ -378,6 +394,10 @@ func TestProduce(t *testing.T) {
 				t.Errorf("unexpected available got=%d want=%d", productInventory.Available, test.wantAvailable)
 			}
 
+			if productInventory.OnHand != test.wantAvailable {
+				t.Errorf("unexpected on hand got=%d want=%d", productInventory.OnHand, test.wantAvailable)
+			}
+
 			for f, c := range test.wantRepoCallCnt {
 				mockRepo.VerifyCount(f, c, t)
 			}
@@ -407,16 +427,18 @@ func TestReserve(t *testing.T) {
 		wantQueueCallCnt map[string]int
 		wantTxCallCnt    map[string]int
 		wantState        inventory.ReserveState
+		wantOpenDemand   int64
 		wantErr          bool
 	}{
 		{
 			name:    "reservation is created",
 			request: inventory.ReservationRequest{RequestID: "somerequestid", Sku: "somesku", Requester: "somerequester", Quantity: 1},
 
-			wantRepoCallCnt:  map[string]int{"SaveReservation": 1},
-			wantQueueCallCnt: map[string]int{"PublishInventory": 0, "PublishReservation": 0},
+			wantRepoCallCnt:  map[string]int{"SaveReservation": 1, "SaveProductInventory": 1},
+			wantQueueCallCnt: map[string]int{"PublishInventory": 1, "PublishReservation": 0},
 			wantTxCallCnt:    map[string]int{"Commit": 2, "Rollback": 0},
 			wantState:        inventory.Open,
+			wantOpenDemand:   1,
 		},
 		{
 			name:            "reservation request id is required",
@@ -511,6 +533,7 @@ func TestReserve(t *testing.T) {
 			wantRepoCallCnt:  map[string]int{"SaveReservation": 1},
 			wantQueueCallCnt: map[string]int{"PublishInventory": 0, "PublishReservation": 0},
 			wantTxCallCnt:    map[string]int{"Commit": 1, "Rollback": 1},
+			wantOpenDemand:   1,
 			wantErr:          true,
 		},
 	}
@@ -543,6 +566,12 @@ func TestReserve(t *testing.T) {
 			mockRepo.SaveReservationFunc = test.saveReservationFunc
 		}
 
+		var gotProductInventory inventory.ProductInventory
+		mockRepo.SaveProductInventoryFunc = func(ctx context.Context, productInventory inventory.ProductInventory, options ...core.UpdateOptions) error {
+			gotProductInventory = productInventory
+			return nil
+		}
+
 		mockQueue := queue.NewMockQueue()
 
 		service := inventory.NewService(mockRepo, mockQueue)
@@ -559,6 +588,10 @@ func TestReserve(t *testing.T) {
 				t.Errorf("unexpected state got=%s want=%s", res.State, test.wantState)
 			}
 
+			if gotProductInventory.OpenDemand != test.wantOpenDemand {
+				t.Errorf("unexpected open demand got=%d want=%d", gotProductInventory.OpenDemand, test.wantOpenDemand)
+			}
+
 			for f, c := range test.wantRepoCallCnt {
 				mockRepo.VerifyCount(f, c, t)
 			}
@@ -579,7 +612,7 @@ func TestGetAllProductInventory(t *testing.T) {
 		limit  int
 		offset int
 
-		getAllProductInventoryFunc func(ctx context.Context, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error)
+		getAllProductInventoryFunc func(ctx context.Context, piOptions inventory.GetProductInventoryOptions, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error)
 
 		wantProductInventory []inventory.ProductInventory
 		wantErr              bool
@@ -590,7 +623,7 @@ func TestGetAllProductInventory(t *testing.T) {
 		},
 		{
 			name: "error is returned",
-			getAllProductInventoryFunc: func(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+			getAllProductInventoryFunc: func(ctx context.Context, piOptions inventory.GetProductInventoryOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
 				return []inventory.ProductInventory{}, errors.New("some unexpected error")
 			},
 			wantErr: true,
@@ -602,7 +635,7 @@ func TestGetAllProductInventory(t *testing.T) {
 		if test.getAllProductInventoryFunc != nil {
 			mockRepo.GetAllProductInventoryFunc = test.getAllProductInventoryFunc
 		} else {
-			mockRepo.GetAllProductInventoryFunc = func(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+			mockRepo.GetAllProductInventoryFunc = func(ctx context.Context, piOptions inventory.GetProductInventoryOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
 				return productInv, nil
 			}
 		}
@@ -611,7 +644,7 @@ func TestGetAllProductInventory(t *testing.T) {
 		service := inventory.NewService(mockRepo, mockQueue)
 
 		t.Run(test.name, func(t *testing.T) {
-			res, err := service.GetAllProductInventory(context.Background(), test.limit, test.offset)
+			res, err := service.GetAllProductInventory(context.Background(), inventory.GetProductInventoryOptions{}, test.limit, test.offset)
 			if test.wantErr && err == nil {
 				t.Errorf("expected error, got none")
 			} else if !test.wantErr && err != nil {
@@ -884,12 +917,15 @@ func TestFillReserves(t *testing.T) {
 				}, nil
 			},
 			getProductInventoryFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) (pi inventory.ProductInventory, err error) {
-				return inventory.ProductInventory{Product: product, Available: 10}, nil
+				return inventory.ProductInventory{Product: product, OnHand: 10, Available: 10, OpenDemand: 10}, nil
 			},
 
 			wantProductInventory: inventory.ProductInventory{
-				Product:   product,
-				Available: 0,
+				Product:    product,
+				OnHand:     10,
+				Reserved:   10,
+				Available:  0,
+				OpenDemand: 0,
 			},
 			wantResUpdates: []reservationUpdate{
 				{ID: 0, State: inventory.Closed, Quantity: 10},
@@ -908,12 +944,15 @@ func TestFillReserves(t *testing.T) {
 				}, nil
 			},
 			getProductInventoryFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) (pi inventory.ProductInventory, err error) {
-				return inventory.ProductInventory{Product: product, Available: 5}, nil
+				return inventory.ProductInventory{Product: product, OnHand: 5, Available: 5, OpenDemand: 10}, nil
 			},
 
 			wantProductInventory: inventory.ProductInventory{
-				Product:   product,
-				Available: 0,
+				Product:    product,
+				OnHand:     5,
+				Reserved:   5,
+				Available:  0,
+				OpenDemand: 5,
 			},
 			wantResUpdates: []reservationUpdate{
 				{ID: 0, State: inventory.Open, Quantity: 5},
@@ -935,12 +974,15 @@ func TestFillReserves(t *testing.T) {
 				}, nil
 			},
 			getProductInventoryFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) (pi inventory.ProductInventory, err error) {
-				return inventory.ProductInventory{Product: product, Available: 10}, nil
+				return inventory.ProductInventory{Product: product, OnHand: 10, Available: 10, OpenDemand: 9}, nil
 			},
 
 			wantProductInventory: inventory.ProductInventory{
-				Product:   product,
-				Available: 1,
+				Product:    product,
+				OnHand:     10,
+				Reserved:   9,
+				Available:  1,
+				OpenDemand: 0,
 			},
 			wantResUpdates: []reservationUpdate{
 				{ID: 0, State: inventory.Closed, Quantity: 3},
@@ -962,14 +1004,17 @@ func TestFillReserves(t *testing.T) {
 				}, nil
 			},
 			getProductInventoryFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) (pi inventory.ProductInventory, err error) {
-				return inventory.ProductInventory{Product: product, Available: 10}, nil
+				return inventory.ProductInventory{Product: product, OnHand: 10, Available: 10, OpenDemand: 3}, nil
 			},
 			saveProductInventoryErr: errors.New("some unexpected error"),
 
 			wantErr: true,
 			wantProductInventory: inventory.ProductInventory{
-				Product:   product,
-				Available: 7,
+				Product:    product,
+				OnHand:     10,
+				Reserved:   3,
+				Available:  7,
+				OpenDemand: 0,
 			},
 			wantResUpdates:   []reservationUpdate{},
 			wantQueueCallCnt: map[string]int{"PublishInventory": 0, "PublishReservation": 0},
@@ -986,14 +1031,17 @@ func TestFillReserves(t *testing.T) {
 				}, nil
 			},
 			getProductInventoryFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) (pi inventory.ProductInventory, err error) {
-				return inventory.ProductInventory{Product: product, Available: 10}, nil
+				return inventory.ProductInventory{Product: product, OnHand: 10, Available: 10, OpenDemand: 3}, nil
 			},
 			updateReservationErr: errors.New("some unexpected error"),
 
 			wantErr: true,
 			wantProductInventory: inventory.ProductInventory{
-				Product:   product,
-				Available: 7,
+				Product:    product,
+				OnHand:     10,
+				Reserved:   3,
+				Available:  7,
+				OpenDemand: 0,
 			},
 			wantResUpdates: []reservationUpdate{
 				{ID: 0, State: inventory.Closed, Quantity: 3},
@@ -1012,7 +1060,7 @@ func TestFillReserves(t *testing.T) {
 				}, nil
 			},
 			getProductInventoryFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) (pi inventory.ProductInventory, err error) {
-				return inventory.ProductInventory{Product: product, Available: 10}, nil
+				return inventory.ProductInventory{Product: product, OnHand: 10, Available: 10, OpenDemand: 3}, nil
 			},
 			publishInventoryFunc: func(ctx context.Context, pi inventory.ProductInventory) error {
 				return errors.New("some unexpected error")
@@ -1020,8 +1068,11 @@ func TestFillReserves(t *testing.T) {
 
 			wantErr: true,
 			wantProductInventory: inventory.ProductInventory{
-				Product:   product,
-				Available: 7,
+				Product:    product,
+				OnHand:     10,
+				Reserved:   3,
+				Available:  7,
+				OpenDemand: 0,
 			},
 			wantResUpdates: []reservationUpdate{
 				{ID: 0, State: inventory.Closed, Quantity: 3},
@@ -1040,7 +1091,7 @@ func TestFillReserves(t *testing.T) {
 				}, nil
 			},
 			getProductInventoryFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) (pi inventory.ProductInventory, err error) {
-				return inventory.ProductInventory{Product: product, Available: 10}, nil
+				return inventory.ProductInventory{Product: product, OnHand: 10, Available: 10, OpenDemand: 3}, nil
 			},
 			publishReservationFunc: func(ctx context.Context, r inventory.Reservation) error {
 				return errors.New("some unexpected error")
@@ -1048,8 +1099,11 @@ func TestFillReserves(t *testing.T) {
 
 			wantErr: true,
 			wantProductInventory: inventory.ProductInventory{
-				Product:   product,
-				Available: 7,
+				Product:    product,
+				OnHand:     10,
+				Reserved:   3,
+				Available:  7,
+				OpenDemand: 0,
 			},
 			wantResUpdates: []reservationUpdate{
 				{ID: 0, State: inventory.Closed, Quantity: 3},
@@ -1160,6 +1214,7 @@ func TestSubscribeInventory(t *testing.T) {
 	}()
 
 	want := getProductInventory()[2]
+	want.OnHand++
 	want.Available++
 
 	select {
@@ -1231,9 +1286,9 @@ func TestSubscribeReservations(t *testing.T) {
 
 func getProductInventory() []inventory.ProductInventory {
 	return []inventory.ProductInventory{
-		{Product: inventory.Product{Sku: "sku1", Upc: "upc1", Name: "name1"}, Available: 1},
-		{Product: inventory.Product{Sku: "sku2", Upc: "upc2", Name: "name2"}, Available: 10},
-		{Product: inventory.Product{Sku: "sku3", Upc: "upc3", Name: "name3"}, Available: 0},
+		{Product: inventory.Product{Sku: "sku1", Upc: "upc1", Name: "name1"}, OnHand: 1, Available: 1},
+		{Product: inventory.Product{Sku: "sku2", Upc: "upc2", Name: "name2"}, OnHand: 10, Available: 10},
+		{Product: inventory.Product{Sku: "sku3", Upc: "upc3", Name: "name3"}, OnHand: 0, Available: 0},
 	}
 }
 
@@ -1245,3 +1300,3988 @@ func getReservations() []inventory.Reservation {
 		{ID: 3, RequestID: "request4", Requester: "requester1", Sku: "sku3", State: inventory.Open, ReservedQuantity: 2, RequestedQuantity: 10},
 	}
 }
+
+func TestReceiveCost(t *testing.T) {
+	product := inventory.Product{Sku: "sku", Upc: "upc", Name: "name"}
+
+	tests := []struct {
+		name    string
+		method  inventory.CostingMethod
+		request inventory.ProductionRequest
+		layers  []inventory.CostLayer
+
+		wantLayer        inventory.CostLayer
+		wantLayerUpdates map[uint64]int64
+		wantEntry        inventory.ValuationEntry
+		wantErr          bool
+	}{
+		{
+			name:    "fifo adds a new layer",
+			method:  inventory.FIFO,
+			request: inventory.ProductionRequest{RequestID: "request1", Quantity: 5, UnitCost: 3},
+			layers:  []inventory.CostLayer{{ID: 1, Sku: "sku", UnitCost: 1, Quantity: 5, Remaining: 5}},
+
+			wantLayer:        inventory.CostLayer{Sku: "sku", UnitCost: 3, Quantity: 5, Remaining: 5},
+			wantLayerUpdates: map[uint64]int64{},
+			wantEntry: inventory.ValuationEntry{Sku: "sku", Method: inventory.FIFO, Reason: inventory.ValuationProduction,
+				Reference: "request1", Quantity: 5, Cost: 15, BalanceQuantity: 10, BalanceValue: 20},
+		},
+		{
+			name:    "weighted average folds existing layers",
+			method:  inventory.WeightedAverage,
+			request: inventory.ProductionRequest{RequestID: "request1", Quantity: 5, UnitCost: 3},
+			layers:  []inventory.CostLayer{{ID: 1, Sku: "sku", UnitCost: 1, Quantity: 5, Remaining: 5}},
+
+			wantLayer:        inventory.CostLayer{Sku: "sku", UnitCost: 2, Quantity: 10, Remaining: 10},
+			wantLayerUpdates: map[uint64]int64{1: 0},
+			wantEntry: inventory.ValuationEntry{Sku: "sku", Method: inventory.WeightedAverage, Reason: inventory.ValuationProduction,
+				Reference: "request1", Quantity: 5, Cost: 15, BalanceQuantity: 10, BalanceValue: 20},
+		},
+		{
+			name:    "weighted average with no existing layers",
+			method:  inventory.WeightedAverage,
+			request: inventory.ProductionRequest{RequestID: "request1", Quantity: 5, UnitCost: 3},
+
+			wantLayer:        inventory.CostLayer{Sku: "sku", UnitCost: 3, Quantity: 5, Remaining: 5},
+			wantLayerUpdates: map[uint64]int64{},
+			wantEntry: inventory.ValuationEntry{Sku: "sku", Method: inventory.WeightedAverage, Reason: inventory.ValuationProduction,
+				Reference: "request1", Quantity: 5, Cost: 15, BalanceQuantity: 5, BalanceValue: 15},
+		},
+		{
+			name:    "negative unit cost is rejected",
+			method:  inventory.FIFO,
+			request: inventory.ProductionRequest{RequestID: "request1", Quantity: 5, UnitCost: -1},
+
+			wantLayerUpdates: map[uint64]int64{},
+			wantErr:          true,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductionEventByRequestIDFunc = func(ctx context.Context, requestID string, options ...core.QueryOptions) (inventory.ProductionEvent, error) {
+			return inventory.ProductionEvent{}, core.ErrNotFound
+		}
+		mockRepo.GetCostLayersFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CostLayer, error) {
+			return test.layers, nil
+		}
+
+		var gotLayer inventory.CostLayer
+		mockRepo.SaveCostLayerFunc = func(ctx context.Context, layer *inventory.CostLayer, options ...core.UpdateOptions) error {
+			gotLayer = *layer
+			return nil
+		}
+		gotLayerUpdates := map[uint64]int64{}
+		mockRepo.UpdateCostLayerFunc = func(ctx context.Context, ID uint64, remaining int64, options ...core.UpdateOptions) error {
+			gotLayerUpdates[ID] = remaining
+			return nil
+		}
+		var gotEntry inventory.ValuationEntry
+		mockRepo.SaveValuationEntryFunc = func(ctx context.Context, entry *inventory.ValuationEntry, options ...core.UpdateOptions) error {
+			gotEntry = *entry
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue(), inventory.Costing(test.method))
+
+		t.Run(test.name, func(t *testing.T) {
+			err := service.Produce(context.Background(), product, test.request)
+			if test.wantErr && err == nil {
+				t.Errorf("expected error, got none")
+			} else if !test.wantErr && err != nil {
+				t.Errorf("did not want error, got=%v", err)
+			}
+
+			gotLayer.Created = time.Time{}
+			if !reflect.DeepEqual(gotLayer, test.wantLayer) {
+				t.Errorf("unexpected cost layer\n got=%+v\nwant=%+v", gotLayer, test.wantLayer)
+			}
+
+			if !reflect.DeepEqual(gotLayerUpdates, test.wantLayerUpdates) {
+				t.Errorf("unexpected layer updates\n got=%+v\nwant=%+v", gotLayerUpdates, test.wantLayerUpdates)
+			}
+
+			gotEntry.Created = time.Time{}
+			if !reflect.DeepEqual(gotEntry, test.wantEntry) {
+				t.Errorf("unexpected valuation entry\n got=%+v\nwant=%+v", gotEntry, test.wantEntry)
+			}
+		})
+	}
+}
+
+func TestRelieveCost(t *testing.T) {
+	product := inventory.Product{Sku: "sku", Upc: "upc", Name: "name"}
+
+	tests := []struct {
+		name         string
+		layers       []inventory.CostLayer
+		reservations []inventory.Reservation
+
+		wantLayerUpdates map[uint64]int64
+		wantCosts        []float64
+	}{
+		{
+			name: "oldest layers are relieved first",
+			layers: []inventory.CostLayer{
+				{ID: 1, Sku: "sku", UnitCost: 1, Quantity: 2, Remaining: 2},
+				{ID: 2, Sku: "sku", UnitCost: 3, Quantity: 5, Remaining: 5},
+			},
+			reservations: []inventory.Reservation{
+				{ID: 7, State: inventory.Open, RequestedQuantity: 4},
+			},
+
+			wantLayerUpdates: map[uint64]int64{1: 0, 2: 3},
+			wantCosts:        []float64{8},
+		},
+		{
+			name: "stock without cost layers is relieved at no cost",
+			reservations: []inventory.Reservation{
+				{ID: 7, State: inventory.Open, RequestedQuantity: 4},
+			},
+
+			wantLayerUpdates: map[uint64]int64{},
+			wantCosts:        []float64{0},
+		},
+	}
+
+	for _, test := range tests {
+		mockTx := db.NewMockTransaction()
+		mockTx.BeginFunc = func(ctx context.Context) (pgx.Tx, error) {
+			return db.NewMockPgxTx(), nil
+		}
+
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.BeginTransactionFunc = func(ctx context.Context) (core.Transaction, error) {
+			return mockTx, nil
+		}
+		mockRepo.GetReservationsFunc = func(ctx context.Context, resOptions inventory.GetReservationsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.Reservation, error) {
+			return test.reservations, nil
+		}
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			return inventory.ProductInventory{Product: product, OnHand: 10, Available: 10, OpenDemand: 4}, nil
+		}
+		mockRepo.GetCostLayersFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CostLayer, error) {
+			return test.layers, nil
+		}
+
+		gotLayerUpdates := map[uint64]int64{}
+		mockRepo.UpdateCostLayerFunc = func(ctx context.Context, ID uint64, remaining int64, options ...core.UpdateOptions) error {
+			gotLayerUpdates[ID] = remaining
+			return nil
+		}
+		gotCosts := []float64{}
+		mockRepo.UpdateReservationCostFunc = func(ctx context.Context, ID uint64, costOfGoods float64, options ...core.UpdateOptions) error {
+			gotCosts = append(gotCosts, costOfGoods)
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			if err := service.FillReserves(context.Background(), product); err != nil {
+				t.Errorf("did not want error, got=%v", err)
+			}
+
+			if !reflect.DeepEqual(gotLayerUpdates, test.wantLayerUpdates) {
+				t.Errorf("unexpected layer updates\n got=%+v\nwant=%+v", gotLayerUpdates, test.wantLayerUpdates)
+			}
+
+			if !reflect.DeepEqual(gotCosts, test.wantCosts) {
+				t.Errorf("unexpected cost of goods\n got=%+v\nwant=%+v", gotCosts, test.wantCosts)
+			}
+		})
+	}
+}
+
+func TestCreateKit(t *testing.T) {
+	kit := inventory.Product{Sku: "kit", Upc: "kitupc", Name: "kit"}
+
+	tests := []struct {
+		name       string
+		components []inventory.KitComponent
+		products   map[string]inventory.Product
+
+		wantComponentsSaved int
+		wantErr             bool
+	}{
+		{
+			name:       "kit is created with its components",
+			components: []inventory.KitComponent{{Sku: "a", Quantity: 2}, {Sku: "b", Quantity: 1}},
+			products: map[string]inventory.Product{
+				"a": {Sku: "a", Type: inventory.Standard},
+				"b": {Sku: "b", Type: inventory.Standard},
+			},
+			wantComponentsSaved: 1,
+		},
+		{
+			name:    "kit requires components",
+			wantErr: true,
+		},
+		{
+			name:       "components must exist",
+			components: []inventory.KitComponent{{Sku: "a", Quantity: 2}},
+			products:   map[string]inventory.Product{},
+			wantErr:    true,
+		},
+		{
+			name:       "kits cannot be nested",
+			components: []inventory.KitComponent{{Sku: "a", Quantity: 1}},
+			products:   map[string]inventory.Product{"a": {Sku: "a", Type: inventory.Kit}},
+			wantErr:    true,
+		},
+		{
+			name:       "component quantity must be positive",
+			components: []inventory.KitComponent{{Sku: "a", Quantity: 0}},
+			products:   map[string]inventory.Product{"a": {Sku: "a", Type: inventory.Standard}},
+			wantErr:    true,
+		},
+		{
+			name:       "kit cannot contain itself",
+			components: []inventory.KitComponent{{Sku: "kit", Quantity: 1}},
+			products:   map[string]inventory.Product{},
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			p, ok := test.products[sku]
+			if !ok {
+				return inventory.Product{}, core.ErrNotFound
+			}
+			return p, nil
+		}
+		var savedProduct inventory.Product
+		mockRepo.SaveProductFunc = func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+			savedProduct = product
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			err := service.CreateKit(context.Background(), kit, test.components)
+			if test.wantErr && err == nil {
+				t.Errorf("expected error, got none")
+			} else if !test.wantErr && err != nil {
+				t.Errorf("did not want error, got=%v", err)
+			}
+
+			mockRepo.VerifyCount("SaveKitComponents", test.wantComponentsSaved, t)
+			if !test.wantErr && savedProduct.Type != inventory.Kit {
+				t.Errorf("unexpected product type got=%v want=%v", savedProduct.Type, inventory.Kit)
+			}
+		})
+	}
+}
+
+func TestGetKitInventory(t *testing.T) {
+	kit := inventory.Product{Sku: "kit", Type: inventory.Kit}
+
+	tests := []struct {
+		name       string
+		components []inventory.KitComponent
+		inventory  map[string]inventory.ProductInventory
+
+		wantAvailable int64
+	}{
+		{
+			name:       "availability is limited by the scarcest component",
+			components: []inventory.KitComponent{{Sku: "a", Quantity: 2}, {Sku: "b", Quantity: 1}},
+			inventory: map[string]inventory.ProductInventory{
+				"a": {Available: 7},
+				"b": {Available: 5},
+			},
+			wantAvailable: 3,
+		},
+		{
+			name:       "a missing component means no kits are available",
+			components: []inventory.KitComponent{{Sku: "a", Quantity: 2}, {Sku: "b", Quantity: 1}},
+			inventory: map[string]inventory.ProductInventory{
+				"a": {Available: 7},
+				"b": {Available: 0},
+			},
+			wantAvailable: 0,
+		},
+		{
+			name:          "a kit without components has no availability",
+			wantAvailable: 0,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetKitComponentsFunc = func(ctx context.Context, kitSku string, options ...core.QueryOptions) ([]inventory.KitComponent, error) {
+			return test.components, nil
+		}
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			if sku == kit.Sku {
+				return inventory.ProductInventory{Product: kit, Reserved: 1}, nil
+			}
+			return test.inventory[sku], nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			got, err := service.GetProductInventory(context.Background(), kit.Sku)
+			if err != nil {
+				t.Errorf("did not want error, got=%v", err)
+			}
+			if got.Available != test.wantAvailable {
+				t.Errorf("unexpected available got=%d want=%d", got.Available, test.wantAvailable)
+			}
+			if got.OnHand != test.wantAvailable+1 {
+				t.Errorf("unexpected on hand got=%d want=%d", got.OnHand, test.wantAvailable+1)
+			}
+		})
+	}
+}
+
+func TestFillKitReserves(t *testing.T) {
+	kit := inventory.Product{Sku: "kit", Type: inventory.Kit}
+	components := []inventory.KitComponent{{Sku: "a", Quantity: 2}, {Sku: "b", Quantity: 1}}
+
+	tests := []struct {
+		name         string
+		inventory    map[string]inventory.ProductInventory
+		reservations []inventory.Reservation
+
+		wantInventory          map[string]inventory.ProductInventory
+		wantReservations       []inventory.Reservation
+		wantPublishInventory   int
+		wantPublishReservation int
+	}{
+		{
+			name: "components are reserved for whole kits",
+			inventory: map[string]inventory.ProductInventory{
+				"kit": {Product: kit, OpenDemand: 5},
+				"a":   {Product: inventory.Product{Sku: "a"}, OnHand: 7, Available: 7},
+				"b":   {Product: inventory.Product{Sku: "b"}, OnHand: 5, Available: 5},
+			},
+			reservations: []inventory.Reservation{
+				{ID: 1, Sku: "kit", State: inventory.Open, RequestedQuantity: 2},
+				{ID: 2, Sku: "kit", State: inventory.Open, RequestedQuantity: 3},
+			},
+			wantInventory: map[string]inventory.ProductInventory{
+				"kit": {Product: kit, OnHand: 3, Reserved: 3, Available: 0, OpenDemand: 2},
+				"a":   {Product: inventory.Product{Sku: "a"}, OnHand: 7, Reserved: 6, Available: 1},
+				"b":   {Product: inventory.Product{Sku: "b"}, OnHand: 5, Reserved: 3, Available: 2},
+			},
+			wantReservations: []inventory.Reservation{
+				{ID: 1, Sku: "kit", State: inventory.Closed, RequestedQuantity: 2, ReservedQuantity: 2},
+				{ID: 2, Sku: "kit", State: inventory.Open, RequestedQuantity: 3, ReservedQuantity: 1},
+			},
+			wantPublishInventory:   3,
+			wantPublishReservation: 2,
+		},
+		{
+			name: "nothing is reserved when a component is short",
+			inventory: map[string]inventory.ProductInventory{
+				"kit": {Product: kit, OpenDemand: 2},
+				"a":   {Product: inventory.Product{Sku: "a"}, OnHand: 1, Available: 1},
+				"b":   {Product: inventory.Product{Sku: "b"}, OnHand: 5, Available: 5},
+			},
+			reservations: []inventory.Reservation{
+				{ID: 1, Sku: "kit", State: inventory.Open, RequestedQuantity: 2},
+			},
+			wantInventory: map[string]inventory.ProductInventory{
+				"kit": {Product: kit, OpenDemand: 2},
+			},
+			wantReservations:     []inventory.Reservation{},
+			wantPublishInventory: 1,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetKitComponentsFunc = func(ctx context.Context, kitSku string, options ...core.QueryOptions) ([]inventory.KitComponent, error) {
+			return components, nil
+		}
+		mockRepo.GetReservationsFunc = func(ctx context.Context, resOptions inventory.GetReservationsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.Reservation, error) {
+			return test.reservations, nil
+		}
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			return test.inventory[sku], nil
+		}
+		gotInventory := map[string]inventory.ProductInventory{}
+		mockRepo.SaveProductInventoryFunc = func(ctx context.Context, productInventory inventory.ProductInventory, options ...core.UpdateOptions) error {
+			gotInventory[productInventory.Sku] = productInventory
+			return nil
+		}
+		gotReservations := []inventory.Reservation{}
+		mockRepo.UpdateReservationFunc = func(ctx context.Context, ID uint64, state inventory.ReserveState, qty int64, options ...core.UpdateOptions) error {
+			for _, r := range test.reservations {
+				if r.ID == ID {
+					r.State = state
+					r.ReservedQuantity = qty
+					gotReservations = append(gotReservations, r)
+				}
+			}
+			return nil
+		}
+
+		mockQueue := queue.NewMockQueue()
+		service := inventory.NewService(mockRepo, mockQueue)
+
+		t.Run(test.name, func(t *testing.T) {
+			if err := service.FillReserves(context.Background(), kit); err != nil {
+				t.Errorf("did not want error, got=%v", err)
+			}
+
+			if !reflect.DeepEqual(gotInventory, test.wantInventory) {
+				t.Errorf("unexpected inventory\n got=%+v\nwant=%+v", gotInventory, test.wantInventory)
+			}
+			if !reflect.DeepEqual(gotReservations, test.wantReservations) {
+				t.Errorf("unexpected reservations\n got=%+v\nwant=%+v", gotReservations, test.wantReservations)
+			}
+			mockQueue.VerifyCount("PublishInventory", test.wantPublishInventory, t)
+			mockQueue.VerifyCount("PublishReservation", test.wantPublishReservation, t)
+		})
+	}
+}
+
+func TestFillReservesRefreshesKits(t *testing.T) {
+	component := inventory.Product{Sku: "a", Type: inventory.Standard}
+
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetKitsContainingFunc = func(ctx context.Context, componentSku string, options ...core.QueryOptions) ([]inventory.Product, error) {
+		return []inventory.Product{{Sku: "kit1", Type: inventory.Kit}, {Sku: "kit2", Type: inventory.Kit}}, nil
+	}
+	mockQueue := queue.NewMockQueue()
+	service := inventory.NewService(mockRepo, mockQueue)
+
+	if err := service.FillReserves(context.Background(), component); err != nil {
+		t.Errorf("did not want error, got=%v", err)
+	}
+
+	mockRepo.VerifyCount("GetKitComponents", 2, t)
+	mockQueue.VerifyCount("PublishInventory", 2, t)
+}
+
+func TestProductAttributes(t *testing.T) {
+	definitions := []inventory.AttributeDefinition{
+		{Name: "weight", Type: inventory.AttributeNumber},
+		{Name: "hazmat", Type: inventory.AttributeBoolean},
+		{Name: "color", Type: inventory.AttributeString},
+	}
+
+	tests := []struct {
+		name       string
+		attributes inventory.Attributes
+
+		wantSaveProduct int
+		wantErr         error
+	}{
+		{
+			name:            "attributes match their definitions",
+			attributes:      inventory.Attributes{"weight": 2.5, "hazmat": true, "color": "red"},
+			wantSaveProduct: 1,
+		},
+		{
+			name:            "undefined attributes are not validated",
+			attributes:      inventory.Attributes{"customerPart": 1234},
+			wantSaveProduct: 1,
+		},
+		{
+			name:       "number attribute given a string",
+			attributes: inventory.Attributes{"weight": "heavy"},
+			wantErr:    inventory.ErrInvalidAttribute,
+		},
+		{
+			name:       "boolean attribute given a number",
+			attributes: inventory.Attributes{"hazmat": 1},
+			wantErr:    inventory.ErrInvalidAttribute,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetAttributeDefinitionsFunc = func(ctx context.Context, options ...core.QueryOptions) ([]inventory.AttributeDefinition, error) {
+			return definitions, nil
+		}
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			return inventory.Product{Sku: sku, Attributes: inventory.Attributes{"old": "value"}}, nil
+		}
+		var saved inventory.Product
+		mockRepo.SaveProductFunc = func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+			saved = product
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.UpdateProductAttributes(context.Background(), "sku", test.attributes)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+
+			mockRepo.VerifyCount("SaveProduct", test.wantSaveProduct, t)
+			if test.wantErr == nil && !reflect.DeepEqual(saved.Attributes, test.attributes) {
+				t.Errorf("unexpected attributes\n got=%+v\nwant=%+v", saved.Attributes, test.attributes)
+			}
+		})
+	}
+}
+
+func TestGetAllProductInventoryAttributeFilter(t *testing.T) {
+	definitions := []inventory.AttributeDefinition{
+		{Name: "weight", Type: inventory.AttributeNumber},
+		{Name: "hazmat", Type: inventory.AttributeBoolean},
+	}
+
+	tests := []struct {
+		name   string
+		filter inventory.Attributes
+
+		wantFilter inventory.Attributes
+		wantErr    error
+	}{
+		{
+			name:       "filter values are converted to their defined types",
+			filter:     inventory.Attributes{"weight": "2.5", "hazmat": "true", "color": "red"},
+			wantFilter: inventory.Attributes{"weight": 2.5, "hazmat": true, "color": "red"},
+		},
+		{
+			name:    "invalid number filter",
+			filter:  inventory.Attributes{"weight": "heavy"},
+			wantErr: inventory.ErrInvalidAttribute,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetAttributeDefinitionsFunc = func(ctx context.Context, options ...core.QueryOptions) ([]inventory.AttributeDefinition, error) {
+			return definitions, nil
+		}
+		var gotFilter inventory.Attributes
+		mockRepo.GetAllProductInventoryFunc = func(ctx context.Context, piOptions inventory.GetProductInventoryOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+			gotFilter = piOptions.Attributes
+			return nil, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.GetAllProductInventory(context.Background(), inventory.GetProductInventoryOptions{Attributes: test.filter}, 10, 0)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(gotFilter, test.wantFilter) {
+				t.Errorf("unexpected filter\n got=%+v\nwant=%+v", gotFilter, test.wantFilter)
+			}
+		})
+	}
+}
+
+func TestUpdateCategory(t *testing.T) {
+	// 1 -> 2 -> 3 and a separate root 4
+	tree := map[uint64]inventory.Category{
+		1: {ID: 1, Name: "sports"},
+		2: {ID: 2, Name: "baseball", ParentID: 1},
+		3: {ID: 3, Name: "bats", ParentID: 2},
+		4: {ID: 4, Name: "outdoors"},
+	}
+
+	tests := []struct {
+		name     string
+		category inventory.Category
+
+		wantSave int
+		wantErr  error
+	}{
+		{
+			name:     "category is moved to another branch",
+			category: inventory.Category{ID: 2, Name: "baseball", ParentID: 4},
+			wantSave: 1,
+		},
+		{
+			name:     "category is made a root",
+			category: inventory.Category{ID: 3, Name: "bats"},
+			wantSave: 1,
+		},
+		{
+			name:     "category cannot be its own parent",
+			category: inventory.Category{ID: 2, Name: "baseball", ParentID: 2},
+			wantErr:  inventory.ErrInvalidCategory,
+		},
+		{
+			name:     "category cannot be moved under a descendant",
+			category: inventory.Category{ID: 1, Name: "sports", ParentID: 3},
+			wantErr:  inventory.ErrInvalidCategory,
+		},
+		{
+			name:     "parent must exist",
+			category: inventory.Category{ID: 3, Name: "bats", ParentID: 99},
+			wantErr:  inventory.ErrInvalidCategory,
+		},
+		{
+			name:     "category must exist",
+			category: inventory.Category{ID: 99, Name: "bats"},
+			wantErr:  core.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetCategoryFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Category, error) {
+			c, ok := tree[ID]
+			if !ok {
+				return inventory.Category{}, core.ErrNotFound
+			}
+			return c, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.UpdateCategory(context.Background(), test.category)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SaveCategory", test.wantSave, t)
+		})
+	}
+}
+
+func TestDeleteCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		children []inventory.Category
+
+		wantDelete int
+		wantErr    error
+	}{
+		{
+			name:       "empty category is deleted",
+			wantDelete: 1,
+		},
+		{
+			name:     "category with children is not deleted",
+			children: []inventory.Category{{ID: 2, ParentID: 1}},
+			wantErr:  inventory.ErrInvalidCategory,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetChildCategoriesFunc = func(ctx context.Context, parentID uint64, options ...core.QueryOptions) ([]inventory.Category, error) {
+			return test.children, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			err := service.DeleteCategory(context.Background(), 1)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("DeleteCategory", test.wantDelete, t)
+		})
+	}
+}
+
+func TestAssignProductCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		categoryID uint64
+
+		wantSave int
+		wantErr  error
+	}{
+		{
+			name:       "product is assigned",
+			categoryID: 1,
+			wantSave:   1,
+		},
+		{
+			name:     "product is unassigned",
+			wantSave: 1,
+		},
+		{
+			name:       "category must exist",
+			categoryID: 99,
+			wantErr:    inventory.ErrInvalidCategory,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetCategoryFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Category, error) {
+			if ID != 1 {
+				return inventory.Category{}, core.ErrNotFound
+			}
+			return inventory.Category{ID: 1}, nil
+		}
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			return inventory.Product{Sku: sku, CategoryID: 5}, nil
+		}
+		var saved inventory.Product
+		mockRepo.SaveProductFunc = func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+			saved = product
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.AssignProductCategory(context.Background(), "sku", test.categoryID)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SaveProduct", test.wantSave, t)
+			if test.wantErr == nil && saved.CategoryID != test.categoryID {
+				t.Errorf("unexpected category got=%d want=%d", saved.CategoryID, test.categoryID)
+			}
+		})
+	}
+}
+
+func TestImportProducts(t *testing.T) {
+	tests := []struct {
+		name    string
+		imports []inventory.ProductImport
+		saveErr string
+
+		wantStatuses     []inventory.ImportStatus
+		wantSave         int
+		wantSaveInv      int
+		wantTransactions int
+	}{
+		{
+			name: "new and existing products are upserted",
+			imports: []inventory.ProductImport{
+				{Line: 2, Product: inventory.Product{Sku: "new", Upc: "1", Name: "New"}},
+				{Line: 3, Product: inventory.Product{Sku: "existing", Upc: "2", Name: "Existing"}},
+			},
+			wantStatuses:     []inventory.ImportStatus{inventory.ImportCreated, inventory.ImportUpdated},
+			wantSave:         2,
+			wantSaveInv:      1,
+			wantTransactions: 1,
+		},
+		{
+			name: "invalid products are reported without being saved",
+			imports: []inventory.ProductImport{
+				{Line: 2, Product: inventory.Product{Sku: "new", Name: "New"}},
+				{Line: 3, Product: inventory.Product{Sku: "new", Upc: "1", Name: "New", Attributes: inventory.Attributes{"weight": "heavy"}}},
+				{Line: 4, Product: inventory.Product{Sku: "new", Upc: "1", Name: "New", CategoryID: 99}},
+				{Line: 5, Product: inventory.Product{Sku: "kit", Upc: "1", Name: "Kit"}},
+				{Line: 6, Product: inventory.Product{Sku: "new", Upc: "1", Name: "New", Attributes: inventory.Attributes{"weight": "1.5"}}},
+			},
+			wantStatuses: []inventory.ImportStatus{
+				inventory.ImportFailed, inventory.ImportFailed, inventory.ImportFailed, inventory.ImportFailed, inventory.ImportCreated,
+			},
+			wantSave:         1,
+			wantSaveInv:      1,
+			wantTransactions: 3,
+		},
+		{
+			name: "style and variant of new products are validated",
+			imports: []inventory.ProductImport{
+				{Line: 2, Product: inventory.Product{Sku: "new", Upc: "1", Name: "New", Style: "shirt", Variant: inventory.Variant{"size": "S"}}},
+				{Line: 3, Product: inventory.Product{Sku: "new", Upc: "1", Name: "New", Style: "hat", Variant: inventory.Variant{"size": "S"}}},
+				{Line: 4, Product: inventory.Product{Sku: "new", Upc: "1", Name: "New", Style: "shirt", Variant: inventory.Variant{"color": "red"}}},
+			},
+			wantStatuses:     []inventory.ImportStatus{inventory.ImportCreated, inventory.ImportFailed, inventory.ImportFailed},
+			wantSave:         1,
+			wantSaveInv:      1,
+			wantTransactions: 1,
+		},
+		{
+			name: "a variant already taken fails on its own",
+			imports: []inventory.ProductImport{
+				{Line: 2, Product: inventory.Product{Sku: "new", Upc: "1", Name: "New", Style: "shirt", Variant: inventory.Variant{"size": "S"}}},
+				{Line: 3, Product: inventory.Product{Sku: "new2", Upc: "2", Name: "New", Style: "shirt", Variant: inventory.Variant{"size": "M"}}},
+			},
+			wantStatuses:     []inventory.ImportStatus{inventory.ImportCreated, inventory.ImportFailed},
+			wantSave:         2,
+			wantSaveInv:      2,
+			wantTransactions: 3,
+		},
+		{
+			name: "a failed save is retried individually",
+			imports: []inventory.ProductImport{
+				{Line: 2, Product: inventory.Product{Sku: "new", Upc: "1", Name: "New"}},
+				{Line: 3, Product: inventory.Product{Sku: "bad", Upc: "2", Name: "Bad"}},
+			},
+			saveErr:          "bad",
+			wantStatuses:     []inventory.ImportStatus{inventory.ImportCreated, inventory.ImportFailed},
+			wantSave:         4,
+			wantSaveInv:      2,
+			wantTransactions: 3,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetAttributeDefinitionsFunc = func(ctx context.Context, options ...core.QueryOptions) ([]inventory.AttributeDefinition, error) {
+			return []inventory.AttributeDefinition{{Name: "weight", Type: inventory.AttributeNumber}}, nil
+		}
+		mockRepo.GetCategoryFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Category, error) {
+			return inventory.Category{}, core.ErrNotFound
+		}
+		mockRepo.GetStyleFunc = func(ctx context.Context, code string, options ...core.QueryOptions) (inventory.Style, error) {
+			if code != "shirt" {
+				return inventory.Style{}, core.ErrNotFound
+			}
+			return inventory.Style{Code: code, Axes: []string{"size"}}, nil
+		}
+		mockRepo.GetStyleVariantsFunc = func(ctx context.Context, codes []string, attributes inventory.Attributes, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+			return []inventory.ProductInventory{{Product: inventory.Product{Sku: "taken", Style: "shirt", Variant: inventory.Variant{"size": "M"}}}}, nil
+		}
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			switch sku {
+			case "existing":
+				return inventory.Product{Sku: sku, Type: inventory.Standard}, nil
+			case "kit":
+				return inventory.Product{Sku: sku, Type: inventory.Kit}, nil
+			}
+			return inventory.Product{}, core.ErrNotFound
+		}
+		mockRepo.SaveProductFunc = func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+			if product.Sku == test.saveErr {
+				return errors.New("some unexpected error")
+			}
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			results, err := service.ImportProducts(context.Background(), test.imports)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(results) != len(test.wantStatuses) {
+				t.Fatalf("unexpected results got=%d want=%d", len(results), len(test.wantStatuses))
+			}
+			for i, result := range results {
+				if result.Line != test.imports[i].Line {
+					t.Errorf("unexpected line got=%d want=%d", result.Line, test.imports[i].Line)
+				}
+				if result.Status != test.wantStatuses[i] {
+					t.Errorf("line %d unexpected status got=%s want=%s error=%s", result.Line, result.Status, test.wantStatuses[i], result.Error)
+				}
+				if result.Status == inventory.ImportFailed && result.Error == "" {
+					t.Errorf("line %d failed without an error", result.Line)
+				}
+			}
+			mockRepo.VerifyCount("SaveProduct", test.wantSave, t)
+			mockRepo.VerifyCount("SaveProductInventory", test.wantSaveInv, t)
+			mockRepo.VerifyCount("BeginTransaction", test.wantTransactions, t)
+		})
+	}
+}
+
+func TestImportProductSettings(t *testing.T) {
+	noInspection, noThreshold := false, int64(0)
+
+	tests := []struct {
+		name string
+		imp  inventory.ProductImport
+
+		wantInspection bool
+		wantThreshold  int64
+		wantSlaHours   int64
+	}{
+		{
+			name:           "settings left out are kept",
+			imp:            inventory.ProductImport{Line: 2, Product: inventory.Product{Sku: "existing", Upc: "1", Name: "Existing"}},
+			wantInspection: true,
+			wantThreshold:  5,
+			wantSlaHours:   24,
+		},
+		{
+			name: "explicit false and zero settings are applied",
+			imp: inventory.ProductImport{
+				Line:               2,
+				Product:            inventory.Product{Sku: "existing", Upc: "1", Name: "Existing"},
+				RequiresInspection: &noInspection,
+				ApprovalThreshold:  &noThreshold,
+			},
+			wantInspection: false,
+			wantThreshold:  0,
+			wantSlaHours:   24,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			return inventory.Product{
+				Sku:                 sku,
+				Type:                inventory.Standard,
+				RequiresInspection:  true,
+				ApprovalThreshold:   5,
+				ReservationSlaHours: 24,
+			}, nil
+		}
+		var saved inventory.Product
+		mockRepo.SaveProductFunc = func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+			saved = product
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			results, err := service.ImportProducts(context.Background(), []inventory.ProductImport{test.imp})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if results[0].Status != inventory.ImportUpdated {
+				t.Fatalf("unexpected status got=%s want=%s error=%s", results[0].Status, inventory.ImportUpdated, results[0].Error)
+			}
+			if saved.RequiresInspection != test.wantInspection {
+				t.Errorf("unexpected requires inspection got=%v want=%v", saved.RequiresInspection, test.wantInspection)
+			}
+			if saved.ApprovalThreshold != test.wantThreshold {
+				t.Errorf("unexpected approval threshold got=%d want=%d", saved.ApprovalThreshold, test.wantThreshold)
+			}
+			if saved.ReservationSlaHours != test.wantSlaHours {
+				t.Errorf("unexpected reservation sla hours got=%d want=%d", saved.ReservationSlaHours, test.wantSlaHours)
+			}
+		})
+	}
+}
+
+func TestImportFrozenProduct(t *testing.T) {
+	freezes := inventory.Freezes{inventory.FreezeIntake: inventory.FreezeSetting{Reason: "recall", User: "admin"}}
+
+	tests := []struct {
+		name string
+		sku  string
+
+		wantStatus  inventory.ImportStatus
+		wantFreezes inventory.Freezes
+	}{
+		{
+			name:        "existing freezes are kept",
+			sku:         "existing",
+			wantStatus:  inventory.ImportUpdated,
+			wantFreezes: freezes,
+		},
+		{
+			name:       "new products cannot be imported frozen",
+			sku:        "new",
+			wantStatus: inventory.ImportCreated,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			if sku != "existing" {
+				return inventory.Product{}, core.ErrNotFound
+			}
+			return inventory.Product{Sku: sku, Type: inventory.Standard, Freezes: freezes}, nil
+		}
+		var saved inventory.Product
+		mockRepo.SaveProductFunc = func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+			saved = product
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			imp := inventory.ProductImport{Line: 2, Product: inventory.Product{
+				Sku:     test.sku,
+				Upc:     "1",
+				Name:    "Frozen",
+				Freezes: inventory.Freezes{inventory.FreezeReservations: inventory.FreezeSetting{Reason: "imported"}},
+			}}
+			results, err := service.ImportProducts(context.Background(), []inventory.ProductImport{imp})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if results[0].Status != test.wantStatus {
+				t.Fatalf("unexpected status got=%s want=%s error=%s", results[0].Status, test.wantStatus, results[0].Error)
+			}
+			if !reflect.DeepEqual(saved.Freezes, test.wantFreezes) {
+				t.Errorf("unexpected freezes got=%v want=%v", saved.Freezes, test.wantFreezes)
+			}
+		})
+	}
+}
+
+func TestProduceBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests []inventory.SkuProductionRequest
+		saveErr  string
+
+		wantStatuses     []inventory.ProductionStatus
+		wantSaveEvent    int
+		wantSaveInv      int
+		wantFillReserves int
+		wantHeld         int64
+	}{
+		{
+			name: "reserves are filled once per sku",
+			requests: []inventory.SkuProductionRequest{
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "req1", Quantity: 1}},
+				{Sku: "sku2", ProductionRequest: inventory.ProductionRequest{RequestID: "req2", Quantity: 2}},
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "req3", Quantity: 3}},
+			},
+			wantStatuses:     []inventory.ProductionStatus{inventory.ProductionProduced, inventory.ProductionProduced, inventory.ProductionProduced},
+			wantSaveEvent:    3,
+			wantSaveInv:      2,
+			wantFillReserves: 2,
+		},
+		{
+			name: "duplicates are reported",
+			requests: []inventory.SkuProductionRequest{
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "existing", Quantity: 1}},
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "req1", Quantity: 1}},
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "req1", Quantity: 1}},
+			},
+			wantStatuses:     []inventory.ProductionStatus{inventory.ProductionDuplicate, inventory.ProductionProduced, inventory.ProductionDuplicate},
+			wantSaveEvent:    1,
+			wantSaveInv:      1,
+			wantFillReserves: 1,
+		},
+		{
+			name: "invalid requests fail without affecting the rest",
+			requests: []inventory.SkuProductionRequest{
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "req1", Quantity: 0}},
+				{Sku: "missing", ProductionRequest: inventory.ProductionRequest{RequestID: "req2", Quantity: 1}},
+				{Sku: "kit", ProductionRequest: inventory.ProductionRequest{RequestID: "req3", Quantity: 1}},
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{Quantity: 1}},
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "req4", Quantity: 1}},
+			},
+			wantStatuses: []inventory.ProductionStatus{
+				inventory.ProductionFailed, inventory.ProductionFailed, inventory.ProductionFailed, inventory.ProductionFailed, inventory.ProductionProduced,
+			},
+			wantSaveEvent:    1,
+			wantSaveInv:      1,
+			wantFillReserves: 1,
+		},
+		{
+			name: "a failed save is retried individually",
+			requests: []inventory.SkuProductionRequest{
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "req1", Quantity: 1}},
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "bad", Quantity: 1}},
+			},
+			saveErr:          "bad",
+			wantStatuses:     []inventory.ProductionStatus{inventory.ProductionProduced, inventory.ProductionFailed},
+			wantSaveEvent:    4,
+			wantSaveInv:      1,
+			wantFillReserves: 1,
+		},
+		{
+			name: "production requiring inspection is held on its lots",
+			requests: []inventory.SkuProductionRequest{
+				{Sku: "inspected", ProductionRequest: inventory.ProductionRequest{RequestID: "req1", Quantity: 2}},
+				{Sku: "inspected", ProductionRequest: inventory.ProductionRequest{RequestID: "req2", Quantity: 3}},
+			},
+			wantStatuses:  []inventory.ProductionStatus{inventory.ProductionProduced, inventory.ProductionProduced},
+			wantSaveEvent: 2,
+			wantSaveInv:   1,
+			wantHeld:      5,
+		},
+		{
+			name: "intake frozen while the batch runs fails the sku",
+			requests: []inventory.SkuProductionRequest{
+				{Sku: "frozen", ProductionRequest: inventory.ProductionRequest{RequestID: "req1", Quantity: 2}},
+				{Sku: "frozen", ProductionRequest: inventory.ProductionRequest{RequestID: "req2", Quantity: 3}},
+				{Sku: "sku1", ProductionRequest: inventory.ProductionRequest{RequestID: "req3", Quantity: 1}},
+			},
+			wantStatuses:     []inventory.ProductionStatus{inventory.ProductionFailed, inventory.ProductionFailed, inventory.ProductionProduced},
+			wantSaveEvent:    1,
+			wantSaveInv:      1,
+			wantFillReserves: 1,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			switch sku {
+			case "missing":
+				return inventory.Product{}, core.ErrNotFound
+			case "kit":
+				return inventory.Product{Sku: sku, Type: inventory.Kit}, nil
+			}
+			return inventory.Product{Sku: sku, Type: inventory.Standard, RequiresInspection: sku == "inspected"}, nil
+		}
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			pi := inventory.ProductInventory{Product: inventory.Product{Sku: sku, RequiresInspection: sku == "inspected"}}
+			if sku == "frozen" {
+				pi.Freezes = inventory.Freezes{inventory.FreezeIntake: {Reason: "incident 42"}}
+			}
+			return pi, nil
+		}
+		var gotInventory inventory.ProductInventory
+		mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+			gotInventory = pi
+			return nil
+		}
+		mockRepo.GetProductionEventByRequestIDFunc = func(ctx context.Context, requestID string, options ...core.QueryOptions) (inventory.ProductionEvent, error) {
+			if requestID == "existing" {
+				return inventory.ProductionEvent{RequestID: requestID}, nil
+			}
+			return inventory.ProductionEvent{}, core.ErrNotFound
+		}
+		held := int64(0)
+		mockRepo.SaveProductionEventFunc = func(ctx context.Context, event *inventory.ProductionEvent, options ...core.UpdateOptions) error {
+			if event.RequestID == test.saveErr {
+				return errors.New("some unexpected error")
+			}
+			held += event.Held
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			results, err := service.ProduceBatch(context.Background(), test.requests)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(results) != len(test.wantStatuses) {
+				t.Fatalf("unexpected results got=%d want=%d", len(results), len(test.wantStatuses))
+			}
+			for i, result := range results {
+				if result.RequestID != test.requests[i].RequestID {
+					t.Errorf("unexpected request id got=%s want=%s", result.RequestID, test.requests[i].RequestID)
+				}
+				if result.Status != test.wantStatuses[i] {
+					t.Errorf("result %d unexpected status got=%s want=%s error=%s", i, result.Status, test.wantStatuses[i], result.Error)
+				}
+			}
+			mockRepo.VerifyCount("SaveProductionEvent", test.wantSaveEvent, t)
+			mockRepo.VerifyCount("SaveProductInventory", test.wantSaveInv, t)
+			mockRepo.VerifyCount("GetReservations", test.wantFillReserves, t)
+			if held != test.wantHeld || gotInventory.Held != test.wantHeld {
+				t.Errorf("held got lots=%d inventory=%d want=%d", held, gotInventory.Held, test.wantHeld)
+			}
+		})
+	}
+}
+
+func TestGetFillRates(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   inventory.FillRateGroup
+		options inventory.ReportOptions
+
+		wantRates []inventory.FillRate
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:  "fill rate is the reserved share of requested",
+			group: inventory.FillRateBySku,
+			wantRates: []inventory.FillRate{
+				{Key: "sku1", Requested: 8, Reserved: 6, FillRate: 0.75},
+				{Key: "sku2"},
+			},
+			wantCalls: 1,
+		},
+		{
+			name:    "group must be known",
+			group:   "color",
+			wantErr: inventory.ErrInvalidReport,
+		},
+		{
+			name:  "from must be before to",
+			group: inventory.FillRateByRequester,
+			options: inventory.ReportOptions{
+				From: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: inventory.ErrInvalidReport,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetFillRatesFunc = func(ctx context.Context, group inventory.FillRateGroup, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.FillRate, error) {
+			return []inventory.FillRate{{Key: "sku1", Requested: 8, Reserved: 6}, {Key: "sku2"}}, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			rates, err := service.GetFillRates(context.Background(), test.group, test.options)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			if test.wantErr == nil && !reflect.DeepEqual(rates, test.wantRates) {
+				t.Errorf("unexpected rates got=%+v want=%+v", rates, test.wantRates)
+			}
+			mockRepo.VerifyCount("GetFillRates", test.wantCalls, t)
+		})
+	}
+}
+
+func TestGetTopOpenDemand(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "demand is reported", limit: 10, wantCalls: 1},
+		{name: "limit must be positive", limit: 0, wantErr: inventory.ErrInvalidReport},
+		{name: "limit is capped", limit: 101, wantErr: inventory.ErrInvalidReport},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.GetTopOpenDemand(context.Background(), inventory.ReportOptions{}, test.limit)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("GetTopOpenDemand", test.wantCalls, t)
+		})
+	}
+}
+
+func TestGetAvailableToPromise(t *testing.T) {
+	day1 := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	day3 := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name         string
+		available    int64
+		reservations []inventory.Reservation
+		plans        []inventory.PlannedProduction
+		inbound      []inventory.PurchaseOrderLine
+
+		wantProfile   []inventory.AtpPeriod
+		wantFillDates []*time.Time
+		wantDemand    int64
+		wantShortfall int64
+	}{
+		{
+			name:      "supply is allocated to reservations in order",
+			available: 5,
+			reservations: []inventory.Reservation{
+				{ID: 1, RequestedQuantity: 10, ReservedQuantity: 2},
+				{ID: 2, RequestedQuantity: 4},
+				{ID: 3, RequestedQuantity: 10},
+			},
+			plans: []inventory.PlannedProduction{
+				{Quantity: 3, Due: time.Now().Add(-time.Hour)},
+				{Quantity: 5, Due: day1},
+				{Quantity: 6, Due: day3},
+			},
+			wantProfile: []inventory.AtpPeriod{
+				{Receipts: 8, Allocated: 8, Available: 0},
+				{Date: day1, Receipts: 5, Allocated: 5, Available: 0},
+				{Date: day3, Receipts: 6, Allocated: 6, Available: 0},
+			},
+			wantFillDates: []*time.Time{{}, &day1, nil},
+			wantDemand:    22,
+			wantShortfall: 3,
+		},
+		{
+			name:      "supply beyond demand is free to promise",
+			available: 2,
+			reservations: []inventory.Reservation{
+				{ID: 1, RequestedQuantity: 1},
+			},
+			plans: []inventory.PlannedProduction{
+				{Quantity: 4, Due: day1},
+			},
+			wantProfile: []inventory.AtpPeriod{
+				{Receipts: 2, Allocated: 1, Available: 1},
+				{Date: day1, Receipts: 4, Allocated: 0, Available: 5},
+			},
+			wantFillDates: []*time.Time{{}},
+			wantDemand:    1,
+		},
+		{
+			name:      "outstanding purchase order lines are supply",
+			available: 1,
+			reservations: []inventory.Reservation{
+				{ID: 1, RequestedQuantity: 6},
+			},
+			plans: []inventory.PlannedProduction{
+				{Quantity: 2, Due: day3},
+			},
+			inbound: []inventory.PurchaseOrderLine{
+				{Quantity: 10, Received: 7, Expected: day1},
+			},
+			wantProfile: []inventory.AtpPeriod{
+				{Receipts: 1, Allocated: 1, Available: 0},
+				{Date: day1, Receipts: 3, Allocated: 3, Available: 0},
+				{Date: day3, Receipts: 2, Allocated: 2, Available: 0},
+			},
+			wantFillDates: []*time.Time{&day3},
+			wantDemand:    6,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			return inventory.Product{Sku: sku, Type: inventory.Standard}, nil
+		}
+		mockRepo.GetInboundLinesFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PurchaseOrderLine, error) {
+			return test.inbound, nil
+		}
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			return inventory.ProductInventory{Product: inventory.Product{Sku: sku, Type: inventory.Standard}, Available: test.available}, nil
+		}
+		mockRepo.GetReservationsFunc = func(ctx context.Context, resOptions inventory.GetReservationsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.Reservation, error) {
+			if resOptions.State != inventory.Open {
+				t.Errorf("unexpected state got=%s want=%s", resOptions.State, inventory.Open)
+			}
+			return test.reservations, nil
+		}
+		mockRepo.GetPlannedProductionFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PlannedProduction, error) {
+			return test.plans, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			before := time.Now()
+			atp, err := service.GetAvailableToPromise(context.Background(), "sku")
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			now := atp.Profile[0].Date
+			if now.Before(before) || now.After(time.Now()) {
+				t.Errorf("first period is not now got=%v", now)
+			}
+			test.wantProfile[0].Date = now
+			if !reflect.DeepEqual(atp.Profile, test.wantProfile) {
+				t.Errorf("unexpected profile\n got=%+v\nwant=%+v", atp.Profile, test.wantProfile)
+			}
+
+			if len(atp.Reservations) != len(test.wantFillDates) {
+				t.Fatalf("unexpected reservations got=%d want=%d", len(atp.Reservations), len(test.wantFillDates))
+			}
+			for i, want := range test.wantFillDates {
+				if want != nil && want.IsZero() {
+					want = &now
+				}
+				got := atp.Reservations[i].FillDate
+				if (got == nil) != (want == nil) || (got != nil && !got.Equal(*want)) {
+					t.Errorf("reservation %d unexpected fill date got=%v want=%v", i, got, want)
+				}
+			}
+
+			if atp.OpenDemand != test.wantDemand {
+				t.Errorf("unexpected open demand got=%d want=%d", atp.OpenDemand, test.wantDemand)
+			}
+			if atp.Shortfall != test.wantShortfall {
+				t.Errorf("unexpected shortfall got=%d want=%d", atp.Shortfall, test.wantShortfall)
+			}
+		})
+	}
+}
+
+func TestPlanProduction(t *testing.T) {
+	due := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		plan     inventory.PlannedProduction
+		wantSave int
+		wantErr  error
+	}{
+		{
+			name:     "production is planned",
+			plan:     inventory.PlannedProduction{Sku: "sku", Quantity: 5, Due: due},
+			wantSave: 1,
+		},
+		{
+			name:    "quantity must be positive",
+			plan:    inventory.PlannedProduction{Sku: "sku", Due: due},
+			wantErr: inventory.ErrInvalidPlan,
+		},
+		{
+			name:    "due date is required",
+			plan:    inventory.PlannedProduction{Sku: "sku", Quantity: 5},
+			wantErr: inventory.ErrInvalidPlan,
+		},
+		{
+			name:    "kits cannot be planned",
+			plan:    inventory.PlannedProduction{Sku: "kit", Quantity: 5, Due: due},
+			wantErr: inventory.ErrInvalidPlan,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			if sku == "kit" {
+				return inventory.Product{Sku: sku, Type: inventory.Kit}, nil
+			}
+			return inventory.Product{Sku: sku, Type: inventory.Standard}, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.PlanProduction(context.Background(), test.plan)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SavePlannedProduction", test.wantSave, t)
+		})
+	}
+}
+
+func TestStartCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		skus     []string
+		frozen   []string
+		wantSave int
+		wantErr  error
+	}{
+		{
+			name:     "count is started",
+			skus:     []string{"sku1", "sku2"},
+			wantSave: 1,
+		},
+		{
+			name:    "skus are required",
+			wantErr: inventory.ErrInvalidCount,
+		},
+		{
+			name:    "skus must be unique",
+			skus:    []string{"sku1", "sku1"},
+			wantErr: inventory.ErrInvalidCount,
+		},
+		{
+			name:    "kits cannot be counted",
+			skus:    []string{"sku1", "kit"},
+			wantErr: inventory.ErrInvalidCount,
+		},
+		{
+			name:    "products must exist",
+			skus:    []string{"missing"},
+			wantErr: inventory.ErrInvalidCount,
+		},
+		{
+			name:    "skus cannot be in two open counts",
+			skus:    []string{"sku1", "sku2"},
+			frozen:  []string{"sku2"},
+			wantErr: inventory.ErrInvalidCount,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			switch sku {
+			case "kit":
+				return inventory.Product{Sku: sku, Type: inventory.Kit}, nil
+			case "missing":
+				return inventory.Product{}, core.ErrNotFound
+			}
+			return inventory.Product{Sku: sku, Type: inventory.Standard}, nil
+		}
+		mockRepo.GetFrozenSkusFunc = func(ctx context.Context, skus []string, options ...core.QueryOptions) ([]string, error) {
+			return test.frozen, nil
+		}
+		var saved inventory.CountSession
+		mockRepo.SaveCountSessionFunc = func(ctx context.Context, session *inventory.CountSession, options ...core.UpdateOptions) error {
+			session.ID = 3
+			saved = *session
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			session, err := service.StartCount(context.Background(), test.skus)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SaveCountSession", test.wantSave, t)
+
+			if test.wantErr == nil {
+				if session.ID != 3 || session.State != inventory.CountOpen || len(saved.Lines) != len(test.skus) {
+					t.Errorf("session got=%+v", session)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordCount(t *testing.T) {
+	tests := []struct {
+		name         string
+		state        inventory.CountState
+		counts       []inventory.CountEntry
+		wantVariance map[string]int64
+		wantErr      error
+	}{
+		{
+			name:         "variance is counted less on hand",
+			state:        inventory.CountOpen,
+			counts:       []inventory.CountEntry{{Sku: "sku1", Quantity: 7}, {Sku: "sku2", Quantity: 12}},
+			wantVariance: map[string]int64{"sku1": -3, "sku2": 2},
+		},
+		{
+			name:    "sku must be part of the session",
+			state:   inventory.CountOpen,
+			counts:  []inventory.CountEntry{{Sku: "sku9", Quantity: 7}},
+			wantErr: inventory.ErrInvalidCount,
+		},
+		{
+			name:    "counts cannot be negative",
+			state:   inventory.CountOpen,
+			counts:  []inventory.CountEntry{{Sku: "sku1", Quantity: -1}},
+			wantErr: inventory.ErrInvalidCount,
+		},
+		{
+			name:    "closed sessions cannot be counted",
+			state:   inventory.CountApproved,
+			counts:  []inventory.CountEntry{{Sku: "sku1", Quantity: 7}},
+			wantErr: inventory.ErrInvalidCount,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetCountSessionFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CountSession, error) {
+			return inventory.CountSession{ID: ID, State: test.state, Lines: []inventory.CountLine{{Sku: "sku1"}, {Sku: "sku2"}}}, nil
+		}
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 10, Available: 10}, nil
+		}
+		gotVariance := make(map[string]int64)
+		mockRepo.UpdateCountLineFunc = func(ctx context.Context, sessionID uint64, line inventory.CountLine, options ...core.UpdateOptions) error {
+			gotVariance[line.Sku] = line.Variance
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.RecordCount(context.Background(), 3, test.counts)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			if test.wantErr == nil && !reflect.DeepEqual(gotVariance, test.wantVariance) {
+				t.Errorf("variance got=%v want=%v", gotVariance, test.wantVariance)
+			}
+		})
+	}
+}
+
+func TestApproveCount(t *testing.T) {
+	counted := int64(7)
+
+	tests := []struct {
+		name           string
+		lines          []inventory.CountLine
+		reserved       int64
+		wantOnHand     int64
+		wantAdjustment int
+		wantCostLayer  int
+		wantLot        int
+		wantRelieve    int
+		wantErr        error
+	}{
+		{
+			name:           "shortage is relieved",
+			lines:          []inventory.CountLine{{Sku: "sku1", Counted: &counted, SystemOnHand: 10, Variance: -3}},
+			wantOnHand:     7,
+			wantAdjustment: 1,
+			wantRelieve:    1,
+		},
+		{
+			name:           "overage is received",
+			lines:          []inventory.CountLine{{Sku: "sku1", Counted: &counted, SystemOnHand: 5, Variance: 2}},
+			wantOnHand:     12,
+			wantAdjustment: 1,
+			wantCostLayer:  1,
+			wantLot:        1,
+		},
+		{
+			name:  "no variance needs no adjustment",
+			lines: []inventory.CountLine{{Sku: "sku1", Counted: &counted, SystemOnHand: 7}},
+		},
+		{
+			name:    "every sku must be counted",
+			lines:   []inventory.CountLine{{Sku: "sku1", Counted: &counted, SystemOnHand: 7}, {Sku: "sku2"}},
+			wantErr: inventory.ErrInvalidCount,
+		},
+		{
+			name:     "on hand cannot fall below reserved",
+			lines:    []inventory.CountLine{{Sku: "sku1", Counted: &counted, SystemOnHand: 10, Variance: -3}},
+			reserved: 8,
+			wantErr:  inventory.ErrInvalidCount,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetCountSessionFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CountSession, error) {
+			return inventory.CountSession{ID: ID, State: inventory.CountOpen, Lines: test.lines}, nil
+		}
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 10, Available: 10 - test.reserved, Reserved: test.reserved}, nil
+		}
+		mockRepo.GetCostLayersFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CostLayer, error) {
+			return []inventory.CostLayer{{ID: 1, Sku: sku, Quantity: 10, Remaining: 10, UnitCost: 2}}, nil
+		}
+		var gotLot inventory.ProductionEvent
+		mockRepo.SaveProductionEventFunc = func(ctx context.Context, event *inventory.ProductionEvent, options ...core.UpdateOptions) error {
+			event.ID = 12
+			gotLot = *event
+			return nil
+		}
+		var gotLayer inventory.CostLayer
+		mockRepo.SaveCostLayerFunc = func(ctx context.Context, layer *inventory.CostLayer, options ...core.UpdateOptions) error {
+			gotLayer = *layer
+			return nil
+		}
+		var gotInventory inventory.ProductInventory
+		mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+			gotInventory = pi
+			return nil
+		}
+		var gotSession inventory.CountSession
+		mockRepo.UpdateCountSessionFunc = func(ctx context.Context, session inventory.CountSession, options ...core.UpdateOptions) error {
+			gotSession = session
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.ApproveCount(context.Background(), 3, "someadmin")
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SaveAdjustment", test.wantAdjustment, t)
+			mockRepo.VerifyCount("SaveCostLayer", test.wantCostLayer, t)
+			mockRepo.VerifyCount("UpdateCostLayer", test.wantRelieve, t)
+			mockRepo.VerifyCount("SaveProductionEvent", test.wantLot, t)
+			if test.wantLot > 0 && (gotLot.RequestID != "count-3-sku1" || gotLot.Remaining != 2 || gotLayer.ProductionEventID != gotLot.ID) {
+				t.Errorf("counted lot got=%+v layer=%+v", gotLot, gotLayer)
+			}
+
+			if test.wantErr != nil {
+				mockRepo.VerifyCount("UpdateCountSession", 0, t)
+				return
+			}
+			if test.wantAdjustment > 0 && gotInventory.OnHand != test.wantOnHand {
+				t.Errorf("on hand got=%d want=%d", gotInventory.OnHand, test.wantOnHand)
+			}
+			if gotSession.State != inventory.CountApproved || gotSession.ClosedBy != "someadmin" {
+				t.Errorf("session got=%+v", gotSession)
+			}
+		})
+	}
+}
+
+func TestFillReservesFrozen(t *testing.T) {
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetReservationsFunc = func(ctx context.Context, options inventory.GetReservationsOptions, limit, offset int, queryOptions ...core.QueryOptions) ([]inventory.Reservation, error) {
+		return []inventory.Reservation{{ID: 1, Sku: "sku1", State: inventory.Open, RequestedQuantity: 5}}, nil
+	}
+	mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+		return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 10, Available: 10}, nil
+	}
+	mockRepo.GetFrozenSkusFunc = func(ctx context.Context, skus []string, options ...core.QueryOptions) ([]string, error) {
+		return skus, nil
+	}
+	mockRepo.GetKitsContainingFunc = func(ctx context.Context, componentSku string, options ...core.QueryOptions) ([]inventory.Product, error) {
+		return []inventory.Product{{Sku: "kit1", Type: inventory.Kit}}, nil
+	}
+	mockRepo.GetKitComponentsFunc = func(ctx context.Context, kitSku string, options ...core.QueryOptions) ([]inventory.KitComponent, error) {
+		return []inventory.KitComponent{{Sku: "sku1", Quantity: 2}}, nil
+	}
+	var saved []inventory.ProductInventory
+	mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+		saved = append(saved, pi)
+		return nil
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+	if err := service.FillReserves(context.Background(), inventory.Product{Sku: "sku1"}); err != nil {
+		t.Fatal(err)
+	}
+	mockRepo.VerifyCount("UpdateReservation", 0, t)
+	mockRepo.VerifyCount("GetKitsContaining", 1, t)
+
+	if len(saved) != 1 || saved[0].Sku != "kit1" || saved[0].Available != 5 {
+		t.Errorf("saved inventory got %+v want only kit1 with 5 available", saved)
+	}
+}
+
+func TestProduceRequiresInspection(t *testing.T) {
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+		return inventory.ProductInventory{Product: inventory.Product{Sku: sku, RequiresInspection: true}, OnHand: 4, Available: 4}, nil
+	}
+	var got inventory.ProductInventory
+	mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+		got = pi
+		return nil
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+	err := service.Produce(context.Background(), inventory.Product{Sku: "sku1"}, inventory.ProductionRequest{RequestID: "req1", Quantity: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.OnHand != 9 || got.Held != 5 || got.Available != 4 {
+		t.Errorf("inventory got on hand=%d held=%d available=%d want 9, 5, 4", got.OnHand, got.Held, got.Available)
+	}
+	mockRepo.VerifyCount("GetReservations", 0, t)
+}
+
+func TestHeldInventory(t *testing.T) {
+	tests := []struct {
+		name    string
+		reject  bool
+		qty     int64
+		user    string
+		held    int64
+		wantPi  inventory.ProductInventory
+		wantErr error
+
+		wantAdjustment int
+		wantFill       int
+	}{
+		{
+			name:     "released inventory becomes available",
+			qty:      3,
+			user:     "inspector",
+			held:     5,
+			wantPi:   inventory.ProductInventory{OnHand: 10, Available: 8, Held: 2},
+			wantFill: 1,
+		},
+		{
+			name:           "rejected inventory is scrapped",
+			reject:         true,
+			qty:            3,
+			user:           "inspector",
+			held:           5,
+			wantPi:         inventory.ProductInventory{OnHand: 7, Available: 5, Held: 2},
+			wantAdjustment: 1,
+		},
+		{
+			name:    "cannot release more than is held",
+			qty:     6,
+			user:    "inspector",
+			held:    5,
+			wantErr: inventory.ErrInvalidHold,
+		},
+		{
+			name:    "quantity must be positive",
+			reject:  true,
+			user:    "inspector",
+			held:    5,
+			wantErr: inventory.ErrInvalidHold,
+		},
+		{
+			name:    "rejections require a user",
+			reject:  true,
+			qty:     3,
+			held:    5,
+			wantErr: inventory.ErrInvalidHold,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 10, Available: 5, Held: test.held}, nil
+		}
+		var got inventory.ProductInventory
+		mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+			got = pi
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			var err error
+			if test.reject {
+				_, err = service.RejectHeld(context.Background(), "sku1", test.qty, "lot 7", test.user)
+			} else {
+				_, err = service.ReleaseHeld(context.Background(), "sku1", test.qty, test.user)
+			}
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SaveAdjustment", test.wantAdjustment, t)
+			mockRepo.VerifyCount("GetReservations", test.wantFill, t)
+
+			if test.wantErr != nil {
+				return
+			}
+			if got.OnHand != test.wantPi.OnHand || got.Available != test.wantPi.Available || got.Held != test.wantPi.Held {
+				t.Errorf("inventory\n got=%+v\nwant=%+v", got, test.wantPi)
+			}
+		})
+	}
+}
+
+func TestFillReservesFromSubstitutes(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  inventory.SubstituteRule
+		allow bool
+
+		wantReserved  int64
+		wantFill      []inventory.ReservationFill
+		wantAvailable int64
+	}{
+		{
+			name:          "remainder is filled in whole multiples of the ratio",
+			rule:          inventory.SubstituteRule{Sku: "skuA", SubstituteSku: "skuB", Quantity: 1, SubstituteQuantity: 2},
+			allow:         true,
+			wantReserved:  4,
+			wantFill:      []inventory.ReservationFill{{Sku: "skuB", Quantity: 6}},
+			wantAvailable: 1,
+		},
+		{
+			name:          "two way rules fill in reverse",
+			rule:          inventory.SubstituteRule{Sku: "skuB", SubstituteSku: "skuA", Quantity: 1, SubstituteQuantity: 1, TwoWay: true},
+			allow:         true,
+			wantReserved:  5,
+			wantFill:      []inventory.ReservationFill{{Sku: "skuB", Quantity: 4}},
+			wantAvailable: 3,
+		},
+		{
+			name:          "one way rules do not fill in reverse",
+			rule:          inventory.SubstituteRule{Sku: "skuB", SubstituteSku: "skuA", Quantity: 1, SubstituteQuantity: 1},
+			allow:         true,
+			wantReserved:  1,
+			wantAvailable: 7,
+		},
+		{
+			name:          "reservations must opt in",
+			rule:          inventory.SubstituteRule{Sku: "skuA", SubstituteSku: "skuB", Quantity: 1, SubstituteQuantity: 1},
+			wantReserved:  1,
+			wantAvailable: 7,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetSubstituteRulesFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.SubstituteRule, error) {
+			return []inventory.SubstituteRule{test.rule}, nil
+		}
+		mockRepo.GetReservationsFunc = func(ctx context.Context, options inventory.GetReservationsOptions, limit, offset int, queryOptions ...core.QueryOptions) ([]inventory.Reservation, error) {
+			if options.Sku != "skuA" {
+				return []inventory.Reservation{}, nil
+			}
+			return []inventory.Reservation{{ID: 1, Sku: "skuA", State: inventory.Open, RequestedQuantity: 5, ReservedQuantity: 1, AllowSubstitutes: test.allow}}, nil
+		}
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			if sku == "skuA" {
+				return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 1, Reserved: 1, OpenDemand: 4}, nil
+			}
+			return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 7, Available: 7}, nil
+		}
+		saved := make(map[string]inventory.ProductInventory)
+		mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+			saved[pi.Sku] = pi
+			return nil
+		}
+		reserved := int64(1)
+		mockRepo.UpdateReservationFunc = func(ctx context.Context, ID uint64, state inventory.ReserveState, qty int64, options ...core.UpdateOptions) error {
+			reserved = qty
+			return nil
+		}
+		var fills []inventory.ReservationFill
+		mockRepo.AddReservationFillFunc = func(ctx context.Context, reservationID uint64, fill inventory.ReservationFill, options ...core.UpdateOptions) error {
+			fills = append(fills, fill)
+			return nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			if err := service.FillReserves(context.Background(), inventory.Product{Sku: "skuA"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if reserved != test.wantReserved {
+				t.Errorf("reserved got=%d want=%d", reserved, test.wantReserved)
+			}
+			if !reflect.DeepEqual(fills, test.wantFill) {
+				t.Errorf("fills got=%v want=%v", fills, test.wantFill)
+			}
+			if pi, ok := saved["skuB"]; ok && pi.Available != test.wantAvailable {
+				t.Errorf("substitute available got=%d want=%d", pi.Available, test.wantAvailable)
+			}
+			if pi, ok := saved["skuA"]; ok && pi.OpenDemand != 5-test.wantReserved {
+				t.Errorf("open demand got=%d want=%d", pi.OpenDemand, 5-test.wantReserved)
+			}
+		})
+	}
+}
+
+func TestCreateSubstitute(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     inventory.SubstituteRule
+		wantRule inventory.SubstituteRule
+		wantErr  error
+	}{
+		{
+			name:     "ratio defaults to one for one",
+			rule:     inventory.SubstituteRule{Sku: "sku1", SubstituteSku: "sku2"},
+			wantRule: inventory.SubstituteRule{Sku: "sku1", SubstituteSku: "sku2", Quantity: 1, SubstituteQuantity: 1},
+		},
+		{
+			name:    "a product cannot substitute for itself",
+			rule:    inventory.SubstituteRule{Sku: "sku1", SubstituteSku: "sku1"},
+			wantErr: inventory.ErrInvalidSubstitute,
+		},
+		{
+			name:    "kits cannot be substituted",
+			rule:    inventory.SubstituteRule{Sku: "sku1", SubstituteSku: "kit"},
+			wantErr: inventory.ErrInvalidSubstitute,
+		},
+		{
+			name:    "substitute must exist",
+			rule:    inventory.SubstituteRule{Sku: "sku1", SubstituteSku: "missing"},
+			wantErr: inventory.ErrInvalidSubstitute,
+		},
+	}
+
+	for _, test := range tests {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			switch sku {
+			case "kit":
+				return inventory.Product{Sku: sku, Type: inventory.Kit}, nil
+			case "missing":
+				return inventory.Product{}, core.ErrNotFound
+			}
+			return inventory.Product{Sku: sku, Type: inventory.Standard}, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := service.CreateSubstitute(context.Background(), test.rule)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("unexpected error got=%v want=%v", err, test.wantErr)
+			}
+			if test.wantErr != nil {
+				mockRepo.VerifyCount("SaveSubstituteRule", 0, t)
+				return
+			}
+			rule.Created = time.Time{}
+			if !reflect.DeepEqual(rule, test.wantRule) {
+				t.Errorf("rule\n got=%+v\nwant=%+v", rule, test.wantRule)
+			}
+		})
+	}
+}
+
+func TestFillReservesSplitBackorder(t *testing.T) {
+	tests := []struct {
+		name      string
+		split     bool
+		available int64
+
+		wantState     inventory.ReserveState
+		wantRequested int64
+		wantBackorder *inventory.Reservation
+		wantPublished int
+	}{
+		{
+			name:          "partly filled reservation is split",
+			split:         true,
+			available:     4,
+			wantState:     inventory.Closed,
+			wantRequested: 4,
+			wantBackorder: &inventory.Reservation{
+				ID: 2, RequestID: "req1-bo1", Requester: "requester", Sku: "sku1", State: inventory.Open,
+				RequestedQuantity: 6, ParentID: 1, SplitBackorder: true,
+			},
+			wantPublished: 2,
+		},
+		{
+			name:          "partly filled reservation is not split unless asked",
+			available:     4,
+			wantState:     inventory.Open,
+			wantRequested: 10,
+			wantPublished: 1,
+		},
+		{
+			name:          "filled reservation is not split",
+			split:         true,
+			available:     10,
+			wantState:     inventory.Closed,
+			wantRequested: 10,
+			wantPublished: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetReservationsFunc = func(ctx context.Context, options inventory.GetReservationsOptions, limit, offset int, queryOptions ...core.QueryOptions) ([]inventory.Reservation, error) {
+				return []inventory.Reservation{{ID: 1, RequestID: "req1", Requester: "requester", Sku: "sku1", State: inventory.Open, RequestedQuantity: 10, SplitBackorder: test.split}}, nil
+			}
+			mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+				return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: test.available, Available: test.available, OpenDemand: 10}, nil
+			}
+			var backorder *inventory.Reservation
+			mockRepo.SaveReservationFunc = func(ctx context.Context, r *inventory.Reservation, options ...core.UpdateOptions) error {
+				r.ID = 2
+				backorder = r
+				return nil
+			}
+			requested := int64(10)
+			mockRepo.UpdateReservationRequestedFunc = func(ctx context.Context, ID uint64, qty int64, options ...core.UpdateOptions) error {
+				requested = qty
+				return nil
+			}
+			var state inventory.ReserveState
+			mockRepo.UpdateReservationFunc = func(ctx context.Context, ID uint64, s inventory.ReserveState, qty int64, options ...core.UpdateOptions) error {
+				state = s
+				return nil
+			}
+			mockQueue := queue.NewMockQueue()
+
+			service := inventory.NewService(mockRepo, mockQueue)
+			if err := service.FillReserves(context.Background(), inventory.Product{Sku: "sku1"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if state != test.wantState {
+				t.Errorf("state got=%s want=%s", state, test.wantState)
+			}
+			if requested != test.wantRequested {
+				t.Errorf("requested got=%d want=%d", requested, test.wantRequested)
+			}
+			if !reflect.DeepEqual(backorder, test.wantBackorder) {
+				t.Errorf("backorder\n got=%+v\nwant=%+v", backorder, test.wantBackorder)
+			}
+			mockQueue.VerifyCount("PublishReservation", test.wantPublished, t)
+		})
+	}
+}
+
+func TestReserveSplitBackorder(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name    string
+		config  bool
+		request *bool
+		want    bool
+	}{
+		{name: "configured default is used", config: true, want: true},
+		{name: "request enables splitting", request: &yes, want: true},
+		{name: "request disables splitting", config: true, request: &no, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			var got bool
+			mockRepo.SaveReservationFunc = func(ctx context.Context, r *inventory.Reservation, options ...core.UpdateOptions) error {
+				got = r.SplitBackorder
+				return nil
+			}
+
+			service := inventory.NewService(mockRepo, queue.NewMockQueue(), inventory.SplitBackorders(test.config))
+			rr := inventory.ReservationRequest{RequestID: "req1", Requester: "requester", Sku: "sku1", Quantity: 1, SplitBackorder: test.request}
+			if _, err := service.Reserve(context.Background(), rr); err != nil {
+				t.Fatal(err)
+			}
+
+			if got != test.want {
+				t.Errorf("split backorder got=%t want=%t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFillReservesPegsProduction(t *testing.T) {
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetReservationsFunc = func(ctx context.Context, options inventory.GetReservationsOptions, limit, offset int, queryOptions ...core.QueryOptions) ([]inventory.Reservation, error) {
+		return []inventory.Reservation{{ID: 7, Sku: "sku1", State: inventory.Open, RequestedQuantity: 5}}, nil
+	}
+	mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+		return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 10, Available: 10, OpenDemand: 5}, nil
+	}
+	mockRepo.GetProductionLotsFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+		return []inventory.ProductionEvent{{ID: 1, Sku: sku, Remaining: 3}, {ID: 2, Sku: sku, Remaining: 7}}, nil
+	}
+	remaining := make(map[uint64]int64)
+	mockRepo.UpdateProductionLotFunc = func(ctx context.Context, ID uint64, qty, held int64, options ...core.UpdateOptions) error {
+		remaining[ID] = qty
+		return nil
+	}
+	var pegs []inventory.Peg
+	mockRepo.SavePegFunc = func(ctx context.Context, peg inventory.Peg, options ...core.UpdateOptions) error {
+		peg.Created = time.Time{}
+		pegs = append(pegs, peg)
+		return nil
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue())
+	if err := service.FillReserves(context.Background(), inventory.Product{Sku: "sku1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	wantPegs := []inventory.Peg{
+		{ProductionEventID: 1, ReservationID: 7, Sku: "sku1", Quantity: 3},
+		{ProductionEventID: 2, ReservationID: 7, Sku: "sku1", Quantity: 2},
+	}
+	if !reflect.DeepEqual(pegs, wantPegs) {
+		t.Errorf("pegs\n got=%+v\nwant=%+v", pegs, wantPegs)
+	}
+	wantRemaining := map[uint64]int64{1: 0, 2: 5}
+	if !reflect.DeepEqual(remaining, wantRemaining) {
+		t.Errorf("remaining got=%v want=%v", remaining, wantRemaining)
+	}
+}
+
+func TestRejectHeldWritesOffLots(t *testing.T) {
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+		return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 9, Available: 5, Held: 4}, nil
+	}
+	mockRepo.GetProductionLotsFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+		return []inventory.ProductionEvent{{ID: 1, Sku: sku, Remaining: 5}, {ID: 2, Sku: sku, Remaining: 4, Held: 4}}, nil
+	}
+	type lot struct{ remaining, held int64 }
+	lots := make(map[uint64]lot)
+	mockRepo.UpdateProductionLotFunc = func(ctx context.Context, ID uint64, remaining, held int64, options ...core.UpdateOptions) error {
+		lots[ID] = lot{remaining, held}
+		return nil
+	}
+	mockRepo.GetCostLayersFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CostLayer, error) {
+		return []inventory.CostLayer{
+			{ID: 10, Sku: sku, ProductionEventID: 1, UnitCost: 1, Quantity: 5, Remaining: 5},
+			{ID: 11, Sku: sku, ProductionEventID: 2, UnitCost: 3, Quantity: 4, Remaining: 4},
+		}, nil
+	}
+	layers := make(map[uint64]int64)
+	mockRepo.UpdateCostLayerFunc = func(ctx context.Context, ID uint64, remaining int64, options ...core.UpdateOptions) error {
+		layers[ID] = remaining
+		return nil
+	}
+	var entry inventory.ValuationEntry
+	mockRepo.SaveValuationEntryFunc = func(ctx context.Context, e *inventory.ValuationEntry, options ...core.UpdateOptions) error {
+		entry = *e
+		return nil
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue())
+	if _, err := service.RejectHeld(context.Background(), "sku1", 3, "ncr-1", "inspector"); err != nil {
+		t.Fatal(err)
+	}
+
+	wantLots := map[uint64]lot{2: {1, 1}}
+	if !reflect.DeepEqual(lots, wantLots) {
+		t.Errorf("lots got=%v want=%v", lots, wantLots)
+	}
+	wantLayers := map[uint64]int64{11: 1}
+	if !reflect.DeepEqual(layers, wantLayers) {
+		t.Errorf("cost layers got=%v want=%v", layers, wantLayers)
+	}
+	if entry.Quantity != -3 || entry.Cost != -9 {
+		t.Errorf("valuation entry got quantity=%d cost=%v want quantity=-3 cost=-9", entry.Quantity, entry.Cost)
+	}
+	mockRepo.VerifyCount("SavePeg", 0, t)
+}
+
+func TestReleaseHeldReleasesLots(t *testing.T) {
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+		return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 6, Held: 6}, nil
+	}
+	mockRepo.GetProductionLotsFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+		return []inventory.ProductionEvent{{ID: 1, Sku: sku, Remaining: 2, Held: 2}, {ID: 2, Sku: sku, Remaining: 4, Held: 4}}, nil
+	}
+	held := make(map[uint64]int64)
+	mockRepo.UpdateProductionLotFunc = func(ctx context.Context, ID uint64, remaining, h int64, options ...core.UpdateOptions) error {
+		held[ID] = h
+		return nil
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue())
+	if _, err := service.ReleaseHeld(context.Background(), "sku1", 3, "inspector"); err != nil {
+		t.Fatal(err)
+	}
+
+	wantHeld := map[uint64]int64{1: 0, 2: 3}
+	if !reflect.DeepEqual(held, wantHeld) {
+		t.Errorf("held got=%v want=%v", held, wantHeld)
+	}
+}
+
+func TestGetProductionConsumers(t *testing.T) {
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetProductionEventByRequestIDFunc = func(ctx context.Context, requestID string, options ...core.QueryOptions) (inventory.ProductionEvent, error) {
+		return inventory.ProductionEvent{}, core.ErrNotFound
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue())
+	if _, err := service.GetProductionConsumers(context.Background(), "missing"); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("error got=%v want=%v", err, core.ErrNotFound)
+	}
+	mockRepo.VerifyCount("GetPegsByProductionEvent", 0, t)
+}
+
+func TestReserveRequiresApproval(t *testing.T) {
+	tests := []struct {
+		name             string
+		globalThreshold  int64
+		productThreshold int64
+		quantity         int64
+		wantState        inventory.ReserveState
+	}{
+		{name: "no threshold", quantity: 1000, wantState: inventory.Open},
+		{name: "above global threshold", globalThreshold: 100, quantity: 101, wantState: inventory.PendingApproval},
+		{name: "at global threshold", globalThreshold: 100, quantity: 100, wantState: inventory.Open},
+		{name: "product threshold overrides global", globalThreshold: 100, productThreshold: 500, quantity: 200, wantState: inventory.Open},
+		{name: "above product threshold", productThreshold: 50, quantity: 51, wantState: inventory.PendingApproval},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+				return inventory.Product{Sku: sku, ApprovalThreshold: test.productThreshold}, nil
+			}
+			var saved inventory.Reservation
+			mockRepo.SaveReservationFunc = func(ctx context.Context, r *inventory.Reservation, options ...core.UpdateOptions) error {
+				saved = *r
+				return nil
+			}
+			mockQueue := queue.NewMockQueue()
+
+			service := inventory.NewService(mockRepo, mockQueue, inventory.ApprovalThreshold(test.globalThreshold))
+			rr := inventory.ReservationRequest{RequestID: "req1", Requester: "requester", Sku: "sku1", Quantity: test.quantity}
+			res, err := service.Reserve(context.Background(), rr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if saved.State != test.wantState || res.State != test.wantState {
+				t.Errorf("state got=%s want=%s", saved.State, test.wantState)
+			}
+			if test.wantState == inventory.PendingApproval {
+				mockRepo.VerifyCount("SaveProductInventory", 0, t)
+				mockRepo.VerifyCount("GetReservations", 0, t)
+				mockQueue.VerifyCount("PublishReservation", 1, t)
+			}
+		})
+	}
+}
+
+func TestDecideReservation(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    inventory.ReserveState
+		approve  bool
+		approver string
+
+		wantState      inventory.ReserveState
+		wantOpenDemand int64
+		wantFill       int
+		wantErr        error
+	}{
+		{
+			name:           "approved reservation is opened and filled",
+			state:          inventory.PendingApproval,
+			approve:        true,
+			approver:       "admin",
+			wantState:      inventory.Open,
+			wantOpenDemand: 500,
+			wantFill:       1,
+		},
+		{
+			name:      "rejected reservation is never filled",
+			state:     inventory.PendingApproval,
+			approver:  "admin",
+			wantState: inventory.Rejected,
+		},
+		{
+			name:     "only pending reservations can be decided",
+			state:    inventory.Open,
+			approve:  true,
+			approver: "admin",
+			wantErr:  inventory.ErrInvalidApproval,
+		},
+		{
+			name:    "approver is required",
+			state:   inventory.PendingApproval,
+			wantErr: inventory.ErrInvalidApproval,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetReservationFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Reservation, error) {
+				return inventory.Reservation{ID: ID, Sku: "sku1", State: test.state, RequestedQuantity: 500}, nil
+			}
+			var decided inventory.Reservation
+			mockRepo.DecideReservationFunc = func(ctx context.Context, r inventory.Reservation, options ...core.UpdateOptions) error {
+				decided = r
+				return nil
+			}
+			var saved inventory.ProductInventory
+			mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+				saved = pi
+				return nil
+			}
+			mockQueue := queue.NewMockQueue()
+
+			service := inventory.NewService(mockRepo, mockQueue)
+			var err error
+			if test.approve {
+				_, err = service.ApproveReservation(context.Background(), 3, test.approver)
+			} else {
+				_, err = service.RejectReservation(context.Background(), 3, test.approver, "too large")
+			}
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("error got=%v want=%v", err, test.wantErr)
+				}
+				mockRepo.VerifyCount("DecideReservation", 0, t)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if decided.State != test.wantState || decided.DecidedBy != test.approver || decided.Decided == nil {
+				t.Errorf("decision got=%+v", decided)
+			}
+			if saved.OpenDemand != test.wantOpenDemand {
+				t.Errorf("open demand got=%d want=%d", saved.OpenDemand, test.wantOpenDemand)
+			}
+			mockRepo.VerifyCount("GetReservations", test.wantFill, t)
+			mockQueue.VerifyCount("PublishReservation", 1, t)
+		})
+	}
+}
+
+func TestCreateReturn(t *testing.T) {
+	tests := []struct {
+		name        string
+		rma         inventory.ReturnAuthorization
+		returned    int64
+		productType inventory.ProductType
+
+		wantSku string
+		wantErr error
+	}{
+		{
+			name:    "return against a sku",
+			rma:     inventory.ReturnAuthorization{Sku: "sku1", Quantity: 2, Reason: "damaged", CreatedBy: "agent"},
+			wantSku: "sku1",
+		},
+		{
+			name:     "return against a reservation takes its sku",
+			rma:      inventory.ReturnAuthorization{ReservationID: 3, Quantity: 4, Reason: "wrong item", CreatedBy: "agent"},
+			returned: 6,
+			wantSku:  "sku1",
+		},
+		{
+			name:     "cannot return more than was reserved",
+			rma:      inventory.ReturnAuthorization{ReservationID: 3, Quantity: 5, Reason: "wrong item", CreatedBy: "agent"},
+			returned: 6,
+			wantErr:  inventory.ErrInvalidReturn,
+		},
+		{
+			name:    "sku must match the reservation",
+			rma:     inventory.ReturnAuthorization{Sku: "sku2", ReservationID: 3, Quantity: 1, Reason: "wrong item", CreatedBy: "agent"},
+			wantErr: inventory.ErrInvalidReturn,
+		},
+		{
+			name:        "kits cannot be returned",
+			rma:         inventory.ReturnAuthorization{Sku: "kit1", Quantity: 1, Reason: "damaged", CreatedBy: "agent"},
+			productType: inventory.Kit,
+			wantErr:     inventory.ErrInvalidReturn,
+		},
+		{
+			name:    "reason is required",
+			rma:     inventory.ReturnAuthorization{Sku: "sku1", Quantity: 1, CreatedBy: "agent"},
+			wantErr: inventory.ErrInvalidReturn,
+		},
+		{
+			name:    "quantity must be positive",
+			rma:     inventory.ReturnAuthorization{Sku: "sku1", Reason: "damaged", CreatedBy: "agent"},
+			wantErr: inventory.ErrInvalidReturn,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetReservationFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Reservation, error) {
+				return inventory.Reservation{ID: ID, Sku: "sku1", ReservedQuantity: 10}, nil
+			}
+			mockRepo.GetReturnedQuantityFunc = func(ctx context.Context, reservationID uint64, options ...core.QueryOptions) (int64, error) {
+				return test.returned, nil
+			}
+			mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+				return inventory.Product{Sku: sku, Type: test.productType}, nil
+			}
+			mockQueue := queue.NewMockQueue()
+
+			service := inventory.NewService(mockRepo, mockQueue)
+			got, err := service.CreateReturn(context.Background(), test.rma)
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("error got=%v want=%v", err, test.wantErr)
+				}
+				mockRepo.VerifyCount("SaveReturn", 0, t)
+				mockQueue.VerifyCount("PublishReturn", 0, t)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Sku != test.wantSku || got.State != inventory.ReturnRequested {
+				t.Errorf("return got=%+v", got)
+			}
+			mockRepo.VerifyCount("SaveReturn", 1, t)
+			mockQueue.VerifyCount("PublishReturn", 1, t)
+		})
+	}
+}
+
+func TestReceiveAndInspectReturn(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   inventory.ReturnState
+		receive bool
+		outcome inventory.InspectionOutcome
+		user    string
+
+		wantState      inventory.ReturnState
+		wantPi         *inventory.ProductInventory
+		wantUnitCost   float64
+		wantAdjustment int
+		wantLot        int
+		wantFill       int
+		wantErr        error
+	}{
+		{
+			name:      "requested return is received",
+			state:     inventory.ReturnRequested,
+			receive:   true,
+			user:      "dock",
+			wantState: inventory.ReturnReceived,
+		},
+		{
+			name:           "restocked return becomes available",
+			state:          inventory.ReturnReceived,
+			outcome:        inventory.OutcomeRestock,
+			user:           "inspector",
+			wantState:      inventory.ReturnRestocked,
+			wantPi:         &inventory.ProductInventory{OnHand: 14, Available: 9},
+			wantUnitCost:   2.5,
+			wantAdjustment: 1,
+			wantLot:        1,
+			wantFill:       1,
+		},
+		{
+			name:      "scrapped return is not restocked",
+			state:     inventory.ReturnReceived,
+			outcome:   inventory.OutcomeScrap,
+			user:      "inspector",
+			wantState: inventory.ReturnScrapped,
+		},
+		{
+			name:    "returns must be received before inspection",
+			state:   inventory.ReturnRequested,
+			outcome: inventory.OutcomeRestock,
+			user:    "inspector",
+			wantErr: inventory.ErrInvalidReturn,
+		},
+		{
+			name:    "returns are received once",
+			state:   inventory.ReturnReceived,
+			receive: true,
+			user:    "dock",
+			wantErr: inventory.ErrInvalidReturn,
+		},
+		{
+			name:    "user is required",
+			state:   inventory.ReturnReceived,
+			outcome: inventory.OutcomeScrap,
+			wantErr: inventory.ErrInvalidReturn,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetReturnFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.ReturnAuthorization, error) {
+				return inventory.ReturnAuthorization{ID: ID, Sku: "sku1", ReservationID: 3, Quantity: 4, State: test.state}, nil
+			}
+			mockRepo.GetReservationFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Reservation, error) {
+				return inventory.Reservation{ID: ID, Sku: "sku1", ReservedQuantity: 10, CostOfGoods: 25}, nil
+			}
+			mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+				return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 10, Available: 5}, nil
+			}
+			var gotPi *inventory.ProductInventory
+			mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+				gotPi = &pi
+				return nil
+			}
+			var gotLot inventory.ProductionEvent
+			mockRepo.SaveProductionEventFunc = func(ctx context.Context, event *inventory.ProductionEvent, options ...core.UpdateOptions) error {
+				event.ID = 12
+				gotLot = *event
+				return nil
+			}
+			var gotLayer inventory.CostLayer
+			mockRepo.SaveCostLayerFunc = func(ctx context.Context, layer *inventory.CostLayer, options ...core.UpdateOptions) error {
+				gotLayer = *layer
+				return nil
+			}
+			var updated inventory.ReturnAuthorization
+			mockRepo.UpdateReturnFunc = func(ctx context.Context, rma inventory.ReturnAuthorization, options ...core.UpdateOptions) error {
+				updated = rma
+				return nil
+			}
+			mockQueue := queue.NewMockQueue()
+
+			service := inventory.NewService(mockRepo, mockQueue)
+			var err error
+			if test.receive {
+				_, err = service.ReceiveReturn(context.Background(), 7, test.user)
+			} else {
+				_, err = service.InspectReturn(context.Background(), 7, test.outcome, "note", test.user)
+			}
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("error got=%v want=%v", err, test.wantErr)
+				}
+				mockRepo.VerifyCount("UpdateReturn", 0, t)
+				mockQueue.VerifyCount("PublishReturn", 0, t)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if updated.State != test.wantState {
+				t.Errorf("state got=%v want=%v", updated.State, test.wantState)
+			}
+			if test.wantPi == nil && gotPi != nil {
+				t.Errorf("inventory should not change got=%+v", *gotPi)
+			}
+			if test.wantPi != nil {
+				if gotPi == nil || gotPi.OnHand != test.wantPi.OnHand || gotPi.Available != test.wantPi.Available {
+					t.Errorf("inventory\n got=%+v\nwant=%+v", gotPi, test.wantPi)
+				}
+				if gotLayer.UnitCost != test.wantUnitCost || gotLayer.Quantity != 4 {
+					t.Errorf("cost layer got=%+v want unit cost %v", gotLayer, test.wantUnitCost)
+				}
+				if gotLot.Remaining != 4 || gotLayer.ProductionEventID != gotLot.ID {
+					t.Errorf("restocked lot got=%+v layer=%+v", gotLot, gotLayer)
+				}
+			}
+			mockRepo.VerifyCount("SaveAdjustment", test.wantAdjustment, t)
+			mockRepo.VerifyCount("SaveProductionEvent", test.wantLot, t)
+			mockRepo.VerifyCount("GetReservations", test.wantFill, t)
+			mockQueue.VerifyCount("PublishReturn", 1, t)
+		})
+	}
+}
+
+func TestGetInventoryTimeSeries(t *testing.T) {
+	from := time.Date(2026, 3, 1, 10, 20, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC)
+	hour := func(h, m int) time.Time { return time.Date(2026, 3, 1, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		options  inventory.ReportOptions
+		interval time.Duration
+
+		wantFrom    time.Time
+		wantBuckets []inventory.InventoryBucket
+		wantErr     error
+	}{
+		{
+			name:     "levels are bucketed from the opening level",
+			options:  inventory.ReportOptions{Sku: "sku1", From: from, To: to},
+			interval: time.Hour,
+			wantFrom: hour(10, 0),
+			wantBuckets: []inventory.InventoryBucket{
+				{Start: hour(10, 0), End: hour(11, 0), Min: 2, Max: 12, Close: 12, Produced: 10},
+				{Start: hour(11, 0), End: hour(12, 0), Min: 4, Max: 12, Close: 4, Allocated: 8},
+				{Start: hour(12, 0), End: hour(13, 0), Min: 4, Max: 4, Close: 4},
+			},
+		},
+		{
+			name:     "interval must be at least a minute",
+			options:  inventory.ReportOptions{Sku: "sku1", From: from, To: to},
+			interval: time.Second,
+			wantErr:  inventory.ErrInvalidReport,
+		},
+		{
+			name:     "from must be before to",
+			options:  inventory.ReportOptions{Sku: "sku1", From: to, To: from},
+			interval: time.Hour,
+			wantErr:  inventory.ErrInvalidReport,
+		},
+		{
+			name:     "too many buckets",
+			options:  inventory.ReportOptions{Sku: "sku1", From: from.AddDate(0, 0, -1), To: to},
+			interval: time.Minute,
+			wantErr:  inventory.ErrInvalidReport,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			var gotFrom time.Time
+			mockRepo.GetInventoryHistoryFunc = func(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.InventoryLevel, error) {
+				gotFrom = from
+				return []inventory.InventoryLevel{
+					{Sku: sku, Available: 2, Reserved: 5, Created: hour(9, 0)},
+					{Sku: sku, Available: 12, Reserved: 5, Created: hour(10, 30)},
+					{Sku: sku, Available: 7, Reserved: 10, Created: hour(11, 10)},
+					{Sku: sku, Available: 4, Reserved: 13, Created: hour(11, 50)},
+				}, nil
+			}
+			mockRepo.GetProductionEventsFunc = func(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+				return []inventory.ProductionEvent{{Sku: sku, Quantity: 10, Created: hour(10, 30)}}, nil
+			}
+
+			service := inventory.NewService(mockRepo, queue.NewMockQueue())
+			got, err := service.GetInventoryTimeSeries(context.Background(), test.options, test.interval)
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("error got=%v want=%v", err, test.wantErr)
+				}
+				mockRepo.VerifyCount("GetInventoryHistory", 0, t)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !gotFrom.Equal(test.wantFrom) {
+				t.Errorf("history from got=%v want=%v", gotFrom, test.wantFrom)
+			}
+			if !reflect.DeepEqual(got, test.wantBuckets) {
+				t.Errorf("buckets\n got=%+v\nwant=%+v", got, test.wantBuckets)
+			}
+		})
+	}
+}
+
+func TestCreatePurchaseOrder(t *testing.T) {
+	expected := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		po          inventory.PurchaseOrder
+		existing    []inventory.PurchaseOrder
+		productType inventory.ProductType
+
+		wantLineNumbers []int
+		wantSave        int
+		wantErr         error
+	}{
+		{
+			name: "lines are numbered in order",
+			po: inventory.PurchaseOrder{Number: "PO-1", Supplier: "acme", CreatedBy: "buyer", Lines: []inventory.PurchaseOrderLine{
+				{Sku: "sku1", Quantity: 10, UnitCost: 2, Expected: expected},
+				{Sku: "sku2", Quantity: 5, Expected: expected},
+			}},
+			wantLineNumbers: []int{1, 2},
+			wantSave:        1,
+		},
+		{
+			name: "existing purchase order is returned",
+			po: inventory.PurchaseOrder{Number: "PO-1", Supplier: "acme", CreatedBy: "buyer", Lines: []inventory.PurchaseOrderLine{
+				{Sku: "sku1", Quantity: 10, Expected: expected},
+			}},
+			existing: []inventory.PurchaseOrder{{ID: 4, Number: "PO-1"}},
+		},
+		{
+			name: "line numbers must be unique",
+			po: inventory.PurchaseOrder{Number: "PO-1", Supplier: "acme", CreatedBy: "buyer", Lines: []inventory.PurchaseOrderLine{
+				{LineNumber: 1, Sku: "sku1", Quantity: 10, Expected: expected},
+				{LineNumber: 1, Sku: "sku2", Quantity: 5, Expected: expected},
+			}},
+			wantErr: inventory.ErrInvalidPurchaseOrder,
+		},
+		{
+			name: "expected date is required",
+			po: inventory.PurchaseOrder{Number: "PO-1", Supplier: "acme", CreatedBy: "buyer", Lines: []inventory.PurchaseOrderLine{
+				{Sku: "sku1", Quantity: 10},
+			}},
+			wantErr: inventory.ErrInvalidPurchaseOrder,
+		},
+		{
+			name: "kits cannot be ordered",
+			po: inventory.PurchaseOrder{Number: "PO-1", Supplier: "acme", CreatedBy: "buyer", Lines: []inventory.PurchaseOrderLine{
+				{Sku: "kit1", Quantity: 10, Expected: expected},
+			}},
+			productType: inventory.Kit,
+			wantErr:     inventory.ErrInvalidPurchaseOrder,
+		},
+		{
+			name:    "lines are required",
+			po:      inventory.PurchaseOrder{Number: "PO-1", Supplier: "acme", CreatedBy: "buyer"},
+			wantErr: inventory.ErrInvalidPurchaseOrder,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+				return inventory.Product{Sku: sku, Type: test.productType}, nil
+			}
+			mockRepo.GetPurchaseOrdersFunc = func(ctx context.Context, poOptions inventory.GetPurchaseOrdersOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.PurchaseOrder, error) {
+				if poOptions.Number != test.po.Number {
+					t.Errorf("number got=%s want=%s", poOptions.Number, test.po.Number)
+				}
+				return test.existing, nil
+			}
+
+			service := inventory.NewService(mockRepo, queue.NewMockQueue())
+			got, err := service.CreatePurchaseOrder(context.Background(), test.po)
+
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SavePurchaseOrder", test.wantSave, t)
+			if test.wantErr != nil || test.wantSave == 0 {
+				return
+			}
+
+			if got.State != inventory.PurchaseOrderOpen {
+				t.Errorf("state got=%s want=%s", got.State, inventory.PurchaseOrderOpen)
+			}
+			for i, want := range test.wantLineNumbers {
+				if got.Lines[i].LineNumber != want || got.Lines[i].Variance != -got.Lines[i].Quantity {
+					t.Errorf("line %d got=%+v", i, got.Lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReceivePurchaseOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   inventory.ReceiptRequest
+		state     inventory.PurchaseOrderState
+		duplicate bool
+
+		wantState    inventory.PurchaseOrderState
+		wantVariance int64
+		wantProduced int
+		wantErr      error
+	}{
+		{
+			name:         "part of a line is received",
+			request:      inventory.ReceiptRequest{RequestID: "rcpt1", LineNumber: 1, Quantity: 4},
+			state:        inventory.PurchaseOrderOpen,
+			wantState:    inventory.PurchaseOrderPartiallyReceived,
+			wantVariance: -6,
+			wantProduced: 1,
+		},
+		{
+			name:         "over-receipt completes the order",
+			request:      inventory.ReceiptRequest{RequestID: "rcpt1", LineNumber: 1, Quantity: 12},
+			state:        inventory.PurchaseOrderOpen,
+			wantState:    inventory.PurchaseOrderReceived,
+			wantVariance: 2,
+			wantProduced: 1,
+		},
+		{
+			name:      "repeated request is ignored",
+			request:   inventory.ReceiptRequest{RequestID: "rcpt1", LineNumber: 1, Quantity: 4},
+			state:     inventory.PurchaseOrderOpen,
+			duplicate: true,
+		},
+		{
+			name:    "closed orders cannot be received",
+			request: inventory.ReceiptRequest{RequestID: "rcpt1", LineNumber: 1, Quantity: 4},
+			state:   inventory.PurchaseOrderClosed,
+			wantErr: inventory.ErrInvalidPurchaseOrder,
+		},
+		{
+			name:    "line must exist",
+			request: inventory.ReceiptRequest{RequestID: "rcpt1", LineNumber: 3, Quantity: 4},
+			state:   inventory.PurchaseOrderOpen,
+			wantErr: inventory.ErrInvalidPurchaseOrder,
+		},
+		{
+			name:    "request id is required",
+			request: inventory.ReceiptRequest{LineNumber: 1, Quantity: 4},
+			state:   inventory.PurchaseOrderOpen,
+			wantErr: inventory.ErrInvalidPurchaseOrder,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetProductionEventByRequestIDFunc = func(ctx context.Context, requestID string, options ...core.QueryOptions) (inventory.ProductionEvent, error) {
+				if test.duplicate {
+					return inventory.ProductionEvent{RequestID: requestID}, nil
+				}
+				return inventory.ProductionEvent{}, core.ErrNotFound
+			}
+			mockRepo.GetPurchaseOrderFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.PurchaseOrder, error) {
+				return inventory.PurchaseOrder{ID: ID, Number: "PO-1", State: test.state, Lines: []inventory.PurchaseOrderLine{
+					{LineNumber: 1, Sku: "sku1", Quantity: 10, Variance: -10, UnitCost: 2.5},
+					{LineNumber: 2, Sku: "sku2", Quantity: 5, Received: 5},
+				}}, nil
+			}
+			mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+				return inventory.ProductInventory{Product: inventory.Product{Sku: sku}}, nil
+			}
+			var gotEvent inventory.ProductionEvent
+			mockRepo.SaveProductionEventFunc = func(ctx context.Context, event *inventory.ProductionEvent, options ...core.UpdateOptions) error {
+				gotEvent = *event
+				return nil
+			}
+			var gotLine inventory.PurchaseOrderLine
+			mockRepo.UpdatePurchaseOrderLineFunc = func(ctx context.Context, ID uint64, line inventory.PurchaseOrderLine, options ...core.UpdateOptions) error {
+				gotLine = line
+				return nil
+			}
+			mockQueue := queue.NewMockQueue()
+
+			service := inventory.NewService(mockRepo, mockQueue)
+			got, err := service.ReceivePurchaseOrder(context.Background(), 9, test.request, "receiver")
+
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SaveProductionEvent", test.wantProduced, t)
+			mockRepo.VerifyCount("SavePurchaseReceipt", test.wantProduced, t)
+			mockRepo.VerifyCount("GetReservations", test.wantProduced, t)
+			if test.wantErr != nil || test.wantProduced == 0 {
+				return
+			}
+
+			if got.State != test.wantState {
+				t.Errorf("state got=%s want=%s", got.State, test.wantState)
+			}
+			if gotLine.Variance != test.wantVariance {
+				t.Errorf("variance got=%d want=%d", gotLine.Variance, test.wantVariance)
+			}
+			if gotEvent.UnitCost != 2.5 || gotEvent.Quantity != test.request.Quantity || gotEvent.Remaining != test.request.Quantity {
+				t.Errorf("production event got=%+v", gotEvent)
+			}
+			mockQueue.VerifyCount("PublishInventory", 1, t)
+		})
+	}
+}
+
+func TestClosePurchaseOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    inventory.PurchaseOrderState
+		receipts []inventory.PurchaseReceipt
+
+		wantState inventory.PurchaseOrderState
+		wantErr   error
+	}{
+		{
+			name:      "partly received order is closed short",
+			state:     inventory.PurchaseOrderPartiallyReceived,
+			receipts:  []inventory.PurchaseReceipt{{RequestID: "rcpt1", LineNumber: 1, Quantity: 4}},
+			wantState: inventory.PurchaseOrderClosed,
+		},
+		{
+			name:      "order with nothing received is cancelled",
+			state:     inventory.PurchaseOrderOpen,
+			wantState: inventory.PurchaseOrderCancelled,
+		},
+		{
+			name:    "order is already closed",
+			state:   inventory.PurchaseOrderClosed,
+			wantErr: inventory.ErrInvalidPurchaseOrder,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetPurchaseOrderFunc = func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.PurchaseOrder, error) {
+				return inventory.PurchaseOrder{ID: ID, Number: "PO-1", State: test.state, Receipts: test.receipts}, nil
+			}
+
+			service := inventory.NewService(mockRepo, queue.NewMockQueue())
+			got, err := service.ClosePurchaseOrder(context.Background(), 9, "buyer")
+
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("error got=%v want=%v", err, test.wantErr)
+			}
+			if test.wantErr != nil {
+				mockRepo.VerifyCount("UpdatePurchaseOrder", 0, t)
+				return
+			}
+			if got.State != test.wantState || got.ClosedBy != "buyer" || got.Closed == nil {
+				t.Errorf("purchase order got=%+v", got)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     inventory.ConversionRequest
+		existing    inventory.Conversion
+		targetType  inventory.ProductType
+		frozen      []string
+		freezes     map[string]inventory.Freezes
+		available   int64
+		sourceLayer inventory.CostLayer
+
+		wantTargetQty int64
+		wantUnitCost  float64
+		wantSave      int
+		wantErr       error
+	}{
+		{
+			name:          "relabel moves stock and cost",
+			request:       inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku2", Quantity: 4, Ratio: 1},
+			available:     10,
+			sourceLayer:   inventory.CostLayer{ID: 1, UnitCost: 2.5, Quantity: 10, Remaining: 10},
+			wantTargetQty: 4,
+			wantUnitCost:  2.5,
+			wantSave:      1,
+		},
+		{
+			name:          "case is broken into eaches",
+			request:       inventory.ConversionRequest{RequestID: "conv1", SourceSku: "case1", TargetSku: "each1", Quantity: 2, Ratio: 12},
+			available:     2,
+			sourceLayer:   inventory.CostLayer{ID: 1, UnitCost: 24, Quantity: 2, Remaining: 2},
+			wantTargetQty: 24,
+			wantUnitCost:  2,
+			wantSave:      1,
+		},
+		{
+			name:     "repeated request returns the original conversion",
+			request:  inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku2", Quantity: 4, Ratio: 1},
+			existing: inventory.Conversion{ID: 3, RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku2", SourceQuantity: 4, TargetQuantity: 4},
+		},
+		{
+			name:      "ratio must give whole units",
+			request:   inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku2", Quantity: 3, Ratio: 0.5},
+			available: 10,
+			wantErr:   inventory.ErrInvalidConversion,
+		},
+		{
+			name:      "source and target must differ",
+			request:   inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku1", Quantity: 3, Ratio: 1},
+			available: 10,
+			wantErr:   inventory.ErrInvalidConversion,
+		},
+		{
+			name:      "reserved stock cannot be converted",
+			request:   inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku2", Quantity: 4, Ratio: 1},
+			available: 3,
+			wantErr:   inventory.ErrInvalidConversion,
+		},
+		{
+			name:       "kits cannot be converted into",
+			request:    inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "kit1", Quantity: 4, Ratio: 1},
+			targetType: inventory.Kit,
+			available:  10,
+			wantErr:    inventory.ErrInvalidConversion,
+		},
+		{
+			name:      "inventory being counted",
+			request:   inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku2", Quantity: 4, Ratio: 1},
+			frozen:    []string{"sku2"},
+			available: 10,
+			wantErr:   inventory.ErrInvalidConversion,
+		},
+		{
+			name:      "source allocation frozen",
+			request:   inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku2", Quantity: 4, Ratio: 1},
+			freezes:   map[string]inventory.Freezes{"sku1": {inventory.FreezeAllocation: {Reason: "incident 42"}}},
+			available: 10,
+			wantErr:   inventory.ErrFrozen,
+		},
+		{
+			name:      "target intake frozen",
+			request:   inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku2", Quantity: 4, Ratio: 1},
+			freezes:   map[string]inventory.Freezes{"sku2": {inventory.FreezeIntake: {Reason: "incident 42"}}},
+			available: 10,
+			wantErr:   inventory.ErrFrozen,
+		},
+		{
+			name:          "target allocation frozen",
+			request:       inventory.ConversionRequest{RequestID: "conv1", SourceSku: "sku1", TargetSku: "sku2", Quantity: 4, Ratio: 1},
+			freezes:       map[string]inventory.Freezes{"sku2": {inventory.FreezeAllocation: {Reason: "incident 42"}}},
+			available:     10,
+			sourceLayer:   inventory.CostLayer{ID: 1, UnitCost: 2.5, Quantity: 10, Remaining: 10},
+			wantTargetQty: 4,
+			wantUnitCost:  2.5,
+			wantSave:      1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetConversionByRequestIDFunc = func(ctx context.Context, requestID string, options ...core.QueryOptions) (inventory.Conversion, error) {
+				if test.existing.RequestID == "" {
+					return inventory.Conversion{}, core.ErrNotFound
+				}
+				return test.existing, nil
+			}
+			mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+				if sku == test.request.TargetSku {
+					return inventory.Product{Sku: sku, Type: test.targetType, Freezes: test.freezes[sku]}, nil
+				}
+				return inventory.Product{Sku: sku, Freezes: test.freezes[sku]}, nil
+			}
+			mockRepo.GetFrozenSkusFunc = func(ctx context.Context, skus []string, options ...core.QueryOptions) ([]string, error) {
+				return test.frozen, nil
+			}
+			mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+				if sku == test.request.SourceSku {
+					return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: test.available + 5, Available: test.available}, nil
+				}
+				return inventory.ProductInventory{Product: inventory.Product{Sku: sku}}, nil
+			}
+			mockRepo.GetCostLayersFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CostLayer, error) {
+				if sku == test.request.SourceSku {
+					return []inventory.CostLayer{test.sourceLayer}, nil
+				}
+				return []inventory.CostLayer{}, nil
+			}
+			gotInventory := make(map[string]inventory.ProductInventory)
+			mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+				gotInventory[pi.Sku] = pi
+				return nil
+			}
+			var gotLot inventory.ProductionEvent
+			mockRepo.SaveProductionEventFunc = func(ctx context.Context, event *inventory.ProductionEvent, options ...core.UpdateOptions) error {
+				event.ID = 12
+				gotLot = *event
+				return nil
+			}
+			var gotLayer inventory.CostLayer
+			mockRepo.SaveCostLayerFunc = func(ctx context.Context, layer *inventory.CostLayer, options ...core.UpdateOptions) error {
+				gotLayer = *layer
+				return nil
+			}
+			gotAdjustments := make(map[string]int64)
+			mockRepo.SaveAdjustmentFunc = func(ctx context.Context, adjustment *inventory.InventoryAdjustment, options ...core.UpdateOptions) error {
+				if adjustment.Reason != inventory.AdjustmentConversion || adjustment.Reference != test.request.RequestID {
+					t.Errorf("adjustment got=%+v", adjustment)
+				}
+				gotAdjustments[adjustment.Sku] = adjustment.Quantity
+				return nil
+			}
+			mockQueue := queue.NewMockQueue()
+
+			service := inventory.NewService(mockRepo, mockQueue)
+			got, err := service.Convert(context.Background(), test.request, "reworker")
+
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SaveConversion", test.wantSave, t)
+			if test.existing.RequestID != "" && got != test.existing {
+				t.Errorf("conversion got=%+v want=%+v", got, test.existing)
+			}
+			if test.wantErr != nil || test.wantSave == 0 {
+				mockRepo.VerifyCount("SaveProductInventory", 0, t)
+				mockRepo.VerifyCount("SaveProductionEvent", 0, t)
+				mockQueue.VerifyCount("PublishInventory", 0, t)
+				return
+			}
+
+			if got.TargetQuantity != test.wantTargetQty || got.User != "reworker" {
+				t.Errorf("conversion got=%+v", got)
+			}
+			source := gotInventory[test.request.SourceSku]
+			if source.Available != test.available-test.request.Quantity || source.OnHand != test.available+5-test.request.Quantity {
+				t.Errorf("source inventory got=%+v", source)
+			}
+			target := gotInventory[test.request.TargetSku]
+			if target.Available != test.wantTargetQty || target.OnHand != test.wantTargetQty {
+				t.Errorf("target inventory got=%+v", target)
+			}
+			if gotLayer.Sku != test.request.TargetSku || gotLayer.UnitCost != test.wantUnitCost {
+				t.Errorf("target cost layer got=%+v", gotLayer)
+			}
+			if gotLot.Sku != test.request.TargetSku || gotLot.Remaining != test.wantTargetQty || gotLayer.ProductionEventID != gotLot.ID {
+				t.Errorf("target lot got=%+v layer=%+v", gotLot, gotLayer)
+			}
+			if gotAdjustments[test.request.SourceSku] != -test.request.Quantity || gotAdjustments[test.request.TargetSku] != test.wantTargetQty {
+				t.Errorf("adjustments got=%v", gotAdjustments)
+			}
+			mockQueue.VerifyCount("PublishInventory", 2, t)
+			mockRepo.VerifyCount("GetReservations", 1, t)
+		})
+	}
+}
+
+func TestProduceScrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		request inventory.ProductionRequest
+
+		wantAvailable int64
+		wantLayers    int
+		wantErr       bool
+	}{
+		{
+			name:          "only good quantity is added",
+			request:       inventory.ProductionRequest{RequestID: "req1", Quantity: 8, Scrapped: 2, Line: "L1", Shift: "A", Operator: "op1"},
+			wantAvailable: 12,
+			wantLayers:    1,
+		},
+		{
+			name:          "fully scrapped run is recorded",
+			request:       inventory.ProductionRequest{RequestID: "req1", Scrapped: 5, Line: "L1", Shift: "B", Operator: "op2"},
+			wantAvailable: 4,
+		},
+		{
+			name:    "scrapped must not be negative",
+			request: inventory.ProductionRequest{RequestID: "req1", Quantity: 5, Scrapped: -1},
+			wantErr: true,
+		},
+		{
+			name:    "nothing produced",
+			request: inventory.ProductionRequest{RequestID: "req1"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+				return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 4, Available: 4}, nil
+			}
+			var gotEvent inventory.ProductionEvent
+			mockRepo.SaveProductionEventFunc = func(ctx context.Context, event *inventory.ProductionEvent, options ...core.UpdateOptions) error {
+				gotEvent = *event
+				return nil
+			}
+			var got inventory.ProductInventory
+			mockRepo.SaveProductInventoryFunc = func(ctx context.Context, pi inventory.ProductInventory, options ...core.UpdateOptions) error {
+				got = pi
+				return nil
+			}
+
+			service := inventory.NewService(mockRepo, queue.NewMockQueue())
+			err := service.Produce(context.Background(), inventory.Product{Sku: "sku1"}, test.request)
+
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error got=%v wantErr=%v", err, test.wantErr)
+			}
+			if test.wantErr {
+				mockRepo.VerifyCount("SaveProductionEvent", 0, t)
+				return
+			}
+
+			if got.Available != test.wantAvailable || got.OnHand != test.wantAvailable {
+				t.Errorf("inventory got on hand=%d available=%d want %d", got.OnHand, got.Available, test.wantAvailable)
+			}
+			want := inventory.ProductionEvent{
+				RequestID: test.request.RequestID,
+				Sku:       "sku1",
+				Quantity:  test.request.Quantity,
+				Scrapped:  test.request.Scrapped,
+				Remaining: test.request.Quantity,
+				Line:      test.request.Line,
+				Shift:     test.request.Shift,
+				Operator:  test.request.Operator,
+				Created:   gotEvent.Created,
+			}
+			if gotEvent != want {
+				t.Errorf("production event got=%+v want=%+v", gotEvent, want)
+			}
+			mockRepo.VerifyCount("SaveCostLayer", test.wantLayers, t)
+		})
+	}
+}
+
+func TestGetLineYields(t *testing.T) {
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetLineYieldsFunc = func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.LineYield, error) {
+		return []inventory.LineYield{
+			{Line: "L1", Shift: "A", Events: 3, Good: 80, Scrapped: 20},
+			{Line: "L1", Shift: "B", Events: 1, Scrapped: 5},
+			{Line: "L2", Shift: "A", Events: 1},
+		}, nil
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue())
+	yields, err := service.GetLineYields(context.Background(), inventory.ReportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []float64{80, 0, 0}
+	for i, y := range yields {
+		if y.Yield != want[i] {
+			t.Errorf("%s/%s yield got=%f want=%f", y.Line, y.Shift, y.Yield, want[i])
+		}
+	}
+
+	_, err = service.GetLineYields(context.Background(), inventory.ReportOptions{
+		From: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if !errors.Is(err, inventory.ErrInvalidReport) {
+		t.Errorf("error got=%v want=%v", err, inventory.ErrInvalidReport)
+	}
+}
+
+func TestCheckReservationSla(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour)
+	breaches := []inventory.ReservationAlert{
+		{Type: inventory.SlaBreached, ReservationID: 1, Sku: "sku1", State: inventory.Open, Outstanding: 5, Created: created, ThresholdHours: 24},
+		{Type: inventory.SlaBreached, ReservationID: 2, Sku: "sku2", State: inventory.PendingApproval, Outstanding: 8, Created: created, ThresholdHours: 12},
+	}
+
+	tests := []struct {
+		name        string
+		alerted     map[uint64]bool
+		markErr     error
+		publishErr  error
+		wantIDs     []uint64
+		wantPublish int
+		wantErr     bool
+	}{
+		{
+			name:        "every breach is alerted",
+			wantIDs:     []uint64{1, 2},
+			wantPublish: 2,
+		},
+		{
+			name:        "breaches already alerted elsewhere are skipped",
+			alerted:     map[uint64]bool{1: true},
+			wantIDs:     []uint64{2},
+			wantPublish: 1,
+		},
+		{
+			name:        "failure to mark stops the check",
+			markErr:     errors.New("some unexpected error"),
+			wantIDs:     []uint64{},
+			wantPublish: 0,
+			wantErr:     true,
+		},
+		{
+			name:        "failure to publish stops the check",
+			publishErr:  errors.New("some unexpected error"),
+			wantIDs:     []uint64{},
+			wantPublish: 1,
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetSlaBreachesFunc = func(ctx context.Context, defaultHours int64, asOf time.Time, options ...core.QueryOptions) ([]inventory.ReservationAlert, error) {
+				if defaultHours != 72 {
+					t.Errorf("default hours got=%d want=%d", defaultHours, 72)
+				}
+				return breaches, nil
+			}
+			mockRepo.MarkSlaAlertedFunc = func(ctx context.Context, ID uint64, alerted time.Time, options ...core.UpdateOptions) error {
+				if test.markErr != nil {
+					return test.markErr
+				}
+				if test.alerted[ID] {
+					return core.ErrNotFound
+				}
+				return nil
+			}
+			mockQueue := queue.NewMockQueue()
+			mockQueue.PublishReservationAlertFunc = func(ctx context.Context, alert inventory.ReservationAlert) error {
+				if alert.Alerted.IsZero() {
+					t.Errorf("alert for reservation %d published without alerted time", alert.ReservationID)
+				}
+				return test.publishErr
+			}
+
+			service := inventory.NewService(mockRepo, mockQueue, inventory.ReservationSla(72))
+			alerts, err := service.CheckReservationSla(context.Background())
+			if test.wantErr != (err != nil) {
+				t.Fatalf("unexpected error got=%v wantErr=%v", err, test.wantErr)
+			}
+
+			ids := make([]uint64, 0)
+			for _, a := range alerts {
+				ids = append(ids, a.ReservationID)
+			}
+			if !reflect.DeepEqual(ids, test.wantIDs) {
+				t.Errorf("alerted reservations got=%v want=%v", ids, test.wantIDs)
+			}
+			mockQueue.VerifyCount("PublishReservationAlert", test.wantPublish, t)
+		})
+	}
+}
+
+func TestSetReservationSla(t *testing.T) {
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+		return inventory.Product{Sku: sku, ApprovalThreshold: 10}, nil
+	}
+	mockRepo.SaveProductFunc = func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+		if product.ReservationSlaHours != 24 || product.ApprovalThreshold != 10 {
+			t.Errorf("unexpected product saved got=%+v", product)
+		}
+		return nil
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue())
+	product, err := service.SetReservationSla(context.Background(), "sku1", 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.ReservationSlaHours != 24 {
+		t.Errorf("reservation sla got=%d want=%d", product.ReservationSlaHours, 24)
+	}
+
+	_, err = service.SetReservationSla(context.Background(), "sku1", -1)
+	if !errors.Is(err, inventory.ErrInvalidSla) {
+		t.Errorf("error got=%v want=%v", err, inventory.ErrInvalidSla)
+	}
+	mockRepo.VerifyCount("SaveProduct", 1, t)
+}
+
+func TestGetInventoryAging(t *testing.T) {
+	recent := time.Now().AddDate(0, 0, -5)
+	stale := time.Now().AddDate(0, 0, -45)
+
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetAgedLotsFunc = func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.AgedLot, error) {
+		return []inventory.AgedLot{
+			{Sku: "never", Remaining: 10, Received: time.Now().AddDate(0, 0, -100)},
+			{Sku: "stale", Remaining: 20, Received: stale, LastAllocated: &stale},
+			{Sku: "recent", Remaining: 30, Received: recent, LastAllocated: &recent},
+		}, nil
+	}
+
+	tests := []struct {
+		name     string
+		slowDays int
+		wantSlow map[string]bool
+		wantErr  error
+	}{
+		{
+			name:     "skus not allocated within the window are slow moving",
+			slowDays: 30,
+			wantSlow: map[string]bool{"never": true, "stale": true, "recent": false},
+		},
+		{
+			name:     "a longer window only flags skus never allocated",
+			slowDays: 90,
+			wantSlow: map[string]bool{"never": true, "stale": false, "recent": false},
+		},
+		{
+			name:     "window must be at least a day",
+			slowDays: 0,
+			wantErr:  inventory.ErrInvalidReport,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := inventory.NewService(mockRepo, queue.NewMockQueue())
+			aging, err := service.GetInventoryAging(context.Background(), inventory.ReportOptions{}, test.slowDays)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("error got=%v want=%v", err, test.wantErr)
+			}
+			for _, a := range aging {
+				if a.SlowMoving != test.wantSlow[a.Sku] {
+					t.Errorf("%s slow moving got=%v want=%v", a.Sku, a.SlowMoving, test.wantSlow[a.Sku])
+				}
+			}
+		})
+	}
+}
+
+func TestGetInventoryAgingWeightedAverage(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -75)
+	recent := time.Now().AddDate(0, 0, -2)
+
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetAgedLotsFunc = func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.AgedLot, error) {
+		return []inventory.AgedLot{
+			{Sku: "sku1", Remaining: 6, UnitCost: 2.5, Received: old},
+			{Sku: "sku1", Remaining: 4, UnitCost: 2.5, Received: recent},
+		}, nil
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue(), inventory.Costing(inventory.WeightedAverage))
+	aging, err := service.GetInventoryAging(context.Background(), inventory.ReportOptions{}, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []inventory.InventoryAge{
+		{Sku: "sku1", Quantity: 10, Value: 25, UnderThirty: 4, SixtyToNinety: 6, Oldest: old, SlowMoving: true},
+	}
+	if !reflect.DeepEqual(aging, want) {
+		t.Errorf("aging\n got=%+v\nwant=%+v", aging, want)
+	}
+}
+
+func TestCreateCrossReference(t *testing.T) {
+	tests := []struct {
+		name     string
+		xref     inventory.CrossReference
+		existing inventory.CrossReference
+		noProd   bool
+
+		wantSave int
+		wantErr  error
+	}{
+		{
+			name:     "new identifier",
+			xref:     inventory.CrossReference{Sku: "sku1", Identifier: "0123", Type: inventory.IdentifierUpc},
+			wantSave: 1,
+		},
+		{
+			name:     "same identifier for the same sku replaces it",
+			xref:     inventory.CrossReference{Sku: "sku1", Identifier: "0123", Type: inventory.IdentifierUpc},
+			existing: inventory.CrossReference{ID: 3, Sku: "sku1", Identifier: "0123", Type: inventory.IdentifierSupplierPart},
+			wantSave: 1,
+		},
+		{
+			name:     "requester scoped identifier may override an unscoped one",
+			xref:     inventory.CrossReference{Sku: "sku1", Identifier: "P-1", Type: inventory.IdentifierCustomerPart, Requester: "acme"},
+			existing: inventory.CrossReference{ID: 3, Sku: "sku2", Identifier: "P-1", Type: inventory.IdentifierSupplierPart},
+			wantSave: 1,
+		},
+		{
+			name:     "identifier already refers to another sku for the requester",
+			xref:     inventory.CrossReference{Sku: "sku1", Identifier: "P-1", Type: inventory.IdentifierCustomerPart, Requester: "acme"},
+			existing: inventory.CrossReference{ID: 3, Sku: "sku2", Identifier: "P-1", Type: inventory.IdentifierCustomerPart, Requester: "acme"},
+			wantErr:  inventory.ErrInvalidCrossReference,
+		},
+		{
+			name:     "identifier is another product's upc",
+			xref:     inventory.CrossReference{Sku: "sku1", Identifier: "0123", Type: inventory.IdentifierUpc, Requester: "acme"},
+			existing: inventory.CrossReference{Sku: "sku2", Identifier: "0123", Type: inventory.IdentifierUpc},
+			wantErr:  inventory.ErrInvalidCrossReference,
+		},
+		{
+			name:    "identifier is required",
+			xref:    inventory.CrossReference{Sku: "sku1", Type: inventory.IdentifierUpc},
+			wantErr: inventory.ErrInvalidCrossReference,
+		},
+		{
+			name:    "type must be valid",
+			xref:    inventory.CrossReference{Sku: "sku1", Identifier: "0123", Type: inventory.IdentifierSku},
+			wantErr: inventory.ErrInvalidCrossReference,
+		},
+		{
+			name:    "product must exist",
+			xref:    inventory.CrossReference{Sku: "sku1", Identifier: "0123", Type: inventory.IdentifierUpc},
+			noProd:  true,
+			wantErr: inventory.ErrInvalidCrossReference,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+				if test.noProd {
+					return inventory.Product{}, core.ErrNotFound
+				}
+				return inventory.Product{Sku: sku}, nil
+			}
+			mockRepo.ResolveIdentifierFunc = func(ctx context.Context, identifier, requester string, options ...core.QueryOptions) (inventory.CrossReference, error) {
+				if test.existing.Sku == "" {
+					return inventory.CrossReference{}, core.ErrNotFound
+				}
+				return test.existing, nil
+			}
+
+			service := inventory.NewService(mockRepo, queue.NewMockQueue())
+			xref, err := service.CreateCrossReference(context.Background(), test.xref)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("error got=%v want=%v", err, test.wantErr)
+			}
+			if err == nil && xref.Created.IsZero() {
+				t.Errorf("created was not set")
+			}
+			mockRepo.VerifyCount("SaveCrossReference", test.wantSave, t)
+		})
+	}
+}
+
+func TestReserveByCustomerPartNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		request inventory.ReservationRequest
+		wantSku string
+		wantErr error
+	}{
+		{
+			name:    "part number resolves to sku",
+			request: inventory.ReservationRequest{RequestID: "req1", Requester: "acme", CustomerPartNumber: "P-1", Quantity: 1},
+			wantSku: "sku1",
+		},
+		{
+			name:    "sku is used when given",
+			request: inventory.ReservationRequest{RequestID: "req1", Requester: "acme", Sku: "sku2", CustomerPartNumber: "P-1", Quantity: 1},
+			wantSku: "sku2",
+		},
+		{
+			name:    "unknown part number",
+			request: inventory.ReservationRequest{RequestID: "req1", Requester: "acme", CustomerPartNumber: "P-2", Quantity: 1},
+			wantErr: core.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.ResolveIdentifierFunc = func(ctx context.Context, identifier, requester string, options ...core.QueryOptions) (inventory.CrossReference, error) {
+				if identifier == "P-1" && requester == "acme" {
+					return inventory.CrossReference{ID: 1, Sku: "sku1", Identifier: identifier, Type: inventory.IdentifierCustomerPart, Requester: requester}, nil
+				}
+				return inventory.CrossReference{}, core.ErrNotFound
+			}
+			mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+				return inventory.Product{Sku: sku}, nil
+			}
+
+			service := inventory.NewService(mockRepo, queue.NewMockQueue())
+			res, err := service.Reserve(context.Background(), test.request)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("error got=%v want=%v", err, test.wantErr)
+			}
+			if res.Sku != test.wantSku {
+				t.Errorf("sku got=%s want=%s", res.Sku, test.wantSku)
+			}
+		})
+	}
+}
+
+func TestCreateStyle(t *testing.T) {
+	tests := []struct {
+		name   string
+		style  inventory.Style
+		exists bool
+
+		wantSave int
+		wantErr  error
+	}{
+		{
+			name:     "new style",
+			style:    inventory.Style{Code: "TEE", Name: "Tee", Axes: []string{"size", "color"}},
+			wantSave: 1,
+		},
+		{
+			name:    "style already exists",
+			style:   inventory.Style{Code: "TEE", Name: "Tee", Axes: []string{"size"}},
+			exists:  true,
+			wantErr: inventory.ErrInvalidStyle,
+		},
+		{
+			name:    "code is required",
+			style:   inventory.Style{Name: "Tee", Axes: []string{"size"}},
+			wantErr: inventory.ErrInvalidStyle,
+		},
+		{
+			name:    "axes are required",
+			style:   inventory.Style{Code: "TEE", Name: "Tee"},
+			wantErr: inventory.ErrInvalidStyle,
+		},
+		{
+			name:    "axes must be unique",
+			style:   inventory.Style{Code: "TEE", Name: "Tee", Axes: []string{"size", "size"}},
+			wantErr: inventory.ErrInvalidStyle,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetStyleFunc = func(ctx context.Context, code string, options ...core.QueryOptions) (inventory.Style, error) {
+				if test.exists {
+					return test.style, nil
+				}
+				return inventory.Style{}, core.ErrNotFound
+			}
+
+			service := inventory.NewService(mockRepo, queue.NewMockQueue())
+			style, err := service.CreateStyle(context.Background(), test.style)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("error got=%v want=%v", err, test.wantErr)
+			}
+			if err == nil && style.Created.IsZero() {
+				t.Errorf("created was not set")
+			}
+			mockRepo.VerifyCount("SaveStyle", test.wantSave, t)
+		})
+	}
+}
+
+func TestAssignVariant(t *testing.T) {
+	tee := inventory.Style{Code: "TEE", Name: "Tee", Axes: []string{"size", "color"}}
+
+	tests := []struct {
+		name    string
+		sku     string
+		style   string
+		variant inventory.Variant
+		kit     bool
+
+		wantSave int
+		wantErr  error
+	}{
+		{
+			name:     "new variant",
+			sku:      "TEE-M-BLU",
+			style:    "TEE",
+			variant:  inventory.Variant{"size": "M", "color": "blue"},
+			wantSave: 1,
+		},
+		{
+			name:     "product keeps its own variant",
+			sku:      "TEE-M-RED",
+			style:    "TEE",
+			variant:  inventory.Variant{"size": "M", "color": "red"},
+			wantSave: 1,
+		},
+		{
+			name:     "empty style removes the product from its style",
+			sku:      "TEE-M-RED",
+			wantSave: 1,
+		},
+		{
+			name:    "variant taken by another product",
+			sku:     "TEE-M-BLU",
+			style:   "TEE",
+			variant: inventory.Variant{"size": "M", "color": "red"},
+			wantErr: inventory.ErrInvalidStyle,
+		},
+		{
+			name:    "value required for every axis",
+			sku:     "TEE-M-BLU",
+			style:   "TEE",
+			variant: inventory.Variant{"size": "M"},
+			wantErr: inventory.ErrInvalidStyle,
+		},
+		{
+			name:    "no values for other axes",
+			sku:     "TEE-M-BLU",
+			style:   "TEE",
+			variant: inventory.Variant{"size": "M", "color": "blue", "fit": "slim"},
+			wantErr: inventory.ErrInvalidStyle,
+		},
+		{
+			name:    "style must exist",
+			sku:     "TEE-M-BLU",
+			style:   "POLO",
+			variant: inventory.Variant{"size": "M", "color": "blue"},
+			wantErr: inventory.ErrInvalidStyle,
+		},
+		{
+			name:    "kits cannot be variants",
+			sku:     "TEE-M-BLU",
+			style:   "TEE",
+			variant: inventory.Variant{"size": "M", "color": "blue"},
+			kit:     true,
+			wantErr: inventory.ErrInvalidStyle,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetStyleFunc = func(ctx context.Context, code string, options ...core.QueryOptions) (inventory.Style, error) {
+				if code == tee.Code {
+					return tee, nil
+				}
+				return inventory.Style{}, core.ErrNotFound
+			}
+			mockRepo.GetStyleVariantsFunc = func(ctx context.Context, codes []string, attributes inventory.Attributes, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+				return []inventory.ProductInventory{
+					{Product: inventory.Product{Sku: "TEE-M-RED", Style: "TEE", Variant: inventory.Variant{"size": "M", "color": "red"}}},
+				}, nil
+			}
+			mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+				if test.kit {
+					return inventory.Product{Sku: sku, Type: inventory.Kit}, nil
+				}
+				return inventory.Product{Sku: sku, Type: inventory.Standard}, nil
+			}
+			var saved inventory.Product
+			mockRepo.SaveProductFunc = func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+				saved = product
+				return nil
+			}
+
+			service := inventory.NewService(mockRepo, queue.NewMockQueue())
+			_, err := service.AssignVariant(context.Background(), test.sku, test.style, test.variant)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SaveProduct", test.wantSave, t)
+			if err == nil {
+				if saved.Style != test.style {
+					t.Errorf("style got=%s want=%s", saved.Style, test.style)
+				}
+				if !reflect.DeepEqual(saved.Variant, test.variant) {
+					t.Errorf("variant got=%v want=%v", saved.Variant, test.variant)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllStyleInventory(t *testing.T) {
+	mockRepo := invrepo.NewMockRepo()
+	mockRepo.GetStylesFunc = func(ctx context.Context, attributes inventory.Attributes, limit, offset int, options ...core.QueryOptions) ([]inventory.Style, error) {
+		return []inventory.Style{
+			{Code: "POLO", Name: "Polo", Axes: []string{"size"}},
+			{Code: "TEE", Name: "Tee", Axes: []string{"size", "color"}},
+		}, nil
+	}
+	mockRepo.GetStyleVariantsFunc = func(ctx context.Context, codes []string, attributes inventory.Attributes, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+		return []inventory.ProductInventory{
+			{Product: inventory.Product{Sku: "TEE-L-RED", Style: "TEE", Variant: inventory.Variant{"size": "L", "color": "red"}}, OnHand: 5, Available: 3, Reserved: 2},
+			{Product: inventory.Product{Sku: "TEE-M-BLU", Style: "TEE", Variant: inventory.Variant{"size": "M", "color": "blue"}}, OnHand: 4, Available: 4},
+			{Product: inventory.Product{Sku: "TEE-M-RED", Style: "TEE", Variant: inventory.Variant{"size": "M", "color": "red"}}, OnHand: 1, Available: 0, Reserved: 1, OpenDemand: 2},
+		}, nil
+	}
+
+	service := inventory.NewService(mockRepo, queue.NewMockQueue())
+	got, err := service.GetAllStyleInventory(context.Background(), inventory.GetProductInventoryOptions{}, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("styles got=%d want=2", len(got))
+	}
+
+	if len(got[0].Variants) != 0 || got[0].OnHand != 0 {
+		t.Errorf("style without variants got=%+v", got[0])
+	}
+
+	tee := got[1]
+	if len(tee.Variants) != 3 {
+		t.Errorf("variants got=%d want=3", len(tee.Variants))
+	}
+	if tee.OnHand != 10 || tee.Available != 7 || tee.Reserved != 3 || tee.OpenDemand != 2 {
+		t.Errorf("totals got onHand=%d available=%d reserved=%d openDemand=%d", tee.OnHand, tee.Available, tee.Reserved, tee.OpenDemand)
+	}
+	wantValues := map[string][]string{"size": {"L", "M"}, "color": {"red", "blue"}}
+	if !reflect.DeepEqual(tee.Values, wantValues) {
+		t.Errorf("values got=%v want=%v", tee.Values, wantValues)
+	}
+}
+
+func TestSetFreeze(t *testing.T) {
+	existing := inventory.Freezes{inventory.FreezeIntake: {Reason: "recall", User: "admin1"}}
+
+	tests := []struct {
+		name    string
+		freeze  inventory.Freeze
+		frozen  bool
+		reason  string
+		user    string
+		freezes inventory.Freezes
+
+		wantFrozen  bool
+		wantSave    int
+		wantPublish int
+		wantFill    int
+		wantErr     error
+	}{
+		{
+			name:        "activity is frozen",
+			freeze:      inventory.FreezeReservations,
+			frozen:      true,
+			reason:      "incident 42",
+			user:        "admin1",
+			wantFrozen:  true,
+			wantSave:    1,
+			wantPublish: 1,
+		},
+		{
+			name:        "activity is unfrozen",
+			freeze:      inventory.FreezeIntake,
+			reason:      "recall closed",
+			user:        "admin1",
+			freezes:     existing,
+			wantSave:    1,
+			wantPublish: 1,
+		},
+		{
+			name:        "unfreezing allocation fills reserves",
+			freeze:      inventory.FreezeAllocation,
+			reason:      "incident closed",
+			user:        "admin1",
+			freezes:     inventory.Freezes{inventory.FreezeAllocation: {Reason: "incident 42", User: "admin1"}},
+			wantSave:    1,
+			wantPublish: 1,
+			wantFill:    1,
+		},
+		{
+			name:   "unfreezing an activity that is not frozen does nothing",
+			freeze: inventory.FreezeAllocation,
+			reason: "incident closed",
+			user:   "admin1",
+		},
+		{
+			name:    "freeze must be valid",
+			freeze:  "Returns",
+			frozen:  true,
+			reason:  "incident 42",
+			user:    "admin1",
+			wantErr: inventory.ErrInvalidFreeze,
+		},
+		{
+			name:    "reason is required",
+			freeze:  inventory.FreezeReservations,
+			frozen:  true,
+			user:    "admin1",
+			wantErr: inventory.ErrInvalidFreeze,
+		},
+		{
+			name:    "user is required",
+			freeze:  inventory.FreezeReservations,
+			frozen:  true,
+			reason:  "incident 42",
+			wantErr: inventory.ErrInvalidFreeze,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := invrepo.NewMockRepo()
+			mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+				freezes := inventory.Freezes{}
+				for k, v := range test.freezes {
+					freezes[k] = v
+				}
+				return inventory.Product{Sku: sku, Freezes: freezes}, nil
+			}
+			var saved inventory.Product
+			mockRepo.SaveProductFunc = func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error {
+				saved = product
+				return nil
+			}
+			mockQueue := queue.NewMockQueue()
+			var published inventory.FreezeChange
+			mockQueue.PublishFreezeFunc = func(ctx context.Context, change inventory.FreezeChange) error {
+				published = change
+				return nil
+			}
+
+			service := inventory.NewService(mockRepo, mockQueue)
+			_, err := service.SetFreeze(context.Background(), "sku1", test.freeze, test.frozen, test.reason, test.user)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("error got=%v want=%v", err, test.wantErr)
+			}
+			mockRepo.VerifyCount("SaveProduct", test.wantSave, t)
+			mockQueue.VerifyCount("PublishFreeze", test.wantPublish, t)
+			mockRepo.VerifyCount("GetReservations", test.wantFill, t)
+
+			if test.wantSave > 0 {
+				if saved.Frozen(test.freeze) != test.wantFrozen {
+					t.Errorf("frozen got=%v want=%v", saved.Frozen(test.freeze), test.wantFrozen)
+				}
+				if test.wantFrozen && (saved.Freezes[test.freeze].Reason != test.reason || saved.Freezes[test.freeze].User != test.user) {
+					t.Errorf("freeze setting got=%+v", saved.Freezes[test.freeze])
+				}
+				want := inventory.FreezeChange{Sku: "sku1", Freeze: test.freeze, Frozen: test.frozen, Reason: test.reason, User: test.user}
+				published.Changed = time.Time{}
+				if published != want {
+					t.Errorf("published\n got=%+v\nwant=%+v", published, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFrozenProduct(t *testing.T) {
+	frozen := func(freeze inventory.Freeze) inventory.Freezes {
+		return inventory.Freezes{freeze: {Reason: "incident 42", User: "admin1"}}
+	}
+
+	t.Run("reservations are rejected", func(t *testing.T) {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
+			return inventory.Product{Sku: sku, Freezes: frozen(inventory.FreezeReservations)}, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+		_, err := service.Reserve(context.Background(), inventory.ReservationRequest{RequestID: "req1", Requester: "acme", Sku: "sku1", Quantity: 1})
+		if !errors.Is(err, inventory.ErrFrozen) {
+			t.Fatalf("error got=%v want=%v", err, inventory.ErrFrozen)
+		}
+		mockRepo.VerifyCount("SaveReservation", 0, t)
+	})
+
+	t.Run("production intake is rejected", func(t *testing.T) {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			return inventory.ProductInventory{Product: inventory.Product{Sku: sku, Freezes: frozen(inventory.FreezeIntake)}}, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+		err := service.Produce(context.Background(), inventory.Product{Sku: "sku1"}, inventory.ProductionRequest{RequestID: "req1", Quantity: 5})
+		if !errors.Is(err, inventory.ErrFrozen) {
+			t.Fatalf("error got=%v want=%v", err, inventory.ErrFrozen)
+		}
+		mockRepo.VerifyCount("SaveProductInventory", 0, t)
+	})
+
+	t.Run("allocation is skipped", func(t *testing.T) {
+		mockRepo := invrepo.NewMockRepo()
+		mockRepo.GetReservationsFunc = func(ctx context.Context, options inventory.GetReservationsOptions, limit, offset int, queryOptions ...core.QueryOptions) ([]inventory.Reservation, error) {
+			return []inventory.Reservation{{ID: 1, Sku: "sku1", State: inventory.Open, RequestedQuantity: 5}}, nil
+		}
+		mockRepo.GetProductInventoryFunc = func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
+			return inventory.ProductInventory{Product: inventory.Product{Sku: sku}, OnHand: 10, Available: 10}, nil
+		}
+		mockRepo.GetFrozenProductsFunc = func(ctx context.Context, skus []string, freeze inventory.Freeze, options ...core.QueryOptions) ([]string, error) {
+			if freeze != inventory.FreezeAllocation {
+				t.Errorf("freeze got=%s want=%s", freeze, inventory.FreezeAllocation)
+			}
+			return skus, nil
+		}
+
+		service := inventory.NewService(mockRepo, queue.NewMockQueue())
+		if err := service.FillReserves(context.Background(), inventory.Product{Sku: "sku1"}); err != nil {
+			t.Fatal(err)
+		}
+		mockRepo.VerifyCount("UpdateReservation", 0, t)
+		mockRepo.VerifyCount("SaveProductInventory", 0, t)
+	})
+}
@@ -0,0 +1,141 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidSla is returned when a reservation SLA cannot be set as requested.
+var ErrInvalidSla = errors.New("invalid reservation sla")
+
+// SetReservationSla changes how many hours reservations of the product may stay open before an alert is raised, zero
+// defers to the configured default. Reservations already alerted on are not alerted on again.
+func (s *service) SetReservationSla(ctx context.Context, sku string, hours int64) (Product, error) {
+	const funcName = "SetReservationSla"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Int64("hours", hours).Msg("setting reservation sla")
+
+	if hours < 0 {
+		return Product{}, errors.Wrap(ErrInvalidSla, "reservation sla must not be negative")
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	product, err := s.repo.GetProduct(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	product.ReservationSlaHours = hours
+	if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	return product, nil
+}
+
+// CheckReservationSla raises an alert for each open or pending reservation that has waited longer than its SLA and has
+// not been alerted on yet. Each reservation is marked before its alert is published so that it is alerted on at most
+// once, even when several instances check at the same time.
+func (s *service) CheckReservationSla(ctx context.Context) ([]ReservationAlert, error) {
+	const funcName = "CheckReservationSla"
+
+	now := time.Now()
+	breaches, err := s.repo.GetSlaBreaches(ctx, s.reservationSlaHours, now)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	alerts := make([]ReservationAlert, 0, len(breaches))
+	for _, alert := range breaches {
+		if err = s.repo.MarkSlaAlerted(ctx, alert.ReservationID, now); err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				log.Debug().Str("func", funcName).Uint64("id", alert.ReservationID).Msg("reservation already alerted")
+				continue
+			}
+			return alerts, errors.WithStack(err)
+		}
+
+		alert.Alerted = now
+		log.Info().
+			Str("func", funcName).
+			Uint64("id", alert.ReservationID).
+			Str("sku", alert.Sku).
+			Int64("thresholdHours", alert.ThresholdHours).
+			Msg("reservation sla breached")
+
+		if err = s.publishReservationAlert(ctx, alert); err != nil {
+			return alerts, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// MonitorReservationSla checks reservation SLAs every interval until the context is done. An interval of zero or less
+// disables the checks.
+func (s *service) MonitorReservationSla(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		log.Info().Msg("reservation sla checks are disabled")
+		return
+	}
+	log.Info().Dur("interval", interval).Msg("monitoring reservation sla...")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CheckReservationSla(ctx); err != nil {
+				log.Err(err).Msg("failed to check reservation sla")
+			}
+		}
+	}
+}
+
+func (s *service) SubscribeAlerts(ch chan<- ReservationAlert) (id AlertsSubID) {
+	id = AlertsSubID(uuid.NewString())
+	s.alertSubs[id] = ch
+	log.Debug().Interface("clientId", id).Msg("subscribing to reservation alerts")
+	return id
+}
+
+func (s *service) UnsubscribeAlerts(id AlertsSubID) {
+	log.Debug().Interface("clientId", id).Msg("unsubscribing from reservation alerts")
+	close(s.alertSubs[id])
+	delete(s.alertSubs, id)
+}
+
+func (s *service) publishReservationAlert(ctx context.Context, alert ReservationAlert) error {
+	err := s.queue.PublishReservationAlert(ctx, alert)
+	if err != nil {
+		return errors.WithMessage(err, "failed to publish reservation alert to queue")
+	}
+	go s.notifyAlertSubscribers(alert)
+	return nil
+}
+
+func (s *service) notifyAlertSubscribers(alert ReservationAlert) {
+	for id, ch := range s.alertSubs {
+		log.Debug().Interface("clientId", id).Interface("alert", alert).Msg("notifying subscriber of reservation alert")
+		ch <- alert
+	}
+}
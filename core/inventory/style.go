@@ -0,0 +1,276 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidStyle is returned when a style or a product's variant of it cannot be changed as requested.
+var ErrInvalidStyle = errors.New("invalid style")
+
+// CreateStyle creates a style with the axes its variants differ along. Axes cannot be changed once the style exists
+// since every variant must have a value for each of them.
+func (s *service) CreateStyle(ctx context.Context, style Style) (Style, error) {
+	const funcName = "CreateStyle"
+
+	if style.Code == "" {
+		return Style{}, errors.Wrap(ErrInvalidStyle, "code is required")
+	}
+	if style.Name == "" {
+		return Style{}, errors.Wrap(ErrInvalidStyle, "name is required")
+	}
+	if len(style.Axes) == 0 {
+		return Style{}, errors.Wrap(ErrInvalidStyle, "at least one axis is required")
+	}
+	seen := make(map[string]bool, len(style.Axes))
+	for _, axis := range style.Axes {
+		if axis == "" {
+			return Style{}, errors.Wrap(ErrInvalidStyle, "axes must not be blank")
+		}
+		if seen[axis] {
+			return Style{}, errors.Wrapf(ErrInvalidStyle, "axis %s is repeated", axis)
+		}
+		seen[axis] = true
+	}
+
+	_, err := s.repo.GetStyle(ctx, style.Code)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		return Style{}, errors.WithStack(err)
+	}
+	if err == nil {
+		return Style{}, errors.Wrapf(ErrInvalidStyle, "style %s already exists", style.Code)
+	}
+
+	log.Debug().Str("func", funcName).Str("code", style.Code).Strs("axes", style.Axes).Msg("creating style")
+
+	style.Created = time.Now()
+	if err = s.repo.SaveStyle(ctx, style); err != nil {
+		return Style{}, errors.WithStack(err)
+	}
+	return style, nil
+}
+
+func (s *service) GetStyles(ctx context.Context, limit, offset int) ([]Style, error) {
+	const funcName = "GetStyles"
+
+	log.Debug().Str("func", funcName).Int("limit", limit).Int("offset", offset).Msg("getting styles")
+
+	styles, err := s.repo.GetStyles(ctx, nil, limit, offset)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return styles, nil
+}
+
+// GetStyleInventory gets a style with the inventory of each of its variants.
+func (s *service) GetStyleInventory(ctx context.Context, code string) (StyleInventory, error) {
+	const funcName = "GetStyleInventory"
+
+	log.Debug().Str("func", funcName).Str("code", code).Msg("getting style inventory")
+
+	style, err := s.repo.GetStyle(ctx, code)
+	if err != nil {
+		return StyleInventory{}, errors.WithStack(err)
+	}
+
+	variants, err := s.repo.GetStyleVariants(ctx, []string{code}, nil)
+	if err != nil {
+		return StyleInventory{}, errors.WithStack(err)
+	}
+	return newStyleInventory(style, variants), nil
+}
+
+// GetAllStyleInventory pages through styles, each with the inventory of its variants. The options limit the variants
+// returned and styles left without any matching variant are skipped.
+func (s *service) GetAllStyleInventory(ctx context.Context, options GetProductInventoryOptions, limit, offset int) ([]StyleInventory, error) {
+	const funcName = "GetAllStyleInventory"
+
+	log.Debug().Str("func", funcName).Str("style", options.Style).Int("limit", limit).Int("offset", offset).Msg("getting style inventory")
+
+	filter, err := s.typedAttributeFilter(ctx, options.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var styles []Style
+	if options.Style != "" {
+		styles, err = s.getStyle(ctx, options.Style, offset)
+	} else {
+		styles, err = s.repo.GetStyles(ctx, filter, limit, offset)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	inventories := make([]StyleInventory, 0, len(styles))
+	if len(styles) == 0 {
+		return inventories, nil
+	}
+
+	codes := make([]string, len(styles))
+	for i, style := range styles {
+		codes[i] = style.Code
+	}
+	variants, err := s.repo.GetStyleVariants(ctx, codes, filter)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	byStyle := make(map[string][]ProductInventory, len(styles))
+	for _, v := range variants {
+		byStyle[v.Style] = append(byStyle[v.Style], v)
+	}
+	for _, style := range styles {
+		if len(byStyle[style.Code]) == 0 && len(filter) > 0 {
+			continue
+		}
+		inventories = append(inventories, newStyleInventory(style, byStyle[style.Code]))
+	}
+	return inventories, nil
+}
+
+// getStyle gets the single style a listing is limited to as a page of results.
+func (s *service) getStyle(ctx context.Context, code string, offset int) ([]Style, error) {
+	if offset > 0 {
+		return nil, nil
+	}
+	style, err := s.repo.GetStyle(ctx, code)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []Style{style}, nil
+}
+
+// AssignVariant makes the product the variant of a style with the given value along each of its axes. No two variants
+// of a style may share the same values. Assigning an empty style removes the product from its style.
+func (s *service) AssignVariant(ctx context.Context, sku, code string, variant Variant) (Product, error) {
+	const funcName = "AssignVariant"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Str("style", code).Interface("variant", variant).Msg("assigning variant")
+
+	if code == "" {
+		variant = nil
+	} else if err := s.validateVariant(ctx, code, variant); err != nil {
+		return Product{}, err
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+
+	product, err := s.repo.GetProduct(ctx, sku, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	if code != "" && product.Type == Kit {
+		err = errors.Wrapf(ErrInvalidStyle, "%s is a kit, kits cannot be variants", sku)
+		return Product{}, err
+	}
+
+	if code != "" {
+		if err = s.validateVariantUnique(ctx, sku, code, variant, core.QueryOptions{Tx: tx}); err != nil {
+			return Product{}, err
+		}
+	}
+
+	product.Style, product.Variant = code, variant
+	if err = s.repo.SaveProduct(ctx, product, core.UpdateOptions{Tx: tx}); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Product{}, errors.WithStack(err)
+	}
+	return product, nil
+}
+
+// validateVariant checks the style exists and the variant has a value for each of its axes and no others.
+func (s *service) validateVariant(ctx context.Context, code string, variant Variant) error {
+	style, err := s.repo.GetStyle(ctx, code)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return errors.Wrapf(ErrInvalidStyle, "style %s does not exist", code)
+		}
+		return errors.WithStack(err)
+	}
+
+	for _, axis := range style.Axes {
+		if variant[axis] == "" {
+			return errors.Wrapf(ErrInvalidStyle, "a value for %s is required", axis)
+		}
+	}
+	if len(variant) != len(style.Axes) {
+		return errors.Wrapf(ErrInvalidStyle, "style %s only varies by %v", code, style.Axes)
+	}
+	return nil
+}
+
+// validateVariantUnique checks no other product is already the same variant of the style.
+func (s *service) validateVariantUnique(ctx context.Context, sku, code string, variant Variant, options ...core.QueryOptions) error {
+	siblings, err := s.repo.GetStyleVariants(ctx, []string{code}, nil, options...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, sibling := range siblings {
+		if sibling.Sku != sku && sameVariant(sibling.Variant, variant) {
+			return errors.Wrapf(ErrInvalidStyle, "%s is already that variant of %s", sibling.Sku, code)
+		}
+	}
+	return nil
+}
+
+func sameVariant(a, b Variant) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for axis, value := range a {
+		if b[axis] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func newStyleInventory(style Style, variants []ProductInventory) StyleInventory {
+	si := StyleInventory{
+		Style:    style,
+		Values:   make(map[string][]string, len(style.Axes)),
+		Variants: variants,
+	}
+	if si.Variants == nil {
+		si.Variants = []ProductInventory{}
+	}
+
+	seen := make(map[string]map[string]bool, len(style.Axes))
+	for _, axis := range style.Axes {
+		si.Values[axis] = []string{}
+		seen[axis] = make(map[string]bool)
+	}
+	for _, v := range variants {
+		si.OnHand += v.OnHand
+		si.Reserved += v.Reserved
+		si.Available += v.Available
+		si.OpenDemand += v.OpenDemand
+		for _, axis := range style.Axes {
+			value := v.Variant[axis]
+			if !seen[axis][value] {
+				seen[axis][value] = true
+				si.Values[axis] = append(si.Values[axis], value)
+			}
+		}
+	}
+	return si
+}
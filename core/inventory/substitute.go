@@ -0,0 +1,323 @@
+package inventory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sksmith/go-micro-example/core"
+)
+
+// ErrInvalidSubstitute is returned when a substitution rule cannot be created as requested.
+var ErrInvalidSubstitute = errors.New("invalid substitute")
+
+// substitute is a SKU able to stand in for another, using substituteQty of it in place of every qty of the other.
+type substitute struct {
+	sku           string
+	qty           int64
+	substituteQty int64
+}
+
+// substitutesFor lists the SKUs that may fill reservations for the SKU in the order their rules were created.
+func substitutesFor(sku string, rules []SubstituteRule) []substitute {
+	subs := make([]substitute, 0)
+	for _, r := range rules {
+		if r.Sku == sku {
+			subs = append(subs, substitute{sku: r.SubstituteSku, qty: r.Quantity, substituteQty: r.SubstituteQuantity})
+		} else if r.TwoWay && r.SubstituteSku == sku {
+			subs = append(subs, substitute{sku: r.Sku, qty: r.SubstituteQuantity, substituteQty: r.Quantity})
+		}
+	}
+	return subs
+}
+
+// substitutedBy lists the SKUs whose reservations the SKU may fill.
+func substitutedBy(sku string, rules []SubstituteRule) []string {
+	skus := make([]string, 0)
+	for _, r := range rules {
+		if r.SubstituteSku == sku {
+			skus = append(skus, r.Sku)
+		} else if r.TwoWay && r.Sku == sku {
+			skus = append(skus, r.SubstituteSku)
+		}
+	}
+	return skus
+}
+
+// CreateSubstitute allows one product to be substituted for another. Quantities default to one for one. Creating a
+// rule for a pair that already has one replaces its ratio and direction.
+func (s *service) CreateSubstitute(ctx context.Context, rule SubstituteRule) (SubstituteRule, error) {
+	const funcName = "CreateSubstitute"
+
+	if rule.Sku == rule.SubstituteSku {
+		return SubstituteRule{}, errors.Wrap(ErrInvalidSubstitute, "a product cannot substitute for itself")
+	}
+	if rule.Quantity == 0 {
+		rule.Quantity = 1
+	}
+	if rule.SubstituteQuantity == 0 {
+		rule.SubstituteQuantity = 1
+	}
+	if rule.Quantity < 0 || rule.SubstituteQuantity < 0 {
+		return SubstituteRule{}, errors.Wrap(ErrInvalidSubstitute, "quantities must be greater than zero")
+	}
+
+	for _, sku := range []string{rule.Sku, rule.SubstituteSku} {
+		product, err := s.repo.GetProduct(ctx, sku)
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				return SubstituteRule{}, errors.Wrapf(ErrInvalidSubstitute, "product %s does not exist", sku)
+			}
+			return SubstituteRule{}, errors.WithStack(err)
+		}
+		if product.Type == Kit {
+			return SubstituteRule{}, errors.Wrapf(ErrInvalidSubstitute, "%s is a kit, kits cannot be substituted", sku)
+		}
+	}
+
+	log.Debug().Str("func", funcName).Str("sku", rule.Sku).Str("substituteSku", rule.SubstituteSku).Msg("creating substitute")
+
+	rule.Created = time.Now()
+	if err := s.repo.SaveSubstituteRule(ctx, &rule); err != nil {
+		return SubstituteRule{}, errors.WithStack(err)
+	}
+	return rule, nil
+}
+
+// GetSubstitutes returns every rule involving the SKU, including two-way rules where it is the substitute.
+func (s *service) GetSubstitutes(ctx context.Context, sku string) ([]SubstituteRule, error) {
+	const funcName = "GetSubstitutes"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Msg("getting substitutes")
+
+	rules, err := s.repo.GetSubstituteRules(ctx, sku)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return rules, nil
+}
+
+func (s *service) DeleteSubstitute(ctx context.Context, sku string, ID uint64) error {
+	const funcName = "DeleteSubstitute"
+
+	log.Debug().Str("func", funcName).Str("sku", sku).Uint64("id", ID).Msg("deleting substitute")
+
+	if err := s.repo.DeleteSubstituteRule(ctx, sku, ID); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// fillFromSubstitutes fills what the SKU could not from its own stock using its substitutes and, if it has stock left,
+// offers it to the SKUs it substitutes for.
+func (s *service) fillFromSubstitutes(ctx context.Context, sku string, available int64) error {
+	rules, err := s.repo.GetSubstituteRules(ctx, sku)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	if subs := substitutesFor(sku, rules); len(subs) > 0 {
+		if err = s.fillSubstituteReserves(ctx, sku, subs); err != nil {
+			return err
+		}
+	}
+
+	if available == 0 {
+		return nil
+	}
+	for _, dependent := range substitutedBy(sku, rules) {
+		var dependentRules []SubstituteRule
+		dependentRules, err = s.repo.GetSubstituteRules(ctx, dependent)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err = s.fillSubstituteReserves(ctx, dependent, substitutesFor(dependent, dependentRules)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillSubstituteReserves fills the open reservations of the SKU that allow substitutes from the available stock of its
+// substitutes. Each substitute fills in whole multiples of its ratio. Inventories are locked in SKU order so fills
+// running for different SKUs sharing substitutes cannot deadlock.
+func (s *service) fillSubstituteReserves(ctx context.Context, sku string, subs []substitute) error {
+	const funcName = "fillSubstituteReserves"
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	defer func() {
+		if err != nil {
+			rollback(ctx, tx, err)
+		}
+	}()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	openReservations, err := s.repo.GetReservations(ctx, GetReservationsOptions{Sku: sku, State: Open}, 100, 0, core.QueryOptions{Tx: tx, ForUpdate: true})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	pending := make([]Reservation, 0)
+	for _, r := range openReservations {
+		if r.AllowSubstitutes {
+			pending = append(pending, r)
+		}
+	}
+	if len(pending) == 0 {
+		err = tx.Commit(ctx)
+		return errors.WithStack(err)
+	}
+
+	skus := []string{sku}
+	for _, sub := range subs {
+		skus = append(skus, sub.sku)
+	}
+	sort.Strings(skus)
+
+	inventories := make(map[string]ProductInventory)
+	for _, k := range skus {
+		var pi ProductInventory
+		pi, err = s.repo.GetProductInventory(ctx, k, core.QueryOptions{Tx: tx, ForUpdate: true})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		inventories[k] = pi
+	}
+
+	frozenSkus, err := s.frozenSkus(ctx, skus, core.QueryOptions{Tx: tx})
+	if err != nil {
+		return err
+	}
+	frozen := make(map[string]bool)
+	for _, k := range frozenSkus {
+		frozen[k] = true
+	}
+	if frozen[sku] {
+		log.Debug().Str("func", funcName).Str("sku", sku).Msg("allocation is frozen, skipping substitutes")
+		err = tx.Commit(ctx)
+		return errors.WithStack(err)
+	}
+
+	changed := make(map[string]bool)
+	filled := make([]Reservation, 0)
+	for _, reservation := range pending {
+		reserved := reservation.ReservedQuantity
+		for _, sub := range subs {
+			si := inventories[sub.sku]
+			if frozen[sub.sku] || si.Available == 0 {
+				continue
+			}
+
+			chunks := (reservation.RequestedQuantity - reservation.ReservedQuantity) / sub.qty
+			if limit := si.Available / sub.substituteQty; chunks > limit {
+				chunks = limit
+			}
+			if chunks == 0 {
+				continue
+			}
+			qty, subQty := chunks*sub.qty, chunks*sub.substituteQty
+
+			log.Debug().
+				Str("func", funcName).
+				Str("sku", sku).
+				Str("substituteSku", sub.sku).
+				Str("reservation.RequestID", reservation.RequestID).
+				Int64("quantity", subQty).
+				Msg("filling reservation from substitute")
+
+			si.Available -= subQty
+			si.Reserved += subQty
+			inventories[sub.sku] = si
+
+			pi := inventories[sku]
+			pi.OpenDemand -= qty
+			inventories[sku] = pi
+
+			var cost float64
+			cost, err = s.relieveCost(ctx, sub.sku, subQty, ValuationAllocation, reservation.RequestID, tx)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			reservation.CostOfGoods += cost
+			reservation.ReservedQuantity += qty
+
+			if err = s.consumeLots(ctx, sub.sku, subQty, reservation.ID, tx); err != nil {
+				return errors.WithStack(err)
+			}
+
+			fill := ReservationFill{Sku: sub.sku, Quantity: subQty}
+			if err = s.repo.AddReservationFill(ctx, reservation.ID, fill, core.UpdateOptions{Tx: tx}); err != nil {
+				return errors.WithStack(err)
+			}
+			reservation.Fills = append(reservation.Fills, fill)
+			changed[sku], changed[sub.sku] = true, true
+		}
+
+		if reservation.ReservedQuantity == reserved {
+			continue
+		}
+		if reservation.ReservedQuantity == reservation.RequestedQuantity {
+			reservation.State = Closed
+		}
+		var backorder Reservation
+		if backorder, err = s.splitBackorder(ctx, &reservation, tx); err != nil {
+			return err
+		}
+		if err = s.repo.UpdateReservation(ctx, reservation.ID, reservation.State, reservation.ReservedQuantity, core.UpdateOptions{Tx: tx}); err != nil {
+			return errors.WithStack(err)
+		}
+		if err = s.repo.UpdateReservationCost(ctx, reservation.ID, reservation.CostOfGoods, core.UpdateOptions{Tx: tx}); err != nil {
+			return errors.WithStack(err)
+		}
+		filled = append(filled, reservation)
+		if backorder.ID != 0 {
+			filled = append(filled, backorder)
+		}
+	}
+
+	for _, k := range skus {
+		if !changed[k] {
+			continue
+		}
+		if err = s.repo.SaveProductInventory(ctx, inventories[k], core.UpdateOptions{Tx: tx}); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, k := range skus {
+		if !changed[k] {
+			continue
+		}
+		if err = s.publishInventory(ctx, inventories[k]); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	for _, r := range filled {
+		if r.Fills, err = s.repo.GetReservationFills(ctx, r.ID); err != nil {
+			return errors.WithStack(err)
+		}
+		if err = s.publishReservation(ctx, r); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	for _, k := range skus {
+		if k == sku || !changed[k] {
+			continue
+		}
+		if err = s.refreshKits(ctx, k); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
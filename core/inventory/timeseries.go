@@ -0,0 +1,90 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultTimeSeriesPeriod = 24 * time.Hour
+	maxTimeSeriesBuckets    = 1000
+)
+
+// GetInventoryTimeSeries buckets the recorded history of a product's available inventory by interval. The period ends
+// now and covers a day unless the options say otherwise, and buckets are aligned to multiples of the interval. Each
+// bucket opens at the level the previous one closed at, allocations are measured by the growth in reserved inventory.
+func (s *service) GetInventoryTimeSeries(ctx context.Context, options ReportOptions, interval time.Duration) ([]InventoryBucket, error) {
+	const funcName = "GetInventoryTimeSeries"
+
+	if interval < time.Minute {
+		return nil, errors.Wrap(ErrInvalidReport, "interval must be at least a minute")
+	}
+	if options.To.IsZero() {
+		options.To = time.Now()
+	}
+	if options.From.IsZero() {
+		options.From = options.To.Add(-defaultTimeSeriesPeriod)
+	}
+	if err := validateReportOptions(options); err != nil {
+		return nil, err
+	}
+
+	start := options.From.Truncate(interval)
+	count := int((options.To.Sub(start) + interval - 1) / interval)
+	if count > maxTimeSeriesBuckets {
+		return nil, errors.Wrapf(ErrInvalidReport, "no more than %d intervals may be requested", maxTimeSeriesBuckets)
+	}
+
+	log.Debug().
+		Str("func", funcName).
+		Str("sku", options.Sku).
+		Time("from", start).
+		Time("to", options.To).
+		Dur("interval", interval).
+		Msg("getting inventory time series")
+
+	levels, err := s.repo.GetInventoryHistory(ctx, options.Sku, start, options.To)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	events, err := s.repo.GetProductionEvents(ctx, options.Sku, start, options.To)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var level, reserved int64
+	if len(levels) > 0 && levels[0].Created.Before(start) {
+		level, reserved = levels[0].Available, levels[0].Reserved
+		levels = levels[1:]
+	}
+
+	buckets := make([]InventoryBucket, 0, count)
+	for i := 0; i < count; i++ {
+		b := InventoryBucket{Start: start.Add(time.Duration(i) * interval), Min: level, Max: level}
+		b.End = b.Start.Add(interval)
+
+		for len(levels) > 0 && levels[0].Created.Before(b.End) {
+			level = levels[0].Available
+			if level < b.Min {
+				b.Min = level
+			}
+			if level > b.Max {
+				b.Max = level
+			}
+			b.Allocated += levels[0].Reserved - reserved
+			reserved = levels[0].Reserved
+			levels = levels[1:]
+		}
+		for len(events) > 0 && events[0].Created.Before(b.End) {
+			b.Produced += events[0].Quantity
+			events = events[1:]
+		}
+
+		b.Close = level
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
@@ -0,0 +1,58 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+func productAttributes(product inventory.Product) inventory.Attributes {
+	if product.Attributes == nil {
+		return inventory.Attributes{}
+	}
+	return product.Attributes
+}
+
+func (d *dbRepo) GetAttributeDefinitions(ctx context.Context, options ...core.QueryOptions) ([]inventory.AttributeDefinition, error) {
+	m := db.StartMetric("GetAttributeDefinitions")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	definitions := make([]inventory.AttributeDefinition, 0)
+	rows, err := tx.Query(ctx, `SELECT name, type FROM attribute_definitions ORDER BY name `+forUpdate)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		def := inventory.AttributeDefinition{}
+		if err = rows.Scan(&def.Name, &def.Type); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		definitions = append(definitions, def)
+	}
+
+	m.Complete(nil)
+	return definitions, nil
+}
+
+func (d *dbRepo) SaveAttributeDefinition(ctx context.Context, definition inventory.AttributeDefinition, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveAttributeDefinition")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO attribute_definitions (name, type)
+		     VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET type = EXCLUDED.type;`,
+		definition.Name, definition.Type)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
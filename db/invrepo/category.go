@@ -0,0 +1,145 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+const categoryFields = "id, name, COALESCE(parent_id, 0)"
+
+func (d *dbRepo) GetCategory(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Category, error) {
+	m := db.StartMetric("GetCategory")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	c := inventory.Category{}
+	err := tx.QueryRow(ctx, `SELECT `+categoryFields+` FROM categories WHERE id = $1 `+forUpdate, ID).
+		Scan(&c.ID, &c.Name, &c.ParentID)
+	if err != nil {
+		m.Complete(err)
+		if err == pgx.ErrNoRows {
+			return c, errors.WithStack(core.ErrNotFound)
+		}
+		return c, errors.WithStack(err)
+	}
+
+	m.Complete(nil)
+	return c, nil
+}
+
+func (d *dbRepo) GetCategories(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.Category, error) {
+	m := db.StartMetric("GetCategories")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx, `SELECT `+categoryFields+` FROM categories ORDER BY id LIMIT $1 OFFSET $2 `+forUpdate, limit, offset)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	categories, err := scanCategories(rows)
+	m.Complete(err)
+	return categories, err
+}
+
+func (d *dbRepo) GetChildCategories(ctx context.Context, parentID uint64, options ...core.QueryOptions) ([]inventory.Category, error) {
+	m := db.StartMetric("GetChildCategories")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx, `SELECT `+categoryFields+` FROM categories WHERE parent_id = $1 ORDER BY id `+forUpdate, parentID)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	categories, err := scanCategories(rows)
+	m.Complete(err)
+	return categories, err
+}
+
+func scanCategories(rows pgx.Rows) ([]inventory.Category, error) {
+	categories := make([]inventory.Category, 0)
+	for rows.Next() {
+		c := inventory.Category{}
+		if err := rows.Scan(&c.ID, &c.Name, &c.ParentID); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		categories = append(categories, c)
+	}
+	return categories, nil
+}
+
+func (d *dbRepo) GetCategoryInventory(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CategoryInventory, error) {
+	m := db.StartMetric("GetCategoryInventory")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	ci := inventory.CategoryInventory{}
+	err := tx.QueryRow(ctx,
+		`WITH RECURSIVE tree AS (
+		     SELECT id FROM categories WHERE id = $1
+		      UNION ALL
+		     SELECT c.id FROM categories c, tree t WHERE c.parent_id = t.id
+		 )
+		 SELECT COUNT(p.sku),
+		        COALESCE(SUM(CASE WHEN p.type = $2 THEN 0 ELSE pi.on_hand END), 0),
+		        COALESCE(SUM(CASE WHEN p.type = $2 THEN 0 ELSE pi.reserved END), 0),
+		        COALESCE(SUM(CASE WHEN p.type = $2 THEN 0 ELSE pi.available END), 0),
+		        COALESCE(SUM(CASE WHEN p.type = $2 THEN 0 ELSE pi.held END), 0),
+		        COALESCE(SUM(pi.open_demand), 0)
+		   FROM products p, product_inventory pi
+		  WHERE p.sku = pi.sku AND p.category_id IN (SELECT id FROM tree)`,
+		ID, inventory.Kit).
+		Scan(&ci.Products, &ci.OnHand, &ci.Reserved, &ci.Available, &ci.Held, &ci.OpenDemand)
+	m.Complete(err)
+	if err != nil {
+		return ci, errors.WithStack(err)
+	}
+	return ci, nil
+}
+
+func (d *dbRepo) SaveCategory(ctx context.Context, category *inventory.Category, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveCategory")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	if category.ID == 0 {
+		err := tx.QueryRow(ctx, `INSERT INTO categories (name, parent_id) VALUES ($1, NULLIF($2, 0)) RETURNING id;`,
+			category.Name, category.ParentID).Scan(&category.ID)
+		m.Complete(err)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+
+	ct, err := tx.Exec(ctx, `UPDATE categories SET name = $2, parent_id = NULLIF($3, 0) WHERE id = $1;`,
+		category.ID, category.Name, category.ParentID)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
+
+func (d *dbRepo) DeleteCategory(ctx context.Context, ID uint64, options ...core.UpdateOptions) error {
+	m := db.StartMetric("DeleteCategory")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx, `DELETE FROM categories WHERE id = $1;`, ID)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
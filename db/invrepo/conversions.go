@@ -0,0 +1,85 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+const conversionFields = "id, request_id, source_sku, source_quantity, target_sku, target_quantity, ratio, cost, username, created"
+
+func scanConversion(row pgx.Row, c *inventory.Conversion) error {
+	return row.Scan(&c.ID, &c.RequestID, &c.SourceSku, &c.SourceQuantity, &c.TargetSku, &c.TargetQuantity, &c.Ratio,
+		&c.Cost, &c.User, &c.Created)
+}
+
+func (d *dbRepo) GetConversionByRequestID(ctx context.Context, requestID string, options ...core.QueryOptions) (inventory.Conversion, error) {
+	m := db.StartMetric("GetConversionByRequestID")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	c := inventory.Conversion{}
+	err := scanConversion(tx.QueryRow(ctx, `SELECT `+conversionFields+` FROM conversions WHERE request_id = $1 `+forUpdate,
+		requestID), &c)
+	if err != nil {
+		m.Complete(err)
+		if err == pgx.ErrNoRows {
+			return c, errors.WithStack(core.ErrNotFound)
+		}
+		return c, errors.WithStack(err)
+	}
+
+	m.Complete(nil)
+	return c, nil
+}
+
+// GetConversions returns the conversions the SKU was either the source or the target of, newest first.
+func (d *dbRepo) GetConversions(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.Conversion, error) {
+	m := db.StartMetric("GetConversions")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+conversionFields+`
+		   FROM conversions
+		  WHERE source_sku = $1 OR target_sku = $1
+		  ORDER BY created DESC, id DESC LIMIT $2 OFFSET $3 `+forUpdate,
+		sku, limit, offset)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	conversions := make([]inventory.Conversion, 0)
+	for rows.Next() {
+		c := inventory.Conversion{}
+		if err = scanConversion(rows, &c); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		conversions = append(conversions, c)
+	}
+
+	m.Complete(nil)
+	return conversions, nil
+}
+
+func (d *dbRepo) SaveConversion(ctx context.Context, conversion *inventory.Conversion, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveConversion")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	err := tx.QueryRow(ctx,
+		`INSERT INTO conversions (request_id, source_sku, source_quantity, target_sku, target_quantity, ratio, cost, username, created)
+		      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;`,
+		conversion.RequestID, conversion.SourceSku, conversion.SourceQuantity, conversion.TargetSku,
+		conversion.TargetQuantity, conversion.Ratio, conversion.Cost, conversion.User, conversion.Created).
+		Scan(&conversion.ID)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
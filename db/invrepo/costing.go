@@ -0,0 +1,178 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+func (d *dbRepo) GetCostLayers(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CostLayer, error) {
+	m := db.StartMetric("GetCostLayers")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	layers := make([]inventory.CostLayer, 0)
+	rows, err := tx.Query(ctx,
+		`SELECT id, sku, COALESCE(production_event_id, 0), unit_cost, quantity, remaining, created
+		   FROM cost_layers
+		  WHERE sku = $1 AND remaining > 0
+		  ORDER BY created ASC, id ASC `+forUpdate,
+		sku)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		l := inventory.CostLayer{}
+		err = rows.Scan(&l.ID, &l.Sku, &l.ProductionEventID, &l.UnitCost, &l.Quantity, &l.Remaining, &l.Created)
+		if err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		layers = append(layers, l)
+	}
+
+	m.Complete(nil)
+	return layers, nil
+}
+
+func (d *dbRepo) SaveCostLayer(ctx context.Context, layer *inventory.CostLayer, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveCostLayer")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	insert := `INSERT INTO cost_layers (sku, production_event_id, unit_cost, quantity, remaining, created)
+                      VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6) RETURNING id;`
+	err := tx.QueryRow(ctx, insert, layer.Sku, layer.ProductionEventID, layer.UnitCost, layer.Quantity, layer.Remaining, layer.Created).
+		Scan(&layer.ID)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (d *dbRepo) UpdateCostLayer(ctx context.Context, ID uint64, remaining int64, options ...core.UpdateOptions) error {
+	m := db.StartMetric("UpdateCostLayer")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	_, err := tx.Exec(ctx, `UPDATE cost_layers SET remaining = $2 WHERE id = $1;`, ID, remaining)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+const valuationSelect = `SELECT sku,
+                                COALESCE(SUM(remaining), 0),
+                                COALESCE(SUM(remaining * unit_cost) / NULLIF(SUM(remaining), 0), 0),
+                                COALESCE(SUM(remaining * unit_cost), 0)
+                           FROM cost_layers `
+
+func (d *dbRepo) GetValuation(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Valuation, error) {
+	m := db.StartMetric("GetValuation")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	v := inventory.Valuation{Sku: sku}
+	err := tx.QueryRow(ctx, valuationSelect+`WHERE sku = $1 AND remaining > 0 GROUP BY sku`, sku).
+		Scan(&v.Sku, &v.Quantity, &v.UnitCost, &v.Value)
+	if err != nil && err != pgx.ErrNoRows {
+		m.Complete(err)
+		return v, errors.WithStack(err)
+	}
+
+	m.Complete(nil)
+	return v, nil
+}
+
+func (d *dbRepo) GetValuations(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.Valuation, error) {
+	m := db.StartMetric("GetValuations")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	valuations := make([]inventory.Valuation, 0)
+	rows, err := tx.Query(ctx, valuationSelect+`WHERE remaining > 0 GROUP BY sku ORDER BY sku LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		v := inventory.Valuation{}
+		err = rows.Scan(&v.Sku, &v.Quantity, &v.UnitCost, &v.Value)
+		if err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		valuations = append(valuations, v)
+	}
+
+	m.Complete(nil)
+	return valuations, nil
+}
+
+func (d *dbRepo) GetTotalValuation(ctx context.Context, options ...core.QueryOptions) (float64, error) {
+	m := db.StartMetric("GetTotalValuation")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	var total float64
+	err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(remaining * unit_cost), 0) FROM cost_layers WHERE remaining > 0`).Scan(&total)
+	m.Complete(err)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return total, nil
+}
+
+func (d *dbRepo) SaveValuationEntry(ctx context.Context, entry *inventory.ValuationEntry, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveValuationEntry")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	insert := `INSERT INTO valuation_entries (sku, method, reason, reference, quantity, cost, balance_quantity, balance_value, created)
+                      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;`
+	err := tx.QueryRow(ctx, insert, entry.Sku, entry.Method, entry.Reason, entry.Reference, entry.Quantity, entry.Cost,
+		entry.BalanceQuantity, entry.BalanceValue, entry.Created).Scan(&entry.ID)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (d *dbRepo) GetValuationHistory(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.ValuationEntry, error) {
+	m := db.StartMetric("GetValuationHistory")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	entries := make([]inventory.ValuationEntry, 0)
+	rows, err := tx.Query(ctx,
+		`SELECT id, sku, method, reason, reference, quantity, cost, balance_quantity, balance_value, created
+		   FROM valuation_entries
+		  WHERE sku = $1
+		  ORDER BY created DESC, id DESC
+		  LIMIT $2 OFFSET $3`,
+		sku, limit, offset)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := inventory.ValuationEntry{}
+		err = rows.Scan(&e.ID, &e.Sku, &e.Method, &e.Reason, &e.Reference, &e.Quantity, &e.Cost,
+			&e.BalanceQuantity, &e.BalanceValue, &e.Created)
+		if err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		entries = append(entries, e)
+	}
+
+	m.Complete(nil)
+	return entries, nil
+}
@@ -0,0 +1,216 @@
+package invrepo
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+const countSessionFields = "id, state, created, COALESCE(closed_by, ''), closed"
+
+func (d *dbRepo) GetCountSession(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CountSession, error) {
+	m := db.StartMetric("GetCountSession")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	c := inventory.CountSession{}
+	err := tx.QueryRow(ctx, `SELECT `+countSessionFields+` FROM count_sessions WHERE id = $1 `+forUpdate, ID).
+		Scan(&c.ID, &c.State, &c.Created, &c.ClosedBy, &c.Closed)
+	if err != nil {
+		m.Complete(err)
+		if err == pgx.ErrNoRows {
+			return c, errors.WithStack(core.ErrNotFound)
+		}
+		return c, errors.WithStack(err)
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT sku, counted, system_on_hand, variance, counted_at FROM count_lines WHERE session_id = $1 ORDER BY sku `+forUpdate, ID)
+	if err != nil {
+		m.Complete(err)
+		return c, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	c.Lines = make([]inventory.CountLine, 0)
+	for rows.Next() {
+		l := inventory.CountLine{}
+		if err = rows.Scan(&l.Sku, &l.Counted, &l.SystemOnHand, &l.Variance, &l.CountedAt); err != nil {
+			m.Complete(err)
+			return c, errors.WithStack(err)
+		}
+		c.Lines = append(c.Lines, l)
+	}
+
+	m.Complete(nil)
+	return c, nil
+}
+
+func (d *dbRepo) GetCountSessions(ctx context.Context, state inventory.CountState, limit, offset int, options ...core.QueryOptions) ([]inventory.CountSession, error) {
+	m := db.StartMetric("GetCountSessions")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	params := []interface{}{limit, offset}
+	whereClause := ""
+	if state != "" {
+		params = append(params, state)
+		whereClause = " WHERE state = $" + strconv.Itoa(len(params))
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+countSessionFields+` FROM count_sessions`+whereClause+` ORDER BY id DESC LIMIT $1 OFFSET $2 `+forUpdate,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	sessions := make([]inventory.CountSession, 0)
+	for rows.Next() {
+		c := inventory.CountSession{}
+		if err = rows.Scan(&c.ID, &c.State, &c.Created, &c.ClosedBy, &c.Closed); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		sessions = append(sessions, c)
+	}
+
+	m.Complete(nil)
+	return sessions, nil
+}
+
+func (d *dbRepo) GetFrozenSkus(ctx context.Context, skus []string, options ...core.QueryOptions) ([]string, error) {
+	m := db.StartMetric("GetFrozenSkus")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT DISTINCT l.sku
+		   FROM count_lines l, count_sessions s
+		  WHERE l.session_id = s.id AND s.state = $1 AND l.sku = ANY($2)`,
+		inventory.CountOpen, skus)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	frozen := make([]string, 0)
+	for rows.Next() {
+		var sku string
+		if err = rows.Scan(&sku); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		frozen = append(frozen, sku)
+	}
+
+	m.Complete(nil)
+	return frozen, nil
+}
+
+func (d *dbRepo) SaveCountSession(ctx context.Context, session *inventory.CountSession, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveCountSession")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	err := tx.QueryRow(ctx, `INSERT INTO count_sessions (state, created) VALUES ($1, $2) RETURNING id;`,
+		session.State, session.Created).Scan(&session.ID)
+	if err != nil {
+		m.Complete(err)
+		return errors.WithStack(err)
+	}
+
+	for _, line := range session.Lines {
+		_, err = tx.Exec(ctx, `INSERT INTO count_lines (session_id, sku) VALUES ($1, $2);`, session.ID, line.Sku)
+		if err != nil {
+			m.Complete(err)
+			return errors.WithStack(err)
+		}
+	}
+
+	m.Complete(nil)
+	return nil
+}
+
+func (d *dbRepo) UpdateCountSession(ctx context.Context, session inventory.CountSession, options ...core.UpdateOptions) error {
+	m := db.StartMetric("UpdateCountSession")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx, `UPDATE count_sessions SET state = $2, closed_by = NULLIF($3, ''), closed = $4 WHERE id = $1;`,
+		session.ID, session.State, session.ClosedBy, session.Closed)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
+
+func (d *dbRepo) UpdateCountLine(ctx context.Context, sessionID uint64, line inventory.CountLine, options ...core.UpdateOptions) error {
+	m := db.StartMetric("UpdateCountLine")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx,
+		`UPDATE count_lines SET counted = $3, system_on_hand = $4, variance = $5, counted_at = $6 WHERE session_id = $1 AND sku = $2;`,
+		sessionID, line.Sku, line.Counted, line.SystemOnHand, line.Variance, line.CountedAt)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
+
+func (d *dbRepo) SaveAdjustment(ctx context.Context, adjustment *inventory.InventoryAdjustment, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveAdjustment")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	err := tx.QueryRow(ctx,
+		`INSERT INTO inventory_adjustments (sku, quantity, reason, reference, username, created)
+		      VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`,
+		adjustment.Sku, adjustment.Quantity, adjustment.Reason, adjustment.Reference, adjustment.User, adjustment.Created).
+		Scan(&adjustment.ID)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (d *dbRepo) GetAdjustments(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.InventoryAdjustment, error) {
+	m := db.StartMetric("GetAdjustments")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, sku, quantity, reason, COALESCE(reference, ''), username, created
+		   FROM inventory_adjustments
+		  WHERE sku = $1
+		  ORDER BY created DESC, id DESC LIMIT $2 OFFSET $3 `+forUpdate,
+		sku, limit, offset)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	adjustments := make([]inventory.InventoryAdjustment, 0)
+	for rows.Next() {
+		a := inventory.InventoryAdjustment{}
+		if err = rows.Scan(&a.ID, &a.Sku, &a.Quantity, &a.Reason, &a.Reference, &a.User, &a.Created); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		adjustments = append(adjustments, a)
+	}
+
+	m.Complete(nil)
+	return adjustments, nil
+}
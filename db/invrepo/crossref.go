@@ -0,0 +1,115 @@
+package invrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+func (d *dbRepo) GetCrossReferences(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CrossReference, error) {
+	m := db.StartMetric("GetCrossReferences")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, sku, identifier, type, requester, created
+		   FROM cross_references
+		  WHERE sku = $1
+		  ORDER BY id `+forUpdate,
+		sku)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	xrefs := make([]inventory.CrossReference, 0)
+	for rows.Next() {
+		x := inventory.CrossReference{}
+		if err = rows.Scan(&x.ID, &x.Sku, &x.Identifier, &x.Type, &x.Requester, &x.Created); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		xrefs = append(xrefs, x)
+	}
+
+	m.Complete(nil)
+	return xrefs, nil
+}
+
+// ResolveIdentifier finds the SKU an identifier refers to. A SKU refers to itself, then cross references scoped to the
+// requester are preferred over unscoped ones, and a product's own UPC is used last.
+func (d *dbRepo) ResolveIdentifier(ctx context.Context, identifier, requester string, options ...core.QueryOptions) (inventory.CrossReference, error) {
+	m := db.StartMetric("ResolveIdentifier")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	x := inventory.CrossReference{Identifier: identifier}
+	var created *time.Time
+	err := tx.QueryRow(ctx,
+		`SELECT id, sku, type, requester, created
+		   FROM (SELECT 0 AS id, sku, $3::VARCHAR AS type, '' AS requester, NULL::TIMESTAMPTZ AS created, 0 AS rank
+		           FROM products
+		          WHERE sku = $1
+		          UNION ALL
+		         SELECT id, sku, type, requester, created, CASE WHEN requester = '' THEN 2 ELSE 1 END
+		           FROM cross_references
+		          WHERE identifier = $1 AND requester IN ($2::VARCHAR, '')
+		          UNION ALL
+		         SELECT 0, sku, $4::VARCHAR, '', NULL, 3
+		           FROM products
+		          WHERE upc = $1) ids
+		  ORDER BY rank
+		  LIMIT 1`,
+		identifier, requester, inventory.IdentifierSku, inventory.IdentifierUpc).
+		Scan(&x.ID, &x.Sku, &x.Type, &x.Requester, &created)
+	if err != nil {
+		m.Complete(err)
+		if err == pgx.ErrNoRows {
+			return x, errors.WithStack(core.ErrNotFound)
+		}
+		return x, errors.WithStack(err)
+	}
+	if created != nil {
+		x.Created = *created
+	}
+
+	m.Complete(nil)
+	return x, nil
+}
+
+func (d *dbRepo) SaveCrossReference(ctx context.Context, xref *inventory.CrossReference, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveCrossReference")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	err := tx.QueryRow(ctx,
+		`INSERT INTO cross_references (sku, identifier, type, requester, created)
+		      VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (identifier, requester)
+		   DO UPDATE SET sku = $1, type = $3
+		   RETURNING id, created;`,
+		xref.Sku, xref.Identifier, xref.Type, xref.Requester, xref.Created).Scan(&xref.ID, &xref.Created)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (d *dbRepo) DeleteCrossReference(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error {
+	m := db.StartMetric("DeleteCrossReference")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx, `DELETE FROM cross_references WHERE id = $1 AND sku = $2;`, ID, sku)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+func productFreezes(product inventory.Product) inventory.Freezes {
+	if product.Freezes == nil {
+		return inventory.Freezes{}
+	}
+	return product.Freezes
+}
+
+// GetFrozenProducts gets those of the SKUs having the given activity frozen.
+func (d *dbRepo) GetFrozenProducts(ctx context.Context, skus []string, freeze inventory.Freeze, options ...core.QueryOptions) ([]string, error) {
+	m := db.StartMetric("GetFrozenProducts")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx, `SELECT sku FROM products WHERE sku = ANY($1) AND freezes ? $2`, skus, string(freeze))
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	frozen := make([]string, 0)
+	for rows.Next() {
+		var sku string
+		if err = rows.Scan(&sku); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		frozen = append(frozen, sku)
+	}
+
+	m.Complete(nil)
+	return frozen, nil
+}
@@ -0,0 +1,80 @@
+package invrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+// GetInventoryHistory returns the inventory levels of the product recorded in the period, oldest first, preceded by
+// the last level recorded before it so that the level at the start of the period is known.
+func (d *dbRepo) GetInventoryHistory(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.InventoryLevel, error) {
+	m := db.StartMetric("GetInventoryHistory")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT sku, on_hand, available, reserved, held, created FROM (
+		     (SELECT id, sku, on_hand, available, reserved, held, created
+		        FROM inventory_history
+		       WHERE sku = $1 AND created < $2
+		       ORDER BY created DESC, id DESC LIMIT 1)
+		     UNION ALL
+		     (SELECT id, sku, on_hand, available, reserved, held, created
+		        FROM inventory_history
+		       WHERE sku = $1 AND created >= $2 AND created < $3)
+		 ) h ORDER BY created, id`,
+		sku, from, to)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	levels := make([]inventory.InventoryLevel, 0)
+	for rows.Next() {
+		l := inventory.InventoryLevel{}
+		if err = rows.Scan(&l.Sku, &l.OnHand, &l.Available, &l.Reserved, &l.Held, &l.Created); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		levels = append(levels, l)
+	}
+
+	m.Complete(nil)
+	return levels, nil
+}
+
+func (d *dbRepo) GetProductionEvents(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+	m := db.StartMetric("GetProductionEvents")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+productionEventFields+`
+		   FROM production_events
+		  WHERE sku = $1 AND created >= $2 AND created < $3
+		  ORDER BY created, id`,
+		sku, from, to)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	events := make([]inventory.ProductionEvent, 0)
+	for rows.Next() {
+		e := inventory.ProductionEvent{}
+		if err = rows.Scan(&e.ID, &e.RequestID, &e.Sku, &e.Quantity, &e.Scrapped, &e.UnitCost, &e.Remaining, &e.Held,
+			&e.Line, &e.Shift, &e.Operator, &e.Created); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		events = append(events, e)
+	}
+
+	m.Complete(nil)
+	return events, nil
+}
@@ -0,0 +1,97 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+func productType(product inventory.Product) inventory.ProductType {
+	if product.Type == "" {
+		return inventory.Standard
+	}
+	return product.Type
+}
+
+func (d *dbRepo) GetKitComponents(ctx context.Context, kitSku string, options ...core.QueryOptions) ([]inventory.KitComponent, error) {
+	m := db.StartMetric("GetKitComponents")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	components := make([]inventory.KitComponent, 0)
+	rows, err := tx.Query(ctx,
+		`SELECT component_sku, quantity FROM kit_components WHERE kit_sku = $1 ORDER BY component_sku `+forUpdate,
+		kitSku)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c := inventory.KitComponent{}
+		if err = rows.Scan(&c.Sku, &c.Quantity); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		components = append(components, c)
+	}
+
+	m.Complete(nil)
+	return components, nil
+}
+
+func (d *dbRepo) GetKitsContaining(ctx context.Context, componentSku string, options ...core.QueryOptions) ([]inventory.Product, error) {
+	m := db.StartMetric("GetKitsContaining")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	kits := make([]inventory.Product, 0)
+	rows, err := tx.Query(ctx,
+		`SELECT `+productFields+`
+		   FROM products p, kit_components kc
+		  WHERE kc.component_sku = $1 AND p.sku = kc.kit_sku
+		  ORDER BY p.sku `+forUpdate,
+		componentSku)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := inventory.Product{}
+		if err = rows.Scan(productScanFields(&p)...); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		kits = append(kits, p)
+	}
+
+	m.Complete(nil)
+	return kits, nil
+}
+
+func (d *dbRepo) SaveKitComponents(ctx context.Context, kitSku string, components []inventory.KitComponent, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveKitComponents")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	_, err := tx.Exec(ctx, `DELETE FROM kit_components WHERE kit_sku = $1;`, kitSku)
+	if err != nil {
+		m.Complete(err)
+		return errors.WithStack(err)
+	}
+
+	for _, c := range components {
+		_, err = tx.Exec(ctx, `INSERT INTO kit_components (kit_sku, component_sku, quantity) VALUES ($1, $2, $3);`,
+			kitSku, c.Sku, c.Quantity)
+		if err != nil {
+			m.Complete(err)
+			return errors.WithStack(err)
+		}
+	}
+
+	m.Complete(nil)
+	return nil
+}
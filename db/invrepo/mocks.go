@@ -2,6 +2,7 @@ package invrepo
 
 import (
 	"context"
+	"time"
 
 	"github.com/sksmith/go-micro-example/core"
 	"github.com/sksmith/go-micro-example/core/inventory"
@@ -13,19 +14,112 @@ type MockRepo struct {
 	GetProductionEventByRequestIDFunc func(ctx context.Context, requestID string, options ...core.QueryOptions) (pe inventory.ProductionEvent, err error)
 	SaveProductionEventFunc           func(ctx context.Context, event *inventory.ProductionEvent, options ...core.UpdateOptions) error
 
-	GetReservationFunc            func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Reservation, error)
-	GetReservationsFunc           func(ctx context.Context, resOptions inventory.GetReservationsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.Reservation, error)
-	GetReservationByRequestIDFunc func(ctx context.Context, requestId string, options ...core.QueryOptions) (inventory.Reservation, error)
-	UpdateReservationFunc         func(ctx context.Context, ID uint64, state inventory.ReserveState, qty int64, options ...core.UpdateOptions) error
-	SaveReservationFunc           func(ctx context.Context, reservation *inventory.Reservation, options ...core.UpdateOptions) error
+	GetReservationFunc             func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Reservation, error)
+	GetReservationsFunc            func(ctx context.Context, resOptions inventory.GetReservationsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.Reservation, error)
+	GetReservationByRequestIDFunc  func(ctx context.Context, requestId string, options ...core.QueryOptions) (inventory.Reservation, error)
+	UpdateReservationFunc          func(ctx context.Context, ID uint64, state inventory.ReserveState, qty int64, options ...core.UpdateOptions) error
+	SaveReservationFunc            func(ctx context.Context, reservation *inventory.Reservation, options ...core.UpdateOptions) error
+	DecideReservationFunc          func(ctx context.Context, reservation inventory.Reservation, options ...core.UpdateOptions) error
+	UpdateReservationRequestedFunc func(ctx context.Context, ID uint64, qty int64, options ...core.UpdateOptions) error
+	UpdateReservationCostFunc      func(ctx context.Context, ID uint64, costOfGoods float64, options ...core.UpdateOptions) error
+	GetSlaBreachesFunc             func(ctx context.Context, defaultHours int64, asOf time.Time, options ...core.QueryOptions) ([]inventory.ReservationAlert, error)
+	MarkSlaAlertedFunc             func(ctx context.Context, ID uint64, alerted time.Time, options ...core.UpdateOptions) error
 
-	GetProductFunc  func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error)
-	SaveProductFunc func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error
+	GetProductFunc        func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error)
+	SaveProductFunc       func(ctx context.Context, product inventory.Product, options ...core.UpdateOptions) error
+	GetKitComponentsFunc  func(ctx context.Context, kitSku string, options ...core.QueryOptions) ([]inventory.KitComponent, error)
+	GetKitsContainingFunc func(ctx context.Context, componentSku string, options ...core.QueryOptions) ([]inventory.Product, error)
+	GetFrozenProductsFunc func(ctx context.Context, skus []string, freeze inventory.Freeze, options ...core.QueryOptions) ([]string, error)
+	SaveKitComponentsFunc func(ctx context.Context, kitSku string, components []inventory.KitComponent, options ...core.UpdateOptions) error
+
+	GetAttributeDefinitionsFunc func(ctx context.Context, options ...core.QueryOptions) ([]inventory.AttributeDefinition, error)
+	SaveAttributeDefinitionFunc func(ctx context.Context, definition inventory.AttributeDefinition, options ...core.UpdateOptions) error
 
 	GetProductInventoryFunc    func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error)
-	GetAllProductInventoryFunc func(ctx context.Context, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error)
+	GetAllProductInventoryFunc func(ctx context.Context, piOptions inventory.GetProductInventoryOptions, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error)
 	SaveProductInventoryFunc   func(ctx context.Context, productInventory inventory.ProductInventory, options ...core.UpdateOptions) error
 
+	GetCostLayersFunc       func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CostLayer, error)
+	GetValuationFunc        func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Valuation, error)
+	GetValuationsFunc       func(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.Valuation, error)
+	GetTotalValuationFunc   func(ctx context.Context, options ...core.QueryOptions) (float64, error)
+	GetValuationHistoryFunc func(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.ValuationEntry, error)
+	SaveCostLayerFunc       func(ctx context.Context, layer *inventory.CostLayer, options ...core.UpdateOptions) error
+	UpdateCostLayerFunc     func(ctx context.Context, ID uint64, remaining int64, options ...core.UpdateOptions) error
+	SaveValuationEntryFunc  func(ctx context.Context, entry *inventory.ValuationEntry, options ...core.UpdateOptions) error
+
+	GetCategoryFunc          func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Category, error)
+	GetCategoriesFunc        func(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.Category, error)
+	GetChildCategoriesFunc   func(ctx context.Context, parentID uint64, options ...core.QueryOptions) ([]inventory.Category, error)
+	GetCategoryInventoryFunc func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CategoryInventory, error)
+	SaveCategoryFunc         func(ctx context.Context, category *inventory.Category, options ...core.UpdateOptions) error
+	DeleteCategoryFunc       func(ctx context.Context, ID uint64, options ...core.UpdateOptions) error
+
+	GetFillRatesFunc            func(ctx context.Context, group inventory.FillRateGroup, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.FillRate, error)
+	GetTimeToCloseFunc          func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.TimeToClose, error)
+	GetDailyProductionFunc      func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.DailyProduction, error)
+	GetPlannedProductionFunc    func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PlannedProduction, error)
+	SavePlannedProductionFunc   func(ctx context.Context, plan *inventory.PlannedProduction, options ...core.UpdateOptions) error
+	DeletePlannedProductionFunc func(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error
+
+	GetCountSessionFunc    func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CountSession, error)
+	GetCountSessionsFunc   func(ctx context.Context, state inventory.CountState, limit, offset int, options ...core.QueryOptions) ([]inventory.CountSession, error)
+	GetFrozenSkusFunc      func(ctx context.Context, skus []string, options ...core.QueryOptions) ([]string, error)
+	SaveCountSessionFunc   func(ctx context.Context, session *inventory.CountSession, options ...core.UpdateOptions) error
+	UpdateCountSessionFunc func(ctx context.Context, session inventory.CountSession, options ...core.UpdateOptions) error
+	UpdateCountLineFunc    func(ctx context.Context, sessionID uint64, line inventory.CountLine, options ...core.UpdateOptions) error
+	SaveAdjustmentFunc     func(ctx context.Context, adjustment *inventory.InventoryAdjustment, options ...core.UpdateOptions) error
+	GetAdjustmentsFunc     func(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.InventoryAdjustment, error)
+
+	GetSubstituteRulesFunc   func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.SubstituteRule, error)
+	GetReservationFillsFunc  func(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]inventory.ReservationFill, error)
+	SaveSubstituteRuleFunc   func(ctx context.Context, rule *inventory.SubstituteRule, options ...core.UpdateOptions) error
+	DeleteSubstituteRuleFunc func(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error
+	AddReservationFillFunc   func(ctx context.Context, reservationID uint64, fill inventory.ReservationFill, options ...core.UpdateOptions) error
+
+	GetProductionLotsFunc        func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.ProductionEvent, error)
+	GetPegsByProductionEventFunc func(ctx context.Context, requestID string, options ...core.QueryOptions) ([]inventory.Peg, error)
+	GetPegsByReservationFunc     func(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]inventory.Peg, error)
+	UpdateProductionLotFunc      func(ctx context.Context, ID uint64, remaining, held int64, options ...core.UpdateOptions) error
+	SavePegFunc                  func(ctx context.Context, peg inventory.Peg, options ...core.UpdateOptions) error
+
+	GetPurchaseOrderFunc        func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.PurchaseOrder, error)
+	GetPurchaseOrdersFunc       func(ctx context.Context, poOptions inventory.GetPurchaseOrdersOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.PurchaseOrder, error)
+	GetInboundLinesFunc         func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PurchaseOrderLine, error)
+	SavePurchaseOrderFunc       func(ctx context.Context, po *inventory.PurchaseOrder, options ...core.UpdateOptions) error
+	UpdatePurchaseOrderFunc     func(ctx context.Context, po inventory.PurchaseOrder, options ...core.UpdateOptions) error
+	UpdatePurchaseOrderLineFunc func(ctx context.Context, ID uint64, line inventory.PurchaseOrderLine, options ...core.UpdateOptions) error
+	SavePurchaseReceiptFunc     func(ctx context.Context, ID uint64, eventID uint64, receipt inventory.PurchaseReceipt, options ...core.UpdateOptions) error
+
+	GetConversionByRequestIDFunc func(ctx context.Context, requestID string, options ...core.QueryOptions) (inventory.Conversion, error)
+	GetConversionsFunc           func(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.Conversion, error)
+	SaveConversionFunc           func(ctx context.Context, conversion *inventory.Conversion, options ...core.UpdateOptions) error
+
+	GetCrossReferencesFunc   func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CrossReference, error)
+	ResolveIdentifierFunc    func(ctx context.Context, identifier, requester string, options ...core.QueryOptions) (inventory.CrossReference, error)
+	SaveCrossReferenceFunc   func(ctx context.Context, xref *inventory.CrossReference, options ...core.UpdateOptions) error
+	DeleteCrossReferenceFunc func(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error
+
+	GetStyleFunc         func(ctx context.Context, code string, options ...core.QueryOptions) (inventory.Style, error)
+	GetStylesFunc        func(ctx context.Context, attributes inventory.Attributes, limit, offset int, options ...core.QueryOptions) ([]inventory.Style, error)
+	GetStyleVariantsFunc func(ctx context.Context, codes []string, attributes inventory.Attributes, options ...core.QueryOptions) ([]inventory.ProductInventory, error)
+	SaveStyleFunc        func(ctx context.Context, style inventory.Style, options ...core.UpdateOptions) error
+
+	GetInventoryHistoryFunc func(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.InventoryLevel, error)
+	GetProductionEventsFunc func(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.ProductionEvent, error)
+
+	GetReturnFunc           func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.ReturnAuthorization, error)
+	GetReturnsFunc          func(ctx context.Context, rtnOptions inventory.GetReturnsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.ReturnAuthorization, error)
+	GetReturnedQuantityFunc func(ctx context.Context, reservationID uint64, options ...core.QueryOptions) (int64, error)
+	SaveReturnFunc          func(ctx context.Context, rma *inventory.ReturnAuthorization, options ...core.UpdateOptions) error
+	UpdateReturnFunc        func(ctx context.Context, rma inventory.ReturnAuthorization, options ...core.UpdateOptions) error
+
+	GetTopOpenDemandFunc    func(ctx context.Context, reportOptions inventory.ReportOptions, limit int, options ...core.QueryOptions) ([]inventory.SkuDemand, error)
+	GetLineYieldsFunc       func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.LineYield, error)
+	GetLineOutputFunc       func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.LineOutput, error)
+	GetAgedLotsFunc         func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.AgedLot, error)
+	GetReservationAgingFunc func(ctx context.Context, reportOptions inventory.ReportOptions, asOf time.Time, options ...core.QueryOptions) ([]inventory.ReservationAging, error)
+
 	BeginTransactionFunc func(ctx context.Context) (core.Transaction, error)
 
 	*testutil.CallWatcher
@@ -71,6 +165,36 @@ func (r *MockRepo) GetProduct(ctx context.Context, sku string, options ...core.Q
 	return r.GetProductFunc(ctx, sku, options...)
 }
 
+func (r *MockRepo) GetKitComponents(ctx context.Context, kitSku string, options ...core.QueryOptions) ([]inventory.KitComponent, error) {
+	r.AddCall(ctx, kitSku, options)
+	return r.GetKitComponentsFunc(ctx, kitSku, options...)
+}
+
+func (r *MockRepo) GetKitsContaining(ctx context.Context, componentSku string, options ...core.QueryOptions) ([]inventory.Product, error) {
+	r.AddCall(ctx, componentSku, options)
+	return r.GetKitsContainingFunc(ctx, componentSku, options...)
+}
+
+func (r *MockRepo) GetFrozenProducts(ctx context.Context, skus []string, freeze inventory.Freeze, options ...core.QueryOptions) ([]string, error) {
+	r.AddCall(ctx, skus, freeze, options)
+	return r.GetFrozenProductsFunc(ctx, skus, freeze, options...)
+}
+
+func (r *MockRepo) SaveKitComponents(ctx context.Context, kitSku string, components []inventory.KitComponent, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, kitSku, components, options)
+	return r.SaveKitComponentsFunc(ctx, kitSku, components, options...)
+}
+
+func (r *MockRepo) GetAttributeDefinitions(ctx context.Context, options ...core.QueryOptions) ([]inventory.AttributeDefinition, error) {
+	r.AddCall(ctx, options)
+	return r.GetAttributeDefinitionsFunc(ctx, options...)
+}
+
+func (r *MockRepo) SaveAttributeDefinition(ctx context.Context, definition inventory.AttributeDefinition, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, definition, options)
+	return r.SaveAttributeDefinitionFunc(ctx, definition, options...)
+}
+
 func (r *MockRepo) GetProductInventory(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
 	r.AddCall(ctx, sku, options)
 	return r.GetProductInventoryFunc(ctx, sku, options...)
@@ -81,9 +205,9 @@ func (r *MockRepo) SaveProductInventory(ctx context.Context, productInventory in
 	return r.SaveProductInventoryFunc(ctx, productInventory, options...)
 }
 
-func (r *MockRepo) GetAllProductInventory(ctx context.Context, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+func (r *MockRepo) GetAllProductInventory(ctx context.Context, piOptions inventory.GetProductInventoryOptions, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
 	r.AddCall(ctx, limit, offset, options)
-	return r.GetAllProductInventoryFunc(ctx, limit, offset, options...)
+	return r.GetAllProductInventoryFunc(ctx, piOptions, limit, offset, options...)
 }
 
 func (r *MockRepo) BeginTransaction(ctx context.Context) (core.Transaction, error) {
@@ -96,6 +220,371 @@ func (r *MockRepo) GetReservationByRequestID(ctx context.Context, requestId stri
 	return r.GetReservationByRequestIDFunc(ctx, requestId, options...)
 }
 
+func (r *MockRepo) DecideReservation(ctx context.Context, reservation inventory.Reservation, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, reservation, options)
+	return r.DecideReservationFunc(ctx, reservation, options...)
+}
+
+func (r *MockRepo) UpdateReservationRequested(ctx context.Context, ID uint64, qty int64, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, ID, qty, options)
+	return r.UpdateReservationRequestedFunc(ctx, ID, qty, options...)
+}
+
+func (r *MockRepo) UpdateReservationCost(ctx context.Context, ID uint64, costOfGoods float64, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, ID, costOfGoods, options)
+	return r.UpdateReservationCostFunc(ctx, ID, costOfGoods, options...)
+}
+
+func (r *MockRepo) GetSlaBreaches(ctx context.Context, defaultHours int64, asOf time.Time, options ...core.QueryOptions) ([]inventory.ReservationAlert, error) {
+	r.AddCall(ctx, defaultHours, asOf, options)
+	return r.GetSlaBreachesFunc(ctx, defaultHours, asOf, options...)
+}
+
+func (r *MockRepo) MarkSlaAlerted(ctx context.Context, ID uint64, alerted time.Time, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, ID, alerted, options)
+	return r.MarkSlaAlertedFunc(ctx, ID, alerted, options...)
+}
+
+func (r *MockRepo) GetCostLayers(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CostLayer, error) {
+	r.AddCall(ctx, sku, options)
+	return r.GetCostLayersFunc(ctx, sku, options...)
+}
+
+func (r *MockRepo) GetValuation(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Valuation, error) {
+	r.AddCall(ctx, sku, options)
+	return r.GetValuationFunc(ctx, sku, options...)
+}
+
+func (r *MockRepo) GetValuations(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.Valuation, error) {
+	r.AddCall(ctx, limit, offset, options)
+	return r.GetValuationsFunc(ctx, limit, offset, options...)
+}
+
+func (r *MockRepo) GetTotalValuation(ctx context.Context, options ...core.QueryOptions) (float64, error) {
+	r.AddCall(ctx, options)
+	return r.GetTotalValuationFunc(ctx, options...)
+}
+
+func (r *MockRepo) GetValuationHistory(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.ValuationEntry, error) {
+	r.AddCall(ctx, sku, limit, offset, options)
+	return r.GetValuationHistoryFunc(ctx, sku, limit, offset, options...)
+}
+
+func (r *MockRepo) SaveCostLayer(ctx context.Context, layer *inventory.CostLayer, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, layer, options)
+	return r.SaveCostLayerFunc(ctx, layer, options...)
+}
+
+func (r *MockRepo) UpdateCostLayer(ctx context.Context, ID uint64, remaining int64, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, ID, remaining, options)
+	return r.UpdateCostLayerFunc(ctx, ID, remaining, options...)
+}
+
+func (r *MockRepo) SaveValuationEntry(ctx context.Context, entry *inventory.ValuationEntry, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, entry, options)
+	return r.SaveValuationEntryFunc(ctx, entry, options...)
+}
+
+func (r *MockRepo) GetCategory(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Category, error) {
+	r.AddCall(ctx, ID, options)
+	return r.GetCategoryFunc(ctx, ID, options...)
+}
+
+func (r *MockRepo) GetCategories(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.Category, error) {
+	r.AddCall(ctx, limit, offset, options)
+	return r.GetCategoriesFunc(ctx, limit, offset, options...)
+}
+
+func (r *MockRepo) GetChildCategories(ctx context.Context, parentID uint64, options ...core.QueryOptions) ([]inventory.Category, error) {
+	r.AddCall(ctx, parentID, options)
+	return r.GetChildCategoriesFunc(ctx, parentID, options...)
+}
+
+func (r *MockRepo) GetCategoryInventory(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CategoryInventory, error) {
+	r.AddCall(ctx, ID, options)
+	return r.GetCategoryInventoryFunc(ctx, ID, options...)
+}
+
+func (r *MockRepo) SaveCategory(ctx context.Context, category *inventory.Category, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, category, options)
+	return r.SaveCategoryFunc(ctx, category, options...)
+}
+
+func (r *MockRepo) DeleteCategory(ctx context.Context, ID uint64, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, ID, options)
+	return r.DeleteCategoryFunc(ctx, ID, options...)
+}
+
+func (r *MockRepo) GetFillRates(ctx context.Context, group inventory.FillRateGroup, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.FillRate, error) {
+	r.AddCall(ctx, group, reportOptions, options)
+	return r.GetFillRatesFunc(ctx, group, reportOptions, options...)
+}
+
+func (r *MockRepo) GetTimeToClose(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.TimeToClose, error) {
+	r.AddCall(ctx, reportOptions, options)
+	return r.GetTimeToCloseFunc(ctx, reportOptions, options...)
+}
+
+func (r *MockRepo) GetDailyProduction(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.DailyProduction, error) {
+	r.AddCall(ctx, reportOptions, options)
+	return r.GetDailyProductionFunc(ctx, reportOptions, options...)
+}
+
+func (r *MockRepo) GetTopOpenDemand(ctx context.Context, reportOptions inventory.ReportOptions, limit int, options ...core.QueryOptions) ([]inventory.SkuDemand, error) {
+	r.AddCall(ctx, reportOptions, limit, options)
+	return r.GetTopOpenDemandFunc(ctx, reportOptions, limit, options...)
+}
+
+func (r *MockRepo) GetLineYields(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.LineYield, error) {
+	r.AddCall(ctx, reportOptions, options)
+	return r.GetLineYieldsFunc(ctx, reportOptions, options...)
+}
+
+func (r *MockRepo) GetLineOutput(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.LineOutput, error) {
+	r.AddCall(ctx, reportOptions, options)
+	return r.GetLineOutputFunc(ctx, reportOptions, options...)
+}
+
+func (r *MockRepo) GetAgedLots(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.AgedLot, error) {
+	r.AddCall(ctx, reportOptions, options)
+	return r.GetAgedLotsFunc(ctx, reportOptions, options...)
+}
+
+func (r *MockRepo) GetReservationAging(ctx context.Context, reportOptions inventory.ReportOptions, asOf time.Time, options ...core.QueryOptions) ([]inventory.ReservationAging, error) {
+	r.AddCall(ctx, reportOptions, asOf, options)
+	return r.GetReservationAgingFunc(ctx, reportOptions, asOf, options...)
+}
+
+func (r *MockRepo) GetPlannedProduction(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PlannedProduction, error) {
+	r.AddCall(ctx, sku, options)
+	return r.GetPlannedProductionFunc(ctx, sku, options...)
+}
+
+func (r *MockRepo) SavePlannedProduction(ctx context.Context, plan *inventory.PlannedProduction, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, plan, options)
+	return r.SavePlannedProductionFunc(ctx, plan, options...)
+}
+
+func (r *MockRepo) DeletePlannedProduction(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, sku, ID, options)
+	return r.DeletePlannedProductionFunc(ctx, sku, ID, options...)
+}
+
+func (r *MockRepo) GetCountSession(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CountSession, error) {
+	r.AddCall(ctx, ID, options)
+	return r.GetCountSessionFunc(ctx, ID, options...)
+}
+
+func (r *MockRepo) GetCountSessions(ctx context.Context, state inventory.CountState, limit, offset int, options ...core.QueryOptions) ([]inventory.CountSession, error) {
+	r.AddCall(ctx, state, limit, offset, options)
+	return r.GetCountSessionsFunc(ctx, state, limit, offset, options...)
+}
+
+func (r *MockRepo) GetSubstituteRules(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.SubstituteRule, error) {
+	r.AddCall(ctx, sku, options)
+	return r.GetSubstituteRulesFunc(ctx, sku, options...)
+}
+
+func (r *MockRepo) GetReservationFills(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]inventory.ReservationFill, error) {
+	r.AddCall(ctx, reservationID, options)
+	return r.GetReservationFillsFunc(ctx, reservationID, options...)
+}
+
+func (r *MockRepo) SaveSubstituteRule(ctx context.Context, rule *inventory.SubstituteRule, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, rule, options)
+	return r.SaveSubstituteRuleFunc(ctx, rule, options...)
+}
+
+func (r *MockRepo) DeleteSubstituteRule(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, sku, ID, options)
+	return r.DeleteSubstituteRuleFunc(ctx, sku, ID, options...)
+}
+
+func (r *MockRepo) AddReservationFill(ctx context.Context, reservationID uint64, fill inventory.ReservationFill, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, reservationID, fill, options)
+	return r.AddReservationFillFunc(ctx, reservationID, fill, options...)
+}
+
+func (r *MockRepo) GetProductionLots(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+	r.AddCall(ctx, sku, options)
+	return r.GetProductionLotsFunc(ctx, sku, options...)
+}
+
+func (r *MockRepo) GetPegsByProductionEvent(ctx context.Context, requestID string, options ...core.QueryOptions) ([]inventory.Peg, error) {
+	r.AddCall(ctx, requestID, options)
+	return r.GetPegsByProductionEventFunc(ctx, requestID, options...)
+}
+
+func (r *MockRepo) GetPegsByReservation(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]inventory.Peg, error) {
+	r.AddCall(ctx, reservationID, options)
+	return r.GetPegsByReservationFunc(ctx, reservationID, options...)
+}
+
+func (r *MockRepo) UpdateProductionLot(ctx context.Context, ID uint64, remaining, held int64, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, ID, remaining, options)
+	return r.UpdateProductionLotFunc(ctx, ID, remaining, held, options...)
+}
+
+func (r *MockRepo) SavePeg(ctx context.Context, peg inventory.Peg, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, peg, options)
+	return r.SavePegFunc(ctx, peg, options...)
+}
+
+func (r *MockRepo) GetPurchaseOrder(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.PurchaseOrder, error) {
+	r.AddCall(ctx, ID, options)
+	return r.GetPurchaseOrderFunc(ctx, ID, options...)
+}
+
+func (r *MockRepo) GetPurchaseOrders(ctx context.Context, poOptions inventory.GetPurchaseOrdersOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.PurchaseOrder, error) {
+	r.AddCall(ctx, poOptions, limit, offset, options)
+	return r.GetPurchaseOrdersFunc(ctx, poOptions, limit, offset, options...)
+}
+
+func (r *MockRepo) GetInboundLines(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PurchaseOrderLine, error) {
+	r.AddCall(ctx, sku, options)
+	return r.GetInboundLinesFunc(ctx, sku, options...)
+}
+
+func (r *MockRepo) SavePurchaseOrder(ctx context.Context, po *inventory.PurchaseOrder, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, po, options)
+	return r.SavePurchaseOrderFunc(ctx, po, options...)
+}
+
+func (r *MockRepo) UpdatePurchaseOrder(ctx context.Context, po inventory.PurchaseOrder, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, po, options)
+	return r.UpdatePurchaseOrderFunc(ctx, po, options...)
+}
+
+func (r *MockRepo) UpdatePurchaseOrderLine(ctx context.Context, ID uint64, line inventory.PurchaseOrderLine, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, ID, line, options)
+	return r.UpdatePurchaseOrderLineFunc(ctx, ID, line, options...)
+}
+
+func (r *MockRepo) SavePurchaseReceipt(ctx context.Context, ID uint64, eventID uint64, receipt inventory.PurchaseReceipt, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, ID, eventID, receipt, options)
+	return r.SavePurchaseReceiptFunc(ctx, ID, eventID, receipt, options...)
+}
+
+func (r *MockRepo) GetConversionByRequestID(ctx context.Context, requestID string, options ...core.QueryOptions) (inventory.Conversion, error) {
+	r.AddCall(ctx, requestID, options)
+	return r.GetConversionByRequestIDFunc(ctx, requestID, options...)
+}
+
+func (r *MockRepo) GetConversions(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.Conversion, error) {
+	r.AddCall(ctx, sku, limit, offset, options)
+	return r.GetConversionsFunc(ctx, sku, limit, offset, options...)
+}
+
+func (r *MockRepo) SaveConversion(ctx context.Context, conversion *inventory.Conversion, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, conversion, options)
+	return r.SaveConversionFunc(ctx, conversion, options...)
+}
+
+func (r *MockRepo) GetCrossReferences(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CrossReference, error) {
+	r.AddCall(ctx, sku, options)
+	return r.GetCrossReferencesFunc(ctx, sku, options...)
+}
+
+func (r *MockRepo) ResolveIdentifier(ctx context.Context, identifier, requester string, options ...core.QueryOptions) (inventory.CrossReference, error) {
+	r.AddCall(ctx, identifier, requester, options)
+	return r.ResolveIdentifierFunc(ctx, identifier, requester, options...)
+}
+
+func (r *MockRepo) SaveCrossReference(ctx context.Context, xref *inventory.CrossReference, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, xref, options)
+	return r.SaveCrossReferenceFunc(ctx, xref, options...)
+}
+
+func (r *MockRepo) DeleteCrossReference(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, sku, ID, options)
+	return r.DeleteCrossReferenceFunc(ctx, sku, ID, options...)
+}
+
+func (r *MockRepo) GetStyle(ctx context.Context, code string, options ...core.QueryOptions) (inventory.Style, error) {
+	r.AddCall(ctx, code, options)
+	return r.GetStyleFunc(ctx, code, options...)
+}
+
+func (r *MockRepo) GetStyles(ctx context.Context, attributes inventory.Attributes, limit, offset int, options ...core.QueryOptions) ([]inventory.Style, error) {
+	r.AddCall(ctx, attributes, limit, offset, options)
+	return r.GetStylesFunc(ctx, attributes, limit, offset, options...)
+}
+
+func (r *MockRepo) GetStyleVariants(ctx context.Context, codes []string, attributes inventory.Attributes, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+	r.AddCall(ctx, codes, attributes, options)
+	return r.GetStyleVariantsFunc(ctx, codes, attributes, options...)
+}
+
+func (r *MockRepo) SaveStyle(ctx context.Context, style inventory.Style, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, style, options)
+	return r.SaveStyleFunc(ctx, style, options...)
+}
+
+func (r *MockRepo) GetInventoryHistory(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.InventoryLevel, error) {
+	r.AddCall(ctx, sku, from, to, options)
+	return r.GetInventoryHistoryFunc(ctx, sku, from, to, options...)
+}
+
+func (r *MockRepo) GetProductionEvents(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+	r.AddCall(ctx, sku, from, to, options)
+	return r.GetProductionEventsFunc(ctx, sku, from, to, options...)
+}
+
+func (r *MockRepo) GetReturn(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.ReturnAuthorization, error) {
+	r.AddCall(ctx, ID, options)
+	return r.GetReturnFunc(ctx, ID, options...)
+}
+
+func (r *MockRepo) GetReturns(ctx context.Context, rtnOptions inventory.GetReturnsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.ReturnAuthorization, error) {
+	r.AddCall(ctx, rtnOptions, limit, offset, options)
+	return r.GetReturnsFunc(ctx, rtnOptions, limit, offset, options...)
+}
+
+func (r *MockRepo) GetReturnedQuantity(ctx context.Context, reservationID uint64, options ...core.QueryOptions) (int64, error) {
+	r.AddCall(ctx, reservationID, options)
+	return r.GetReturnedQuantityFunc(ctx, reservationID, options...)
+}
+
+func (r *MockRepo) SaveReturn(ctx context.Context, rma *inventory.ReturnAuthorization, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, rma, options)
+	return r.SaveReturnFunc(ctx, rma, options...)
+}
+
+func (r *MockRepo) UpdateReturn(ctx context.Context, rma inventory.ReturnAuthorization, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, rma, options)
+	return r.UpdateReturnFunc(ctx, rma, options...)
+}
+
+func (r *MockRepo) GetFrozenSkus(ctx context.Context, skus []string, options ...core.QueryOptions) ([]string, error) {
+	r.AddCall(ctx, skus, options)
+	return r.GetFrozenSkusFunc(ctx, skus, options...)
+}
+
+func (r *MockRepo) SaveCountSession(ctx context.Context, session *inventory.CountSession, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, session, options)
+	return r.SaveCountSessionFunc(ctx, session, options...)
+}
+
+func (r *MockRepo) UpdateCountSession(ctx context.Context, session inventory.CountSession, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, session, options)
+	return r.UpdateCountSessionFunc(ctx, session, options...)
+}
+
+func (r *MockRepo) UpdateCountLine(ctx context.Context, sessionID uint64, line inventory.CountLine, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, sessionID, line, options)
+	return r.UpdateCountLineFunc(ctx, sessionID, line, options...)
+}
+
+func (r *MockRepo) SaveAdjustment(ctx context.Context, adjustment *inventory.InventoryAdjustment, options ...core.UpdateOptions) error {
+	r.AddCall(ctx, adjustment, options)
+	return r.SaveAdjustmentFunc(ctx, adjustment, options...)
+}
+
+func (r *MockRepo) GetAdjustments(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.InventoryAdjustment, error) {
+	r.AddCall(ctx, sku, limit, offset, options)
+	return r.GetAdjustmentsFunc(ctx, sku, limit, offset, options...)
+}
+
 func NewMockRepo() *MockRepo {
 	return &MockRepo{
 		SaveProductionEventFunc: func(ctx context.Context, event *inventory.ProductionEvent, options ...core.UpdateOptions) error {
@@ -117,7 +606,25 @@ func NewMockRepo() *MockRepo {
 		GetProductFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Product, error) {
 			return inventory.Product{}, nil
 		},
-		GetAllProductInventoryFunc: func(ctx context.Context, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+		GetKitComponentsFunc: func(ctx context.Context, kitSku string, options ...core.QueryOptions) ([]inventory.KitComponent, error) {
+			return nil, nil
+		},
+		GetFrozenProductsFunc: func(ctx context.Context, skus []string, freeze inventory.Freeze, options ...core.QueryOptions) ([]string, error) {
+			return []string{}, nil
+		},
+		GetKitsContainingFunc: func(ctx context.Context, componentSku string, options ...core.QueryOptions) ([]inventory.Product, error) {
+			return nil, nil
+		},
+		SaveKitComponentsFunc: func(ctx context.Context, kitSku string, components []inventory.KitComponent, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetAttributeDefinitionsFunc: func(ctx context.Context, options ...core.QueryOptions) ([]inventory.AttributeDefinition, error) {
+			return nil, nil
+		},
+		SaveAttributeDefinitionFunc: func(ctx context.Context, definition inventory.AttributeDefinition, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetAllProductInventoryFunc: func(ctx context.Context, piOptions inventory.GetProductInventoryOptions, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
 			return nil, nil
 		},
 		BeginTransactionFunc: func(ctx context.Context) (core.Transaction, error) { return db.NewMockTransaction(), nil },
@@ -133,6 +640,217 @@ func NewMockRepo() *MockRepo {
 		SaveProductInventoryFunc: func(ctx context.Context, productInventory inventory.ProductInventory, options ...core.UpdateOptions) error {
 			return nil
 		},
+		DecideReservationFunc: func(ctx context.Context, reservation inventory.Reservation, options ...core.UpdateOptions) error {
+			return nil
+		},
+		UpdateReservationRequestedFunc: func(ctx context.Context, ID uint64, qty int64, options ...core.UpdateOptions) error {
+			return nil
+		},
+		UpdateReservationCostFunc: func(ctx context.Context, ID uint64, costOfGoods float64, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetSlaBreachesFunc: func(ctx context.Context, defaultHours int64, asOf time.Time, options ...core.QueryOptions) ([]inventory.ReservationAlert, error) {
+			return nil, nil
+		},
+		MarkSlaAlertedFunc: func(ctx context.Context, ID uint64, alerted time.Time, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetCostLayersFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CostLayer, error) {
+			return nil, nil
+		},
+		GetValuationFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.Valuation, error) {
+			return inventory.Valuation{}, nil
+		},
+		GetValuationsFunc: func(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.Valuation, error) {
+			return nil, nil
+		},
+		GetTotalValuationFunc: func(ctx context.Context, options ...core.QueryOptions) (float64, error) { return 0, nil },
+		GetValuationHistoryFunc: func(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.ValuationEntry, error) {
+			return nil, nil
+		},
+		SaveCostLayerFunc:   func(ctx context.Context, layer *inventory.CostLayer, options ...core.UpdateOptions) error { return nil },
+		UpdateCostLayerFunc: func(ctx context.Context, ID uint64, remaining int64, options ...core.UpdateOptions) error { return nil },
+		SaveValuationEntryFunc: func(ctx context.Context, entry *inventory.ValuationEntry, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetCategoryFunc: func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.Category, error) {
+			return inventory.Category{ID: ID}, nil
+		},
+		GetCategoriesFunc: func(ctx context.Context, limit, offset int, options ...core.QueryOptions) ([]inventory.Category, error) {
+			return nil, nil
+		},
+		GetChildCategoriesFunc: func(ctx context.Context, parentID uint64, options ...core.QueryOptions) ([]inventory.Category, error) {
+			return nil, nil
+		},
+		GetCategoryInventoryFunc: func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CategoryInventory, error) {
+			return inventory.CategoryInventory{}, nil
+		},
+		SaveCategoryFunc: func(ctx context.Context, category *inventory.Category, options ...core.UpdateOptions) error {
+			return nil
+		},
+		DeleteCategoryFunc: func(ctx context.Context, ID uint64, options ...core.UpdateOptions) error { return nil },
+		GetFillRatesFunc: func(ctx context.Context, group inventory.FillRateGroup, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.FillRate, error) {
+			return []inventory.FillRate{}, nil
+		},
+		GetTimeToCloseFunc: func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.TimeToClose, error) {
+			return []inventory.TimeToClose{}, nil
+		},
+		GetDailyProductionFunc: func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.DailyProduction, error) {
+			return []inventory.DailyProduction{}, nil
+		},
+		GetTopOpenDemandFunc: func(ctx context.Context, reportOptions inventory.ReportOptions, limit int, options ...core.QueryOptions) ([]inventory.SkuDemand, error) {
+			return []inventory.SkuDemand{}, nil
+		},
+		GetLineYieldsFunc: func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.LineYield, error) {
+			return []inventory.LineYield{}, nil
+		},
+		GetAgedLotsFunc: func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.AgedLot, error) {
+			return nil, nil
+		},
+		GetReservationAgingFunc: func(ctx context.Context, reportOptions inventory.ReportOptions, asOf time.Time, options ...core.QueryOptions) ([]inventory.ReservationAging, error) {
+			return nil, nil
+		},
+		GetLineOutputFunc: func(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.LineOutput, error) {
+			return []inventory.LineOutput{}, nil
+		},
+		GetPlannedProductionFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PlannedProduction, error) {
+			return []inventory.PlannedProduction{}, nil
+		},
+		SavePlannedProductionFunc: func(ctx context.Context, plan *inventory.PlannedProduction, options ...core.UpdateOptions) error {
+			return nil
+		},
+		DeletePlannedProductionFunc: func(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetCountSessionFunc: func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.CountSession, error) {
+			return inventory.CountSession{ID: ID, State: inventory.CountOpen}, nil
+		},
+		GetCountSessionsFunc: func(ctx context.Context, state inventory.CountState, limit, offset int, options ...core.QueryOptions) ([]inventory.CountSession, error) {
+			return []inventory.CountSession{}, nil
+		},
+		GetSubstituteRulesFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.SubstituteRule, error) {
+			return []inventory.SubstituteRule{}, nil
+		},
+		GetReservationFillsFunc: func(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]inventory.ReservationFill, error) {
+			return nil, nil
+		},
+		SaveSubstituteRuleFunc: func(ctx context.Context, rule *inventory.SubstituteRule, options ...core.UpdateOptions) error {
+			return nil
+		},
+		DeleteSubstituteRuleFunc: func(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error {
+			return nil
+		},
+		AddReservationFillFunc: func(ctx context.Context, reservationID uint64, fill inventory.ReservationFill, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetProductionLotsFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+			return nil, nil
+		},
+		GetPegsByProductionEventFunc: func(ctx context.Context, requestID string, options ...core.QueryOptions) ([]inventory.Peg, error) {
+			return nil, nil
+		},
+		GetPegsByReservationFunc: func(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]inventory.Peg, error) {
+			return nil, nil
+		},
+		UpdateProductionLotFunc: func(ctx context.Context, ID uint64, remaining, held int64, options ...core.UpdateOptions) error {
+			return nil
+		},
+		SavePegFunc: func(ctx context.Context, peg inventory.Peg, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetPurchaseOrderFunc: func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.PurchaseOrder, error) {
+			return inventory.PurchaseOrder{}, nil
+		},
+		GetPurchaseOrdersFunc: func(ctx context.Context, poOptions inventory.GetPurchaseOrdersOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.PurchaseOrder, error) {
+			return []inventory.PurchaseOrder{}, nil
+		},
+		GetInboundLinesFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PurchaseOrderLine, error) {
+			return []inventory.PurchaseOrderLine{}, nil
+		},
+		SavePurchaseOrderFunc: func(ctx context.Context, po *inventory.PurchaseOrder, options ...core.UpdateOptions) error {
+			return nil
+		},
+		UpdatePurchaseOrderFunc: func(ctx context.Context, po inventory.PurchaseOrder, options ...core.UpdateOptions) error {
+			return nil
+		},
+		UpdatePurchaseOrderLineFunc: func(ctx context.Context, ID uint64, line inventory.PurchaseOrderLine, options ...core.UpdateOptions) error {
+			return nil
+		},
+		SavePurchaseReceiptFunc: func(ctx context.Context, ID uint64, eventID uint64, receipt inventory.PurchaseReceipt, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetConversionByRequestIDFunc: func(ctx context.Context, requestID string, options ...core.QueryOptions) (inventory.Conversion, error) {
+			return inventory.Conversion{}, nil
+		},
+		GetConversionsFunc: func(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.Conversion, error) {
+			return []inventory.Conversion{}, nil
+		},
+		SaveConversionFunc: func(ctx context.Context, conversion *inventory.Conversion, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetCrossReferencesFunc: func(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.CrossReference, error) {
+			return nil, nil
+		},
+		ResolveIdentifierFunc: func(ctx context.Context, identifier, requester string, options ...core.QueryOptions) (inventory.CrossReference, error) {
+			return inventory.CrossReference{}, core.ErrNotFound
+		},
+		SaveCrossReferenceFunc: func(ctx context.Context, xref *inventory.CrossReference, options ...core.UpdateOptions) error {
+			return nil
+		},
+		DeleteCrossReferenceFunc: func(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetStyleFunc: func(ctx context.Context, code string, options ...core.QueryOptions) (inventory.Style, error) {
+			return inventory.Style{}, core.ErrNotFound
+		},
+		GetStylesFunc: func(ctx context.Context, attributes inventory.Attributes, limit, offset int, options ...core.QueryOptions) ([]inventory.Style, error) {
+			return []inventory.Style{}, nil
+		},
+		GetStyleVariantsFunc: func(ctx context.Context, codes []string, attributes inventory.Attributes, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+			return []inventory.ProductInventory{}, nil
+		},
+		SaveStyleFunc: func(ctx context.Context, style inventory.Style, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetInventoryHistoryFunc: func(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.InventoryLevel, error) {
+			return []inventory.InventoryLevel{}, nil
+		},
+		GetProductionEventsFunc: func(ctx context.Context, sku string, from, to time.Time, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+			return []inventory.ProductionEvent{}, nil
+		},
+		GetReturnFunc: func(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.ReturnAuthorization, error) {
+			return inventory.ReturnAuthorization{}, nil
+		},
+		GetReturnsFunc: func(ctx context.Context, rtnOptions inventory.GetReturnsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.ReturnAuthorization, error) {
+			return []inventory.ReturnAuthorization{}, nil
+		},
+		GetReturnedQuantityFunc: func(ctx context.Context, reservationID uint64, options ...core.QueryOptions) (int64, error) {
+			return 0, nil
+		},
+		SaveReturnFunc: func(ctx context.Context, rma *inventory.ReturnAuthorization, options ...core.UpdateOptions) error {
+			return nil
+		},
+		UpdateReturnFunc: func(ctx context.Context, rma inventory.ReturnAuthorization, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetFrozenSkusFunc: func(ctx context.Context, skus []string, options ...core.QueryOptions) ([]string, error) {
+			return []string{}, nil
+		},
+		SaveCountSessionFunc: func(ctx context.Context, session *inventory.CountSession, options ...core.UpdateOptions) error {
+			return nil
+		},
+		UpdateCountSessionFunc: func(ctx context.Context, session inventory.CountSession, options ...core.UpdateOptions) error {
+			return nil
+		},
+		UpdateCountLineFunc: func(ctx context.Context, sessionID uint64, line inventory.CountLine, options ...core.UpdateOptions) error {
+			return nil
+		},
+		SaveAdjustmentFunc: func(ctx context.Context, adjustment *inventory.InventoryAdjustment, options ...core.UpdateOptions) error {
+			return nil
+		},
+		GetAdjustmentsFunc: func(ctx context.Context, sku string, limit, offset int, options ...core.QueryOptions) ([]inventory.InventoryAdjustment, error) {
+			return []inventory.InventoryAdjustment{}, nil
+		},
 		CallWatcher: testutil.NewCallWatcher(),
 	}
 }
@@ -0,0 +1,115 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+// GetProductionLots returns the production events of a SKU that have output remaining, oldest first.
+func (d *dbRepo) GetProductionLots(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.ProductionEvent, error) {
+	m := db.StartMetric("GetProductionLots")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, request_id, sku, quantity, unit_cost, remaining, held, created
+		   FROM production_events
+		  WHERE sku = $1 AND remaining > 0
+		  ORDER BY created, id `+forUpdate,
+		sku)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	lots := make([]inventory.ProductionEvent, 0)
+	for rows.Next() {
+		pe := inventory.ProductionEvent{}
+		if err = rows.Scan(&pe.ID, &pe.RequestID, &pe.Sku, &pe.Quantity, &pe.UnitCost, &pe.Remaining, &pe.Held, &pe.Created); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		lots = append(lots, pe)
+	}
+
+	m.Complete(nil)
+	return lots, nil
+}
+
+func (d *dbRepo) UpdateProductionLot(ctx context.Context, ID uint64, remaining, held int64, options ...core.UpdateOptions) error {
+	m := db.StartMetric("UpdateProductionLot")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx, `UPDATE production_events SET remaining = $2, held = $3 WHERE id = $1;`, ID, remaining, held)
+	if err != nil {
+		m.Complete(err)
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		m.Complete(nil)
+		return errors.WithStack(core.ErrNotFound)
+	}
+	m.Complete(nil)
+	return nil
+}
+
+const pegQuery = `SELECT p.production_event_id, e.request_id, p.reservation_id, r.request_id, r.requester, r.state, p.sku, p.quantity, p.created
+                    FROM pegs p
+                    JOIN production_events e ON e.id = p.production_event_id
+                    JOIN reservations r ON r.id = p.reservation_id `
+
+func (d *dbRepo) GetPegsByProductionEvent(ctx context.Context, requestID string, options ...core.QueryOptions) ([]inventory.Peg, error) {
+	m := db.StartMetric("GetPegsByProductionEvent")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	pegs, err := scanPegs(tx.Query(ctx, pegQuery+`WHERE e.request_id = $1 ORDER BY p.created, p.id `+forUpdate, requestID))
+	m.Complete(err)
+	return pegs, err
+}
+
+func (d *dbRepo) GetPegsByReservation(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]inventory.Peg, error) {
+	m := db.StartMetric("GetPegsByReservation")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	pegs, err := scanPegs(tx.Query(ctx, pegQuery+`WHERE p.reservation_id = $1 ORDER BY p.created, p.id `+forUpdate, reservationID))
+	m.Complete(err)
+	return pegs, err
+}
+
+func scanPegs(rows pgx.Rows, err error) ([]inventory.Peg, error) {
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	pegs := make([]inventory.Peg, 0)
+	for rows.Next() {
+		p := inventory.Peg{}
+		err = rows.Scan(&p.ProductionEventID, &p.ProductionRequestID, &p.ReservationID, &p.ReservationRequestID,
+			&p.Requester, &p.State, &p.Sku, &p.Quantity, &p.Created)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		pegs = append(pegs, p)
+	}
+	return pegs, nil
+}
+
+func (d *dbRepo) SavePeg(ctx context.Context, peg inventory.Peg, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SavePeg")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	_, err := tx.Exec(ctx,
+		`INSERT INTO pegs (production_event_id, reservation_id, sku, quantity, created) VALUES ($1, $2, $3, $4, $5);`,
+		peg.ProductionEventID, peg.ReservationID, peg.Sku, peg.Quantity, peg.Created)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+func (d *dbRepo) GetPlannedProduction(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PlannedProduction, error) {
+	m := db.StartMetric("GetPlannedProduction")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, sku, quantity, due, COALESCE(reference, ''), created
+		   FROM planned_production
+		  WHERE sku = $1
+		  ORDER BY due, id `+forUpdate,
+		sku)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	plans := make([]inventory.PlannedProduction, 0)
+	for rows.Next() {
+		p := inventory.PlannedProduction{}
+		if err = rows.Scan(&p.ID, &p.Sku, &p.Quantity, &p.Due, &p.Reference, &p.Created); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		plans = append(plans, p)
+	}
+
+	m.Complete(nil)
+	return plans, nil
+}
+
+func (d *dbRepo) SavePlannedProduction(ctx context.Context, plan *inventory.PlannedProduction, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SavePlannedProduction")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	err := tx.QueryRow(ctx,
+		`INSERT INTO planned_production (sku, quantity, due, reference, created)
+		      VALUES ($1, $2, $3, NULLIF($4, ''), $5) RETURNING id;`,
+		plan.Sku, plan.Quantity, plan.Due, plan.Reference, plan.Created).Scan(&plan.ID)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (d *dbRepo) DeletePlannedProduction(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error {
+	m := db.StartMetric("DeletePlannedProduction")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx, `DELETE FROM planned_production WHERE id = $1 AND sku = $2;`, ID, sku)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
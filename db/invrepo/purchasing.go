@@ -0,0 +1,231 @@
+package invrepo
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+const purchaseOrderFields = "id, number, supplier, state, created_by, created, COALESCE(closed_by, ''), closed"
+
+const purchaseOrderLineFields = "purchase_order_id, line_number, sku, quantity, received, received - quantity, unit_cost, expected"
+
+func scanPurchaseOrder(row pgx.Row, po *inventory.PurchaseOrder) error {
+	return row.Scan(&po.ID, &po.Number, &po.Supplier, &po.State, &po.CreatedBy, &po.Created, &po.ClosedBy, &po.Closed)
+}
+
+func scanPurchaseOrderLines(rows pgx.Rows) ([]inventory.PurchaseOrderLine, error) {
+	lines := make([]inventory.PurchaseOrderLine, 0)
+	for rows.Next() {
+		l := inventory.PurchaseOrderLine{}
+		if err := rows.Scan(&l.PurchaseOrderID, &l.LineNumber, &l.Sku, &l.Quantity, &l.Received, &l.Variance, &l.UnitCost, &l.Expected); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		lines = append(lines, l)
+	}
+	return lines, nil
+}
+
+func (d *dbRepo) GetPurchaseOrder(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.PurchaseOrder, error) {
+	m := db.StartMetric("GetPurchaseOrder")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	po := inventory.PurchaseOrder{}
+	err := scanPurchaseOrder(tx.QueryRow(ctx, `SELECT `+purchaseOrderFields+` FROM purchase_orders WHERE id = $1 `+forUpdate, ID), &po)
+	if err != nil {
+		m.Complete(err)
+		if err == pgx.ErrNoRows {
+			return po, errors.WithStack(core.ErrNotFound)
+		}
+		return po, errors.WithStack(err)
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+purchaseOrderLineFields+` FROM purchase_order_lines WHERE purchase_order_id = $1 ORDER BY line_number `+forUpdate, ID)
+	if err != nil {
+		m.Complete(err)
+		return po, errors.WithStack(err)
+	}
+	po.Lines, err = scanPurchaseOrderLines(rows)
+	rows.Close()
+	if err != nil {
+		m.Complete(err)
+		return po, err
+	}
+
+	rows, err = tx.Query(ctx,
+		`SELECT e.request_id, r.line_number, e.sku, r.quantity, r.username, r.created
+		   FROM purchase_receipts r, production_events e
+		  WHERE r.production_event_id = e.id AND r.purchase_order_id = $1
+		  ORDER BY r.created, e.id`, ID)
+	if err != nil {
+		m.Complete(err)
+		return po, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	po.Receipts = make([]inventory.PurchaseReceipt, 0)
+	for rows.Next() {
+		r := inventory.PurchaseReceipt{}
+		if err = rows.Scan(&r.RequestID, &r.LineNumber, &r.Sku, &r.Quantity, &r.User, &r.Created); err != nil {
+			m.Complete(err)
+			return po, errors.WithStack(err)
+		}
+		po.Receipts = append(po.Receipts, r)
+	}
+
+	m.Complete(nil)
+	return po, nil
+}
+
+func (d *dbRepo) GetPurchaseOrders(ctx context.Context, poOptions inventory.GetPurchaseOrdersOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.PurchaseOrder, error) {
+	m := db.StartMetric("GetPurchaseOrders")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	params := []interface{}{limit, offset}
+	whereClause := ""
+	addCondition := func(column string, value interface{}) {
+		if whereClause == "" {
+			whereClause = " WHERE"
+		} else {
+			whereClause += " AND"
+		}
+		params = append(params, value)
+		whereClause += " " + column + " = $" + strconv.Itoa(len(params))
+	}
+
+	if poOptions.Number != "" {
+		addCondition("number", poOptions.Number)
+	}
+	if poOptions.Supplier != "" {
+		addCondition("supplier", poOptions.Supplier)
+	}
+	if poOptions.State != "" {
+		addCondition("state", poOptions.State)
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+purchaseOrderFields+` FROM purchase_orders`+whereClause+` ORDER BY id DESC LIMIT $1 OFFSET $2 `+forUpdate,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	orders := make([]inventory.PurchaseOrder, 0)
+	for rows.Next() {
+		po := inventory.PurchaseOrder{}
+		if err = scanPurchaseOrder(rows, &po); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		orders = append(orders, po)
+	}
+
+	m.Complete(nil)
+	return orders, nil
+}
+
+// GetInboundLines returns the lines of the SKU still to be received on purchase orders that are open, in the order
+// they are expected.
+func (d *dbRepo) GetInboundLines(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.PurchaseOrderLine, error) {
+	m := db.StartMetric("GetInboundLines")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT l.purchase_order_id, l.line_number, l.sku, l.quantity, l.received, l.received - l.quantity, l.unit_cost, l.expected
+		   FROM purchase_order_lines l, purchase_orders p
+		  WHERE l.purchase_order_id = p.id AND l.sku = $1 AND l.received < l.quantity AND p.state = ANY($2)
+		  ORDER BY l.expected, l.purchase_order_id, l.line_number`,
+		sku, []string{string(inventory.PurchaseOrderOpen), string(inventory.PurchaseOrderPartiallyReceived)})
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	lines, err := scanPurchaseOrderLines(rows)
+	m.Complete(err)
+	return lines, err
+}
+
+func (d *dbRepo) SavePurchaseOrder(ctx context.Context, po *inventory.PurchaseOrder, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SavePurchaseOrder")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	err := tx.QueryRow(ctx,
+		`INSERT INTO purchase_orders (number, supplier, state, created_by, created) VALUES ($1, $2, $3, $4, $5) RETURNING id;`,
+		po.Number, po.Supplier, po.State, po.CreatedBy, po.Created).Scan(&po.ID)
+	if err != nil {
+		m.Complete(err)
+		return errors.WithStack(err)
+	}
+
+	for i, line := range po.Lines {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO purchase_order_lines (purchase_order_id, line_number, sku, quantity, received, unit_cost, expected)
+			      VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+			po.ID, line.LineNumber, line.Sku, line.Quantity, line.Received, line.UnitCost, line.Expected)
+		if err != nil {
+			m.Complete(err)
+			return errors.WithStack(err)
+		}
+		po.Lines[i].PurchaseOrderID = po.ID
+	}
+
+	m.Complete(nil)
+	return nil
+}
+
+func (d *dbRepo) UpdatePurchaseOrder(ctx context.Context, po inventory.PurchaseOrder, options ...core.UpdateOptions) error {
+	m := db.StartMetric("UpdatePurchaseOrder")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx, `UPDATE purchase_orders SET state = $2, closed_by = NULLIF($3, ''), closed = $4 WHERE id = $1;`,
+		po.ID, po.State, po.ClosedBy, po.Closed)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
+
+func (d *dbRepo) UpdatePurchaseOrderLine(ctx context.Context, ID uint64, line inventory.PurchaseOrderLine, options ...core.UpdateOptions) error {
+	m := db.StartMetric("UpdatePurchaseOrderLine")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx, `UPDATE purchase_order_lines SET received = $3 WHERE purchase_order_id = $1 AND line_number = $2;`,
+		ID, line.LineNumber, line.Received)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
+
+func (d *dbRepo) SavePurchaseReceipt(ctx context.Context, ID uint64, eventID uint64, receipt inventory.PurchaseReceipt, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SavePurchaseReceipt")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	_, err := tx.Exec(ctx,
+		`INSERT INTO purchase_receipts (production_event_id, purchase_order_id, line_number, quantity, username, created)
+		      VALUES ($1, $2, $3, $4, $5, $6);`,
+		eventID, ID, receipt.LineNumber, receipt.Quantity, receipt.User, receipt.Created)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
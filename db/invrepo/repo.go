@@ -3,6 +3,7 @@ package invrepo
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/pkg/errors"
@@ -29,18 +30,23 @@ func (d *dbRepo) SaveProduct(ctx context.Context, product inventory.Product, opt
 
 	ct, err := tx.Exec(ctx, `
 		UPDATE products
-           SET upc = $2, name = $3
+           SET upc = $2, name = $3, type = $4, attributes = $5, category_id = NULLIF($6, 0), requires_inspection = $7,
+               approval_threshold = $8, reservation_sla_hours = $9, style = NULLIF($10, ''), variant = $11,
+               freezes = $12
          WHERE sku = $1;`,
-		product.Sku, product.Upc, product.Name)
+		product.Sku, product.Upc, product.Name, productType(product), productAttributes(product), product.CategoryID, product.RequiresInspection,
+		product.ApprovalThreshold, product.ReservationSlaHours, product.Style, productVariant(product), productFreezes(product))
 	if err != nil {
 		m.Complete(nil)
 		return errors.WithStack(err)
 	}
 	if ct.RowsAffected() == 0 {
 		_, err := tx.Exec(ctx, `
-		INSERT INTO products (sku, upc, name)
-                      VALUES ($1, $2, $3);`,
-			product.Sku, product.Upc, product.Name)
+		INSERT INTO products (sku, upc, name, type, attributes, category_id, requires_inspection, approval_threshold, reservation_sla_hours,
+		                      style, variant, freezes)
+                      VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8, $9, NULLIF($10, ''), $11, $12);`,
+			product.Sku, product.Upc, product.Name, productType(product), productAttributes(product), product.CategoryID, product.RequiresInspection,
+			product.ApprovalThreshold, product.ReservationSlaHours, product.Style, productVariant(product), productFreezes(product))
 		if err != nil {
 			m.Complete(err)
 			return err
@@ -56,23 +62,31 @@ func (d *dbRepo) SaveProductInventory(ctx context.Context, productInventory inve
 
 	ct, err := tx.Exec(ctx, `
 		UPDATE product_inventory
-           SET available = $2
+           SET on_hand = $2, reserved = $3, available = $4, open_demand = $5, held = $6
          WHERE sku = $1;`,
-		productInventory.Sku, productInventory.Available)
+		productInventory.Sku, productInventory.OnHand, productInventory.Reserved, productInventory.Available, productInventory.OpenDemand, productInventory.Held)
 	if err != nil {
 		m.Complete(nil)
 		return errors.WithStack(err)
 	}
 	if ct.RowsAffected() == 0 {
-		insert := `INSERT INTO product_inventory (sku, available)
-                      VALUES ($1, $2);`
-		_, err := tx.Exec(ctx, insert, productInventory.Sku, productInventory.Available)
-		m.Complete(err)
+		insert := `INSERT INTO product_inventory (sku, on_hand, reserved, available, open_demand, held)
+                      VALUES ($1, $2, $3, $4, $5, $6);`
+		_, err := tx.Exec(ctx, insert, productInventory.Sku, productInventory.OnHand, productInventory.Reserved, productInventory.Available, productInventory.OpenDemand, productInventory.Held)
 		if err != nil {
+			m.Complete(err)
 			return err
 		}
 	}
-	m.Complete(nil)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO inventory_history (sku, on_hand, available, reserved, held, created)
+		     VALUES ($1, $2, $3, $4, $5, $6);`,
+		productInventory.Sku, productInventory.OnHand, productInventory.Available, productInventory.Reserved, productInventory.Held, time.Now())
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
 	return nil
 }
 
@@ -81,8 +95,8 @@ func (d *dbRepo) GetProduct(ctx context.Context, sku string, options ...core.Que
 	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
 
 	product := inventory.Product{}
-	err := tx.QueryRow(ctx, `SELECT sku, upc, name FROM products WHERE sku = $1 `+forUpdate, sku).
-		Scan(&product.Sku, &product.Upc, &product.Name)
+	err := tx.QueryRow(ctx, `SELECT `+productFields+` FROM products p WHERE p.sku = $1 `+forUpdate, sku).
+		Scan(productScanFields(&product)...)
 
 	if err != nil {
 		m.Complete(err)
@@ -96,13 +110,27 @@ func (d *dbRepo) GetProduct(ctx context.Context, sku string, options ...core.Que
 	return product, nil
 }
 
+const productFields = "p.sku, p.upc, p.name, p.type, p.attributes, COALESCE(p.category_id, 0), p.requires_inspection, p.approval_threshold, p.reservation_sla_hours, " +
+	"COALESCE(p.style, ''), p.variant, p.freezes"
+
+const productInventoryFields = productFields + ", pi.on_hand, pi.reserved, pi.available, pi.open_demand, pi.held"
+
+func productScanFields(p *inventory.Product) []interface{} {
+	return []interface{}{&p.Sku, &p.Upc, &p.Name, &p.Type, &p.Attributes, &p.CategoryID, &p.RequiresInspection, &p.ApprovalThreshold, &p.ReservationSlaHours,
+		&p.Style, &p.Variant, &p.Freezes}
+}
+
+func productInventoryScanFields(pi *inventory.ProductInventory) []interface{} {
+	return append(productScanFields(&pi.Product), &pi.OnHand, &pi.Reserved, &pi.Available, &pi.OpenDemand, &pi.Held)
+}
+
 func (d *dbRepo) GetProductInventory(ctx context.Context, sku string, options ...core.QueryOptions) (inventory.ProductInventory, error) {
 	m := db.StartMetric("GetProductInventory")
 	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
 
 	productInventory := inventory.ProductInventory{}
-	err := tx.QueryRow(ctx, `SELECT p.sku, p.upc, p.name, pi.available FROM products p, product_inventory pi WHERE p.sku = $1 AND p.sku = pi.sku `+forUpdate, sku).
-		Scan(&productInventory.Sku, &productInventory.Upc, &productInventory.Name, &productInventory.Available)
+	err := tx.QueryRow(ctx, `SELECT `+productInventoryFields+` FROM products p, product_inventory pi WHERE p.sku = $1 AND p.sku = pi.sku `+forUpdate, sku).
+		Scan(productInventoryScanFields(&productInventory)...)
 
 	if err != nil {
 		m.Complete(err)
@@ -116,14 +144,28 @@ func (d *dbRepo) GetProductInventory(ctx context.Context, sku string, options ..
 	return productInventory, nil
 }
 
-func (d *dbRepo) GetAllProductInventory(ctx context.Context, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+func (d *dbRepo) GetAllProductInventory(ctx context.Context, piOptions inventory.GetProductInventoryOptions, limit int, offset int, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
 	m := db.StartMetric("GetAllProducts")
 	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
 
+	params := make([]interface{}, 0)
+	params = append(params, limit)
+	params = append(params, offset)
+
+	whereClause := ""
+	if len(piOptions.Attributes) > 0 {
+		params = append(params, piOptions.Attributes)
+		whereClause += " AND p.attributes @> $" + strconv.Itoa(len(params))
+	}
+	if piOptions.Style != "" {
+		params = append(params, piOptions.Style)
+		whereClause += " AND p.style = $" + strconv.Itoa(len(params))
+	}
+
 	products := make([]inventory.ProductInventory, 0)
 	rows, err := tx.Query(ctx,
-		`SELECT p.sku, p.upc, p.name, pi.available FROM products p, product_inventory pi WHERE p.sku = pi.sku ORDER BY p.sku LIMIT $1 OFFSET $2 `+forUpdate,
-		limit, offset)
+		`SELECT `+productInventoryFields+` FROM products p, product_inventory pi WHERE p.sku = pi.sku`+whereClause+` ORDER BY p.sku LIMIT $1 OFFSET $2 `+forUpdate,
+		params...)
 	if err != nil {
 		m.Complete(err)
 		if err == pgx.ErrNoRows {
@@ -135,7 +177,7 @@ func (d *dbRepo) GetAllProductInventory(ctx context.Context, limit int, offset i
 
 	for rows.Next() {
 		product := inventory.ProductInventory{}
-		err = rows.Scan(&product.Sku, &product.Upc, &product.Name, &product.Available)
+		err = rows.Scan(productInventoryScanFields(&product)...)
 		if err != nil {
 			m.Complete(err)
 			if err == pgx.ErrNoRows {
@@ -150,13 +192,17 @@ func (d *dbRepo) GetAllProductInventory(ctx context.Context, limit int, offset i
 	return products, nil
 }
 
+const productionEventFields = `id, request_id, sku, quantity, scrapped, unit_cost, remaining, held, COALESCE(line, ''),
+	COALESCE(shift, ''), COALESCE(operator, ''), created`
+
 func (d *dbRepo) GetProductionEventByRequestID(ctx context.Context, requestID string, options ...core.QueryOptions) (pe inventory.ProductionEvent, err error) {
 	m := db.StartMetric("GetProductionEventByRequestID")
 	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
 
 	pe = inventory.ProductionEvent{}
-	err = tx.QueryRow(ctx, `SELECT id, request_id, sku, quantity, created FROM production_events `+forUpdate+` WHERE request_id = $1 `+forUpdate, requestID).
-		Scan(&pe.ID, &pe.RequestID, &pe.Sku, &pe.Quantity, &pe.Created)
+	err = tx.QueryRow(ctx, `SELECT `+productionEventFields+` FROM production_events WHERE request_id = $1 `+forUpdate, requestID).
+		Scan(&pe.ID, &pe.RequestID, &pe.Sku, &pe.Quantity, &pe.Scrapped, &pe.UnitCost, &pe.Remaining, &pe.Held, &pe.Line,
+			&pe.Shift, &pe.Operator, &pe.Created)
 
 	if err != nil {
 		m.Complete(err)
@@ -174,10 +220,11 @@ func (d *dbRepo) SaveProductionEvent(ctx context.Context, event *inventory.Produ
 	m := db.StartMetric("SaveProductionEvent")
 	tx := db.GetUpdateOptions(d.conn, options...)
 
-	insert := `INSERT INTO production_events (request_id, sku, quantity, created)
-			       VALUES ($1, $2, $3, $4) RETURNING id;`
+	insert := `INSERT INTO production_events (request_id, sku, quantity, scrapped, unit_cost, remaining, held, line, shift, operator, created)
+			       VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), $11) RETURNING id;`
 
-	err := tx.QueryRow(ctx, insert, event.RequestID, event.Sku, event.Quantity, event.Created).Scan(&event.ID)
+	err := tx.QueryRow(ctx, insert, event.RequestID, event.Sku, event.Quantity, event.Scrapped, event.UnitCost,
+		event.Remaining, event.Held, event.Line, event.Shift, event.Operator, event.Created).Scan(&event.ID)
 	if err != nil {
 		m.Complete(err)
 		if err == pgx.ErrNoRows {
@@ -193,9 +240,9 @@ func (d *dbRepo) SaveReservation(ctx context.Context, r *inventory.Reservation,
 	m := db.StartMetric("SaveReservation")
 	tx := db.GetUpdateOptions(d.conn, options...)
 
-	insert := `INSERT INTO reservations (request_id, requester, sku, state, reserved_quantity, requested_quantity, created)
-                      VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
-	err := tx.QueryRow(ctx, insert, r.RequestID, r.Requester, r.Sku, r.State, r.ReservedQuantity, r.RequestedQuantity, r.Created).Scan(&r.ID)
+	insert := `INSERT INTO reservations (request_id, requester, sku, state, reserved_quantity, requested_quantity, created, allow_substitutes, parent_id, split_backorder)
+                      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, 0), $10) RETURNING id;`
+	err := tx.QueryRow(ctx, insert, r.RequestID, r.Requester, r.Sku, r.State, r.ReservedQuantity, r.RequestedQuantity, r.Created, r.AllowSubstitutes, r.ParentID, r.SplitBackorder).Scan(&r.ID)
 	if err != nil {
 		m.Complete(err)
 		if err == pgx.ErrNoRows {
@@ -211,8 +258,10 @@ func (d *dbRepo) UpdateReservation(ctx context.Context, ID uint64, state invento
 	m := db.StartMetric("UpdateReservation")
 	tx := db.GetUpdateOptions(d.conn, options...)
 
-	update := `UPDATE reservations SET state = $2, reserved_quantity = $3 WHERE id=$1;`
-	_, err := tx.Exec(ctx, update, ID, state, qty)
+	update := `UPDATE reservations SET state = $2, reserved_quantity = $3,
+                      closed = CASE WHEN $2 = $4 THEN COALESCE(closed, now()) ELSE closed END
+                WHERE id=$1;`
+	_, err := tx.Exec(ctx, update, ID, state, qty, inventory.Closed)
 	m.Complete(err)
 	if err != nil {
 		return errors.WithStack(err)
@@ -221,7 +270,55 @@ func (d *dbRepo) UpdateReservation(ctx context.Context, ID uint64, state invento
 	return nil
 }
 
-const reservationFields = "id, request_id, requester, sku, state, reserved_quantity, requested_quantity, created"
+// DecideReservation saves the state of a reservation along with who decided on its approval, when and why.
+func (d *dbRepo) DecideReservation(ctx context.Context, r inventory.Reservation, options ...core.UpdateOptions) error {
+	m := db.StartMetric("DecideReservation")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx,
+		`UPDATE reservations SET state = $2, decided_by = $3, decided = $4, decision_reason = NULLIF($5, '') WHERE id = $1;`,
+		r.ID, r.State, r.DecidedBy, r.Decided, r.DecisionReason)
+	if err != nil {
+		m.Complete(err)
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		m.Complete(nil)
+		return errors.WithStack(core.ErrNotFound)
+	}
+	m.Complete(nil)
+	return nil
+}
+
+func (d *dbRepo) UpdateReservationRequested(ctx context.Context, ID uint64, qty int64, options ...core.UpdateOptions) error {
+	m := db.StartMetric("UpdateReservationRequested")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	_, err := tx.Exec(ctx, `UPDATE reservations SET requested_quantity = $2 WHERE id = $1;`, ID, qty)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (d *dbRepo) UpdateReservationCost(ctx context.Context, ID uint64, costOfGoods float64, options ...core.UpdateOptions) error {
+	m := db.StartMetric("UpdateReservationCost")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	_, err := tx.Exec(ctx, `UPDATE reservations SET cost_of_goods = $2 WHERE id = $1;`, ID, costOfGoods)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+const reservationFields = "id, request_id, requester, sku, state, reserved_quantity, requested_quantity, cost_of_goods, created, allow_substitutes, COALESCE(parent_id, 0), split_backorder, COALESCE(decided_by, ''), decided, COALESCE(decision_reason, '')"
+
+func reservationScanFields(r *inventory.Reservation) []interface{} {
+	return []interface{}{&r.ID, &r.RequestID, &r.Requester, &r.Sku, &r.State, &r.ReservedQuantity, &r.RequestedQuantity, &r.CostOfGoods, &r.Created, &r.AllowSubstitutes, &r.ParentID, &r.SplitBackorder, &r.DecidedBy, &r.Decided, &r.DecisionReason}
+}
 
 func (d *dbRepo) GetReservations(ctx context.Context, resOptions inventory.GetReservationsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.Reservation, error) {
 	m := db.StartMetric("GetSkuOpenReserves")
@@ -234,7 +331,7 @@ func (d *dbRepo) GetReservations(ctx context.Context, resOptions inventory.GetRe
 	whereClause := ""
 	paramIdx := 2
 
-	if resOptions.Sku != "" || resOptions.State != inventory.None {
+	if resOptions.Sku != "" || resOptions.State != inventory.None || resOptions.ParentID != 0 {
 		whereClause = " WHERE "
 	}
 
@@ -256,6 +353,15 @@ func (d *dbRepo) GetReservations(ctx context.Context, resOptions inventory.GetRe
 		params = append(params, resOptions.State)
 	}
 
+	if resOptions.ParentID != 0 {
+		if paramIdx > 2 {
+			whereClause += " AND"
+		}
+		paramIdx++
+		whereClause += " parent_id = $" + strconv.Itoa(paramIdx)
+		params = append(params, resOptions.ParentID)
+	}
+
 	reservations := make([]inventory.Reservation, 0)
 	rows, err := tx.Query(ctx,
 		`SELECT `+reservationFields+` FROM reservations `+whereClause+` ORDER BY created ASC LIMIT $1 OFFSET $2 `+forUpdate,
@@ -271,7 +377,7 @@ func (d *dbRepo) GetReservations(ctx context.Context, resOptions inventory.GetRe
 
 	for rows.Next() {
 		r := inventory.Reservation{}
-		err = rows.Scan(&r.ID, &r.RequestID, &r.Requester, &r.Sku, &r.State, &r.ReservedQuantity, &r.RequestedQuantity, &r.Created)
+		err = rows.Scan(reservationScanFields(&r)...)
 		if err != nil {
 			m.Complete(err)
 			return nil, err
@@ -290,7 +396,7 @@ func (d *dbRepo) GetReservationByRequestID(ctx context.Context, requestId string
 	r := inventory.Reservation{}
 	err := tx.QueryRow(ctx,
 		`SELECT `+reservationFields+` FROM reservations WHERE request_id = $1 `+forUpdate,
-		requestId).Scan(&r.ID, &r.RequestID, &r.Requester, &r.Sku, &r.State, &r.ReservedQuantity, &r.RequestedQuantity, &r.Created)
+		requestId).Scan(reservationScanFields(&r)...)
 	if err != nil {
 		m.Complete(err)
 		if err == pgx.ErrNoRows {
@@ -310,7 +416,7 @@ func (d *dbRepo) GetReservation(ctx context.Context, ID uint64, options ...core.
 	r := inventory.Reservation{}
 	err := tx.QueryRow(ctx,
 		`SELECT `+reservationFields+` FROM reservations WHERE id = $1 `+forUpdate, ID).
-		Scan(&r.ID, &r.RequestID, &r.Requester, &r.Sku, &r.State, &r.ReservedQuantity, &r.RequestedQuantity, &r.Created)
+		Scan(reservationScanFields(&r)...)
 	if err != nil {
 		m.Complete(err)
 		if err == pgx.ErrNoRows {
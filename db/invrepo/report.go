@@ -0,0 +1,330 @@
+package invrepo
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+var fillRateColumns = map[inventory.FillRateGroup]string{
+	inventory.FillRateBySku:       "sku",
+	inventory.FillRateByRequester: "requester",
+}
+
+// reportFilter builds the conditions limiting a report to the period and SKU of the options, with time being the
+// column the period applies to. Conditions are appended to params and numbered after any params already present.
+func reportFilter(timeColumn string, reportOptions inventory.ReportOptions, params []interface{}) (string, []interface{}) {
+	conditions := ""
+	add := func(condition string, value interface{}) {
+		params = append(params, value)
+		conditions += " AND " + condition + " $" + strconv.Itoa(len(params))
+	}
+
+	if !reportOptions.From.IsZero() {
+		add(timeColumn+" >=", reportOptions.From)
+	}
+	if !reportOptions.To.IsZero() {
+		add(timeColumn+" <", reportOptions.To)
+	}
+	if reportOptions.Sku != "" {
+		add("sku =", reportOptions.Sku)
+	}
+	return conditions, params
+}
+
+func (d *dbRepo) GetFillRates(ctx context.Context, group inventory.FillRateGroup, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.FillRate, error) {
+	m := db.StartMetric("GetFillRates")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	column, ok := fillRateColumns[group]
+	if !ok {
+		err := errors.Errorf("unknown fill rate group %s", group)
+		m.Complete(err)
+		return nil, err
+	}
+
+	conditions, params := reportFilter("created", reportOptions, []interface{}{inventory.Closed, inventory.Rejected})
+	rows, err := tx.Query(ctx,
+		`SELECT COALESCE(`+column+`, ''), COUNT(*), COUNT(*) FILTER (WHERE state = $1),
+		        COALESCE(SUM(requested_quantity), 0), COALESCE(SUM(reserved_quantity), 0)
+		   FROM reservations
+		  WHERE state <> $2`+conditions+`
+		  GROUP BY `+column+`
+		  ORDER BY `+column,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	rates := make([]inventory.FillRate, 0)
+	for rows.Next() {
+		f := inventory.FillRate{}
+		if err = rows.Scan(&f.Key, &f.Reservations, &f.Closed, &f.Requested, &f.Reserved); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		rates = append(rates, f)
+	}
+
+	m.Complete(nil)
+	return rates, nil
+}
+
+func (d *dbRepo) GetTimeToClose(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.TimeToClose, error) {
+	m := db.StartMetric("GetTimeToClose")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	conditions, params := reportFilter("closed", reportOptions, []interface{}{})
+	rows, err := tx.Query(ctx,
+		`SELECT sku, COUNT(*),
+		        AVG(EXTRACT(EPOCH FROM closed - created))::float8,
+		        percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM closed - created))::float8
+		   FROM reservations
+		  WHERE closed IS NOT NULL`+conditions+`
+		  GROUP BY sku
+		  ORDER BY sku`,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	times := make([]inventory.TimeToClose, 0)
+	for rows.Next() {
+		c := inventory.TimeToClose{}
+		if err = rows.Scan(&c.Sku, &c.Closed, &c.AverageSeconds, &c.P95Seconds); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		times = append(times, c)
+	}
+
+	m.Complete(nil)
+	return times, nil
+}
+
+func (d *dbRepo) GetDailyProduction(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.DailyProduction, error) {
+	m := db.StartMetric("GetDailyProduction")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	conditions, params := reportFilter("created", reportOptions, []interface{}{})
+	rows, err := tx.Query(ctx,
+		`SELECT date_trunc('day', created, 'UTC') AS day, COUNT(*), COALESCE(SUM(quantity), 0)
+		   FROM production_events
+		  WHERE TRUE`+conditions+`
+		  GROUP BY day
+		  ORDER BY day`,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	days := make([]inventory.DailyProduction, 0)
+	for rows.Next() {
+		p := inventory.DailyProduction{}
+		if err = rows.Scan(&p.Date, &p.Events, &p.Quantity); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		p.Date = p.Date.UTC()
+		days = append(days, p)
+	}
+
+	m.Complete(nil)
+	return days, nil
+}
+
+func (d *dbRepo) GetTopOpenDemand(ctx context.Context, reportOptions inventory.ReportOptions, limit int, options ...core.QueryOptions) ([]inventory.SkuDemand, error) {
+	m := db.StartMetric("GetTopOpenDemand")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	conditions, params := reportFilter("created", reportOptions, []interface{}{limit, inventory.Open})
+	rows, err := tx.Query(ctx,
+		`SELECT sku, COUNT(*), SUM(requested_quantity - reserved_quantity) AS open_demand
+		   FROM reservations
+		  WHERE state = $2`+conditions+`
+		  GROUP BY sku
+		  ORDER BY open_demand DESC, sku
+		  LIMIT $1`,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	demand := make([]inventory.SkuDemand, 0)
+	for rows.Next() {
+		s := inventory.SkuDemand{}
+		if err = rows.Scan(&s.Sku, &s.Reservations, &s.OpenDemand); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		demand = append(demand, s)
+	}
+
+	m.Complete(nil)
+	return demand, nil
+}
+
+func (d *dbRepo) GetLineYields(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.LineYield, error) {
+	m := db.StartMetric("GetLineYields")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	conditions, params := reportFilter("created", reportOptions, []interface{}{})
+	rows, err := tx.Query(ctx,
+		`SELECT COALESCE(line, ''), COALESCE(shift, ''), COUNT(*), COALESCE(SUM(quantity), 0), COALESCE(SUM(scrapped), 0)
+		   FROM production_events
+		  WHERE TRUE`+conditions+`
+		  GROUP BY line, shift
+		  ORDER BY line, shift`,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	yields := make([]inventory.LineYield, 0)
+	for rows.Next() {
+		y := inventory.LineYield{}
+		if err = rows.Scan(&y.Line, &y.Shift, &y.Events, &y.Good, &y.Scrapped); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		yields = append(yields, y)
+	}
+
+	m.Complete(nil)
+	return yields, nil
+}
+
+func (d *dbRepo) GetLineOutput(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.LineOutput, error) {
+	m := db.StartMetric("GetLineOutput")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	conditions, params := reportFilter("created", reportOptions, []interface{}{})
+	rows, err := tx.Query(ctx,
+		`SELECT date_trunc('day', created, 'UTC') AS day, COALESCE(line, ''), COALESCE(shift, ''), COUNT(*),
+		        COALESCE(SUM(quantity), 0), COALESCE(SUM(scrapped), 0)
+		   FROM production_events
+		  WHERE TRUE`+conditions+`
+		  GROUP BY day, line, shift
+		  ORDER BY day, line, shift`,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	output := make([]inventory.LineOutput, 0)
+	for rows.Next() {
+		o := inventory.LineOutput{}
+		if err = rows.Scan(&o.Date, &o.Line, &o.Shift, &o.Events, &o.Good, &o.Scrapped); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		o.Date = o.Date.UTC()
+		output = append(output, o)
+	}
+
+	m.Complete(nil)
+	return output, nil
+}
+
+// GetReservationAging gets the open and pending reservations created in the period, grouped by SKU and requester and
+// counted by their age at asOf.
+func (d *dbRepo) GetReservationAging(ctx context.Context, reportOptions inventory.ReportOptions, asOf time.Time, options ...core.QueryOptions) ([]inventory.ReservationAging, error) {
+	m := db.StartMetric("GetReservationAging")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	conditions, params := reportFilter("created", reportOptions, []interface{}{inventory.Open, inventory.PendingApproval, asOf})
+	rows, err := tx.Query(ctx,
+		`SELECT sku, COALESCE(requester, ''), COUNT(*), COALESCE(SUM(requested_quantity - reserved_quantity), 0),
+		        COUNT(*) FILTER (WHERE age < INTERVAL '1 day'),
+		        COUNT(*) FILTER (WHERE age >= INTERVAL '1 day' AND age < INTERVAL '3 days'),
+		        COUNT(*) FILTER (WHERE age >= INTERVAL '3 days' AND age < INTERVAL '7 days'),
+		        COUNT(*) FILTER (WHERE age >= INTERVAL '7 days' AND age < INTERVAL '14 days'),
+		        COUNT(*) FILTER (WHERE age >= INTERVAL '14 days'),
+		        MIN(created)
+		   FROM (SELECT *, $3::TIMESTAMPTZ - created AS age FROM reservations) r
+		  WHERE state IN ($1, $2)`+conditions+`
+		  GROUP BY sku, requester
+		  ORDER BY MIN(created), sku, requester`,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	aging := make([]inventory.ReservationAging, 0)
+	for rows.Next() {
+		a := inventory.ReservationAging{}
+		if err = rows.Scan(&a.Sku, &a.Requester, &a.Reservations, &a.Outstanding,
+			&a.UnderOneDay, &a.OneToThree, &a.ThreeToSeven, &a.SevenTo14, &a.Over14, &a.Oldest); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		aging = append(aging, a)
+	}
+
+	m.Complete(nil)
+	return aging, nil
+}
+
+// GetAgedLots gets the production lots received in the period with stock remaining, oldest first. Each is carried at
+// the average unit cost of its SKU's cost layers, which under weighted average costing is the only layer.
+func (d *dbRepo) GetAgedLots(ctx context.Context, reportOptions inventory.ReportOptions, options ...core.QueryOptions) ([]inventory.AgedLot, error) {
+	m := db.StartMetric("GetAgedLots")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	conditions, params := reportFilter("created", reportOptions, []interface{}{inventory.ValuationAllocation})
+	rows, err := tx.Query(ctx,
+		`WITH lots AS (SELECT id, sku, remaining, created
+		                 FROM production_events
+		                WHERE remaining > 0`+conditions+`),
+		      costs AS (SELECT sku, SUM(remaining * unit_cost) / SUM(remaining) AS unit_cost
+		                  FROM cost_layers
+		                 WHERE remaining > 0
+		                 GROUP BY sku),
+		      allocations AS (SELECT sku, MAX(created) AS last_allocated
+		                        FROM valuation_entries
+		                       WHERE reason = $1
+		                       GROUP BY sku)
+		SELECT l.sku, l.remaining, COALESCE(c.unit_cost, 0), l.created, a.last_allocated
+		  FROM lots l
+		  LEFT JOIN costs c ON c.sku = l.sku
+		  LEFT JOIN allocations a ON a.sku = l.sku
+		 ORDER BY l.created, l.id`,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	lots := make([]inventory.AgedLot, 0)
+	for rows.Next() {
+		l := inventory.AgedLot{}
+		if err = rows.Scan(&l.Sku, &l.Remaining, &l.UnitCost, &l.Received, &l.LastAllocated); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		lots = append(lots, l)
+	}
+
+	m.Complete(nil)
+	return lots, nil
+}
@@ -0,0 +1,137 @@
+package invrepo
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+const returnFields = `id, sku, COALESCE(reservation_id, 0), quantity, reason, state, created_by, created,
+	COALESCE(received_by, ''), received, COALESCE(inspected_by, ''), inspected, COALESCE(note, '')`
+
+func scanReturn(row pgx.Row, r *inventory.ReturnAuthorization) error {
+	return row.Scan(&r.ID, &r.Sku, &r.ReservationID, &r.Quantity, &r.Reason, &r.State, &r.CreatedBy, &r.Created,
+		&r.ReceivedBy, &r.Received, &r.InspectedBy, &r.Inspected, &r.Note)
+}
+
+func (d *dbRepo) GetReturn(ctx context.Context, ID uint64, options ...core.QueryOptions) (inventory.ReturnAuthorization, error) {
+	m := db.StartMetric("GetReturn")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	r := inventory.ReturnAuthorization{}
+	err := scanReturn(tx.QueryRow(ctx, `SELECT `+returnFields+` FROM returns WHERE id = $1 `+forUpdate, ID), &r)
+	if err != nil {
+		m.Complete(err)
+		if err == pgx.ErrNoRows {
+			return r, errors.WithStack(core.ErrNotFound)
+		}
+		return r, errors.WithStack(err)
+	}
+
+	m.Complete(nil)
+	return r, nil
+}
+
+func (d *dbRepo) GetReturns(ctx context.Context, rtnOptions inventory.GetReturnsOptions, limit, offset int, options ...core.QueryOptions) ([]inventory.ReturnAuthorization, error) {
+	m := db.StartMetric("GetReturns")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	params := []interface{}{limit, offset}
+	whereClause := ""
+	addCondition := func(column string, value interface{}) {
+		if whereClause == "" {
+			whereClause = " WHERE"
+		} else {
+			whereClause += " AND"
+		}
+		params = append(params, value)
+		whereClause += " " + column + " = $" + strconv.Itoa(len(params))
+	}
+
+	if rtnOptions.Sku != "" {
+		addCondition("sku", rtnOptions.Sku)
+	}
+	if rtnOptions.State != "" {
+		addCondition("state", rtnOptions.State)
+	}
+	if rtnOptions.ReservationID != 0 {
+		addCondition("reservation_id", rtnOptions.ReservationID)
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+returnFields+` FROM returns`+whereClause+` ORDER BY id DESC LIMIT $1 OFFSET $2 `+forUpdate,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	returns := make([]inventory.ReturnAuthorization, 0)
+	for rows.Next() {
+		r := inventory.ReturnAuthorization{}
+		if err = scanReturn(rows, &r); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		returns = append(returns, r)
+	}
+
+	m.Complete(nil)
+	return returns, nil
+}
+
+func (d *dbRepo) GetReturnedQuantity(ctx context.Context, reservationID uint64, options ...core.QueryOptions) (int64, error) {
+	m := db.StartMetric("GetReturnedQuantity")
+	tx, _ := db.GetQueryOptions(d.conn, options...)
+
+	var qty int64
+	err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(quantity), 0) FROM returns WHERE reservation_id = $1`, reservationID).
+		Scan(&qty)
+	m.Complete(err)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return qty, nil
+}
+
+func (d *dbRepo) SaveReturn(ctx context.Context, rma *inventory.ReturnAuthorization, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveReturn")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	err := tx.QueryRow(ctx,
+		`INSERT INTO returns (sku, reservation_id, quantity, reason, state, created_by, created)
+		      VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7) RETURNING id;`,
+		rma.Sku, rma.ReservationID, rma.Quantity, rma.Reason, rma.State, rma.CreatedBy, rma.Created).
+		Scan(&rma.ID)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (d *dbRepo) UpdateReturn(ctx context.Context, rma inventory.ReturnAuthorization, options ...core.UpdateOptions) error {
+	m := db.StartMetric("UpdateReturn")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx,
+		`UPDATE returns
+		    SET state = $2, received_by = NULLIF($3, ''), received = $4, inspected_by = NULLIF($5, ''), inspected = $6,
+		        note = NULLIF($7, '')
+		  WHERE id = $1;`,
+		rma.ID, rma.State, rma.ReceivedBy, rma.Received, rma.InspectedBy, rma.Inspected, rma.Note)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
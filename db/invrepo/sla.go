@@ -0,0 +1,65 @@
+package invrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+// GetSlaBreaches gets the open and pending reservations that have not yet been alerted on and were created more than
+// their product's SLA before asOf. Products without an SLA of their own use defaultHours, an SLA of zero never breaches.
+func (d *dbRepo) GetSlaBreaches(ctx context.Context, defaultHours int64, asOf time.Time, options ...core.QueryOptions) ([]inventory.ReservationAlert, error) {
+	m := db.StartMetric("GetSlaBreaches")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT r.id, r.request_id, r.requester, r.sku, r.state, r.requested_quantity - r.reserved_quantity, r.created, s.hours
+		   FROM reservations r
+		   JOIN products p ON p.sku = r.sku
+		  CROSS JOIN LATERAL (SELECT COALESCE(NULLIF(p.reservation_sla_hours, 0), $1::BIGINT) AS hours) s
+		  WHERE r.state IN ($2, $3) AND r.sla_alerted IS NULL AND s.hours > 0
+		    AND r.created <= $4::TIMESTAMPTZ - s.hours * INTERVAL '1 hour'
+		  ORDER BY r.created `+forUpdate,
+		defaultHours, inventory.Open, inventory.PendingApproval, asOf)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	alerts := make([]inventory.ReservationAlert, 0)
+	for rows.Next() {
+		a := inventory.ReservationAlert{Type: inventory.SlaBreached}
+		if err = rows.Scan(&a.ReservationID, &a.RequestID, &a.Requester, &a.Sku, &a.State, &a.Outstanding, &a.Created, &a.ThresholdHours); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		alerts = append(alerts, a)
+	}
+
+	m.Complete(nil)
+	return alerts, nil
+}
+
+// MarkSlaAlerted records when the SLA alert for a reservation was raised. A reservation that has already been alerted
+// on is not found.
+func (d *dbRepo) MarkSlaAlerted(ctx context.Context, ID uint64, alerted time.Time, options ...core.UpdateOptions) error {
+	m := db.StartMetric("MarkSlaAlerted")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx, `UPDATE reservations SET sla_alerted = $2 WHERE id = $1 AND sla_alerted IS NULL;`, ID, alerted)
+	if err != nil {
+		m.Complete(err)
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		m.Complete(nil)
+		return errors.WithStack(core.ErrNotFound)
+	}
+	m.Complete(nil)
+	return nil
+}
@@ -0,0 +1,130 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+func productVariant(product inventory.Product) inventory.Variant {
+	if product.Variant == nil {
+		return inventory.Variant{}
+	}
+	return product.Variant
+}
+
+const styleFields = "s.code, s.name, s.axes, s.created"
+
+func (d *dbRepo) GetStyle(ctx context.Context, code string, options ...core.QueryOptions) (inventory.Style, error) {
+	m := db.StartMetric("GetStyle")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	s := inventory.Style{}
+	err := tx.QueryRow(ctx, `SELECT `+styleFields+` FROM styles s WHERE s.code = $1 `+forUpdate, code).
+		Scan(&s.Code, &s.Name, &s.Axes, &s.Created)
+	if err != nil {
+		m.Complete(err)
+		if err == pgx.ErrNoRows {
+			return s, errors.WithStack(core.ErrNotFound)
+		}
+		return s, errors.WithStack(err)
+	}
+
+	m.Complete(nil)
+	return s, nil
+}
+
+// GetStyles pages through styles by code. When attributes are given only styles having at least one variant with every
+// one of the attribute values are returned.
+func (d *dbRepo) GetStyles(ctx context.Context, attributes inventory.Attributes, limit, offset int, options ...core.QueryOptions) ([]inventory.Style, error) {
+	m := db.StartMetric("GetStyles")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	params := []interface{}{limit, offset}
+	whereClause := ""
+	if len(attributes) > 0 {
+		params = append(params, attributes)
+		whereClause = " WHERE EXISTS (SELECT 1 FROM products p WHERE p.style = s.code AND p.attributes @> $3)"
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+styleFields+` FROM styles s`+whereClause+` ORDER BY s.code LIMIT $1 OFFSET $2 `+forUpdate,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	styles := make([]inventory.Style, 0)
+	for rows.Next() {
+		s := inventory.Style{}
+		if err = rows.Scan(&s.Code, &s.Name, &s.Axes, &s.Created); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		styles = append(styles, s)
+	}
+
+	m.Complete(nil)
+	return styles, nil
+}
+
+// GetStyleVariants gets the inventory of every variant of the given styles ordered by style and SKU, optionally limited
+// to variants having every one of the attribute values.
+func (d *dbRepo) GetStyleVariants(ctx context.Context, codes []string, attributes inventory.Attributes, options ...core.QueryOptions) ([]inventory.ProductInventory, error) {
+	m := db.StartMetric("GetStyleVariants")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	params := []interface{}{codes}
+	whereClause := ""
+	if len(attributes) > 0 {
+		params = append(params, attributes)
+		whereClause = " AND p.attributes @> $2"
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+productInventoryFields+`
+		   FROM products p, product_inventory pi
+		  WHERE p.sku = pi.sku AND p.style = ANY($1)`+whereClause+`
+		  ORDER BY p.style, p.sku `+forUpdate,
+		params...)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	variants := make([]inventory.ProductInventory, 0)
+	for rows.Next() {
+		pi := inventory.ProductInventory{}
+		if err = rows.Scan(productInventoryScanFields(&pi)...); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		variants = append(variants, pi)
+	}
+
+	m.Complete(nil)
+	return variants, nil
+}
+
+func (d *dbRepo) SaveStyle(ctx context.Context, style inventory.Style, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveStyle")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO styles (code, name, axes, created)
+		            VALUES ($1, $2, $3, $4)
+		ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name;`,
+		style.Code, style.Name, style.Axes, style.Created)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
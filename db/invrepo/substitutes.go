@@ -0,0 +1,118 @@
+package invrepo
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sksmith/go-micro-example/core"
+	"github.com/sksmith/go-micro-example/core/inventory"
+	"github.com/sksmith/go-micro-example/db"
+)
+
+func (d *dbRepo) GetSubstituteRules(ctx context.Context, sku string, options ...core.QueryOptions) ([]inventory.SubstituteRule, error) {
+	m := db.StartMetric("GetSubstituteRules")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, sku, substitute_sku, quantity, substitute_quantity, two_way, created
+		   FROM substitutes
+		  WHERE sku = $1 OR substitute_sku = $1
+		  ORDER BY id `+forUpdate,
+		sku)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	rules := make([]inventory.SubstituteRule, 0)
+	for rows.Next() {
+		r := inventory.SubstituteRule{}
+		if err = rows.Scan(&r.ID, &r.Sku, &r.SubstituteSku, &r.Quantity, &r.SubstituteQuantity, &r.TwoWay, &r.Created); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		rules = append(rules, r)
+	}
+
+	m.Complete(nil)
+	return rules, nil
+}
+
+func (d *dbRepo) SaveSubstituteRule(ctx context.Context, rule *inventory.SubstituteRule, options ...core.UpdateOptions) error {
+	m := db.StartMetric("SaveSubstituteRule")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	err := tx.QueryRow(ctx,
+		`INSERT INTO substitutes (sku, substitute_sku, quantity, substitute_quantity, two_way, created)
+		      VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (sku, substitute_sku)
+		   DO UPDATE SET quantity = $3, substitute_quantity = $4, two_way = $5
+		   RETURNING id, created;`,
+		rule.Sku, rule.SubstituteSku, rule.Quantity, rule.SubstituteQuantity, rule.TwoWay, rule.Created).Scan(&rule.ID, &rule.Created)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (d *dbRepo) DeleteSubstituteRule(ctx context.Context, sku string, ID uint64, options ...core.UpdateOptions) error {
+	m := db.StartMetric("DeleteSubstituteRule")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	ct, err := tx.Exec(ctx, `DELETE FROM substitutes WHERE id = $1 AND (sku = $2 OR substitute_sku = $2);`, ID, sku)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.WithStack(core.ErrNotFound)
+	}
+	return nil
+}
+
+func (d *dbRepo) GetReservationFills(ctx context.Context, reservationID uint64, options ...core.QueryOptions) ([]inventory.ReservationFill, error) {
+	m := db.StartMetric("GetReservationFills")
+	tx, forUpdate := db.GetQueryOptions(d.conn, options...)
+
+	rows, err := tx.Query(ctx,
+		`SELECT sku, quantity FROM reservation_fills WHERE reservation_id = $1 ORDER BY sku `+forUpdate,
+		reservationID)
+	if err != nil {
+		m.Complete(err)
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	fills := make([]inventory.ReservationFill, 0)
+	for rows.Next() {
+		f := inventory.ReservationFill{}
+		if err = rows.Scan(&f.Sku, &f.Quantity); err != nil {
+			m.Complete(err)
+			return nil, errors.WithStack(err)
+		}
+		fills = append(fills, f)
+	}
+
+	m.Complete(nil)
+	return fills, nil
+}
+
+// AddReservationFill adds to the quantity of the SKU set aside for the reservation.
+func (d *dbRepo) AddReservationFill(ctx context.Context, reservationID uint64, fill inventory.ReservationFill, options ...core.UpdateOptions) error {
+	m := db.StartMetric("AddReservationFill")
+	tx := db.GetUpdateOptions(d.conn, options...)
+
+	_, err := tx.Exec(ctx,
+		`INSERT INTO reservation_fills (reservation_id, sku, quantity)
+		      VALUES ($1, $2, $3)
+		 ON CONFLICT (reservation_id, sku)
+		   DO UPDATE SET quantity = reservation_fills.quantity + $3;`,
+		reservationID, fill.Sku, fill.Quantity)
+	m.Complete(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
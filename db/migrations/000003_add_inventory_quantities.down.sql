@@ -0,0 +1,6 @@
+ALTER TABLE product_inventory
+    DROP COLUMN IF EXISTS on_hand,
+    DROP COLUMN IF EXISTS reserved,
+    DROP COLUMN IF EXISTS open_demand;
+
+COMMIT;
@@ -0,0 +1,20 @@
+ALTER TABLE product_inventory
+    ADD COLUMN on_hand     INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN reserved    INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN open_demand INTEGER NOT NULL DEFAULT 0;
+
+UPDATE product_inventory pi
+   SET reserved    = COALESCE(r.reserved, 0),
+       open_demand = COALESCE(r.open_demand, 0)
+  FROM (SELECT sku,
+               SUM(reserved_quantity)                                                         AS reserved,
+               SUM(CASE WHEN state = 'Open' THEN requested_quantity - reserved_quantity ELSE 0 END) AS open_demand
+          FROM reservations
+         GROUP BY sku) r
+ WHERE pi.sku = r.sku;
+
+UPDATE product_inventory
+   SET available = COALESCE(available, 0),
+       on_hand   = COALESCE(available, 0) + reserved;
+
+COMMIT;
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS valuation_entries;
+
+DROP TABLE IF EXISTS cost_layers;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS cost_of_goods;
+
+ALTER TABLE production_events
+    DROP COLUMN IF EXISTS unit_cost;
+
+COMMIT;
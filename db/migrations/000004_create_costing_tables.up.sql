@@ -0,0 +1,38 @@
+ALTER TABLE production_events
+    ADD COLUMN unit_cost NUMERIC(14, 4) NOT NULL DEFAULT 0;
+
+ALTER TABLE reservations
+    ADD COLUMN cost_of_goods NUMERIC(14, 4) NOT NULL DEFAULT 0;
+
+CREATE TABLE cost_layers
+(
+    id                  INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    sku                 VARCHAR(50) REFERENCES products (sku),
+    production_event_id INTEGER REFERENCES production_events (id),
+    unit_cost           NUMERIC(14, 4) NOT NULL,
+    quantity            INTEGER        NOT NULL,
+    remaining           INTEGER        NOT NULL,
+    created             TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX cost_layer_sku_idx ON cost_layers (sku, created) WHERE remaining > 0;
+
+CREATE TABLE valuation_entries
+(
+    id               INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    sku              VARCHAR(50) REFERENCES products (sku),
+    method           VARCHAR(50),
+    reason           VARCHAR(50),
+    reference        VARCHAR(100),
+    quantity         INTEGER,
+    cost             NUMERIC(14, 4),
+    balance_quantity INTEGER,
+    balance_value    NUMERIC(14, 4),
+    created          TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX val_entry_sku_idx ON valuation_entries (sku, created);
+
+COMMIT;
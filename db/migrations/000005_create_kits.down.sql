@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS kit_components;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS type;
+
+COMMIT;
@@ -0,0 +1,15 @@
+ALTER TABLE products
+    ADD COLUMN type VARCHAR(50) NOT NULL DEFAULT 'Standard';
+
+CREATE TABLE kit_components
+(
+    kit_sku       VARCHAR(50) REFERENCES products (sku),
+    component_sku VARCHAR(50) REFERENCES products (sku),
+    quantity      INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (kit_sku, component_sku)
+);
+
+CREATE
+INDEX kit_component_sku_idx ON kit_components (component_sku);
+
+COMMIT;
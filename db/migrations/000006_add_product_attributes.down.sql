@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS attribute_definitions;
+
+DROP INDEX IF EXISTS product_attributes_idx;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS attributes;
+
+COMMIT;
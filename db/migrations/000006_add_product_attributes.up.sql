@@ -0,0 +1,13 @@
+ALTER TABLE products
+    ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE
+INDEX product_attributes_idx ON products USING GIN (attributes jsonb_path_ops);
+
+CREATE TABLE attribute_definitions
+(
+    name VARCHAR(100) PRIMARY KEY,
+    type VARCHAR(50) NOT NULL
+);
+
+COMMIT;
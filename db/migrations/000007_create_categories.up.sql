@@ -0,0 +1,17 @@
+CREATE TABLE categories
+(
+    id        INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    name      VARCHAR(100) NOT NULL,
+    parent_id INTEGER REFERENCES categories (id)
+);
+
+CREATE
+INDEX category_parent_idx ON categories (parent_id);
+
+ALTER TABLE products
+    ADD COLUMN category_id INTEGER REFERENCES categories (id) ON DELETE SET NULL;
+
+CREATE
+INDEX product_category_idx ON products (category_id);
+
+COMMIT;
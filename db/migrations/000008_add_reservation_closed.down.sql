@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS prod_evt_created_idx;
+
+DROP INDEX IF EXISTS res_closed_idx;
+
+DROP INDEX IF EXISTS res_created_idx;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS closed;
+
+COMMIT;
@@ -0,0 +1,13 @@
+ALTER TABLE reservations
+    ADD COLUMN closed TIMESTAMP WITH TIME ZONE;
+
+CREATE
+INDEX res_created_idx ON reservations (created);
+
+CREATE
+INDEX res_closed_idx ON reservations (closed);
+
+CREATE
+INDEX prod_evt_created_idx ON production_events (created);
+
+COMMIT;
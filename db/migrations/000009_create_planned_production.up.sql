@@ -0,0 +1,14 @@
+CREATE TABLE planned_production
+(
+    id        INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    sku       VARCHAR(50) REFERENCES products (sku) NOT NULL,
+    quantity  INTEGER                               NOT NULL,
+    due       TIMESTAMP WITH TIME ZONE              NOT NULL,
+    reference VARCHAR(100),
+    created   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX planned_prod_sku_due_idx ON planned_production (sku, due);
+
+COMMIT;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS inventory_adjustments;
+
+DROP TABLE IF EXISTS count_lines;
+
+DROP TABLE IF EXISTS count_sessions;
+
+COMMIT;
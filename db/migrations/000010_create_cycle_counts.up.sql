@@ -0,0 +1,41 @@
+CREATE TABLE count_sessions
+(
+    id        INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    state     VARCHAR(50) NOT NULL,
+    created   TIMESTAMP WITH TIME ZONE,
+    closed_by VARCHAR(100),
+    closed    TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX count_session_state_idx ON count_sessions (state);
+
+CREATE TABLE count_lines
+(
+    session_id     INTEGER REFERENCES count_sessions (id) ON DELETE CASCADE,
+    sku            VARCHAR(50) REFERENCES products (sku),
+    counted        INTEGER,
+    system_on_hand INTEGER NOT NULL DEFAULT 0,
+    variance       INTEGER NOT NULL DEFAULT 0,
+    counted_at     TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (session_id, sku)
+);
+
+CREATE
+INDEX count_line_sku_idx ON count_lines (sku);
+
+CREATE TABLE inventory_adjustments
+(
+    id        INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    sku       VARCHAR(50) REFERENCES products (sku),
+    quantity  INTEGER      NOT NULL,
+    reason    VARCHAR(50)  NOT NULL,
+    reference VARCHAR(100),
+    username  VARCHAR(100) NOT NULL,
+    created   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX inv_adjustment_sku_idx ON inventory_adjustments (sku, created);
+
+COMMIT;
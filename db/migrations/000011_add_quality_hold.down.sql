@@ -0,0 +1,7 @@
+ALTER TABLE product_inventory
+    DROP COLUMN IF EXISTS held;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS requires_inspection;
+
+COMMIT;
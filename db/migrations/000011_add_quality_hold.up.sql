@@ -0,0 +1,7 @@
+ALTER TABLE products
+    ADD COLUMN requires_inspection BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE product_inventory
+    ADD COLUMN held INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS reservation_fills;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS allow_substitutes;
+
+DROP TABLE IF EXISTS substitutes;
+
+COMMIT;
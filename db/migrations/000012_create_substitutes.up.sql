@@ -0,0 +1,27 @@
+CREATE TABLE substitutes
+(
+    id                  INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    sku                 VARCHAR(50) REFERENCES products (sku),
+    substitute_sku      VARCHAR(50) REFERENCES products (sku),
+    quantity            INTEGER NOT NULL DEFAULT 1,
+    substitute_quantity INTEGER NOT NULL DEFAULT 1,
+    two_way             BOOLEAN NOT NULL DEFAULT FALSE,
+    created             TIMESTAMP WITH TIME ZONE,
+    UNIQUE (sku, substitute_sku)
+);
+
+CREATE
+INDEX substitute_sku_idx ON substitutes (substitute_sku);
+
+ALTER TABLE reservations
+    ADD COLUMN allow_substitutes BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE reservation_fills
+(
+    reservation_id INTEGER REFERENCES reservations (id) ON DELETE CASCADE,
+    sku            VARCHAR(50) REFERENCES products (sku),
+    quantity       INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (reservation_id, sku)
+);
+
+COMMIT;
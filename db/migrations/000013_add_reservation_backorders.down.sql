@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS res_parent_id_idx;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS split_backorder;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS parent_id;
+
+COMMIT;
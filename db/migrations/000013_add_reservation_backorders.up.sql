@@ -0,0 +1,10 @@
+ALTER TABLE reservations
+    ADD COLUMN parent_id INTEGER REFERENCES reservations (id);
+
+ALTER TABLE reservations
+    ADD COLUMN split_backorder BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE
+INDEX res_parent_id_idx ON reservations (parent_id);
+
+COMMIT;
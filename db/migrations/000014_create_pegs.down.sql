@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS pegs;
+
+DROP INDEX IF EXISTS prod_evt_sku_remaining_idx;
+
+ALTER TABLE production_events
+    DROP COLUMN IF EXISTS remaining;
+
+COMMIT;
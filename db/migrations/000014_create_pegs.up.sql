@@ -0,0 +1,23 @@
+ALTER TABLE production_events
+    ADD COLUMN remaining INTEGER NOT NULL DEFAULT 0;
+
+CREATE
+INDEX prod_evt_sku_remaining_idx ON production_events (sku) WHERE remaining > 0;
+
+CREATE TABLE pegs
+(
+    id                  INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    production_event_id INTEGER REFERENCES production_events (id),
+    reservation_id      INTEGER REFERENCES reservations (id) ON DELETE CASCADE,
+    sku                 VARCHAR(50) REFERENCES products (sku),
+    quantity            INTEGER NOT NULL,
+    created             TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX peg_prod_evt_idx ON pegs (production_event_id);
+
+CREATE
+INDEX peg_reservation_idx ON pegs (reservation_id);
+
+COMMIT;
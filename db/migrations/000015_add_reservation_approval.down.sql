@@ -0,0 +1,13 @@
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS decision_reason;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS decided;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS decided_by;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS approval_threshold;
+
+COMMIT;
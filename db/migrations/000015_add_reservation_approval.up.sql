@@ -0,0 +1,13 @@
+ALTER TABLE products
+    ADD COLUMN approval_threshold INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE reservations
+    ADD COLUMN decided_by VARCHAR(100);
+
+ALTER TABLE reservations
+    ADD COLUMN decided TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE reservations
+    ADD COLUMN decision_reason VARCHAR(255);
+
+COMMIT;
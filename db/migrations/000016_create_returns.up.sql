@@ -0,0 +1,24 @@
+CREATE TABLE returns
+(
+    id             INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    sku            VARCHAR(50) REFERENCES products (sku),
+    reservation_id INTEGER REFERENCES reservations (id),
+    quantity       INTEGER      NOT NULL,
+    reason         VARCHAR(500) NOT NULL,
+    state          VARCHAR(50)  NOT NULL,
+    created_by     VARCHAR(100) NOT NULL,
+    created        TIMESTAMP WITH TIME ZONE,
+    received_by    VARCHAR(100),
+    received       TIMESTAMP WITH TIME ZONE,
+    inspected_by   VARCHAR(100),
+    inspected      TIMESTAMP WITH TIME ZONE,
+    note           VARCHAR(500)
+);
+
+CREATE
+INDEX return_sku_state_idx ON returns (sku, state);
+
+CREATE
+INDEX return_reservation_id_idx ON returns (reservation_id);
+
+COMMIT;
@@ -0,0 +1,19 @@
+CREATE TABLE inventory_history
+(
+    id        INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    sku       VARCHAR(50) REFERENCES products (sku),
+    on_hand   INTEGER NOT NULL,
+    available INTEGER NOT NULL,
+    reserved  INTEGER NOT NULL,
+    held      INTEGER NOT NULL,
+    created   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX inv_history_sku_idx ON inventory_history (sku, created);
+
+INSERT INTO inventory_history (sku, on_hand, available, reserved, held, created)
+SELECT sku, on_hand, available, reserved, held, now()
+  FROM product_inventory;
+
+COMMIT;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS purchase_receipts;
+
+DROP TABLE IF EXISTS purchase_order_lines;
+
+DROP TABLE IF EXISTS purchase_orders;
+
+COMMIT;
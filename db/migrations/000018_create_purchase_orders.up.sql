@@ -0,0 +1,47 @@
+CREATE TABLE purchase_orders
+(
+    id         INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    number     VARCHAR(100) UNIQUE NOT NULL,
+    supplier   VARCHAR(200)        NOT NULL,
+    state      VARCHAR(50)         NOT NULL,
+    created_by VARCHAR(100)        NOT NULL,
+    created    TIMESTAMP WITH TIME ZONE,
+    closed_by  VARCHAR(100),
+    closed     TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX po_state_idx ON purchase_orders (state);
+
+CREATE
+INDEX po_supplier_idx ON purchase_orders (supplier);
+
+CREATE TABLE purchase_order_lines
+(
+    purchase_order_id INTEGER REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    line_number       INTEGER                               NOT NULL,
+    sku               VARCHAR(50) REFERENCES products (sku) NOT NULL,
+    quantity          INTEGER                               NOT NULL,
+    received          INTEGER                               NOT NULL DEFAULT 0,
+    unit_cost         NUMERIC(14, 4)                        NOT NULL DEFAULT 0,
+    expected          TIMESTAMP WITH TIME ZONE              NOT NULL,
+    PRIMARY KEY (purchase_order_id, line_number)
+);
+
+CREATE
+INDEX po_line_sku_expected_idx ON purchase_order_lines (sku, expected);
+
+CREATE TABLE purchase_receipts
+(
+    production_event_id INTEGER PRIMARY KEY REFERENCES production_events (id),
+    purchase_order_id   INTEGER REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    line_number         INTEGER      NOT NULL,
+    quantity            INTEGER      NOT NULL,
+    username            VARCHAR(100) NOT NULL,
+    created             TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX po_receipt_po_idx ON purchase_receipts (purchase_order_id, line_number);
+
+COMMIT;
@@ -0,0 +1,21 @@
+CREATE TABLE conversions
+(
+    id              INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    request_id      VARCHAR(100) UNIQUE NOT NULL,
+    source_sku      VARCHAR(50) REFERENCES products (sku),
+    source_quantity INTEGER      NOT NULL,
+    target_sku      VARCHAR(50) REFERENCES products (sku),
+    target_quantity INTEGER      NOT NULL,
+    ratio           NUMERIC(14, 6) NOT NULL,
+    cost            NUMERIC(14, 4) NOT NULL DEFAULT 0,
+    username        VARCHAR(100) NOT NULL,
+    created         TIMESTAMP WITH TIME ZONE
+);
+
+CREATE
+INDEX conversion_source_sku_idx ON conversions (source_sku);
+
+CREATE
+INDEX conversion_target_sku_idx ON conversions (target_sku);
+
+COMMIT;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS prod_evt_line_shift_idx;
+
+ALTER TABLE production_events
+    DROP COLUMN IF EXISTS scrapped,
+    DROP COLUMN IF EXISTS line,
+    DROP COLUMN IF EXISTS shift,
+    DROP COLUMN IF EXISTS operator;
+
+COMMIT;
@@ -0,0 +1,10 @@
+ALTER TABLE production_events
+    ADD COLUMN scrapped INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN line     VARCHAR(50),
+    ADD COLUMN shift    VARCHAR(50),
+    ADD COLUMN operator VARCHAR(100);
+
+CREATE
+INDEX prod_evt_line_shift_idx ON production_events (line, shift, created);
+
+COMMIT;
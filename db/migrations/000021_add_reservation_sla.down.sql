@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS res_open_created_idx;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS sla_alerted;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS reservation_sla_hours;
+
+COMMIT;
@@ -0,0 +1,10 @@
+ALTER TABLE products
+    ADD COLUMN reservation_sla_hours INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE reservations
+    ADD COLUMN sla_alerted TIMESTAMP WITH TIME ZONE;
+
+CREATE
+INDEX res_open_created_idx ON reservations (state, created);
+
+COMMIT;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS cross_references;
+
+COMMIT;
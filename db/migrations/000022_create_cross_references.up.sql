@@ -0,0 +1,15 @@
+CREATE TABLE cross_references
+(
+    id         INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    sku        VARCHAR(50) NOT NULL REFERENCES products (sku),
+    identifier VARCHAR(100) NOT NULL,
+    type       VARCHAR(50)  NOT NULL,
+    requester  VARCHAR(100) NOT NULL DEFAULT '',
+    created    TIMESTAMP WITH TIME ZONE,
+    UNIQUE (identifier, requester)
+);
+
+CREATE
+INDEX xref_sku_idx ON cross_references (sku);
+
+COMMIT;
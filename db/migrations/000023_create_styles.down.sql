@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS product_style_variant_idx;
+DROP INDEX IF EXISTS product_style_idx;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS variant,
+    DROP COLUMN IF EXISTS style;
+
+DROP TABLE IF EXISTS styles;
+
+COMMIT;
@@ -0,0 +1,19 @@
+CREATE TABLE styles
+(
+    code    VARCHAR(50) PRIMARY KEY,
+    name    VARCHAR(100) NOT NULL,
+    axes    JSONB        NOT NULL DEFAULT '[]',
+    created TIMESTAMP WITH TIME ZONE
+);
+
+ALTER TABLE products
+    ADD COLUMN style   VARCHAR(50) REFERENCES styles (code),
+    ADD COLUMN variant JSONB NOT NULL DEFAULT '{}';
+
+CREATE
+INDEX product_style_idx ON products (style);
+
+CREATE
+UNIQUE INDEX product_style_variant_idx ON products (style, variant) WHERE style IS NOT NULL;
+
+COMMIT;
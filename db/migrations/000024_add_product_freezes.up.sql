@@ -0,0 +1,4 @@
+ALTER TABLE products
+    ADD COLUMN freezes JSONB NOT NULL DEFAULT '{}';
+
+COMMIT;
@@ -0,0 +1,4 @@
+ALTER TABLE production_events
+    DROP COLUMN IF EXISTS held;
+
+COMMIT;
@@ -0,0 +1,4 @@
+ALTER TABLE production_events
+    ADD COLUMN held INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
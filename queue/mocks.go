@@ -8,8 +8,11 @@ import (
 )
 
 type MockQueue struct {
-	PublishInventoryFunc   func(ctx context.Context, productInventory inventory.ProductInventory) error
-	PublishReservationFunc func(ctx context.Context, reservation inventory.Reservation) error
+	PublishInventoryFunc        func(ctx context.Context, productInventory inventory.ProductInventory) error
+	PublishReservationFunc      func(ctx context.Context, reservation inventory.Reservation) error
+	PublishReturnFunc           func(ctx context.Context, rma inventory.ReturnAuthorization) error
+	PublishReservationAlertFunc func(ctx context.Context, alert inventory.ReservationAlert) error
+	PublishFreezeFunc           func(ctx context.Context, change inventory.FreezeChange) error
 	testutil.CallWatcher
 }
 
@@ -21,6 +24,15 @@ func NewMockQueue() *MockQueue {
 		PublishReservationFunc: func(ctx context.Context, reservation inventory.Reservation) error {
 			return nil
 		},
+		PublishReturnFunc: func(ctx context.Context, rma inventory.ReturnAuthorization) error {
+			return nil
+		},
+		PublishReservationAlertFunc: func(ctx context.Context, alert inventory.ReservationAlert) error {
+			return nil
+		},
+		PublishFreezeFunc: func(ctx context.Context, change inventory.FreezeChange) error {
+			return nil
+		},
 		CallWatcher: *testutil.NewCallWatcher(),
 	}
 }
@@ -34,3 +46,18 @@ func (m *MockQueue) PublishReservation(ctx context.Context, reservation inventor
 	m.AddCall(ctx, reservation)
 	return m.PublishReservationFunc(ctx, reservation)
 }
+
+func (m *MockQueue) PublishReturn(ctx context.Context, rma inventory.ReturnAuthorization) error {
+	m.AddCall(ctx, rma)
+	return m.PublishReturnFunc(ctx, rma)
+}
+
+func (m *MockQueue) PublishReservationAlert(ctx context.Context, alert inventory.ReservationAlert) error {
+	m.AddCall(ctx, alert)
+	return m.PublishReservationAlertFunc(ctx, alert)
+}
+
+func (m *MockQueue) PublishFreeze(ctx context.Context, change inventory.FreezeChange) error {
+	m.AddCall(ctx, change)
+	return m.PublishFreezeFunc(ctx, change)
+}
@@ -16,16 +16,22 @@ type InventoryQueue struct {
 	cfg         *config.Config
 	inventory   chan<- message
 	reservation chan<- message
+	returns     chan<- message
+	freeze      chan<- message
 }
 
 func NewInventoryQueue(ctx context.Context, cfg *config.Config) *InventoryQueue {
 	invChan := make(chan message)
 	resChan := make(chan message)
+	rtnChan := make(chan message)
+	frzChan := make(chan message)
 
 	iq := &InventoryQueue{
 		cfg:         cfg,
 		inventory:   invChan,
 		reservation: resChan,
+		returns:     rtnChan,
+		freeze:      frzChan,
 	}
 
 	url := getUrl(cfg)
@@ -42,6 +48,18 @@ func NewInventoryQueue(ctx context.Context, cfg *config.Config) *InventoryQueue
 		ctx.Done()
 	}()
 
+	go func() {
+		rtnExch := cfg.RabbitMQ.Returns.Exchange.Value
+		publish(redial(ctx, url), rtnExch, rtnChan)
+		ctx.Done()
+	}()
+
+	go func() {
+		frzExch := cfg.RabbitMQ.Freeze.Exchange.Value
+		publish(redial(ctx, url), frzExch, frzChan)
+		ctx.Done()
+	}()
+
 	return iq
 }
 
@@ -68,6 +86,36 @@ func (i *InventoryQueue) PublishReservation(ctx context.Context, reservation inv
 	return nil
 }
 
+// PublishReservationAlert sends an alert about a reservation to the reservation exchange, alongside the reservation
+// updates themselves.
+func (i *InventoryQueue) PublishReservationAlert(ctx context.Context, alert inventory.ReservationAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return errors.WithMessage(err, "error marshalling reservation alert to send to queue")
+	}
+	i.reservation <- message(body)
+	return nil
+}
+
+// PublishFreeze sends a change to the activities frozen on a product to the freeze exchange.
+func (i *InventoryQueue) PublishFreeze(ctx context.Context, change inventory.FreezeChange) error {
+	body, err := json.Marshal(change)
+	if err != nil {
+		return errors.WithMessage(err, "error marshalling freeze change to send to queue")
+	}
+	i.freeze <- message(body)
+	return nil
+}
+
+func (i *InventoryQueue) PublishReturn(ctx context.Context, rma inventory.ReturnAuthorization) error {
+	body, err := json.Marshal(rma)
+	if err != nil {
+		return errors.WithMessage(err, "error marshalling return to send to queue")
+	}
+	i.returns <- message(body)
+	return nil
+}
+
 type ProductQueue struct {
 	cfg        *config.Config
 	product    <-chan message
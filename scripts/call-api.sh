@@ -12,6 +12,40 @@ curl -i -H "content-type:application/json" -u admin:admin \
 curl -i -H "content-type:application/json" -u admin:admin \
     "http://localhost:8080/api/v1/inventory/sku123"
 
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"requestID":"produceReq2","quantity":5,"unitCost":2.5}' \
+    "http://localhost:8080/api/v1/inventory/sku123/productionEvent"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/sku123/valuation"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/sku123/valuation/history"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/valuation"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"sku":"kit123","upc":"somekitupc","name":"some kit","type":"Kit","components":[{"sku":"sku123","quantity":2}]}' \
+    "http://localhost:8080/api/v1/inventory"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/kit123/components"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/kit123"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"name":"weight","type":"number"}' \
+    "http://localhost:8080/api/v1/inventory/attributes"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"attributes":{"color":"red","weight":2.5}}' \
+    "http://localhost:8080/api/v1/inventory/sku123/attributes"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    "http://localhost:8080/api/v1/inventory?attr.color=red&attr.weight=2.5"
+
 curl -i -H "content-type:application/json" -u admin:admin \
     -XPUT -d'{"requestID":"reserveReq1","sku":"sku123","requester":"someperson","quantity":2}' \
     "http://localhost:8080/api/v1/reservation"
@@ -47,4 +81,188 @@ curl -i -H "content-type:application/json" -u admin:admin \
 
 curl -i -H "content-type:application/json" -u admin:admin \
     -XPUT -d'{"requestID":"reserveReq16","requester":"Sean Smith", "sku": "sku123", "quantity":3}' \
-    "http://localhost:8080/api/v1/reservation"
\ No newline at end of file
+    "http://localhost:8080/api/v1/reservation"
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"name":"sporting goods"}' \
+    "http://localhost:8080/api/v1/category"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"name":"baseball","parentId":1}' \
+    "http://localhost:8080/api/v1/category"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"categoryId":2}' \
+    "http://localhost:8080/api/v1/inventory/sku123/category"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    "http://localhost:8080/api/v1/category/1/children"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    "http://localhost:8080/api/v1/category/1/inventory"
+
+curl -i -H "content-type:text/csv" -u admin:admin \
+    -XPOST --data-binary $'sku,upc,name,categoryId,attr.color\nbat1,111,wood bat,2,brown\nglove1,222,glove,,tan\n' \
+    "http://localhost:8080/api/v1/inventory/import"
+
+curl -i -H "content-type:application/x-ndjson" -u admin:admin \
+    -XPOST --data-binary $'{"sku":"ball1","upc":"333","name":"baseball","attributes":{"weight":5}}\n' \
+    "http://localhost:8080/api/v1/inventory/import"
+
+curl -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/export"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"events":[{"sku":"sku123","requestID":"shift1-1","quantity":5},{"sku":"powerbat1","requestID":"shift1-2","quantity":3}]}' \
+    "http://localhost:8080/api/v1/inventory/productionEvents"
+
+curl -i -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/reports/fillRate?groupBy=requester&from=2026-01-01&to=2026-01-31"
+
+curl -i -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/reports/timeToClose?sku=sku123"
+
+curl -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/reports/production?from=2026-01-01&format=csv"
+
+curl -i -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/reports/openDemand?limit=5"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"quantity":50,"due":"2026-11-01T08:00:00Z","reference":"line 2 week 44"}' \
+    "http://localhost:8080/api/v1/inventory/sku123/plannedProduction"
+
+curl -i -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/sku123/atp"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"skus":["sku123","powerbat1"]}' \
+    "http://localhost:8080/api/v1/count"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"counts":[{"sku":"sku123","quantity":9},{"sku":"powerbat1","quantity":4}]}' \
+    "http://localhost:8080/api/v1/count/1/counts"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT "http://localhost:8080/api/v1/count/1/approve"
+
+curl -i -u admin:admin \
+    "http://localhost:8080/api/v1/count?state=Approved"
+
+curl -i -u admin:admin \
+    "http://localhost:8080/api/v1/inventory/sku123/adjustments"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"requiresInspection":true}' \
+    "http://localhost:8080/api/v1/inventory/sku123/inspection"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"quantity":3}' \
+    "http://localhost:8080/api/v1/inventory/sku123/hold/release"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"quantity":1,"reference":"scratched finish"}' \
+    "http://localhost:8080/api/v1/inventory/sku123/hold/reject"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"substituteSku":"powerbat1","quantity":1,"substituteQuantity":1,"twoWay":true}' \
+    "http://localhost:8080/api/v1/inventory/sku123/substitutes"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"requestID":"reserveReq20","requester":"someperson","sku":"sku123","quantity":10,"allowSubstitutes":true}' \
+    "http://localhost:8080/api/v1/reservation"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"requestID":"reserveReq21","requester":"someperson","sku":"sku123","quantity":1000,"splitBackorder":true}' \
+    "http://localhost:8080/api/v1/reservation"
+
+curl -i "http://localhost:8080/api/v1/reservation?parentId=1"
+
+curl -i "http://localhost:8080/api/v1/inventory/productionEvent/prodReq1/consumers"
+
+curl -i "http://localhost:8080/api/v1/reservation/1/sources"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"approvalThreshold":500}' \
+    "http://localhost:8080/api/v1/inventory/sku123/approvalThreshold"
+
+curl -i "http://localhost:8080/api/v1/reservation?state=PendingApproval"
+
+curl -i -u admin:admin -XPUT "http://localhost:8080/api/v1/reservation/1/approve"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"reason":"exceeds customer allocation"}' \
+    "http://localhost:8080/api/v1/reservation/2/reject"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"reservationId":1,"quantity":2,"reason":"damaged in transit"}' \
+    "http://localhost:8080/api/v1/returns"
+
+curl -i -u admin:admin -XPUT "http://localhost:8080/api/v1/returns/1/receive"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"outcome":"Restock","note":"packaging intact"}' \
+    "http://localhost:8080/api/v1/returns/1/inspect"
+
+curl -i "http://localhost:8080/api/v1/returns?state=Restocked&sku=sku123"
+
+curl -i "http://localhost:8080/api/v1/inventory/sku123/timeseries?from=2026-03-01&to=2026-03-07&interval=6h"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"number":"PO-1001","supplier":"acme","lines":[{"sku":"sku123","quantity":100,"unitCost":2.5,"expected":"2026-04-01T00:00:00Z"}]}' \
+    "http://localhost:8080/api/v1/purchaseOrders"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"requestId":"rcpt1","lineNumber":1,"quantity":60}' \
+    "http://localhost:8080/api/v1/purchaseOrders/1/receipts"
+
+curl -i -u admin:admin -XPUT "http://localhost:8080/api/v1/purchaseOrders/1/close"
+
+curl -i "http://localhost:8080/api/v1/purchaseOrders?state=PartiallyReceived"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"requestID":"conv1","targetSku":"sku456","quantity":2,"ratio":12}' \
+    "http://localhost:8080/api/v1/inventory/sku123/conversions"
+
+curl -i "http://localhost:8080/api/v1/inventory/sku123/conversions"
+
+curl -i -H "content-type:application/json" \
+    -XPUT -d'{"requestID":"prodReq9","quantity":95,"scrapped":5,"line":"L1","shift":"A","operator":"jdoe"}' \
+    "http://localhost:8080/api/v1/inventory/sku123/productionEvent"
+
+curl -i "http://localhost:8080/api/v1/inventory/reports/lineYield?from=2026-03-01&to=2026-03-31"
+
+curl -i "http://localhost:8080/api/v1/inventory/reports/lineOutput?from=2026-03-01&to=2026-03-31&format=csv"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"reservationSlaHours":24}' \
+    "http://localhost:8080/api/v1/inventory/sku123/reservationSla"
+
+curl -i "http://localhost:8080/api/v1/inventory/reports/reservationAging?sku=sku123&format=csv"
+
+curl -i "http://localhost:8080/api/v1/inventory/reports/aging?slowDays=60&format=csv"
+
+curl -i -H "content-type:application/json" \
+    -XPUT -d'{"identifier":"CUST-123","type":"CustomerPart","requester":"acme"}' \
+    "http://localhost:8080/api/v1/inventory/sku123/crossReferences"
+
+curl -i "http://localhost:8080/api/v1/inventory/lookup?identifier=CUST-123&requester=acme"
+
+curl -i -H "content-type:application/json" \
+    -XPUT -d'{"requestId":"res9","requester":"acme","customerPartNumber":"CUST-123","quantity":5}' \
+    "http://localhost:8080/api/v1/reservation"
+
+curl -i -H "content-type:application/json" \
+    -XPUT -d'{"code":"TEE","name":"Crew Neck Tee","axes":["size","color"]}' \
+    "http://localhost:8080/api/v1/inventory/styles"
+
+curl -i -H "content-type:application/json" \
+    -XPUT -d'{"style":"TEE","variant":{"size":"M","color":"red"}}' \
+    "http://localhost:8080/api/v1/inventory/sku123/variant"
+
+curl -i "http://localhost:8080/api/v1/inventory/styles/TEE"
+
+curl -i "http://localhost:8080/api/v1/inventory?groupBy=style&attr.color=red"
+
+curl -i -H "content-type:application/json" -u admin:admin \
+    -XPUT -d'{"freeze":"Reservations","frozen":true,"reason":"incident 42"}' \
+    "http://localhost:8080/api/v1/inventory/sku123/freeze"